@@ -4,18 +4,23 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type (
 	Config struct {
-		App      App
-		Db       Db
-		Jwt      Jwt
-		Kafka    Kafka
-		Grpc     Grpc
-		Paginate Paginate
+		App         App
+		Db          Db
+		Jwt         Jwt
+		Kafka       Kafka
+		Grpc        Grpc
+		Paginate    Paginate
+		Marketplace Marketplace
+		Gift        Gift
+		Inventory   Inventory
+		Callback    Callback
 	}
 
 	App struct {
@@ -43,17 +48,44 @@ type (
 	}
 
 	Grpc struct {
-		AuthUrl      string
-		PlayerUrl    string
-		ItemUrl      string
-		InventoryUrl string
-		PaymentUrl   string
+		AuthUrl         string
+		PlayerUrl       string
+		ItemUrl         string
+		InventoryUrl    string
+		PaymentUrl      string
+		SeasonUrl       string
+		SubscriptionUrl string
 	}
 
 	Paginate struct {
 		ItemNextPageBasedUrl      string
 		InventoryNextPageBasedUrl string
 	}
+
+	Marketplace struct {
+		ListingFee float64
+		TaxRate    float64
+	}
+
+	// Inventory slot limits; a zero DefaultCapacity means unlimited and a
+	// zero MaxCapacity means upgrades are not capped.
+	Inventory struct {
+		DefaultCapacity int64
+		MaxCapacity     int64
+	}
+
+	// Gift limits; zero turns a limit off.
+	Gift struct {
+		MinAccountAgeDays int
+		DailyLimit        int
+	}
+
+	// Order callbacks are only sent to AllowedHosts and are signed with
+	// SigningSecret; an empty list or secret turns them off.
+	Callback struct {
+		AllowedHosts  []string
+		SigningSecret string
+	}
 )
 
 func LoadConfig(path string) Config {
@@ -95,15 +127,58 @@ func LoadConfig(path string) Config {
 			Secret: os.Getenv("KAFKA_API_SECRET"),
 		},
 		Grpc: Grpc{
-			AuthUrl:      os.Getenv("GRPC_AUTH_URL"),
-			PlayerUrl:    os.Getenv("GRPC_PLAYER_URL"),
-			ItemUrl:      os.Getenv("GRPC_ITEM_URL"),
-			InventoryUrl: os.Getenv("GRPC_INVENTORY_URL"),
-			PaymentUrl:   os.Getenv("GRPC_PAYMENT_URL"),
+			AuthUrl:         os.Getenv("GRPC_AUTH_URL"),
+			PlayerUrl:       os.Getenv("GRPC_PLAYER_URL"),
+			ItemUrl:         os.Getenv("GRPC_ITEM_URL"),
+			InventoryUrl:    os.Getenv("GRPC_INVENTORY_URL"),
+			PaymentUrl:      os.Getenv("GRPC_PAYMENT_URL"),
+			SeasonUrl:       os.Getenv("GRPC_SEASON_URL"),
+			SubscriptionUrl: os.Getenv("GRPC_SUBSCRIPTION_URL"),
 		},
 		Paginate: Paginate{
 			ItemNextPageBasedUrl:      os.Getenv("PAGINATE_ITEM_NEXT_PAGE_BASED_URL"),
 			InventoryNextPageBasedUrl: os.Getenv("PAGINATE_INVENTORY_NEXT_PAGE_BASED_URL"),
 		},
+		Marketplace: Marketplace{
+			ListingFee: parseOptionalFloat("MARKETPLACE_LISTING_FEE"),
+			TaxRate:    parseOptionalFloat("MARKETPLACE_TAX_RATE"),
+		},
+		Gift: Gift{
+			MinAccountAgeDays: int(parseOptionalFloat("GIFT_MIN_ACCOUNT_AGE_DAYS")),
+			DailyLimit:        int(parseOptionalFloat("GIFT_DAILY_LIMIT")),
+		},
+		Inventory: Inventory{
+			DefaultCapacity: int64(parseOptionalFloat("INVENTORY_DEFAULT_CAPACITY")),
+			MaxCapacity:     int64(parseOptionalFloat("INVENTORY_MAX_CAPACITY")),
+		},
+		Callback: Callback{
+			AllowedHosts:  parseOptionalList("CALLBACK_ALLOWED_HOSTS"),
+			SigningSecret: os.Getenv("CALLBACK_SIGNING_SECRET"),
+		},
+	}
+}
+
+// parseOptionalFloat reads a number that only some services configure.
+func parseOptionalFloat(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Fatalf("error: failed to load %s", key)
+	}
+	return result
+}
+
+// parseOptionalList reads a comma separated list of lower case values.
+func parseOptionalList(key string) []string {
+	result := make([]string, 0)
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			result = append(result, v)
+		}
 	}
+	return result
 }
@@ -30,6 +30,14 @@ func main() {
 		}
 	}()
 
+	// Run a one-off command, e.g. an item catalog import
+	if len(os.Args) > 2 {
+		if err := server.RunCommand(ctx, &cfg, db, os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	// Start Server
 	server.Start(ctx, &cfg, db)
 }
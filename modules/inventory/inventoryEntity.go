@@ -1,11 +1,71 @@
 package inventory
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 type (
 	Inventory struct {
 		Id       bson.ObjectID `json:"_id" bson:"_id,omitempty"`
 		PlayerId string        `json:"player_id" bson:"player_id"`
 		ItemId   string        `json:"item_id" bson:"item_id"`
+		// EquippedSlot is set while the item is equipped; equipped items
+		// cannot be sold, traded or listed. When two copies claim a slot the
+		// later EquippedAt holds it.
+		EquippedSlot string     `json:"equipped_slot,omitempty" bson:"equipped_slot,omitempty"`
+		EquippedAt   *time.Time `json:"-" bson:"equipped_at,omitempty"`
+		ItemInstance `bson:",inline"`
+	}
+
+	// ItemInstance is the state of one copy of an item. It moves with the
+	// copy through trades, listings and the mailbox. Entries created before
+	// instances existed have no serial and a zero MaxDurability.
+	ItemInstance struct {
+		Serial        string     `json:"serial,omitempty" bson:"serial,omitempty"`
+		Durability    int        `json:"durability" bson:"durability"`
+		MaxDurability int        `json:"max_durability" bson:"max_durability"`
+		EnchantLevel  int        `json:"enchant_level" bson:"enchant_level"`
+		Bound         bool       `json:"bound" bson:"bound"`
+		Source        string     `json:"source,omitempty" bson:"source,omitempty"`
+		AcquiredAt    time.Time  `json:"acquired_at" bson:"acquired_at"`
+		ExpiresAt     *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	}
+
+	// InventoryCapacity holds the slots a player has bought on top of the
+	// default capacity.
+	InventoryCapacity struct {
+		PlayerId   string    `json:"player_id" bson:"player_id"`
+		ExtraSlots int64     `json:"extra_slots" bson:"extra_slots"`
+		UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+	}
+
+	// ItemCooldown is when a player may next use a consumable.
+	ItemCooldown struct {
+		PlayerId string    `json:"player_id" bson:"player_id"`
+		ItemId   string    `json:"item_id" bson:"item_id"`
+		ReadyAt  time.Time `json:"ready_at" bson:"ready_at"`
+	}
+
+	// InventoryReference tracks one saga step by its reference id. The step
+	// and a rollback by reference both update it, so whichever comes second
+	// sees the other and the inventory ends up as if the step never ran.
+	InventoryReference struct {
+		ReferenceId string     `bson:"reference_id"`
+		Cancelled   bool       `bson:"cancelled"`
+		AddedIds    []string   `bson:"added_ids,omitempty"`
+		Removed     *Inventory `bson:"removed,omitempty"`
+		UpdatedAt   time.Time  `bson:"updated_at"`
+	}
+
+	// Mail is an item waiting to be claimed because it did not fit in the
+	// player's inventory when it was granted.
+	Mail struct {
+		Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId  string        `json:"player_id" bson:"player_id"`
+		ItemId    string        `json:"item_id" bson:"item_id"`
+		Instance  *ItemInstance `json:"instance,omitempty" bson:"instance,omitempty"`
+		CreatedAt time.Time     `json:"created_at" bson:"created_at"`
 	}
 )
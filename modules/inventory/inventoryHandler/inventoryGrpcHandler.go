@@ -0,0 +1,131 @@
+package inventoryHandler
+
+import (
+	"context"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryUsecase"
+)
+
+type (
+	inventoryGrpcHandler struct {
+		inventoryPb.UnimplementedInventoryGrpcServiceServer
+		cfg              *config.Config
+		inventoryUsecase inventoryUsecase.InventoryUsecaseService
+	}
+)
+
+func NewInventoryGrpcHandler(cfg *config.Config, inventoryUsecase inventoryUsecase.InventoryUsecaseService) *inventoryGrpcHandler {
+	return &inventoryGrpcHandler{
+		cfg:              cfg,
+		inventoryUsecase: inventoryUsecase,
+	}
+}
+
+func (g *inventoryGrpcHandler) HasItem(ctx context.Context, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error) {
+	return g.inventoryUsecase.HasItem(ctx, g.cfg, req)
+}
+
+func (g *inventoryGrpcHandler) CountItem(ctx context.Context, req *inventoryPb.CountItemReq) (*inventoryPb.CountItemRes, error) {
+	return g.inventoryUsecase.CountItem(ctx, g.cfg, req)
+}
+
+func (g *inventoryGrpcHandler) ListPlayerItems(ctx context.Context, req *inventoryPb.ListPlayerItemsReq) (*inventoryPb.ListPlayerItemsRes, error) {
+	return g.inventoryUsecase.ListPlayerItems(ctx, req)
+}
+
+func (g *inventoryGrpcHandler) BatchGrant(ctx context.Context, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	return g.inventoryUsecase.BatchGrant(ctx, g.cfg, req)
+}
+
+func (g *inventoryGrpcHandler) BatchRevoke(ctx context.Context, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	return g.inventoryUsecase.BatchRevoke(ctx, g.cfg, req)
+}
+
+func (g *inventoryGrpcHandler) GetInventoryCapacity(ctx context.Context, req *inventoryPb.GetInventoryCapacityReq) (*inventoryPb.InventoryCapacityRes, error) {
+	result, err := g.inventoryUsecase.GetCapacity(ctx, g.cfg, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryPb.InventoryCapacityRes{
+		Capacity: result.Capacity,
+		Used:     result.Used,
+	}, nil
+}
+
+func (g *inventoryGrpcHandler) UpgradeInventoryCapacity(ctx context.Context, req *inventoryPb.UpgradeInventoryCapacityReq) (*inventoryPb.InventoryCapacityRes, error) {
+	result, err := g.inventoryUsecase.UpgradeCapacity(ctx, g.cfg, req.PlayerId, req.Slots)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryPb.InventoryCapacityRes{
+		Capacity: result.Capacity,
+		Used:     result.Used,
+	}, nil
+}
+
+func (g *inventoryGrpcHandler) GetLoadout(ctx context.Context, req *inventoryPb.GetLoadoutReq) (*inventoryPb.LoadoutRes, error) {
+	result, err := g.inventoryUsecase.GetLoadout(ctx, g.cfg, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*inventoryPb.EquippedItem, 0)
+	for _, v := range result.Items {
+		items = append(items, &inventoryPb.EquippedItem{
+			Slot:          v.EquippedSlot,
+			InventoryId:   v.InventoryId,
+			ItemId:        v.ItemId,
+			Title:         v.Title,
+			Damage:        int32(v.Damage),
+			LevelRequired: int32(v.LevelRequired),
+		})
+	}
+
+	return &inventoryPb.LoadoutRes{
+		PlayerId: result.PlayerId,
+		Items:    items,
+		Damage:   int32(result.Damage),
+	}, nil
+}
+
+func (g *inventoryGrpcHandler) UpdateInventoryInstance(ctx context.Context, req *inventoryPb.UpdateInventoryInstanceReq) (*inventoryPb.InventoryItem, error) {
+	update := new(inventory.UpdateItemInstanceReq)
+	if req.Durability != nil {
+		durability := int(req.GetDurability())
+		update.Durability = &durability
+	}
+	if req.EnchantLevel != nil {
+		enchantLevel := int(req.GetEnchantLevel())
+		update.EnchantLevel = &enchantLevel
+	}
+	update.Bound = req.Bound
+
+	result, err := g.inventoryUsecase.UpdateItemInstance(ctx, req.InventoryId, update)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &inventoryPb.ItemInstance{
+		Serial:        result.Serial,
+		Durability:    int32(result.Durability),
+		MaxDurability: int32(result.MaxDurability),
+		EnchantLevel:  int32(result.EnchantLevel),
+		Bound:         result.Bound,
+		Source:        result.Source,
+	}
+	if result.ExpiresAt != nil {
+		instance.ExpiresAt = result.ExpiresAt.Unix()
+	}
+
+	return &inventoryPb.InventoryItem{
+		InventoryId: result.Id.Hex(),
+		PlayerId:    result.PlayerId,
+		ItemId:      result.ItemId,
+		Instance:    instance,
+	}, nil
+}
@@ -16,6 +16,15 @@ import (
 type (
 	InventoryHttpHandlerService interface {
 		FindPlayerItems(c echo.Context) error
+		FindPlayerMails(c echo.Context) error
+		ClaimMail(c echo.Context) error
+		GetLoadout(c echo.Context) error
+		EquipItem(c echo.Context) error
+		UnequipItem(c echo.Context) error
+		FindOneItemInstance(c echo.Context) error
+		UpdateItemInstance(c echo.Context) error
+		BindItem(c echo.Context) error
+		UseItem(c echo.Context) error
 	}
 
 	inventoryHttpHandler struct {
@@ -53,3 +62,150 @@ func (h *inventoryHttpHandler) FindPlayerItems(c echo.Context) error {
 
 	return response.SuccessResponse(c, http.StatusOK, res)
 }
+
+func (h *inventoryHttpHandler) FindPlayerMails(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.inventoryUsecase.FindPlayerMails(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) ClaimMail(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+	mailId := c.Param("mail_id")
+
+	res, err := h.inventoryUsecase.ClaimMail(ctx, h.cfg, playerId, mailId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *inventoryHttpHandler) GetLoadout(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.inventoryUsecase.GetLoadout(ctx, h.cfg, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) EquipItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	playerId := c.Get("player_id").(string)
+
+	req := new(inventory.EquipItemReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.inventoryUsecase.EquipItem(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) UnequipItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	playerId := c.Get("player_id").(string)
+
+	req := new(inventory.UnequipItemReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.inventoryUsecase.UnequipItem(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) FindOneItemInstance(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+	inventoryId := c.Param("inventory_id")
+
+	res, err := h.inventoryUsecase.FindOneItemInstance(ctx, h.cfg, playerId, inventoryId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) UpdateItemInstance(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	inventoryId := c.Param("inventory_id")
+
+	req := new(inventory.UpdateItemInstanceReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.inventoryUsecase.AdminUpdateItemInstance(ctx, h.cfg, inventoryId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) BindItem(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+	inventoryId := c.Param("inventory_id")
+
+	res, err := h.inventoryUsecase.BindItem(ctx, playerId, inventoryId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *inventoryHttpHandler) UseItem(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId, err := url.QueryUnescape(c.Param("player_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.inventoryUsecase.UseItem(ctx, h.cfg, playerId, c.Param("inventory_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
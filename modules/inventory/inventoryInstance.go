@@ -0,0 +1,48 @@
+package inventory
+
+import (
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+)
+
+// InstanceToPb carries a copy's instance state over grpc, e.g. when escrow
+// hands it to another player.
+func InstanceToPb(v *ItemInstance) *inventoryPb.ItemInstance {
+	if v == nil {
+		return nil
+	}
+
+	result := &inventoryPb.ItemInstance{
+		Serial:        v.Serial,
+		Durability:    int32(v.Durability),
+		MaxDurability: int32(v.MaxDurability),
+		EnchantLevel:  int32(v.EnchantLevel),
+		Bound:         v.Bound,
+		Source:        v.Source,
+	}
+	if v.ExpiresAt != nil {
+		result.ExpiresAt = v.ExpiresAt.Unix()
+	}
+	return result
+}
+
+func InstanceFromPb(v *inventoryPb.ItemInstance) *ItemInstance {
+	if v == nil {
+		return nil
+	}
+
+	result := &ItemInstance{
+		Serial:        v.Serial,
+		Durability:    int(v.Durability),
+		MaxDurability: int(v.MaxDurability),
+		EnchantLevel:  int(v.EnchantLevel),
+		Bound:         v.Bound,
+		Source:        v.Source,
+	}
+	if v.ExpiresAt > 0 {
+		expiresAt := time.Unix(v.ExpiresAt, 0)
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
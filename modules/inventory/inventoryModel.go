@@ -1,29 +1,111 @@
 package inventory
 
 import (
+	"time"
+
 	"github.com/Supakornn/mmorpg-shop/modules/item"
 	"github.com/Supakornn/mmorpg-shop/modules/models"
 )
 
 type (
 	UpdateInventoryReq struct {
-		PlayerId string `json:"player_id" validate:"required,max=64"`
-		ItemId   string `json:"item_id" validate:"required,max=64"`
+		PlayerId    string                  `json:"player_id" validate:"required,max=64"`
+		ItemId      string                  `json:"item_id" validate:"required,max=64"`
+		InventoryId string                  `json:"inventory_id,omitempty"`
+		Components  []*item.BundleComponent `json:"components,omitempty"`
+		Source      string                  `json:"source,omitempty"`
+		ReplyTopic  string                  `json:"reply_topic,omitempty"`
+		ReferenceId string                  `json:"reference_id,omitempty"`
 	}
 
 	ItemInInventory struct {
-		InventoryId string `json:"inventory_id"`
-		PlayerId    string `json:"player_id"`
+		InventoryId  string        `json:"inventory_id"`
+		PlayerId     string        `json:"player_id"`
+		EquippedSlot string        `json:"equipped_slot,omitempty"`
+		Instance     *ItemInstance `json:"instance,omitempty"`
+		// RemainingSeconds is set for copies that expire
+		RemainingSeconds *int64 `json:"remaining_seconds,omitempty"`
 		*item.ItemShowCase
 	}
 
+	// ItemUsedEvent is the payload of the "item.used" event.
+	ItemUsedEvent struct {
+		InventoryId string           `json:"inventory_id"`
+		ItemId      string           `json:"item_id"`
+		Serial      string           `json:"serial,omitempty"`
+		Effect      *item.ItemEffect `json:"effect"`
+	}
+
+	// ItemExpiredEvent is the payload of the "item.expired" event.
+	ItemExpiredEvent struct {
+		InventoryId string    `json:"inventory_id"`
+		ItemId      string    `json:"item_id"`
+		Serial      string    `json:"serial,omitempty"`
+		ExpiresAt   time.Time `json:"expires_at"`
+	}
+
+	// ItemUpdatedEvent is the payload of the "item.updated_by_admin" event.
+	ItemUpdatedEvent struct {
+		InventoryId  string `json:"inventory_id"`
+		ItemId       string `json:"item_id"`
+		Serial       string `json:"serial,omitempty"`
+		Durability   int    `json:"durability"`
+		EnchantLevel int    `json:"enchant_level"`
+		Bound        bool   `json:"bound"`
+	}
+
+	UseItemRes struct {
+		InventoryId string           `json:"inventory_id"`
+		ItemId      string           `json:"item_id"`
+		Effect      *item.ItemEffect `json:"effect"`
+		ReadyAt     time.Time        `json:"ready_at"`
+	}
+
+	// UpdateItemInstanceReq sets the given fields of one inventory entry.
+	UpdateItemInstanceReq struct {
+		Durability   *int  `json:"durability" validate:"omitempty,min=0"`
+		EnchantLevel *int  `json:"enchant_level" validate:"omitempty,min=0,max=20"`
+		Bound        *bool `json:"bound"`
+	}
+
+	EquipItemReq struct {
+		InventoryId string `json:"inventory_id" validate:"required,max=64"`
+	}
+
+	UnequipItemReq struct {
+		Slot string `json:"slot" validate:"required,max=16"`
+	}
+
+	// LoadoutRes is a player's equipped items and their combined stats.
+	LoadoutRes struct {
+		PlayerId string             `json:"player_id"`
+		Items    []*ItemInInventory `json:"items"`
+		Damage   int                `json:"damage"`
+	}
+
 	InventorySearchReq struct {
 		models.PaginateReq
 	}
 
+	PlayerItemsRes struct {
+		*models.PaginateRes
+		Capacity *CapacityRes `json:"capacity"`
+	}
+
+	// CapacityRes is zero in Capacity when the inventory is unlimited.
+	CapacityRes struct {
+		Capacity int64 `json:"capacity"`
+		Used     int64 `json:"used"`
+	}
+
+	// RollbackInventoryReq undoes an add or remove by its inventory ids, or
+	// by the saga step that made it when ReferenceId is set.
 	RollbackInventoryReq struct {
-		InventoryId string `json:"inventory_id"`
-		PlayerId    string `json:"player_id"`
-		ItemId      string `json:"item_id"`
+		InventoryId  string        `json:"inventory_id"`
+		InventoryIds []string      `json:"inventory_ids,omitempty"`
+		PlayerId     string        `json:"player_id"`
+		ItemId       string        `json:"item_id"`
+		Instance     *ItemInstance `json:"instance,omitempty"`
+		ReferenceId  string        `json:"reference_id,omitempty"`
 	}
 )
@@ -0,0 +1,1457 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: modules/inventory/inventoryPb/inventoryPb.proto
+
+package mmorpg_shop
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+//	Structures
+//
+// item_id is either "item:<hex>" or "sku:<SKU>"
+type HasItemReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasItemReq) Reset() {
+	*x = HasItemReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasItemReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasItemReq) ProtoMessage() {}
+
+func (x *HasItemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasItemReq.ProtoReflect.Descriptor instead.
+func (*HasItemReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HasItemReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *HasItemReq) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *HasItemReq) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type HasItemRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Has           bool                   `protobuf:"varint,1,opt,name=has,proto3" json:"has,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasItemRes) Reset() {
+	*x = HasItemRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasItemRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasItemRes) ProtoMessage() {}
+
+func (x *HasItemRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasItemRes.ProtoReflect.Descriptor instead.
+func (*HasItemRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HasItemRes) GetHas() bool {
+	if x != nil {
+		return x.Has
+	}
+	return false
+}
+
+type CountItemReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountItemReq) Reset() {
+	*x = CountItemReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountItemReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountItemReq) ProtoMessage() {}
+
+func (x *CountItemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountItemReq.ProtoReflect.Descriptor instead.
+func (*CountItemReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CountItemReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *CountItemReq) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type CountItemRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountItemRes) Reset() {
+	*x = CountItemRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountItemRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountItemRes) ProtoMessage() {}
+
+func (x *CountItemRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountItemRes.ProtoReflect.Descriptor instead.
+func (*CountItemRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CountItemRes) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ListPlayerItemsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlayerItemsReq) Reset() {
+	*x = ListPlayerItemsReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlayerItemsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlayerItemsReq) ProtoMessage() {}
+
+func (x *ListPlayerItemsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlayerItemsReq.ProtoReflect.Descriptor instead.
+func (*ListPlayerItemsReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPlayerItemsReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type ListPlayerItemsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*InventoryItem       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlayerItemsRes) Reset() {
+	*x = ListPlayerItemsRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlayerItemsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlayerItemsRes) ProtoMessage() {}
+
+func (x *ListPlayerItemsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlayerItemsRes.ProtoReflect.Descriptor instead.
+func (*ListPlayerItemsRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPlayerItemsRes) GetItems() []*InventoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type InventoryItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InventoryId   string                 `protobuf:"bytes,1,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,3,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Instance      *ItemInstance          `protobuf:"bytes,4,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryItem) Reset() {
+	*x = InventoryItem{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryItem) ProtoMessage() {}
+
+func (x *InventoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryItem.ProtoReflect.Descriptor instead.
+func (*InventoryItem) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InventoryItem) GetInventoryId() string {
+	if x != nil {
+		return x.InventoryId
+	}
+	return ""
+}
+
+func (x *InventoryItem) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *InventoryItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *InventoryItem) GetInstance() *ItemInstance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+type ItemInstance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Serial        string                 `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	Durability    int32                  `protobuf:"varint,2,opt,name=durability,proto3" json:"durability,omitempty"`
+	MaxDurability int32                  `protobuf:"varint,3,opt,name=max_durability,json=maxDurability,proto3" json:"max_durability,omitempty"`
+	EnchantLevel  int32                  `protobuf:"varint,4,opt,name=enchant_level,json=enchantLevel,proto3" json:"enchant_level,omitempty"`
+	Bound         bool                   `protobuf:"varint,5,opt,name=bound,proto3" json:"bound,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	// expires_at is a unix time in seconds, 0 when the copy never expires
+	ExpiresAt     int64 `protobuf:"varint,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemInstance) Reset() {
+	*x = ItemInstance{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemInstance) ProtoMessage() {}
+
+func (x *ItemInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemInstance.ProtoReflect.Descriptor instead.
+func (*ItemInstance) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ItemInstance) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *ItemInstance) GetDurability() int32 {
+	if x != nil {
+		return x.Durability
+	}
+	return 0
+}
+
+func (x *ItemInstance) GetMaxDurability() int32 {
+	if x != nil {
+		return x.MaxDurability
+	}
+	return 0
+}
+
+func (x *ItemInstance) GetEnchantLevel() int32 {
+	if x != nil {
+		return x.EnchantLevel
+	}
+	return 0
+}
+
+func (x *ItemInstance) GetBound() bool {
+	if x != nil {
+		return x.Bound
+	}
+	return false
+}
+
+func (x *ItemInstance) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ItemInstance) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// instance carries an existing copy over, e.g. out of trade escrow, and
+// needs a quantity of 1
+type GrantItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Instance      *ItemInstance          `protobuf:"bytes,4,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantItem) Reset() {
+	*x = GrantItem{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantItem) ProtoMessage() {}
+
+func (x *GrantItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantItem.ProtoReflect.Descriptor instead.
+func (*GrantItem) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GrantItem) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *GrantItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *GrantItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *GrantItem) GetInstance() *ItemInstance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+// Items that do not fit go to the mailbox when overflow_to_mailbox is set,
+// otherwise the whole grant fails.
+type BatchGrantReq struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Items             []*GrantItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	OverflowToMailbox bool                   `protobuf:"varint,2,opt,name=overflow_to_mailbox,json=overflowToMailbox,proto3" json:"overflow_to_mailbox,omitempty"`
+	// source is recorded on the granted copies, "grant" when empty
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGrantReq) Reset() {
+	*x = BatchGrantReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGrantReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGrantReq) ProtoMessage() {}
+
+func (x *BatchGrantReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGrantReq.ProtoReflect.Descriptor instead.
+func (*BatchGrantReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchGrantReq) GetItems() []*GrantItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchGrantReq) GetOverflowToMailbox() bool {
+	if x != nil {
+		return x.OverflowToMailbox
+	}
+	return false
+}
+
+func (x *BatchGrantReq) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type BatchGrantRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*InventoryItem       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Mailed        []*MailItem            `protobuf:"bytes,2,rep,name=mailed,proto3" json:"mailed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGrantRes) Reset() {
+	*x = BatchGrantRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGrantRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGrantRes) ProtoMessage() {}
+
+func (x *BatchGrantRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGrantRes.ProtoReflect.Descriptor instead.
+func (*BatchGrantRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchGrantRes) GetItems() []*InventoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchGrantRes) GetMailed() []*MailItem {
+	if x != nil {
+		return x.Mailed
+	}
+	return nil
+}
+
+type MailItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MailId        string                 `protobuf:"bytes,1,opt,name=mail_id,json=mailId,proto3" json:"mail_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,3,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MailItem) Reset() {
+	*x = MailItem{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MailItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MailItem) ProtoMessage() {}
+
+func (x *MailItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MailItem.ProtoReflect.Descriptor instead.
+func (*MailItem) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MailItem) GetMailId() string {
+	if x != nil {
+		return x.MailId
+	}
+	return ""
+}
+
+func (x *MailItem) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *MailItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type GetInventoryCapacityReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInventoryCapacityReq) Reset() {
+	*x = GetInventoryCapacityReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInventoryCapacityReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInventoryCapacityReq) ProtoMessage() {}
+
+func (x *GetInventoryCapacityReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInventoryCapacityReq.ProtoReflect.Descriptor instead.
+func (*GetInventoryCapacityReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetInventoryCapacityReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type UpgradeInventoryCapacityReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Slots         int64                  `protobuf:"varint,2,opt,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpgradeInventoryCapacityReq) Reset() {
+	*x = UpgradeInventoryCapacityReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpgradeInventoryCapacityReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpgradeInventoryCapacityReq) ProtoMessage() {}
+
+func (x *UpgradeInventoryCapacityReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpgradeInventoryCapacityReq.ProtoReflect.Descriptor instead.
+func (*UpgradeInventoryCapacityReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpgradeInventoryCapacityReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *UpgradeInventoryCapacityReq) GetSlots() int64 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+// capacity is zero when the inventory is unlimited
+type InventoryCapacityRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Capacity      int64                  `protobuf:"varint,1,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Used          int64                  `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryCapacityRes) Reset() {
+	*x = InventoryCapacityRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryCapacityRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryCapacityRes) ProtoMessage() {}
+
+func (x *InventoryCapacityRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryCapacityRes.ProtoReflect.Descriptor instead.
+func (*InventoryCapacityRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *InventoryCapacityRes) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *InventoryCapacityRes) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+type BatchRevokeReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*GrantItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// inventory_ids and mail_ids revoke the exact copies a grant returned,
+	// bound or equipped ones included, so the grant can be undone
+	InventoryIds []string `protobuf:"bytes,2,rep,name=inventory_ids,json=inventoryIds,proto3" json:"inventory_ids,omitempty"`
+	MailIds      []string `protobuf:"bytes,3,rep,name=mail_ids,json=mailIds,proto3" json:"mail_ids,omitempty"`
+	// include_bound lets items consume bound copies, for a player using up
+	// their own items rather than handing them over
+	IncludeBound  bool `protobuf:"varint,4,opt,name=include_bound,json=includeBound,proto3" json:"include_bound,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRevokeReq) Reset() {
+	*x = BatchRevokeReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRevokeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRevokeReq) ProtoMessage() {}
+
+func (x *BatchRevokeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRevokeReq.ProtoReflect.Descriptor instead.
+func (*BatchRevokeReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchRevokeReq) GetItems() []*GrantItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchRevokeReq) GetInventoryIds() []string {
+	if x != nil {
+		return x.InventoryIds
+	}
+	return nil
+}
+
+func (x *BatchRevokeReq) GetMailIds() []string {
+	if x != nil {
+		return x.MailIds
+	}
+	return nil
+}
+
+func (x *BatchRevokeReq) GetIncludeBound() bool {
+	if x != nil {
+		return x.IncludeBound
+	}
+	return false
+}
+
+type BatchRevokeRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*InventoryItem       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Mailed        []*MailItem            `protobuf:"bytes,2,rep,name=mailed,proto3" json:"mailed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRevokeRes) Reset() {
+	*x = BatchRevokeRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRevokeRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRevokeRes) ProtoMessage() {}
+
+func (x *BatchRevokeRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRevokeRes.ProtoReflect.Descriptor instead.
+func (*BatchRevokeRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchRevokeRes) GetItems() []*InventoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchRevokeRes) GetMailed() []*MailItem {
+	if x != nil {
+		return x.Mailed
+	}
+	return nil
+}
+
+type GetLoadoutReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoadoutReq) Reset() {
+	*x = GetLoadoutReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoadoutReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoadoutReq) ProtoMessage() {}
+
+func (x *GetLoadoutReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoadoutReq.ProtoReflect.Descriptor instead.
+func (*GetLoadoutReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetLoadoutReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type EquippedItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slot          string                 `protobuf:"bytes,1,opt,name=slot,proto3" json:"slot,omitempty"`
+	InventoryId   string                 `protobuf:"bytes,2,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,3,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Damage        int32                  `protobuf:"varint,5,opt,name=damage,proto3" json:"damage,omitempty"`
+	LevelRequired int32                  `protobuf:"varint,6,opt,name=level_required,json=levelRequired,proto3" json:"level_required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EquippedItem) Reset() {
+	*x = EquippedItem{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EquippedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EquippedItem) ProtoMessage() {}
+
+func (x *EquippedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EquippedItem.ProtoReflect.Descriptor instead.
+func (*EquippedItem) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EquippedItem) GetSlot() string {
+	if x != nil {
+		return x.Slot
+	}
+	return ""
+}
+
+func (x *EquippedItem) GetInventoryId() string {
+	if x != nil {
+		return x.InventoryId
+	}
+	return ""
+}
+
+func (x *EquippedItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *EquippedItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *EquippedItem) GetDamage() int32 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+func (x *EquippedItem) GetLevelRequired() int32 {
+	if x != nil {
+		return x.LevelRequired
+	}
+	return 0
+}
+
+// damage is the sum over all equipped items
+type LoadoutRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Items         []*EquippedItem        `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Damage        int32                  `protobuf:"varint,3,opt,name=damage,proto3" json:"damage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadoutRes) Reset() {
+	*x = LoadoutRes{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadoutRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadoutRes) ProtoMessage() {}
+
+func (x *LoadoutRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadoutRes.ProtoReflect.Descriptor instead.
+func (*LoadoutRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LoadoutRes) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *LoadoutRes) GetItems() []*EquippedItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *LoadoutRes) GetDamage() int32 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+type UpdateInventoryInstanceReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InventoryId   string                 `protobuf:"bytes,1,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	Durability    *int32                 `protobuf:"varint,2,opt,name=durability,proto3,oneof" json:"durability,omitempty"`
+	EnchantLevel  *int32                 `protobuf:"varint,3,opt,name=enchant_level,json=enchantLevel,proto3,oneof" json:"enchant_level,omitempty"`
+	Bound         *bool                  `protobuf:"varint,4,opt,name=bound,proto3,oneof" json:"bound,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateInventoryInstanceReq) Reset() {
+	*x = UpdateInventoryInstanceReq{}
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateInventoryInstanceReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateInventoryInstanceReq) ProtoMessage() {}
+
+func (x *UpdateInventoryInstanceReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateInventoryInstanceReq.ProtoReflect.Descriptor instead.
+func (*UpdateInventoryInstanceReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateInventoryInstanceReq) GetInventoryId() string {
+	if x != nil {
+		return x.InventoryId
+	}
+	return ""
+}
+
+func (x *UpdateInventoryInstanceReq) GetDurability() int32 {
+	if x != nil && x.Durability != nil {
+		return *x.Durability
+	}
+	return 0
+}
+
+func (x *UpdateInventoryInstanceReq) GetEnchantLevel() int32 {
+	if x != nil && x.EnchantLevel != nil {
+		return *x.EnchantLevel
+	}
+	return 0
+}
+
+func (x *UpdateInventoryInstanceReq) GetBound() bool {
+	if x != nil && x.Bound != nil {
+		return *x.Bound
+	}
+	return false
+}
+
+var File_modules_inventory_inventoryPb_inventoryPb_proto protoreflect.FileDescriptor
+
+const file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc = "" +
+	"\n" +
+	"/modules/inventory/inventoryPb/inventoryPb.proto\"^\n" +
+	"\n" +
+	"HasItemReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\tR\x06itemId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\"\x1e\n" +
+	"\n" +
+	"HasItemRes\x12\x10\n" +
+	"\x03has\x18\x01 \x01(\bR\x03has\"D\n" +
+	"\fCountItemReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\tR\x06itemId\"$\n" +
+	"\fCountItemRes\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"1\n" +
+	"\x12ListPlayerItemsReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\":\n" +
+	"\x12ListPlayerItemsRes\x12$\n" +
+	"\x05items\x18\x01 \x03(\v2\x0e.InventoryItemR\x05items\"\x93\x01\n" +
+	"\rInventoryItem\x12!\n" +
+	"\finventory_id\x18\x01 \x01(\tR\vinventoryId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x17\n" +
+	"\aitem_id\x18\x03 \x01(\tR\x06itemId\x12)\n" +
+	"\binstance\x18\x04 \x01(\v2\r.ItemInstanceR\binstance\"\xdf\x01\n" +
+	"\fItemInstance\x12\x16\n" +
+	"\x06serial\x18\x01 \x01(\tR\x06serial\x12\x1e\n" +
+	"\n" +
+	"durability\x18\x02 \x01(\x05R\n" +
+	"durability\x12%\n" +
+	"\x0emax_durability\x18\x03 \x01(\x05R\rmaxDurability\x12#\n" +
+	"\renchant_level\x18\x04 \x01(\x05R\fenchantLevel\x12\x14\n" +
+	"\x05bound\x18\x05 \x01(\bR\x05bound\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\x03R\texpiresAt\"\x88\x01\n" +
+	"\tGrantItem\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\tR\x06itemId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12)\n" +
+	"\binstance\x18\x04 \x01(\v2\r.ItemInstanceR\binstance\"y\n" +
+	"\rBatchGrantReq\x12 \n" +
+	"\x05items\x18\x01 \x03(\v2\n" +
+	".GrantItemR\x05items\x12.\n" +
+	"\x13overflow_to_mailbox\x18\x02 \x01(\bR\x11overflowToMailbox\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"X\n" +
+	"\rBatchGrantRes\x12$\n" +
+	"\x05items\x18\x01 \x03(\v2\x0e.InventoryItemR\x05items\x12!\n" +
+	"\x06mailed\x18\x02 \x03(\v2\t.MailItemR\x06mailed\"Y\n" +
+	"\bMailItem\x12\x17\n" +
+	"\amail_id\x18\x01 \x01(\tR\x06mailId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x17\n" +
+	"\aitem_id\x18\x03 \x01(\tR\x06itemId\"6\n" +
+	"\x17GetInventoryCapacityReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\"P\n" +
+	"\x1bUpgradeInventoryCapacityReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x14\n" +
+	"\x05slots\x18\x02 \x01(\x03R\x05slots\"F\n" +
+	"\x14InventoryCapacityRes\x12\x1a\n" +
+	"\bcapacity\x18\x01 \x01(\x03R\bcapacity\x12\x12\n" +
+	"\x04used\x18\x02 \x01(\x03R\x04used\"\x97\x01\n" +
+	"\x0eBatchRevokeReq\x12 \n" +
+	"\x05items\x18\x01 \x03(\v2\n" +
+	".GrantItemR\x05items\x12#\n" +
+	"\rinventory_ids\x18\x02 \x03(\tR\finventoryIds\x12\x19\n" +
+	"\bmail_ids\x18\x03 \x03(\tR\amailIds\x12#\n" +
+	"\rinclude_bound\x18\x04 \x01(\bR\fincludeBound\"Y\n" +
+	"\x0eBatchRevokeRes\x12$\n" +
+	"\x05items\x18\x01 \x03(\v2\x0e.InventoryItemR\x05items\x12!\n" +
+	"\x06mailed\x18\x02 \x03(\v2\t.MailItemR\x06mailed\",\n" +
+	"\rGetLoadoutReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\"\xb3\x01\n" +
+	"\fEquippedItem\x12\x12\n" +
+	"\x04slot\x18\x01 \x01(\tR\x04slot\x12!\n" +
+	"\finventory_id\x18\x02 \x01(\tR\vinventoryId\x12\x17\n" +
+	"\aitem_id\x18\x03 \x01(\tR\x06itemId\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x16\n" +
+	"\x06damage\x18\x05 \x01(\x05R\x06damage\x12%\n" +
+	"\x0elevel_required\x18\x06 \x01(\x05R\rlevelRequired\"f\n" +
+	"\n" +
+	"LoadoutRes\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12#\n" +
+	"\x05items\x18\x02 \x03(\v2\r.EquippedItemR\x05items\x12\x16\n" +
+	"\x06damage\x18\x03 \x01(\x05R\x06damage\"\xd4\x01\n" +
+	"\x1aUpdateInventoryInstanceReq\x12!\n" +
+	"\finventory_id\x18\x01 \x01(\tR\vinventoryId\x12#\n" +
+	"\n" +
+	"durability\x18\x02 \x01(\x05H\x00R\n" +
+	"durability\x88\x01\x01\x12(\n" +
+	"\renchant_level\x18\x03 \x01(\x05H\x01R\fenchantLevel\x88\x01\x01\x12\x19\n" +
+	"\x05bound\x18\x04 \x01(\bH\x02R\x05bound\x88\x01\x01B\r\n" +
+	"\v_durabilityB\x10\n" +
+	"\x0e_enchant_levelB\b\n" +
+	"\x06_bound2\x8f\x04\n" +
+	"\x14InventoryGrpcService\x12#\n" +
+	"\aHasItem\x12\v.HasItemReq\x1a\v.HasItemRes\x12)\n" +
+	"\tCountItem\x12\r.CountItemReq\x1a\r.CountItemRes\x12;\n" +
+	"\x0fListPlayerItems\x12\x13.ListPlayerItemsReq\x1a\x13.ListPlayerItemsRes\x12,\n" +
+	"\n" +
+	"BatchGrant\x12\x0e.BatchGrantReq\x1a\x0e.BatchGrantRes\x12/\n" +
+	"\vBatchRevoke\x12\x0f.BatchRevokeReq\x1a\x0f.BatchRevokeRes\x12G\n" +
+	"\x14GetInventoryCapacity\x12\x18.GetInventoryCapacityReq\x1a\x15.InventoryCapacityRes\x12O\n" +
+	"\x18UpgradeInventoryCapacity\x12\x1c.UpgradeInventoryCapacityReq\x1a\x15.InventoryCapacityRes\x12)\n" +
+	"\n" +
+	"GetLoadout\x12\x0e.GetLoadoutReq\x1a\v.LoadoutRes\x12F\n" +
+	"\x17UpdateInventoryInstance\x12\x1b.UpdateInventoryInstanceReq\x1a\x0e.InventoryItemB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+
+var (
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescOnce sync.Once
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData []byte
+)
+
+func file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP() []byte {
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescOnce.Do(func() {
+		file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc), len(file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc)))
+	})
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData
+}
+
+var file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes = []any{
+	(*HasItemReq)(nil),                  // 0: HasItemReq
+	(*HasItemRes)(nil),                  // 1: HasItemRes
+	(*CountItemReq)(nil),                // 2: CountItemReq
+	(*CountItemRes)(nil),                // 3: CountItemRes
+	(*ListPlayerItemsReq)(nil),          // 4: ListPlayerItemsReq
+	(*ListPlayerItemsRes)(nil),          // 5: ListPlayerItemsRes
+	(*InventoryItem)(nil),               // 6: InventoryItem
+	(*ItemInstance)(nil),                // 7: ItemInstance
+	(*GrantItem)(nil),                   // 8: GrantItem
+	(*BatchGrantReq)(nil),               // 9: BatchGrantReq
+	(*BatchGrantRes)(nil),               // 10: BatchGrantRes
+	(*MailItem)(nil),                    // 11: MailItem
+	(*GetInventoryCapacityReq)(nil),     // 12: GetInventoryCapacityReq
+	(*UpgradeInventoryCapacityReq)(nil), // 13: UpgradeInventoryCapacityReq
+	(*InventoryCapacityRes)(nil),        // 14: InventoryCapacityRes
+	(*BatchRevokeReq)(nil),              // 15: BatchRevokeReq
+	(*BatchRevokeRes)(nil),              // 16: BatchRevokeRes
+	(*GetLoadoutReq)(nil),               // 17: GetLoadoutReq
+	(*EquippedItem)(nil),                // 18: EquippedItem
+	(*LoadoutRes)(nil),                  // 19: LoadoutRes
+	(*UpdateInventoryInstanceReq)(nil),  // 20: UpdateInventoryInstanceReq
+}
+var file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs = []int32{
+	6,  // 0: ListPlayerItemsRes.items:type_name -> InventoryItem
+	7,  // 1: InventoryItem.instance:type_name -> ItemInstance
+	7,  // 2: GrantItem.instance:type_name -> ItemInstance
+	8,  // 3: BatchGrantReq.items:type_name -> GrantItem
+	6,  // 4: BatchGrantRes.items:type_name -> InventoryItem
+	11, // 5: BatchGrantRes.mailed:type_name -> MailItem
+	8,  // 6: BatchRevokeReq.items:type_name -> GrantItem
+	6,  // 7: BatchRevokeRes.items:type_name -> InventoryItem
+	11, // 8: BatchRevokeRes.mailed:type_name -> MailItem
+	18, // 9: LoadoutRes.items:type_name -> EquippedItem
+	0,  // 10: InventoryGrpcService.HasItem:input_type -> HasItemReq
+	2,  // 11: InventoryGrpcService.CountItem:input_type -> CountItemReq
+	4,  // 12: InventoryGrpcService.ListPlayerItems:input_type -> ListPlayerItemsReq
+	9,  // 13: InventoryGrpcService.BatchGrant:input_type -> BatchGrantReq
+	15, // 14: InventoryGrpcService.BatchRevoke:input_type -> BatchRevokeReq
+	12, // 15: InventoryGrpcService.GetInventoryCapacity:input_type -> GetInventoryCapacityReq
+	13, // 16: InventoryGrpcService.UpgradeInventoryCapacity:input_type -> UpgradeInventoryCapacityReq
+	17, // 17: InventoryGrpcService.GetLoadout:input_type -> GetLoadoutReq
+	20, // 18: InventoryGrpcService.UpdateInventoryInstance:input_type -> UpdateInventoryInstanceReq
+	1,  // 19: InventoryGrpcService.HasItem:output_type -> HasItemRes
+	3,  // 20: InventoryGrpcService.CountItem:output_type -> CountItemRes
+	5,  // 21: InventoryGrpcService.ListPlayerItems:output_type -> ListPlayerItemsRes
+	10, // 22: InventoryGrpcService.BatchGrant:output_type -> BatchGrantRes
+	16, // 23: InventoryGrpcService.BatchRevoke:output_type -> BatchRevokeRes
+	14, // 24: InventoryGrpcService.GetInventoryCapacity:output_type -> InventoryCapacityRes
+	14, // 25: InventoryGrpcService.UpgradeInventoryCapacity:output_type -> InventoryCapacityRes
+	19, // 26: InventoryGrpcService.GetLoadout:output_type -> LoadoutRes
+	6,  // 27: InventoryGrpcService.UpdateInventoryInstance:output_type -> InventoryItem
+	19, // [19:28] is the sub-list for method output_type
+	10, // [10:19] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_modules_inventory_inventoryPb_inventoryPb_proto_init() }
+func file_modules_inventory_inventoryPb_inventoryPb_proto_init() {
+	if File_modules_inventory_inventoryPb_inventoryPb_proto != nil {
+		return
+	}
+	file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[20].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc), len(file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes,
+		DependencyIndexes: file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs,
+		MessageInfos:      file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes,
+	}.Build()
+	File_modules_inventory_inventoryPb_inventoryPb_proto = out.File
+	file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes = nil
+	file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs = nil
+}
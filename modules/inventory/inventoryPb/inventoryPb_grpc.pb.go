@@ -0,0 +1,431 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: modules/inventory/inventoryPb/inventoryPb.proto
+
+package mmorpg_shop
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InventoryGrpcService_HasItem_FullMethodName                  = "/InventoryGrpcService/HasItem"
+	InventoryGrpcService_CountItem_FullMethodName                = "/InventoryGrpcService/CountItem"
+	InventoryGrpcService_ListPlayerItems_FullMethodName          = "/InventoryGrpcService/ListPlayerItems"
+	InventoryGrpcService_BatchGrant_FullMethodName               = "/InventoryGrpcService/BatchGrant"
+	InventoryGrpcService_BatchRevoke_FullMethodName              = "/InventoryGrpcService/BatchRevoke"
+	InventoryGrpcService_GetInventoryCapacity_FullMethodName     = "/InventoryGrpcService/GetInventoryCapacity"
+	InventoryGrpcService_UpgradeInventoryCapacity_FullMethodName = "/InventoryGrpcService/UpgradeInventoryCapacity"
+	InventoryGrpcService_GetLoadout_FullMethodName               = "/InventoryGrpcService/GetLoadout"
+	InventoryGrpcService_UpdateInventoryInstance_FullMethodName  = "/InventoryGrpcService/UpdateInventoryInstance"
+)
+
+// InventoryGrpcServiceClient is the client API for InventoryGrpcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Methods
+type InventoryGrpcServiceClient interface {
+	HasItem(ctx context.Context, in *HasItemReq, opts ...grpc.CallOption) (*HasItemRes, error)
+	CountItem(ctx context.Context, in *CountItemReq, opts ...grpc.CallOption) (*CountItemRes, error)
+	ListPlayerItems(ctx context.Context, in *ListPlayerItemsReq, opts ...grpc.CallOption) (*ListPlayerItemsRes, error)
+	BatchGrant(ctx context.Context, in *BatchGrantReq, opts ...grpc.CallOption) (*BatchGrantRes, error)
+	BatchRevoke(ctx context.Context, in *BatchRevokeReq, opts ...grpc.CallOption) (*BatchRevokeRes, error)
+	GetInventoryCapacity(ctx context.Context, in *GetInventoryCapacityReq, opts ...grpc.CallOption) (*InventoryCapacityRes, error)
+	UpgradeInventoryCapacity(ctx context.Context, in *UpgradeInventoryCapacityReq, opts ...grpc.CallOption) (*InventoryCapacityRes, error)
+	GetLoadout(ctx context.Context, in *GetLoadoutReq, opts ...grpc.CallOption) (*LoadoutRes, error)
+	UpdateInventoryInstance(ctx context.Context, in *UpdateInventoryInstanceReq, opts ...grpc.CallOption) (*InventoryItem, error)
+}
+
+type inventoryGrpcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryGrpcServiceClient(cc grpc.ClientConnInterface) InventoryGrpcServiceClient {
+	return &inventoryGrpcServiceClient{cc}
+}
+
+func (c *inventoryGrpcServiceClient) HasItem(ctx context.Context, in *HasItemReq, opts ...grpc.CallOption) (*HasItemRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HasItemRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_HasItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) CountItem(ctx context.Context, in *CountItemReq, opts ...grpc.CallOption) (*CountItemRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountItemRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_CountItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) ListPlayerItems(ctx context.Context, in *ListPlayerItemsReq, opts ...grpc.CallOption) (*ListPlayerItemsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPlayerItemsRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_ListPlayerItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) BatchGrant(ctx context.Context, in *BatchGrantReq, opts ...grpc.CallOption) (*BatchGrantRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGrantRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_BatchGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) BatchRevoke(ctx context.Context, in *BatchRevokeReq, opts ...grpc.CallOption) (*BatchRevokeRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchRevokeRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_BatchRevoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) GetInventoryCapacity(ctx context.Context, in *GetInventoryCapacityReq, opts ...grpc.CallOption) (*InventoryCapacityRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InventoryCapacityRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_GetInventoryCapacity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) UpgradeInventoryCapacity(ctx context.Context, in *UpgradeInventoryCapacityReq, opts ...grpc.CallOption) (*InventoryCapacityRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InventoryCapacityRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_UpgradeInventoryCapacity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) GetLoadout(ctx context.Context, in *GetLoadoutReq, opts ...grpc.CallOption) (*LoadoutRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadoutRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_GetLoadout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryGrpcServiceClient) UpdateInventoryInstance(ctx context.Context, in *UpdateInventoryInstanceReq, opts ...grpc.CallOption) (*InventoryItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InventoryItem)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_UpdateInventoryInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryGrpcServiceServer is the server API for InventoryGrpcService service.
+// All implementations must embed UnimplementedInventoryGrpcServiceServer
+// for forward compatibility.
+//
+// Methods
+type InventoryGrpcServiceServer interface {
+	HasItem(context.Context, *HasItemReq) (*HasItemRes, error)
+	CountItem(context.Context, *CountItemReq) (*CountItemRes, error)
+	ListPlayerItems(context.Context, *ListPlayerItemsReq) (*ListPlayerItemsRes, error)
+	BatchGrant(context.Context, *BatchGrantReq) (*BatchGrantRes, error)
+	BatchRevoke(context.Context, *BatchRevokeReq) (*BatchRevokeRes, error)
+	GetInventoryCapacity(context.Context, *GetInventoryCapacityReq) (*InventoryCapacityRes, error)
+	UpgradeInventoryCapacity(context.Context, *UpgradeInventoryCapacityReq) (*InventoryCapacityRes, error)
+	GetLoadout(context.Context, *GetLoadoutReq) (*LoadoutRes, error)
+	UpdateInventoryInstance(context.Context, *UpdateInventoryInstanceReq) (*InventoryItem, error)
+	mustEmbedUnimplementedInventoryGrpcServiceServer()
+}
+
+// UnimplementedInventoryGrpcServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInventoryGrpcServiceServer struct{}
+
+func (UnimplementedInventoryGrpcServiceServer) HasItem(context.Context, *HasItemReq) (*HasItemRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HasItem not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) CountItem(context.Context, *CountItemReq) (*CountItemRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountItem not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) ListPlayerItems(context.Context, *ListPlayerItemsReq) (*ListPlayerItemsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlayerItems not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) BatchGrant(context.Context, *BatchGrantReq) (*BatchGrantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGrant not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) BatchRevoke(context.Context, *BatchRevokeReq) (*BatchRevokeRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchRevoke not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) GetInventoryCapacity(context.Context, *GetInventoryCapacityReq) (*InventoryCapacityRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventoryCapacity not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) UpgradeInventoryCapacity(context.Context, *UpgradeInventoryCapacityReq) (*InventoryCapacityRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeInventoryCapacity not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) GetLoadout(context.Context, *GetLoadoutReq) (*LoadoutRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoadout not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) UpdateInventoryInstance(context.Context, *UpdateInventoryInstanceReq) (*InventoryItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateInventoryInstance not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) mustEmbedUnimplementedInventoryGrpcServiceServer() {}
+func (UnimplementedInventoryGrpcServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeInventoryGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryGrpcServiceServer will
+// result in compilation errors.
+type UnsafeInventoryGrpcServiceServer interface {
+	mustEmbedUnimplementedInventoryGrpcServiceServer()
+}
+
+func RegisterInventoryGrpcServiceServer(s grpc.ServiceRegistrar, srv InventoryGrpcServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInventoryGrpcServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InventoryGrpcService_ServiceDesc, srv)
+}
+
+func _InventoryGrpcService_HasItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasItemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).HasItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_HasItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).HasItem(ctx, req.(*HasItemReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_CountItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountItemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).CountItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_CountItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).CountItem(ctx, req.(*CountItemReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_ListPlayerItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlayerItemsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).ListPlayerItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_ListPlayerItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).ListPlayerItems(ctx, req.(*ListPlayerItemsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_BatchGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGrantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).BatchGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_BatchGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).BatchGrant(ctx, req.(*BatchGrantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_BatchRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRevokeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).BatchRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_BatchRevoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).BatchRevoke(ctx, req.(*BatchRevokeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_GetInventoryCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInventoryCapacityReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).GetInventoryCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_GetInventoryCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).GetInventoryCapacity(ctx, req.(*GetInventoryCapacityReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_UpgradeInventoryCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeInventoryCapacityReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).UpgradeInventoryCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_UpgradeInventoryCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).UpgradeInventoryCapacity(ctx, req.(*UpgradeInventoryCapacityReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_GetLoadout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoadoutReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).GetLoadout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_GetLoadout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).GetLoadout(ctx, req.(*GetLoadoutReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryGrpcService_UpdateInventoryInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInventoryInstanceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).UpdateInventoryInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_UpdateInventoryInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).UpdateInventoryInstance(ctx, req.(*UpdateInventoryInstanceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryGrpcService_ServiceDesc is the grpc.ServiceDesc for InventoryGrpcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryGrpcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "InventoryGrpcService",
+	HandlerType: (*InventoryGrpcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "HasItem",
+			Handler:    _InventoryGrpcService_HasItem_Handler,
+		},
+		{
+			MethodName: "CountItem",
+			Handler:    _InventoryGrpcService_CountItem_Handler,
+		},
+		{
+			MethodName: "ListPlayerItems",
+			Handler:    _InventoryGrpcService_ListPlayerItems_Handler,
+		},
+		{
+			MethodName: "BatchGrant",
+			Handler:    _InventoryGrpcService_BatchGrant_Handler,
+		},
+		{
+			MethodName: "BatchRevoke",
+			Handler:    _InventoryGrpcService_BatchRevoke_Handler,
+		},
+		{
+			MethodName: "GetInventoryCapacity",
+			Handler:    _InventoryGrpcService_GetInventoryCapacity_Handler,
+		},
+		{
+			MethodName: "UpgradeInventoryCapacity",
+			Handler:    _InventoryGrpcService_UpgradeInventoryCapacity_Handler,
+		},
+		{
+			MethodName: "GetLoadout",
+			Handler:    _InventoryGrpcService_GetLoadout_Handler,
+		},
+		{
+			MethodName: "UpdateInventoryInstance",
+			Handler:    _InventoryGrpcService_UpdateInventoryInstance_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modules/inventory/inventoryPb/inventoryPb.proto",
+}
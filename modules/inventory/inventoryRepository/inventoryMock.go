@@ -2,11 +2,14 @@ package inventoryRepository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
 	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -45,8 +48,8 @@ func (m *InventoryRepositoryMock) UpsertOffset(pctx context.Context, offset int6
 	return args.Error(0)
 }
 
-func (m *InventoryRepositoryMock) AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
-	args := m.Called(pctx, cfg, req)
+func (m *InventoryRepositoryMock) AddPlayerItemRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error {
+	args := m.Called(pctx, cfg, topic, req)
 	return args.Error(0)
 }
 
@@ -70,6 +73,11 @@ func (m *InventoryRepositoryMock) InsertOnePlayerItem(pctx context.Context, req
 	return args.Get(0).(bson.ObjectID), args.Error(1)
 }
 
+func (m *InventoryRepositoryMock) InsertManyPlayerItems(pctx context.Context, req []*inventory.Inventory) ([]bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).([]bson.ObjectID), args.Error(1)
+}
+
 func (m *InventoryRepositoryMock) FindOnePlayerItem(pctx context.Context, playerId, itemId string) bool {
 	args := m.Called(pctx, playerId, itemId)
 	return args.Bool(0)
@@ -80,7 +88,102 @@ func (m *InventoryRepositoryMock) DeleteOneInventory(pctx context.Context, inven
 	return args.Error(0)
 }
 
-func (m *InventoryRepositoryMock) DeleteOnePlayerItem(pctx context.Context, playerId, itemId string) error {
+func (m *InventoryRepositoryMock) DeleteOnePlayerItem(pctx context.Context, playerId, itemId, inventoryId string) (*inventory.Inventory, error) {
+	args := m.Called(pctx, playerId, itemId, inventoryId)
+	return args.Get(0).(*inventory.Inventory), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) DeleteManyInventories(pctx context.Context, inventoryIds []string) error {
+	args := m.Called(pctx, inventoryIds)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) CountPlayerItem(pctx context.Context, playerId, itemId string) (int64, error) {
+	args := m.Called(pctx, playerId, itemId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) RemoveOneInventory(pctx context.Context, inventoryId string) error {
+	args := m.Called(pctx, inventoryId)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) FindExtraSlots(pctx context.Context, playerId string) (int64, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) IncExtraSlots(pctx context.Context, playerId string, slots int64) error {
+	args := m.Called(pctx, playerId, slots)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) InsertManyMails(pctx context.Context, req []*inventory.Mail) ([]bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).([]bson.ObjectID), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) FindPlayerMails(pctx context.Context, playerId string) ([]*inventory.Mail, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).([]*inventory.Mail), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) FindOneMail(pctx context.Context, mailId string) (*inventory.Mail, error) {
+	args := m.Called(pctx, mailId)
+	return args.Get(0).(*inventory.Mail), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) DeleteOneMail(pctx context.Context, mailId string) error {
+	args := m.Called(pctx, mailId)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.PlayerProfile), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) FindOneInventory(pctx context.Context, inventoryId string) (*inventory.Inventory, error) {
+	args := m.Called(pctx, inventoryId)
+	return args.Get(0).(*inventory.Inventory), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) EquipInventory(pctx context.Context, playerId, inventoryId, slot string) error {
+	args := m.Called(pctx, playerId, inventoryId, slot)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) UnequipSlot(pctx context.Context, playerId, slot string) error {
+	args := m.Called(pctx, playerId, slot)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) UpdateOneInventory(pctx context.Context, inventoryId string, set bson.M) error {
+	args := m.Called(pctx, inventoryId, set)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) FindItemCooldown(pctx context.Context, playerId, itemId string) (time.Time, error) {
 	args := m.Called(pctx, playerId, itemId)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) ReserveItemCooldown(pctx context.Context, playerId, itemId string, now, readyAt time.Time) (time.Time, bool, error) {
+	args := m.Called(pctx, playerId, itemId, now, readyAt)
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
+
+func (m *InventoryRepositoryMock) ReleaseItemCooldown(pctx context.Context, playerId, itemId string, readyAt, previous time.Time) error {
+	args := m.Called(pctx, playerId, itemId, readyAt, previous)
+	return args.Error(0)
+}
+
+func (m *InventoryRepositoryMock) MarkReference(pctx context.Context, referenceId string, set bson.M) (*inventory.InventoryReference, error) {
+	args := m.Called(pctx, referenceId, set)
+	return args.Get(0).(*inventory.InventoryReference), args.Error(1)
+}
+
+func (m *InventoryRepositoryMock) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	args := m.Called(pctx, cfg, req)
 	return args.Error(0)
 }
@@ -12,6 +12,7 @@ import (
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/models"
 	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
 	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
 	"github.com/Supakornn/mmorpg-shop/pkg/queue"
@@ -26,14 +27,34 @@ type (
 		FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
 		FindPlayerItems(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*inventory.Inventory, error)
 		CountPlayerItems(pctx context.Context, playerId string) (int64, error)
+		CountPlayerItem(pctx context.Context, playerId, itemId string) (int64, error)
 		GetOffset(pctx context.Context) (int64, error)
 		UpsertOffset(pctx context.Context, offset int64) error
-		AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
+		AddPlayerItemRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error
 		RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
 		InsertOnePlayerItem(pctx context.Context, req *inventory.Inventory) (bson.ObjectID, error)
+		InsertManyPlayerItems(pctx context.Context, req []*inventory.Inventory) ([]bson.ObjectID, error)
 		FindOnePlayerItem(pctx context.Context, playerId, itemId string) bool
 		DeleteOneInventory(pctx context.Context, inventoryId string) error
-		DeleteOnePlayerItem(pctx context.Context, playerId, itemId string) error
+		RemoveOneInventory(pctx context.Context, inventoryId string) error
+		DeleteManyInventories(pctx context.Context, inventoryIds []string) error
+		DeleteOnePlayerItem(pctx context.Context, playerId, itemId, inventoryId string) (*inventory.Inventory, error)
+		FindExtraSlots(pctx context.Context, playerId string) (int64, error)
+		IncExtraSlots(pctx context.Context, playerId string, slots int64) error
+		InsertManyMails(pctx context.Context, req []*inventory.Mail) ([]bson.ObjectID, error)
+		FindPlayerMails(pctx context.Context, playerId string) ([]*inventory.Mail, error)
+		FindOneMail(pctx context.Context, mailId string) (*inventory.Mail, error)
+		DeleteOneMail(pctx context.Context, mailId string) error
+		FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error)
+		FindOneInventory(pctx context.Context, inventoryId string) (*inventory.Inventory, error)
+		EquipInventory(pctx context.Context, playerId, inventoryId, slot string) error
+		UnequipSlot(pctx context.Context, playerId, slot string) error
+		UpdateOneInventory(pctx context.Context, inventoryId string, set bson.M) error
+		FindItemCooldown(pctx context.Context, playerId, itemId string) (time.Time, error)
+		ReserveItemCooldown(pctx context.Context, playerId, itemId string, now, readyAt time.Time) (time.Time, bool, error)
+		ReleaseItemCooldown(pctx context.Context, playerId, itemId string, readyAt, previous time.Time) error
+		PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error
+		MarkReference(pctx context.Context, referenceId string, set bson.M) (*inventory.InventoryReference, error)
 	}
 
 	inventoryRepository struct {
@@ -130,6 +151,31 @@ func (r *inventoryRepository) CountPlayerItems(pctx context.Context, playerId st
 	return count, nil
 }
 
+// unexpired matches copies that last forever or whose time is not up yet,
+// so copies the sweeper has not removed yet cannot be sold or spent.
+func unexpired() bson.A {
+	return bson.A{
+		bson.M{"expires_at": nil},
+		bson.M{"expires_at": bson.M{"$gt": utils.LocalTime()}},
+	}
+}
+
+func (r *inventoryRepository) CountPlayerItem(pctx context.Context, playerId, itemId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	count, err := col.CountDocuments(ctx, bson.M{"player_id": playerId, "item_id": itemId, "equipped_slot": bson.M{"$exists": false}, "$or": unexpired()})
+	if err != nil {
+		log.Printf("error: count player item: %v", err.Error())
+		return -1, errors.New("error: count player item failed")
+	}
+
+	return count, nil
+}
+
 func (r *inventoryRepository) FindOnePlayerItem(pctx context.Context, playerId, itemId string) bool {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -138,7 +184,7 @@ func (r *inventoryRepository) FindOnePlayerItem(pctx context.Context, playerId,
 	col := db.Collection("inventories")
 
 	result := new(inventory.Inventory)
-	if err := col.FindOne(ctx, bson.M{"player_id": playerId, "item_id": itemId}).Decode(result); err != nil {
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId, "item_id": itemId, "equipped_slot": bson.M{"$exists": false}, "$or": unexpired()}).Decode(result); err != nil {
 		log.Printf("error: find one player item: %v", err.Error())
 		return false
 	}
@@ -180,14 +226,18 @@ func (r *inventoryRepository) UpsertOffset(pctx context.Context, offset int64) e
 	return nil
 }
 
-func (r *inventoryRepository) AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
+func (r *inventoryRepository) AddPlayerItemRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error {
+	if topic == "" {
+		topic = "payment"
+	}
+
 	reqInBytes, err := json.Marshal(req)
 	if err != nil {
 		log.Printf("Error: marshal request failed: %v", err.Error())
 		return errors.New("error: marshal request failed")
 	}
 
-	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "payment", "buy", reqInBytes); err != nil {
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, topic, "buy", reqInBytes); err != nil {
 		log.Printf("Error: push message with key to queue failed: %v", err.Error())
 		return errors.New("error: push message with key to queue failed")
 	}
@@ -210,22 +260,27 @@ func (r *inventoryRepository) RemovePlayerItemRes(pctx context.Context, cfg *con
 	return nil
 }
 
-func (r *inventoryRepository) DeleteOnePlayerItem(pctx context.Context, playerId, itemId string) error {
+// DeleteOnePlayerItem removes one unequipped, unexpired copy of itemId, or
+// exactly inventoryId when it is given, and returns the removed entry.
+func (r *inventoryRepository) DeleteOnePlayerItem(pctx context.Context, playerId, itemId, inventoryId string) (*inventory.Inventory, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
 
 	db := r.inventoryDbConn(ctx)
 	col := db.Collection("inventories")
 
-	result, err := col.DeleteOne(ctx, bson.M{"player_id": playerId, "item_id": itemId})
-	if err != nil {
-		log.Printf("error: delete one player item: %v", err.Error())
-		return errors.New("error: delete one player item failed")
+	filter := bson.M{"player_id": playerId, "item_id": itemId, "equipped_slot": bson.M{"$exists": false}, "$or": unexpired()}
+	if inventoryId != "" {
+		filter["_id"] = utils.ConvertToObjectId(inventoryId)
 	}
 
-	log.Printf("delete one player item: %v", result.DeletedCount)
+	result := new(inventory.Inventory)
+	if err := col.FindOneAndDelete(ctx, filter).Decode(result); err != nil {
+		log.Printf("error: delete one player item: %v", err.Error())
+		return nil, errors.New("error: delete one player item failed")
+	}
 
-	return nil
+	return result, nil
 }
 
 func (r *inventoryRepository) InsertOnePlayerItem(pctx context.Context, req *inventory.Inventory) (bson.ObjectID, error) {
@@ -244,6 +299,47 @@ func (r *inventoryRepository) InsertOnePlayerItem(pctx context.Context, req *inv
 	return result.InsertedID.(bson.ObjectID), nil
 }
 
+func (r *inventoryRepository) InsertManyPlayerItems(pctx context.Context, req []*inventory.Inventory) ([]bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	// Ids are assigned up front so a failed insert can be cleaned up, and
+	// kept when given so revoked items can be restored as they were
+	docs := make([]any, 0)
+	for _, v := range req {
+		if v.Id.IsZero() {
+			v.Id = bson.NewObjectID()
+		}
+		docs = append(docs, v)
+	}
+
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		log.Printf("error: insert many player items: %v", err.Error())
+
+		// Remove any documents that made it in so the bundle is all or nothing
+		inventoryIds := make([]bson.ObjectID, 0)
+		for _, v := range req {
+			inventoryIds = append(inventoryIds, v.Id)
+		}
+
+		if _, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": inventoryIds}}); err != nil {
+			log.Printf("error: cleanup insert many player items: %v", err.Error())
+		}
+
+		return nil, errors.New("error: insert many player items failed")
+	}
+
+	results := make([]bson.ObjectID, 0)
+	for _, v := range req {
+		results = append(results, v.Id)
+	}
+
+	return results, nil
+}
+
 func (r *inventoryRepository) DeleteOneInventory(pctx context.Context, inventoryId string) error {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -261,3 +357,440 @@ func (r *inventoryRepository) DeleteOneInventory(pctx context.Context, inventory
 
 	return nil
 }
+
+// RemoveOneInventory is DeleteOneInventory that fails when the document is
+// already gone, so concurrent revokes cannot take the same item twice.
+func (r *inventoryRepository) RemoveOneInventory(pctx context.Context, inventoryId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	result, err := col.DeleteOne(ctx, bson.M{"_id": utils.ConvertToObjectId(inventoryId)})
+	if err != nil {
+		log.Printf("error: remove one inventory: %v", err.Error())
+		return errors.New("error: remove one inventory failed")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("error: inventory not found")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) DeleteManyInventories(pctx context.Context, inventoryIds []string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	objectIds := make([]bson.ObjectID, 0)
+	for _, inventoryId := range inventoryIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(inventoryId))
+	}
+
+	result, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		log.Printf("error: delete many inventories: %v", err.Error())
+		return errors.New("error: delete many inventories failed")
+	}
+
+	log.Printf("delete many inventories: %v", result.DeletedCount)
+
+	return nil
+}
+
+func (r *inventoryRepository) FindExtraSlots(pctx context.Context, playerId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventory_capacities")
+
+	result := new(inventory.InventoryCapacity)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		log.Printf("error: find extra slots: %v", err.Error())
+		return 0, errors.New("error: find inventory capacity failed")
+	}
+
+	return result.ExtraSlots, nil
+}
+
+func (r *inventoryRepository) IncExtraSlots(pctx context.Context, playerId string, slots int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventory_capacities")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": playerId},
+		bson.M{
+			"$inc": bson.M{"extra_slots": slots},
+			"$set": bson.M{"updated_at": utils.LocalTime()},
+		},
+		options.UpdateOne().SetUpsert(true),
+	); err != nil {
+		log.Printf("error: inc extra slots: %v", err.Error())
+		return errors.New("error: upgrade inventory capacity failed")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) InsertManyMails(pctx context.Context, req []*inventory.Mail) ([]bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("mailbox")
+
+	docs := make([]any, 0)
+	for _, v := range req {
+		if v.Id.IsZero() {
+			v.Id = bson.NewObjectID()
+		}
+		docs = append(docs, v)
+	}
+
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		log.Printf("error: insert many mails: %v", err.Error())
+
+		mailIds := make([]bson.ObjectID, 0)
+		for _, v := range req {
+			mailIds = append(mailIds, v.Id)
+		}
+
+		if _, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": mailIds}}); err != nil {
+			log.Printf("error: cleanup insert many mails: %v", err.Error())
+		}
+
+		return nil, errors.New("error: insert many mails failed")
+	}
+
+	results := make([]bson.ObjectID, 0)
+	for _, v := range req {
+		results = append(results, v.Id)
+	}
+
+	return results, nil
+}
+
+func (r *inventoryRepository) FindPlayerMails(pctx context.Context, playerId string) ([]*inventory.Mail, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("mailbox")
+
+	cursors, err := col.Find(ctx, bson.M{"player_id": playerId}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		log.Printf("error: find player mails: %v", err.Error())
+		return nil, errors.New("error: find player mails failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*inventory.Mail, 0)
+	for cursors.Next(ctx) {
+		result := new(inventory.Mail)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode player mails: %v", err.Error())
+			return nil, errors.New("error: decode player mails failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *inventoryRepository) FindOneMail(pctx context.Context, mailId string) (*inventory.Mail, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("mailbox")
+
+	result := new(inventory.Mail)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(mailId)}).Decode(result); err != nil {
+		log.Printf("error: find one mail: %v", err.Error())
+		return nil, errors.New("error: mail not found")
+	}
+
+	return result, nil
+}
+
+// DeleteOneMail fails when the mail is already gone so it can only be
+// claimed once.
+func (r *inventoryRepository) DeleteOneMail(pctx context.Context, mailId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("mailbox")
+
+	result, err := col.DeleteOne(ctx, bson.M{"_id": utils.ConvertToObjectId(mailId)})
+	if err != nil {
+		log.Printf("error: delete one mail: %v", err.Error())
+		return errors.New("error: delete one mail failed")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("error: mail already claimed")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().FindOnePlayerProfileToRefresh(ctx, req)
+	if err != nil {
+		log.Printf("error: find one player profile failed: %v", err.Error())
+		return nil, errors.New("error: player not found")
+	}
+
+	return result, nil
+}
+
+func (r *inventoryRepository) FindOneInventory(pctx context.Context, inventoryId string) (*inventory.Inventory, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	result := new(inventory.Inventory)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(inventoryId)}).Decode(result); err != nil {
+		log.Printf("error: find one inventory: %v", err.Error())
+		return nil, errors.New("error: inventory item not found")
+	}
+
+	return result, nil
+}
+
+// EquipInventory claims slot for inventoryId in a single update, so a failed
+// write never leaves the slot empty. Claims are ordered by equipped_at and
+// the latest one holds the slot; older copies are moved back to the bag
+// afterwards, and one left behind by a failure there is ignored by the
+// loadout and cleared on the next equip.
+func (r *inventoryRepository) EquipInventory(pctx context.Context, playerId, inventoryId, slot string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	now := utils.LocalTime()
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(inventoryId), "player_id": playerId},
+		bson.M{"$set": bson.M{"equipped_slot": slot, "equipped_at": now}},
+	)
+	if err != nil {
+		log.Printf("error: equip inventory: %v", err.Error())
+		return errors.New("error: equip item failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: inventory item not found")
+	}
+
+	// Only claims older than this one are released, so a concurrent later
+	// equip keeps its copy
+	if _, err := col.UpdateMany(
+		ctx,
+		bson.M{
+			"player_id":     playerId,
+			"equipped_slot": slot,
+			"_id":           bson.M{"$ne": utils.ConvertToObjectId(inventoryId)},
+			"$or": bson.A{
+				bson.M{"equipped_at": bson.M{"$lt": now}},
+				bson.M{"equipped_at": bson.M{"$exists": false}},
+			},
+		},
+		bson.M{"$unset": bson.M{"equipped_slot": "", "equipped_at": ""}},
+	); err != nil {
+		log.Printf("error: release older equipped items: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) UnequipSlot(pctx context.Context, playerId, slot string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	result, err := col.UpdateMany(
+		ctx,
+		bson.M{"player_id": playerId, "equipped_slot": slot},
+		bson.M{"$unset": bson.M{"equipped_slot": "", "equipped_at": ""}},
+	)
+	if err != nil {
+		log.Printf("error: unequip slot: %v", err.Error())
+		return errors.New("error: unequip item failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: nothing is equipped in this slot")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) UpdateOneInventory(pctx context.Context, inventoryId string, set bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventories")
+
+	result, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(inventoryId)}, bson.M{"$set": set})
+	if err != nil {
+		log.Printf("error: update one inventory: %v", err.Error())
+		return errors.New("error: update inventory item failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: inventory item not found")
+	}
+
+	return nil
+}
+
+// FindItemCooldown returns when playerId may next use itemId, or the zero
+// time when the item has never been used.
+func (r *inventoryRepository) FindItemCooldown(pctx context.Context, playerId, itemId string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("item_cooldowns")
+
+	result := new(inventory.ItemCooldown)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId, "item_id": itemId}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, nil
+		}
+		log.Printf("error: find item cooldown: %v", err.Error())
+		return time.Time{}, errors.New("error: find item cooldown failed")
+	}
+
+	return result.ReadyAt, nil
+}
+
+// ReserveItemCooldown starts a cooldown ending at readyAt unless one is still
+// running at now. It returns the previous ready time so the reservation can
+// be released, and false when the item is still on cooldown.
+func (r *inventoryRepository) ReserveItemCooldown(pctx context.Context, playerId, itemId string, now, readyAt time.Time) (time.Time, bool, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("item_cooldowns")
+
+	// A running cooldown fails the filter, so the upsert hits the unique
+	// (player_id, item_id) index instead of overwriting it
+	result := new(inventory.ItemCooldown)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"player_id": playerId, "item_id": itemId, "ready_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"ready_at": readyAt}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, true, nil
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return time.Time{}, false, nil
+		}
+		log.Printf("error: reserve item cooldown: %v", err.Error())
+		return time.Time{}, false, errors.New("error: reserve item cooldown failed")
+	}
+
+	return result.ReadyAt, true, nil
+}
+
+// ReleaseItemCooldown puts back the previous ready time while the cooldown is
+// still the reservation ending at readyAt.
+func (r *inventoryRepository) ReleaseItemCooldown(pctx context.Context, playerId, itemId string, readyAt, previous time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("item_cooldowns")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": playerId, "item_id": itemId, "ready_at": readyAt},
+		bson.M{"$set": bson.M{"ready_at": previous}},
+	); err != nil {
+		log.Printf("error: release item cooldown: %v", err.Error())
+		return errors.New("error: release item cooldown failed")
+	}
+
+	return nil
+}
+
+// MarkReference sets fields on the record of a saga step and returns the
+// record as it was before, or nil when there was none.
+func (r *inventoryRepository) MarkReference(pctx context.Context, referenceId string, set bson.M) (*inventory.InventoryReference, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("inventory_references")
+
+	set["updated_at"] = utils.LocalTime()
+
+	result := new(inventory.InventoryReference)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"reference_id": referenceId},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("error: mark reference: %v", err.Error())
+		return nil, errors.New("error: mark reference failed")
+	}
+
+	return result, nil
+}
+
+func (r *inventoryRepository) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "events", req.Type, reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
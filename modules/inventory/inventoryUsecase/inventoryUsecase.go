@@ -1,17 +1,23 @@
 package inventoryUsecase
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/models"
 	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -19,13 +25,31 @@ import (
 
 type (
 	InventoryUsecaseService interface {
-		FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*models.PaginateRes, error)
+		FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*inventory.PlayerItemsRes, error)
 		GetOffset(pctx context.Context) (int64, error)
 		UpsertOffset(pctx context.Context, offset int64) error
 		AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq)
 		RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq)
 		RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq)
 		RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq)
+		HasItem(pctx context.Context, cfg *config.Config, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error)
+		CountItem(pctx context.Context, cfg *config.Config, req *inventoryPb.CountItemReq) (*inventoryPb.CountItemRes, error)
+		ListPlayerItems(pctx context.Context, req *inventoryPb.ListPlayerItemsReq) (*inventoryPb.ListPlayerItemsRes, error)
+		BatchGrant(pctx context.Context, cfg *config.Config, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error)
+		BatchRevoke(pctx context.Context, cfg *config.Config, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error)
+		GetCapacity(pctx context.Context, cfg *config.Config, playerId string) (*inventory.CapacityRes, error)
+		UpgradeCapacity(pctx context.Context, cfg *config.Config, playerId string, slots int64) (*inventory.CapacityRes, error)
+		FindPlayerMails(pctx context.Context, playerId string) ([]*inventory.Mail, error)
+		ClaimMail(pctx context.Context, cfg *config.Config, playerId, mailId string) (*inventory.Inventory, error)
+		EquipItem(pctx context.Context, cfg *config.Config, playerId string, req *inventory.EquipItemReq) (*inventory.LoadoutRes, error)
+		UnequipItem(pctx context.Context, cfg *config.Config, playerId string, req *inventory.UnequipItemReq) (*inventory.LoadoutRes, error)
+		GetLoadout(pctx context.Context, cfg *config.Config, playerId string) (*inventory.LoadoutRes, error)
+		FindOneItemInstance(pctx context.Context, cfg *config.Config, playerId, inventoryId string) (*inventory.ItemInInventory, error)
+		UpdateItemInstance(pctx context.Context, inventoryId string, req *inventory.UpdateItemInstanceReq) (*inventory.Inventory, error)
+		AdminUpdateItemInstance(pctx context.Context, cfg *config.Config, inventoryId string, req *inventory.UpdateItemInstanceReq) (*inventory.Inventory, error)
+		BindItem(pctx context.Context, playerId, inventoryId string) (*inventory.Inventory, error)
+		UseItem(pctx context.Context, cfg *config.Config, playerId, inventoryId string) (*inventory.UseItemRes, error)
+		SweepExpiredItems(pctx context.Context, cfg *config.Config) error
 	}
 
 	inventoryUsecase struct {
@@ -33,11 +57,18 @@ type (
 	}
 )
 
+const defaultDurability = 100
+
 func NewInventoryUsecase(inventoryRepository inventoryRepository.InventoryRepositoryService) InventoryUsecaseService {
 	return &inventoryUsecase{inventoryRepository}
 }
 
-func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*models.PaginateRes, error) {
+func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*inventory.PlayerItemsRes, error) {
+	capacity, err := u.GetCapacity(pctx, cfg, playerId)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.D{}
 	opts := make([]options.Lister[options.FindOptions], 0)
 
@@ -56,17 +87,20 @@ func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Con
 	}
 
 	if len(inventoryData) == 0 {
-		return &models.PaginateRes{
-			Data:  make([]*inventory.ItemInInventory, 0),
-			Limit: req.Limit,
-			Total: 0,
-			First: models.FirstPaginate{
-				Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
-			},
-			Next: models.NextPaginate{
-				Start: "",
-				Href:  "",
+		return &inventory.PlayerItemsRes{
+			PaginateRes: &models.PaginateRes{
+				Data:  make([]*inventory.ItemInInventory, 0),
+				Limit: req.Limit,
+				Total: 0,
+				First: models.FirstPaginate{
+					Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
+				},
+				Next: models.NextPaginate{
+					Start: "",
+					Href:  "",
+				},
 			},
+			Capacity: capacity,
 		}, nil
 	}
 
@@ -87,6 +121,7 @@ func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Con
 	for _, v := range itemData.Items {
 		itemMaps[v.Id] = &item.ItemShowCase{
 			ItemId:   v.Id,
+			Sku:      v.Sku,
 			Title:    v.Title,
 			Price:    v.Price,
 			ImageUrl: v.ImageUrl,
@@ -97,10 +132,14 @@ func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Con
 	results := make([]*inventory.ItemInInventory, 0)
 	for _, v := range inventoryData {
 		results = append(results, &inventory.ItemInInventory{
-			InventoryId: v.Id.Hex(),
-			PlayerId:    v.PlayerId,
+			InventoryId:      v.Id.Hex(),
+			PlayerId:         v.PlayerId,
+			EquippedSlot:     v.EquippedSlot,
+			Instance:         &v.ItemInstance,
+			RemainingSeconds: remainingSeconds(&v.ItemInstance),
 			ItemShowCase: &item.ItemShowCase{
 				ItemId:   v.ItemId,
+				Sku:      itemMaps[v.ItemId].Sku,
 				Title:    itemMaps[v.ItemId].Title,
 				Price:    itemMaps[v.ItemId].Price,
 				ImageUrl: itemMaps[v.ItemId].ImageUrl,
@@ -115,31 +154,37 @@ func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Con
 	}
 
 	if len(results) == 0 {
-		return &models.PaginateRes{
-			Data:  make([]*inventory.ItemInInventory, 0),
+		return &inventory.PlayerItemsRes{
+			PaginateRes: &models.PaginateRes{
+				Data:  make([]*inventory.ItemInInventory, 0),
+				Limit: req.Limit,
+				Total: count,
+				First: models.FirstPaginate{
+					Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
+				},
+				Next: models.NextPaginate{
+					Start: "",
+					Href:  "",
+				},
+			},
+			Capacity: capacity,
+		}, nil
+	}
+
+	return &inventory.PlayerItemsRes{
+		PaginateRes: &models.PaginateRes{
+			Data:  results,
 			Limit: req.Limit,
 			Total: count,
 			First: models.FirstPaginate{
 				Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
 			},
 			Next: models.NextPaginate{
-				Start: "",
-				Href:  "",
+				Start: results[len(results)-1].InventoryId,
+				Href:  fmt.Sprintf("%s/%s?limit=%d&start=%s", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit, results[len(results)-1].InventoryId),
 			},
-		}, nil
-	}
-
-	return &models.PaginateRes{
-		Data:  results,
-		Limit: req.Limit,
-		Total: count,
-		First: models.FirstPaginate{
-			Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
-		},
-		Next: models.NextPaginate{
-			Start: results[len(results)-1].InventoryId,
-			Href:  fmt.Sprintf("%s/%s?limit=%d&start=%s", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit, results[len(results)-1].InventoryId),
 		},
+		Capacity: capacity,
 	}, nil
 }
 
@@ -151,13 +196,92 @@ func (u *inventoryUsecase) UpsertOffset(pctx context.Context, offset int64) erro
 	return u.inventoryRepository.UpsertOffset(pctx, offset)
 }
 
+// resolveItemId accepts "item:<hex>", a bare hex id or "sku:<SKU>" and
+// returns the "item:<hex>" form stored in inventories.
+func (u *inventoryUsecase) resolveItemId(pctx context.Context, cfg *config.Config, itemId string) (string, error) {
+	if !strings.HasPrefix(itemId, "sku:") {
+		return "item:" + strings.TrimPrefix(itemId, "item:"), nil
+	}
+
+	result, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: []string{itemId},
+	})
+	if err != nil {
+		return "", errors.New("error: item not found")
+	}
+	if len(result.Items) == 0 {
+		return "", errors.New("error: item not found")
+	}
+
+	return result.Items[0].Id, nil
+}
+
 func (u *inventoryUsecase) AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
+	itemId, err := u.resolveItemId(pctx, cfg, req.ItemId)
+	if err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+	req.ItemId = itemId
+
+	slots := int64(1)
+	if len(req.Components) > 0 {
+		slots = 0
+		for _, c := range req.Components {
+			slots += int64(c.Quantity)
+		}
+	}
+
+	if err := u.checkFreeSlots(pctx, cfg, req.PlayerId, slots); err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	itemIds := []string{req.ItemId}
+	if len(req.Components) > 0 {
+		itemIds = make([]string, 0)
+		for _, c := range req.Components {
+			itemIds = append(itemIds, c.ItemId)
+		}
+	}
+
+	expiries, err := u.itemExpiries(pctx, cfg, itemIds)
+	if err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	if len(req.Components) > 0 {
+		u.addPlayerBundleRes(pctx, cfg, req, expiries)
+		return
+	}
+
+	instance := newItemInstance(cmp.Or(req.Source, "shop"))
+	instance.ExpiresAt = expiries[req.ItemId]
+
 	inventoryId, err := u.inventoryRepository.InsertOnePlayerItem(pctx, &inventory.Inventory{
-		PlayerId: req.PlayerId,
-		ItemId:   req.ItemId,
+		PlayerId:     req.PlayerId,
+		ItemId:       req.ItemId,
+		ItemInstance: instance,
 	})
 	if err != nil {
-		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			InventoryId:   "",
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
@@ -169,7 +293,18 @@ func (u *inventoryUsecase) AddPlayerItemRes(pctx context.Context, cfg *config.Co
 		return
 	}
 
-	u.inventoryRepository.AddPlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+	if err := u.trackAdded(pctx, req.ReferenceId, []string{inventoryId.Hex()}); err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+		ReferenceId:   req.ReferenceId,
 		InventoryId:   inventoryId.Hex(),
 		PlayerId:      req.PlayerId,
 		ItemId:        req.ItemId,
@@ -179,9 +314,79 @@ func (u *inventoryUsecase) AddPlayerItemRes(pctx context.Context, cfg *config.Co
 	})
 }
 
+func (u *inventoryUsecase) addPlayerBundleRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq, expiries map[string]*time.Time) {
+	docs := make([]*inventory.Inventory, 0)
+	for _, c := range req.Components {
+		for i := 0; i < c.Quantity; i++ {
+			instance := newItemInstance(cmp.Or(req.Source, "shop"))
+			instance.ExpiresAt = expiries[c.ItemId]
+
+			docs = append(docs, &inventory.Inventory{
+				PlayerId:     req.PlayerId,
+				ItemId:       c.ItemId,
+				ItemInstance: instance,
+			})
+		}
+	}
+
+	inventoryIds, err := u.inventoryRepository.InsertManyPlayerItems(pctx, docs)
+	if err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
+			InventoryId:   "",
+			TransactionId: "",
+			PlayerId:      req.PlayerId,
+			ItemId:        req.ItemId,
+			Amount:        0,
+			Error:         err.Error(),
+		})
+
+		return
+	}
+
+	added := make([]string, 0)
+	for _, v := range inventoryIds {
+		added = append(added, v.Hex())
+	}
+
+	if err := u.trackAdded(pctx, req.ReferenceId, added); err != nil {
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	u.inventoryRepository.AddPlayerItemRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+		ReferenceId:   req.ReferenceId,
+		InventoryId:   "",
+		InventoryIds:  added,
+		PlayerId:      req.PlayerId,
+		ItemId:        req.ItemId,
+		TransactionId: "",
+		Amount:        0,
+		Error:         "",
+	})
+}
+
 func (u *inventoryUsecase) RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
+	itemId, err := u.resolveItemId(pctx, cfg, req.ItemId)
+	if err != nil {
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+	req.ItemId = itemId
+
 	if !u.inventoryRepository.FindOnePlayerItem(pctx, req.PlayerId, req.ItemId) {
 		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			InventoryId:   "",
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
@@ -192,8 +397,10 @@ func (u *inventoryUsecase) RemovePlayerItemRes(pctx context.Context, cfg *config
 		return
 	}
 
-	if err := u.inventoryRepository.DeleteOnePlayerItem(pctx, req.PlayerId, req.ItemId); err != nil {
+	removed, err := u.inventoryRepository.DeleteOnePlayerItem(pctx, req.PlayerId, req.ItemId, strings.TrimPrefix(req.InventoryId, "inventory:"))
+	if err != nil {
 		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			InventoryId:   "",
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
@@ -204,23 +411,1011 @@ func (u *inventoryUsecase) RemovePlayerItemRes(pctx context.Context, cfg *config
 		return
 	}
 
+	if err := u.trackRemoved(pctx, req.ReferenceId, removed); err != nil {
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId: req.ReferenceId,
+			PlayerId:    req.PlayerId,
+			ItemId:      req.ItemId,
+			Error:       err.Error(),
+		})
+		return
+	}
+
 	u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-		InventoryId:   "",
+		ReferenceId:   req.ReferenceId,
+		InventoryId:   removed.Id.Hex(),
 		TransactionId: "",
 		PlayerId:      req.PlayerId,
 		ItemId:        req.ItemId,
 		Amount:        0,
+		ValueFactor:   valueFactor(&removed.ItemInstance),
+		Instance:      &removed.ItemInstance,
 		Error:         "",
 	})
 }
 
+// valueFactor scales the sell price of a copy by its condition: worn copies
+// lose value down to a tenth of the price and each enchant level adds 10%.
+func valueFactor(v *inventory.ItemInstance) float64 {
+	factor := 1.0
+	if v.MaxDurability > 0 {
+		factor = max(float64(v.Durability)/float64(v.MaxDurability), 0.1)
+	}
+
+	return factor * (1 + 0.1*float64(v.EnchantLevel))
+}
+
+// trackAdded records the copies a saga step added under its reference. When
+// the step was already rolled back by reference the copies are taken out
+// again and an error is returned.
+func (u *inventoryUsecase) trackAdded(pctx context.Context, referenceId string, inventoryIds []string) error {
+	if referenceId == "" {
+		return nil
+	}
+
+	previous, err := u.inventoryRepository.MarkReference(pctx, referenceId, bson.M{"added_ids": inventoryIds})
+	if err == nil && (previous == nil || !previous.Cancelled) {
+		return nil
+	}
+
+	if err := u.inventoryRepository.DeleteManyInventories(pctx, inventoryIds); err != nil {
+		log.Printf("error: remove items of cancelled step %s: %v", referenceId, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New("error: inventory update was cancelled")
+}
+
+// trackRemoved records the copy a saga step removed under its reference, so
+// a rollback by reference can put it back. When the step was already rolled
+// back the copy is put back here and an error is returned.
+func (u *inventoryUsecase) trackRemoved(pctx context.Context, referenceId string, removed *inventory.Inventory) error {
+	if referenceId == "" {
+		return nil
+	}
+
+	previous, err := u.inventoryRepository.MarkReference(pctx, referenceId, bson.M{"removed": removed})
+	if err == nil && (previous == nil || !previous.Cancelled) {
+		return nil
+	}
+
+	if _, err := u.inventoryRepository.InsertOnePlayerItem(pctx, removed); err != nil {
+		log.Printf("error: restore item of cancelled step %s: %v", referenceId, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New("error: inventory update was cancelled")
+}
+
+// cancelReference marks a saga step rolled back and returns what it did, or
+// nil when it has not run yet or was rolled back before. A step arriving
+// later sees the mark and undoes itself.
+func (u *inventoryUsecase) cancelReference(pctx context.Context, referenceId string) *inventory.InventoryReference {
+	previous, err := u.inventoryRepository.MarkReference(pctx, referenceId, bson.M{"cancelled": true})
+	if err != nil {
+		log.Printf("error: cancel step %s: %v", referenceId, err.Error())
+		return nil
+	}
+	if previous == nil || previous.Cancelled {
+		return nil
+	}
+	return previous
+}
+
 func (u *inventoryUsecase) RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq) {
+	if req.ReferenceId != "" {
+		if previous := u.cancelReference(pctx, req.ReferenceId); previous != nil && len(previous.AddedIds) > 0 {
+			u.inventoryRepository.DeleteManyInventories(pctx, previous.AddedIds)
+		}
+		return
+	}
+
+	if len(req.InventoryIds) > 0 {
+		u.inventoryRepository.DeleteManyInventories(pctx, req.InventoryIds)
+		return
+	}
+
 	u.inventoryRepository.DeleteOneInventory(pctx, req.InventoryId)
 }
 
+// RollbackRemovePlayerItem puts a removed copy back under its old id with
+// its old state, taken from the step record when ReferenceId is set.
+// Requests from before the copy was carried along fall back to a fresh
+// instance.
 func (u *inventoryUsecase) RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq) {
-	u.inventoryRepository.InsertOnePlayerItem(pctx, &inventory.Inventory{
+	if req.ReferenceId != "" {
+		if previous := u.cancelReference(pctx, req.ReferenceId); previous != nil && previous.Removed != nil {
+			if _, err := u.inventoryRepository.InsertOnePlayerItem(pctx, previous.Removed); err != nil {
+				log.Printf("error: rollback remove player item: %v", err.Error())
+			}
+		}
+		return
+	}
+
+	result := &inventory.Inventory{
+		PlayerId:     req.PlayerId,
+		ItemId:       req.ItemId,
+		ItemInstance: newItemInstance("refund"),
+	}
+	if req.Instance != nil {
+		result.ItemInstance = *req.Instance
+	}
+	if id, err := bson.ObjectIDFromHex(strings.TrimPrefix(req.InventoryId, "inventory:")); err == nil {
+		result.Id = id
+	}
+
+	if _, err := u.inventoryRepository.InsertOnePlayerItem(pctx, result); err != nil {
+		log.Printf("error: rollback remove player item: %v", err.Error())
+	}
+}
+
+func (u *inventoryUsecase) HasItem(pctx context.Context, cfg *config.Config, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error) {
+	res, err := u.CountItem(pctx, cfg, &inventoryPb.CountItemReq{
 		PlayerId: req.PlayerId,
 		ItemId:   req.ItemId,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryPb.HasItemRes{
+		Has: res.Count >= max(req.Quantity, 1),
+	}, nil
+}
+
+func (u *inventoryUsecase) CountItem(pctx context.Context, cfg *config.Config, req *inventoryPb.CountItemReq) (*inventoryPb.CountItemRes, error) {
+	itemId, err := u.resolveItemId(pctx, cfg, req.ItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := u.inventoryRepository.CountPlayerItem(pctx, req.PlayerId, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryPb.CountItemRes{
+		Count: count,
+	}, nil
+}
+
+func (u *inventoryUsecase) ListPlayerItems(pctx context.Context, req *inventoryPb.ListPlayerItemsReq) (*inventoryPb.ListPlayerItemsRes, error) {
+	results, err := u.inventoryRepository.FindPlayerItems(
+		pctx,
+		bson.D{{Key: "player_id", Value: req.PlayerId}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, errors.New("error: find player items failed")
+	}
+
+	return &inventoryPb.ListPlayerItemsRes{
+		Items: inventoriesToPb(results),
+	}, nil
+}
+
+// BatchGrant inserts every requested item or none of them. Items past a
+// player's capacity go to their mailbox when OverflowToMailbox is set.
+// Items sent with an instance keep its serial, durability and enchant level.
+func (u *inventoryUsecase) BatchGrant(pctx context.Context, cfg *config.Config, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	docs := make([]*inventory.Inventory, 0)
+	mails := make([]*inventory.Mail, 0)
+	freeSlots := make(map[string]int64)
+
+	for _, v := range req.Items {
+		if v.PlayerId == "" || v.Quantity < 1 || (v.Instance != nil && v.Quantity != 1) {
+			return nil, errors.New("error: invalid grant item")
+		}
+
+		itemId, err := u.resolveItemId(pctx, cfg, v.ItemId)
+		if err != nil {
+			return nil, err
+		}
+
+		var expiresAt *time.Time
+		if v.Instance == nil {
+			expiries, err := u.itemExpiries(pctx, cfg, []string{itemId})
+			if err != nil {
+				return nil, err
+			}
+			expiresAt = expiries[itemId]
+		}
+
+		if _, ok := freeSlots[v.PlayerId]; !ok {
+			capacity, err := u.GetCapacity(pctx, cfg, v.PlayerId)
+			if err != nil {
+				return nil, err
+			}
+
+			freeSlots[v.PlayerId] = -1
+			if capacity.Capacity > 0 {
+				freeSlots[v.PlayerId] = max(capacity.Capacity-capacity.Used, 0)
+			}
+		}
+
+		for i := 0; i < int(v.Quantity); i++ {
+			instance := newItemInstance(req.Source)
+			instance.ExpiresAt = expiresAt
+			if v.Instance != nil {
+				instance = instanceFromPb(v.Instance, req.Source)
+			}
+
+			if freeSlots[v.PlayerId] == 0 {
+				if !req.OverflowToMailbox {
+					return nil, errors.New("error: inventory is full")
+				}
+
+				mails = append(mails, &inventory.Mail{
+					PlayerId:  v.PlayerId,
+					ItemId:    itemId,
+					Instance:  &instance,
+					CreatedAt: utils.LocalTime(),
+				})
+				continue
+			}
+
+			if freeSlots[v.PlayerId] > 0 {
+				freeSlots[v.PlayerId]--
+			}
+
+			docs = append(docs, &inventory.Inventory{
+				PlayerId:     v.PlayerId,
+				ItemId:       itemId,
+				ItemInstance: instance,
+			})
+		}
+	}
+
+	if len(docs) == 0 && len(mails) == 0 {
+		return nil, errors.New("error: no items to grant")
+	}
+
+	if len(docs) > 0 {
+		if _, err := u.inventoryRepository.InsertManyPlayerItems(pctx, docs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(mails) > 0 {
+		if _, err := u.inventoryRepository.InsertManyMails(pctx, mails); err != nil {
+			if len(docs) > 0 {
+				u.inventoryRepository.DeleteManyInventories(pctx, func() []string {
+					inventoryIds := make([]string, 0)
+					for _, v := range docs {
+						inventoryIds = append(inventoryIds, v.Id.Hex())
+					}
+					return inventoryIds
+				}())
+			}
+			return nil, err
+		}
+	}
+
+	return &inventoryPb.BatchGrantRes{
+		Items:  inventoriesToPb(docs),
+		Mailed: mailsToPb(mails),
+	}, nil
+}
+
+// BatchRevoke removes every requested item or none of them. Bound items are
+// never revoked by item id, which keeps them out of trades and listings,
+// while inventory and mail ids take back the exact copies a grant returned.
+// The removed documents are returned so callers can grant them back on
+// rollback.
+func (u *inventoryUsecase) BatchRevoke(pctx context.Context, cfg *config.Config, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	revoked := make([]*inventory.Inventory, 0)
+	revokedMails := make([]*inventory.Mail, 0)
+
+	restore := func() {
+		if len(revoked) > 0 {
+			if _, err := u.inventoryRepository.InsertManyPlayerItems(pctx, revoked); err != nil {
+				log.Printf("error: restore revoked items: %v", err.Error())
+			}
+		}
+
+		if len(revokedMails) > 0 {
+			if _, err := u.inventoryRepository.InsertManyMails(pctx, revokedMails); err != nil {
+				log.Printf("error: restore revoked mails: %v", err.Error())
+			}
+		}
+	}
+
+	for _, inventoryId := range req.InventoryIds {
+		result, err := u.inventoryRepository.FindOneInventory(pctx, inventoryId)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+
+		if expired(&result.ItemInstance) {
+			restore()
+			return nil, errors.New("error: item has expired")
+		}
+
+		if err := u.inventoryRepository.RemoveOneInventory(pctx, inventoryId); err != nil {
+			restore()
+			return nil, err
+		}
+
+		revoked = append(revoked, result)
+	}
+
+	for _, mailId := range req.MailIds {
+		result, err := u.inventoryRepository.FindOneMail(pctx, mailId)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+
+		if err := u.inventoryRepository.DeleteOneMail(pctx, mailId); err != nil {
+			restore()
+			return nil, err
+		}
+
+		revokedMails = append(revokedMails, result)
+	}
+
+	for _, v := range req.Items {
+		if v.PlayerId == "" || v.Quantity < 1 {
+			restore()
+			return nil, errors.New("error: invalid revoke item")
+		}
+
+		itemId, err := u.resolveItemId(pctx, cfg, v.ItemId)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+
+		filter := bson.D{
+			{Key: "player_id", Value: v.PlayerId},
+			{Key: "item_id", Value: itemId},
+			{Key: "equipped_slot", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "expires_at", Value: nil}},
+				bson.D{{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: utils.LocalTime()}}}},
+			}},
+		}
+		if !req.IncludeBound {
+			filter = append(filter, bson.E{Key: "bound", Value: bson.D{{Key: "$ne", Value: true}}})
+		}
+
+		results, err := u.inventoryRepository.FindPlayerItems(pctx, filter, options.Find().SetLimit(int64(v.Quantity)))
+		if err != nil || len(results) < int(v.Quantity) {
+			restore()
+			if req.IncludeBound {
+				return nil, errors.New("error: player does not have enough items")
+			}
+			return nil, errors.New("error: player does not have enough tradable items")
+		}
+
+		for _, result := range results {
+			if err := u.inventoryRepository.RemoveOneInventory(pctx, result.Id.Hex()); err != nil {
+				restore()
+				return nil, errors.New("error: player does not have enough items")
+			}
+
+			revoked = append(revoked, result)
+		}
+	}
+
+	return &inventoryPb.BatchRevokeRes{
+		Items:  inventoriesToPb(revoked),
+		Mailed: mailsToPb(revokedMails),
+	}, nil
+}
+
+func inventoriesToPb(results []*inventory.Inventory) []*inventoryPb.InventoryItem {
+	items := make([]*inventoryPb.InventoryItem, 0)
+	for _, v := range results {
+		items = append(items, &inventoryPb.InventoryItem{
+			InventoryId: v.Id.Hex(),
+			PlayerId:    v.PlayerId,
+			ItemId:      v.ItemId,
+			Instance:    inventory.InstanceToPb(&v.ItemInstance),
+		})
+	}
+	return items
+}
+
+// itemExpiries returns when copies of itemIds granted now expire, keyed by
+// item id. Items that last forever are left out.
+func (u *inventoryUsecase) itemExpiries(pctx context.Context, cfg *config.Config, itemIds []string) (map[string]*time.Time, error) {
+	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: itemIds,
+	})
+	if err != nil {
+		return nil, errors.New("error: item not found")
+	}
+
+	now := utils.LocalTime()
+	results := make(map[string]*time.Time)
+	for _, v := range itemData.Items {
+		if v.DurationHours > 0 {
+			expiresAt := now.Add(time.Duration(v.DurationHours) * time.Hour)
+			results[v.Id] = &expiresAt
+		}
+	}
+
+	return results, nil
+}
+
+func newItemInstance(source string) inventory.ItemInstance {
+	if source == "" {
+		source = "grant"
+	}
+
+	return inventory.ItemInstance{
+		Serial:        strings.ToUpper(bson.NewObjectID().Hex()),
+		Durability:    defaultDurability,
+		MaxDurability: defaultDurability,
+		Source:        source,
+		AcquiredAt:    utils.LocalTime(),
+	}
+}
+
+// instanceFromPb carries a copy over to its new owner, who acquired it
+// through source. An empty source keeps the copy's own, e.g. when escrow is
+// returned. Copies from before instances existed get a fresh serial.
+func instanceFromPb(v *inventoryPb.ItemInstance, source string) inventory.ItemInstance {
+	if source == "" {
+		source = v.Source
+	}
+
+	if v.Serial == "" {
+		return newItemInstance(source)
+	}
+
+	result := inventory.ItemInstance{
+		Serial:        v.Serial,
+		Durability:    int(v.Durability),
+		MaxDurability: int(v.MaxDurability),
+		EnchantLevel:  int(v.EnchantLevel),
+		Bound:         v.Bound,
+		Source:        source,
+		AcquiredAt:    utils.LocalTime(),
+	}
+	if v.ExpiresAt > 0 {
+		expiresAt := time.Unix(v.ExpiresAt, 0)
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
+
+func mailsToPb(results []*inventory.Mail) []*inventoryPb.MailItem {
+	items := make([]*inventoryPb.MailItem, 0)
+	for _, v := range results {
+		items = append(items, &inventoryPb.MailItem{
+			MailId:   v.Id.Hex(),
+			PlayerId: v.PlayerId,
+			ItemId:   v.ItemId,
+		})
+	}
+	return items
+}
+
+// GetCapacity returns the player's slot limit and how many slots are used.
+// Capacity is zero when inventories are unlimited.
+func (u *inventoryUsecase) GetCapacity(pctx context.Context, cfg *config.Config, playerId string) (*inventory.CapacityRes, error) {
+	used, err := u.inventoryRepository.CountPlayerItems(pctx, playerId)
+	if err != nil {
+		return nil, errors.New("error: count player items failed")
+	}
+
+	if cfg.Inventory.DefaultCapacity == 0 {
+		return &inventory.CapacityRes{Used: used}, nil
+	}
+
+	extraSlots, err := u.inventoryRepository.FindExtraSlots(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventory.CapacityRes{
+		Capacity: cfg.Inventory.DefaultCapacity + extraSlots,
+		Used:     used,
+	}, nil
+}
+
+func (u *inventoryUsecase) checkFreeSlots(pctx context.Context, cfg *config.Config, playerId string, slots int64) error {
+	capacity, err := u.GetCapacity(pctx, cfg, playerId)
+	if err != nil {
+		return err
+	}
+
+	if capacity.Capacity > 0 && capacity.Used+slots > capacity.Capacity {
+		return errors.New("error: inventory is full")
+	}
+
+	return nil
+}
+
+func (u *inventoryUsecase) UpgradeCapacity(pctx context.Context, cfg *config.Config, playerId string, slots int64) (*inventory.CapacityRes, error) {
+	if slots < 1 {
+		return nil, errors.New("error: slots must be positive")
+	}
+
+	if cfg.Inventory.DefaultCapacity == 0 {
+		return nil, errors.New("error: inventory capacity is unlimited")
+	}
+
+	capacity, err := u.GetCapacity(pctx, cfg, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Inventory.MaxCapacity > 0 && capacity.Capacity+slots > cfg.Inventory.MaxCapacity {
+		return nil, errors.New("error: inventory capacity is at its maximum")
+	}
+
+	if err := u.inventoryRepository.IncExtraSlots(pctx, playerId, slots); err != nil {
+		return nil, err
+	}
+
+	capacity.Capacity += slots
+
+	return capacity, nil
+}
+
+func (u *inventoryUsecase) FindPlayerMails(pctx context.Context, playerId string) ([]*inventory.Mail, error) {
+	return u.inventoryRepository.FindPlayerMails(pctx, playerId)
+}
+
+// ClaimMail moves a mailed item into the player's inventory once there is
+// room for it.
+func (u *inventoryUsecase) ClaimMail(pctx context.Context, cfg *config.Config, playerId, mailId string) (*inventory.Inventory, error) {
+	mail, err := u.inventoryRepository.FindOneMail(pctx, mailId)
+	if err != nil {
+		return nil, err
+	}
+
+	if mail.PlayerId != playerId {
+		return nil, errors.New("error: mail not found")
+	}
+
+	if err := u.checkFreeSlots(pctx, cfg, playerId, 1); err != nil {
+		return nil, err
+	}
+
+	if err := u.inventoryRepository.DeleteOneMail(pctx, mailId); err != nil {
+		return nil, err
+	}
+
+	result := &inventory.Inventory{
+		PlayerId:     mail.PlayerId,
+		ItemId:       mail.ItemId,
+		ItemInstance: newItemInstance("mailbox"),
+	}
+	if mail.Instance != nil {
+		result.ItemInstance = *mail.Instance
+	}
+
+	inventoryId, err := u.inventoryRepository.InsertOnePlayerItem(pctx, result)
+	if err != nil {
+		if _, err := u.inventoryRepository.InsertManyMails(pctx, []*inventory.Mail{mail}); err != nil {
+			log.Printf("error: restore mail %s: %v", mailId, err.Error())
+		}
+		return nil, err
+	}
+	result.Id = inventoryId
+
+	return result, nil
+}
+
+// EquipItem puts an inventory item in the slot its item is made for,
+// replacing anything already there.
+func (u *inventoryUsecase) EquipItem(pctx context.Context, cfg *config.Config, playerId string, req *inventory.EquipItemReq) (*inventory.LoadoutRes, error) {
+	inventoryId := strings.TrimPrefix(req.InventoryId, "inventory:")
+
+	result, err := u.inventoryRepository.FindOneInventory(pctx, inventoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.PlayerId != playerId {
+		return nil, errors.New("error: inventory item not found")
+	}
+
+	if result.MaxDurability > 0 && result.Durability == 0 {
+		return nil, errors.New("error: item is broken")
+	}
+
+	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: []string{result.ItemId},
+	})
+	if err != nil || len(itemData.Items) == 0 {
+		return nil, errors.New("error: item not found")
+	}
+	itemDatum := itemData.Items[0]
+
+	if itemDatum.Slot == "" {
+		return nil, errors.New("error: item cannot be equipped")
+	}
+
+	if itemDatum.LevelRequired > 1 {
+		profile, err := u.inventoryRepository.FindOnePlayerProfile(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+			PlayerId: strings.TrimPrefix(playerId, "player:"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if profile.Level < itemDatum.LevelRequired {
+			return nil, fmt.Errorf("error: item requires level %d", itemDatum.LevelRequired)
+		}
+	}
+
+	// Equipping again renews the claim, which also wins the slot back for a
+	// copy an interrupted equip left behind
+	if err := u.inventoryRepository.EquipInventory(pctx, playerId, inventoryId, itemDatum.Slot); err != nil {
+		return nil, err
+	}
+
+	return u.GetLoadout(pctx, cfg, playerId)
+}
+
+func (u *inventoryUsecase) UnequipItem(pctx context.Context, cfg *config.Config, playerId string, req *inventory.UnequipItemReq) (*inventory.LoadoutRes, error) {
+	if err := u.inventoryRepository.UnequipSlot(pctx, playerId, req.Slot); err != nil {
+		return nil, err
+	}
+
+	return u.GetLoadout(pctx, cfg, playerId)
+}
+
+func (u *inventoryUsecase) GetLoadout(pctx context.Context, cfg *config.Config, playerId string) (*inventory.LoadoutRes, error) {
+	results, err := u.inventoryRepository.FindPlayerItems(
+		pctx,
+		bson.D{{Key: "player_id", Value: playerId}, {Key: "equipped_slot", Value: bson.D{{Key: "$exists", Value: true}}}},
+		options.Find().SetSort(bson.D{{Key: "equipped_slot", Value: 1}, {Key: "equipped_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, errors.New("error: find equipped items failed")
+	}
+
+	// The latest claim holds each slot
+	equipped := make([]*inventory.Inventory, 0)
+	for _, v := range results {
+		if len(equipped) > 0 && equipped[len(equipped)-1].EquippedSlot == v.EquippedSlot {
+			continue
+		}
+		equipped = append(equipped, v)
+	}
+	results = equipped
+
+	loadout := &inventory.LoadoutRes{
+		PlayerId: playerId,
+		Items:    make([]*inventory.ItemInInventory, 0),
+	}
+	if len(results) == 0 {
+		return loadout, nil
+	}
+
+	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: func() []string {
+			itemIds := make([]string, 0)
+			for _, v := range results {
+				itemIds = append(itemIds, v.ItemId)
+			}
+			return itemIds
+		}(),
+	})
+	if err != nil {
+		return nil, errors.New("error: find items in ids failed")
+	}
+
+	itemMaps := make(map[string]*itemPb.Item)
+	for _, v := range itemData.Items {
+		itemMaps[v.Id] = v
+	}
+
+	for _, v := range results {
+		itemDatum, ok := itemMaps[v.ItemId]
+		if !ok {
+			continue
+		}
+
+		loadout.Items = append(loadout.Items, &inventory.ItemInInventory{
+			InventoryId:      v.Id.Hex(),
+			PlayerId:         v.PlayerId,
+			EquippedSlot:     v.EquippedSlot,
+			Instance:         &v.ItemInstance,
+			RemainingSeconds: remainingSeconds(&v.ItemInstance),
+			ItemShowCase: &item.ItemShowCase{
+				ItemId:        v.ItemId,
+				Sku:           itemDatum.Sku,
+				Title:         itemDatum.Title,
+				ImageUrl:      itemDatum.ImageUrl,
+				Damage:        int(itemDatum.Damage),
+				Slot:          itemDatum.Slot,
+				LevelRequired: int(itemDatum.LevelRequired),
+			},
+		})
+		loadout.Damage += int(itemDatum.Damage)
+	}
+
+	return loadout, nil
+}
+
+func (u *inventoryUsecase) FindOneItemInstance(pctx context.Context, cfg *config.Config, playerId, inventoryId string) (*inventory.ItemInInventory, error) {
+	result, err := u.inventoryRepository.FindOneInventory(pctx, strings.TrimPrefix(inventoryId, "inventory:"))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.PlayerId != playerId {
+		return nil, errors.New("error: inventory item not found")
+	}
+
+	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: []string{result.ItemId},
+	})
+	if err != nil || len(itemData.Items) == 0 {
+		return nil, errors.New("error: item not found")
+	}
+	itemDatum := itemData.Items[0]
+
+	return &inventory.ItemInInventory{
+		InventoryId:      result.Id.Hex(),
+		PlayerId:         result.PlayerId,
+		EquippedSlot:     result.EquippedSlot,
+		Instance:         &result.ItemInstance,
+		RemainingSeconds: remainingSeconds(&result.ItemInstance),
+		ItemShowCase: &item.ItemShowCase{
+			ItemId:        result.ItemId,
+			Sku:           itemDatum.Sku,
+			Title:         itemDatum.Title,
+			Price:         itemDatum.Price,
+			ImageUrl:      itemDatum.ImageUrl,
+			Damage:        int(itemDatum.Damage),
+			Slot:          itemDatum.Slot,
+			LevelRequired: int(itemDatum.LevelRequired),
+		},
+	}, nil
+}
+
+// UpdateItemInstance sets durability, enchant level or the bound flag of one
+// copy. Durability is capped at the copy's maximum.
+func (u *inventoryUsecase) UpdateItemInstance(pctx context.Context, inventoryId string, req *inventory.UpdateItemInstanceReq) (*inventory.Inventory, error) {
+	inventoryId = strings.TrimPrefix(inventoryId, "inventory:")
+
+	result, err := u.inventoryRepository.FindOneInventory(pctx, inventoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	if result.Serial == "" {
+		// Entries from before instances existed get one on their first update.
+		result.ItemInstance = newItemInstance("legacy")
+		set["serial"] = result.Serial
+		set["durability"] = result.Durability
+		set["max_durability"] = result.MaxDurability
+		set["source"] = result.Source
+		set["acquired_at"] = result.AcquiredAt
+	}
+	if req.Durability != nil {
+		if *req.Durability < 0 {
+			return nil, errors.New("error: durability must not be negative")
+		}
+		result.Durability = min(*req.Durability, result.MaxDurability)
+		set["durability"] = result.Durability
+	}
+	if req.EnchantLevel != nil {
+		if *req.EnchantLevel < 0 {
+			return nil, errors.New("error: enchant level must not be negative")
+		}
+		result.EnchantLevel = *req.EnchantLevel
+		set["enchant_level"] = result.EnchantLevel
+	}
+	if req.Bound != nil {
+		result.Bound = *req.Bound
+		set["bound"] = result.Bound
+	}
+
+	if len(set) == 0 {
+		return nil, errors.New("error: nothing to update")
+	}
+
+	if err := u.inventoryRepository.UpdateOneInventory(pctx, inventoryId, set); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AdminUpdateItemInstance is UpdateItemInstance done by an administrator. The
+// owner is told about the change with an "item.updated_by_admin" event.
+func (u *inventoryUsecase) AdminUpdateItemInstance(pctx context.Context, cfg *config.Config, inventoryId string, req *inventory.UpdateItemInstanceReq) (*inventory.Inventory, error) {
+	result, err := u.UpdateItemInstance(pctx, inventoryId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.inventoryRepository.PushEvent(pctx, cfg, &models.DomainEvent{
+		Type:     "item.updated_by_admin",
+		PlayerId: result.PlayerId,
+		Payload: &inventory.ItemUpdatedEvent{
+			InventoryId:  result.Id.Hex(),
+			ItemId:       result.ItemId,
+			Serial:       result.Serial,
+			Durability:   result.Durability,
+			EnchantLevel: result.EnchantLevel,
+			Bound:        result.Bound,
+		},
+		OccurredAt: utils.LocalTime(),
+	}); err != nil {
+		log.Printf("error: publish admin update of inventory %s: %v", result.Id.Hex(), err.Error())
+	}
+
+	return result, nil
+}
+
+// BindItem lets a player bind a copy they own to themselves. Bound copies
+// cannot be traded or listed.
+func (u *inventoryUsecase) BindItem(pctx context.Context, playerId, inventoryId string) (*inventory.Inventory, error) {
+	result, err := u.inventoryRepository.FindOneInventory(pctx, strings.TrimPrefix(inventoryId, "inventory:"))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.PlayerId != playerId {
+		return nil, errors.New("error: inventory item not found")
+	}
+
+	if result.Bound {
+		return nil, errors.New("error: item is already bound")
+	}
+
+	bound := true
+	return u.UpdateItemInstance(pctx, inventoryId, &inventory.UpdateItemInstanceReq{Bound: &bound})
+}
+
+// UseItem consumes one consumable copy and publishes an "item.used" event
+// carrying its effect. The copy is put back when the event cannot be sent.
+func (u *inventoryUsecase) UseItem(pctx context.Context, cfg *config.Config, playerId, inventoryId string) (*inventory.UseItemRes, error) {
+	inventoryId = strings.TrimPrefix(inventoryId, "inventory:")
+
+	result, err := u.inventoryRepository.FindOneInventory(pctx, inventoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.PlayerId != playerId {
+		return nil, errors.New("error: inventory item not found")
+	}
+
+	if expired(&result.ItemInstance) {
+		return nil, errors.New("error: item has expired")
+	}
+
+	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: []string{result.ItemId},
+	})
+	if err != nil || len(itemData.Items) == 0 {
+		return nil, errors.New("error: item not found")
+	}
+	itemDatum := itemData.Items[0]
+
+	if itemDatum.Effect == nil {
+		return nil, errors.New("error: item is not consumable")
+	}
+
+	// The cooldown is reserved before the copy is removed so two concurrent
+	// uses cannot both pass the check
+	now := utils.LocalTime()
+	readyAt := time.Time{}
+	release := func() {}
+	if itemDatum.CooldownSeconds > 0 {
+		readyAt = now.Add(time.Duration(itemDatum.CooldownSeconds) * time.Second)
+
+		previous, reserved, err := u.inventoryRepository.ReserveItemCooldown(pctx, playerId, result.ItemId, now, readyAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reserved {
+			runningUntil, err := u.inventoryRepository.FindItemCooldown(pctx, playerId, result.ItemId)
+			if err != nil || !now.Before(runningUntil) {
+				return nil, errors.New("error: item is on cooldown")
+			}
+			return nil, fmt.Errorf("error: item is on cooldown for %d more seconds", int(runningUntil.Sub(now).Seconds())+1)
+		}
+
+		release = func() {
+			if err := u.inventoryRepository.ReleaseItemCooldown(pctx, playerId, result.ItemId, readyAt, previous); err != nil {
+				log.Printf("error: release cooldown of item %s for player %s: %v", result.ItemId, playerId, err.Error())
+			}
+		}
+	}
+
+	if err := u.inventoryRepository.RemoveOneInventory(pctx, inventoryId); err != nil {
+		release()
+		return nil, err
+	}
+
+	effect := &item.ItemEffect{
+		Type:            itemDatum.Effect.Type,
+		Value:           itemDatum.Effect.Value,
+		DurationSeconds: int(itemDatum.Effect.DurationSeconds),
+	}
+
+	if err := u.inventoryRepository.PushEvent(pctx, cfg, &models.DomainEvent{
+		Type:     "item.used",
+		PlayerId: playerId,
+		Payload: &inventory.ItemUsedEvent{
+			InventoryId: inventoryId,
+			ItemId:      result.ItemId,
+			Serial:      result.Serial,
+			Effect:      effect,
+		},
+		OccurredAt: now,
+	}); err != nil {
+		if _, err := u.inventoryRepository.InsertOnePlayerItem(pctx, result); err != nil {
+			log.Printf("error: restore used item %s: %v", inventoryId, err.Error())
+		}
+		release()
+		return nil, err
+	}
+
+	return &inventory.UseItemRes{
+		InventoryId: inventoryId,
+		ItemId:      result.ItemId,
+		Effect:      effect,
+		ReadyAt:     readyAt,
+	}, nil
+}
+
+// expired reports whether a copy's time is up; it may still be stored until
+// the next sweep.
+func expired(v *inventory.ItemInstance) bool {
+	return v.ExpiresAt != nil && !utils.LocalTime().Before(*v.ExpiresAt)
+}
+
+func remainingSeconds(v *inventory.ItemInstance) *int64 {
+	if v.ExpiresAt == nil {
+		return nil
+	}
+
+	remaining := max(int64(time.Until(*v.ExpiresAt).Seconds()), 0)
+	return &remaining
+}
+
+// SweepExpiredItems removes copies whose time is up, equipped or not, and
+// publishes an "item.expired" event for each one.
+func (u *inventoryUsecase) SweepExpiredItems(pctx context.Context, cfg *config.Config) error {
+	now := utils.LocalTime()
+
+	results, err := u.inventoryRepository.FindPlayerItems(
+		pctx,
+		bson.D{{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}}},
+		options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(500),
+	)
+	if err != nil {
+		return errors.New("error: find expired items failed")
+	}
+
+	for _, v := range results {
+		// A copy that is already gone was used, sold or traded in the meantime
+		if err := u.inventoryRepository.RemoveOneInventory(pctx, v.Id.Hex()); err != nil {
+			continue
+		}
+
+		if err := u.inventoryRepository.PushEvent(pctx, cfg, &models.DomainEvent{
+			Type:     "item.expired",
+			PlayerId: v.PlayerId,
+			Payload: &inventory.ItemExpiredEvent{
+				InventoryId: v.Id.Hex(),
+				ItemId:      v.ItemId,
+				Serial:      v.Serial,
+				ExpiresAt:   *v.ExpiresAt,
+			},
+			OccurredAt: now,
+		}); err != nil {
+			log.Printf("error: publish expiry of inventory %s: %v", v.Id.Hex(), err.Error())
+		}
+	}
+
+	return nil
 }
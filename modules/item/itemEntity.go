@@ -8,13 +8,119 @@ import (
 
 type (
 	Item struct {
-		Id          bson.ObjectID `json:"_id" bson:"_id,omitempty"`
-		Title       string        `json:"title" bson:"title"`
-		Price       float64       `json:"price" bson:"price"`
-		Damage      int           `json:"damage" bson:"damage"`
-		ImageUrl    string        `json:"image_url" bson:"image_url"`
-		UsageStatus bool          `json:"usage_status" bson:"usage_status"`
-		CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
-		UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
+		Id              bson.ObjectID      `json:"_id" bson:"_id,omitempty"`
+		Sku             string             `json:"sku" bson:"sku,omitempty"`
+		Title           string             `json:"title" bson:"title"`
+		Price           float64            `json:"price" bson:"price"`
+		Damage          int                `json:"damage" bson:"damage"`
+		Slot            string             `json:"slot,omitempty" bson:"slot,omitempty"`
+		LevelRequired   int                `json:"level_required" bson:"level_required"`
+		Effect          *ItemEffect        `json:"effect,omitempty" bson:"effect,omitempty"`
+		CooldownSeconds int                `json:"cooldown_seconds" bson:"cooldown_seconds"`
+		DurationHours   int                `json:"duration_hours" bson:"duration_hours"`
+		LootTable       *LootTable         `json:"loot_table,omitempty" bson:"loot_table,omitempty"`
+		ImageUrl        string             `json:"image_url" bson:"image_url"`
+		UsageStatus     bool               `json:"usage_status" bson:"usage_status"`
+		Components      []*BundleComponent `json:"components,omitempty" bson:"components,omitempty"`
+		Stock           *int64             `json:"stock" bson:"stock"`
+		PurchaseLimit   int                `json:"purchase_limit" bson:"purchase_limit"`
+		CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	ItemPurchase struct {
+		Id       bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		ItemId   string        `json:"item_id" bson:"item_id"`
+		PlayerId string        `json:"player_id" bson:"player_id"`
+		Count    int           `json:"count" bson:"count"`
+	}
+
+	ItemRevision struct {
+		Id            bson.ObjectID       `json:"_id" bson:"_id,omitempty"`
+		ItemId        string              `json:"item_id" bson:"item_id"`
+		Action        string              `json:"action" bson:"action"`
+		ChangedBy     string              `json:"changed_by" bson:"changed_by"`
+		Diff          []*ItemRevisionDiff `json:"diff" bson:"diff"`
+		PreviousPrice float64             `json:"previous_price" bson:"previous_price"`
+		Price         float64             `json:"price" bson:"price"`
+		CreatedAt     time.Time           `json:"created_at" bson:"created_at"`
+	}
+
+	ItemRevisionDiff struct {
+		Field string `json:"field" bson:"field"`
+		Old   any    `json:"old" bson:"old"`
+		New   any    `json:"new" bson:"new"`
+	}
+
+	// ItemEffect is what using a consumable does, e.g. {heal 50 0} or
+	// {damage_buff 10 60}. Items with an effect are consumables.
+	ItemEffect struct {
+		Type            string  `json:"type" bson:"type"`
+		Value           float64 `json:"value" bson:"value"`
+		DurationSeconds int     `json:"duration_seconds,omitempty" bson:"duration_seconds,omitempty"`
+	}
+
+	// LootTable makes an item a loot box. Opening one draws an entry with
+	// probability weight / total weight. With PityThreshold set, a player
+	// who opened PityThreshold-1 boxes in a row without a rare entry draws
+	// only from the rare entries on the next open.
+	LootTable struct {
+		Entries       []*LootEntry `json:"entries" bson:"entries"`
+		PityThreshold int          `json:"pity_threshold" bson:"pity_threshold"`
+	}
+
+	LootEntry struct {
+		ItemId string `json:"item_id" bson:"item_id"`
+		Weight int    `json:"weight" bson:"weight"`
+		Rare   bool   `json:"rare" bson:"rare"`
+	}
+
+	BundleComponent struct {
+		ItemId   string `json:"item_id" bson:"item_id"`
+		Quantity int    `json:"quantity" bson:"quantity"`
+	}
+
+	// Recipe turns Inputs into OutputQuantity copies of OutputItemId for
+	// Cost gold. A craft succeeds with probability SuccessRate; a failed
+	// craft still consumes the inputs and grants ConsolationItemId if set.
+	Recipe struct {
+		Id                bson.ObjectID      `json:"_id" bson:"_id,omitempty"`
+		Title             string             `json:"title" bson:"title"`
+		Inputs            []*BundleComponent `json:"inputs" bson:"inputs"`
+		OutputItemId      string             `json:"output_item_id" bson:"output_item_id"`
+		OutputQuantity    int                `json:"output_quantity" bson:"output_quantity"`
+		Cost              float64            `json:"cost" bson:"cost"`
+		SuccessRate       float64            `json:"success_rate" bson:"success_rate"`
+		ConsolationItemId string             `json:"consolation_item_id,omitempty" bson:"consolation_item_id,omitempty"`
+		UsageStatus       bool               `json:"usage_status" bson:"usage_status"`
+		CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	WishlistItem struct {
+		Id         bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId   string        `json:"player_id" bson:"player_id"`
+		ItemId     string        `json:"item_id" bson:"item_id"`
+		PriceAtAdd float64       `json:"price_at_add" bson:"price_at_add"`
+		CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+	}
+
+	// WishlistNotification tells a player that a wishlisted item changed.
+	// Kind is "price_drop", "available" or "restock". There is at most one
+	// per player and item revision.
+	WishlistNotification struct {
+		Id         bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId   string        `json:"player_id" bson:"player_id"`
+		ItemId     string        `json:"item_id" bson:"item_id"`
+		RevisionId string        `json:"revision_id" bson:"revision_id"`
+		Kind       string        `json:"kind" bson:"kind"`
+		OldPrice   float64       `json:"old_price" bson:"old_price"`
+		Price      float64       `json:"price" bson:"price"`
+		CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+	}
+
+	// WishlistWatch is how far the wishlist watcher has read item_revisions.
+	WishlistWatch struct {
+		RevisionId bson.ObjectID `json:"revision_id" bson:"revision_id"`
 	}
 )
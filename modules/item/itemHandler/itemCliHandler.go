@@ -0,0 +1,108 @@
+package itemHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/modules/item"
+	"github.com/Supakornn/mmorpg-shop/modules/item/itemUsecase"
+)
+
+type (
+	ItemCliHandlerService interface {
+		ImportItems(pctx context.Context, args []string) error
+		ExportItems(pctx context.Context, args []string) error
+	}
+
+	itemCliHandler struct {
+		itemUsecase itemUsecase.ItemUsecaseService
+	}
+)
+
+func NewItemCliHandler(itemUsecase itemUsecase.ItemUsecaseService) ItemCliHandlerService {
+	return &itemCliHandler{itemUsecase}
+}
+
+// catalogFormat falls back to the file extension when -format is not given.
+func catalogFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+func (h *itemCliHandler) ImportItems(pctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "catalog format: csv or json (default: file extension)")
+	dryRun := fs.Bool("dry-run", false, "show the changes without applying them")
+	changedBy := fs.String("changed-by", "cli", "recorded as the author of item revisions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("error: usage: import [-format csv|json] [-dry-run] [-changed-by name] <file>")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	res, err := h.itemUsecase.ImportItems(pctx, *changedBy, &item.ItemImportReq{
+		Format: catalogFormat(*format, fs.Arg(0)),
+		DryRun: *dryRun,
+	}, file)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(res); err != nil {
+		return err
+	}
+
+	if len(res.Errors) > 0 {
+		return errors.New("error: catalog has invalid rows, nothing was imported")
+	}
+
+	log.Printf("import: %d created, %d updated, %d unchanged (dry run: %t)", res.Created, res.Updated, res.Unchanged, res.DryRun)
+
+	return nil
+}
+
+func (h *itemCliHandler) ExportItems(pctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", "catalog format: csv or json (default: output extension, or json)")
+	out := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	req := &item.ItemExportReq{Format: catalogFormat(*format, *out)}
+	if req.Format == "" {
+		req.Format = "json"
+	}
+
+	return h.itemUsecase.ExportItems(pctx, req, w)
+}
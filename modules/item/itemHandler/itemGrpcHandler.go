@@ -23,3 +23,15 @@ func NewItemGrpcHandler(itemUsecase itemUsecase.ItemUsecaseService) *itemGrpcHan
 func (g *itemGrpcHandler) FindItemsInIds(ctx context.Context, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
 	return g.itemUsecase.FindItemsInIds(ctx, req)
 }
+
+func (g *itemGrpcHandler) ReserveItems(ctx context.Context, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error) {
+	return g.itemUsecase.ReserveItems(ctx, req)
+}
+
+func (g *itemGrpcHandler) ReleaseItems(ctx context.Context, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error) {
+	return g.itemUsecase.ReleaseItems(ctx, req)
+}
+
+func (g *itemGrpcHandler) FindOneRecipe(ctx context.Context, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error) {
+	return g.itemUsecase.FindOneRecipePb(ctx, req)
+}
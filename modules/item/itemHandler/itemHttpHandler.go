@@ -1,11 +1,14 @@
 package itemHandler
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
@@ -18,10 +21,25 @@ import (
 type (
 	ItemHttpHandlerService interface {
 		CreateItem(c echo.Context) error
+		CreateBundle(c echo.Context) error
 		FindOneItem(c echo.Context) error
 		FindManyItems(c echo.Context) error
+		CreateLootBox(c echo.Context) error
+		FindLootBoxOdds(c echo.Context) error
+		CreateRecipe(c echo.Context) error
+		FindOneRecipe(c echo.Context) error
+		FindManyRecipes(c echo.Context) error
 		EditItem(c echo.Context) error
 		ToggleItemUsageStatus(c echo.Context) error
+		FindItemHistory(c echo.Context) error
+		FindItemPriceAt(c echo.Context) error
+		FindMyItemPrice(c echo.Context) error
+		AddWishlistItem(c echo.Context) error
+		FindWishlist(c echo.Context) error
+		RemoveWishlistItem(c echo.Context) error
+		FindWishlistNotifications(c echo.Context) error
+		ImportItems(c echo.Context) error
+		ExportItems(c echo.Context) error
 	}
 
 	itemHttpHandler struct {
@@ -53,6 +71,25 @@ func (h *itemHttpHandler) CreateItem(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusCreated, res)
 }
 
+func (h *itemHttpHandler) CreateBundle(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.CreateBundleReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.CreateBundle(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
 func (h *itemHttpHandler) FindOneItem(c echo.Context) error {
 	ctx := context.Background()
 
@@ -112,7 +149,9 @@ func (h *itemHttpHandler) EditItem(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.itemUsecase.EditItem(ctx, itemId, req)
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.itemUsecase.EditItem(ctx, itemId, playerId, req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
 	}
@@ -132,7 +171,9 @@ func (h *itemHttpHandler) ToggleItemUsageStatus(c echo.Context) error {
 
 	itemId := strings.TrimPrefix(decodedParam, "item:")
 
-	res, err := h.itemUsecase.ToggleItemUsageStatus(ctx, itemId)
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.itemUsecase.ToggleItemUsageStatus(ctx, itemId, playerId)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
 	}
@@ -141,3 +182,295 @@ func (h *itemHttpHandler) ToggleItemUsageStatus(c echo.Context) error {
 		Message: fmt.Sprintf("Item status toggled to %t", res),
 	})
 }
+
+func (h *itemHttpHandler) FindItemHistory(c echo.Context) error {
+	ctx := context.Background()
+
+	originalParam := c.Param("item_id")
+
+	decodedParam, err := url.QueryUnescape(originalParam)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	itemId := strings.TrimPrefix(decodedParam, "item:")
+
+	res, err := h.itemUsecase.FindItemHistory(ctx, itemId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) FindItemPriceAt(c echo.Context) error {
+	ctx := context.Background()
+
+	originalParam := c.Param("item_id")
+
+	decodedParam, err := url.QueryUnescape(originalParam)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	itemId := strings.TrimPrefix(decodedParam, "item:")
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.ItemPriceAtReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	at, err := time.Parse(time.RFC3339, req.At)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: at must be an RFC3339 timestamp")
+	}
+
+	res, err := h.itemUsecase.FindItemPriceAt(ctx, itemId, at)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) FindMyItemPrice(c echo.Context) error {
+	ctx := context.Background()
+
+	originalParam := c.Param("item_id")
+
+	decodedParam, err := url.QueryUnescape(originalParam)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	itemId := strings.TrimPrefix(decodedParam, "item:")
+
+	res, err := h.itemUsecase.FindItemPriceFor(ctx, h.cfg, itemId, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) ImportItems(c echo.Context) error {
+	ctx := context.Background()
+
+	// The body is the catalog file itself, so only the query string is bound
+	req := &item.ItemImportReq{
+		Format: c.QueryParam("format"),
+	}
+
+	if dryRun := c.QueryParam("dry_run"); dryRun != "" {
+		var err error
+		if req.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return response.ErrResponse(c, http.StatusBadRequest, "error: dry_run must be a boolean")
+		}
+	}
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.itemUsecase.ImportItems(ctx, playerId, req, c.Request().Body)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if len(res.Errors) > 0 {
+		return response.SuccessResponse(c, http.StatusUnprocessableEntity, res)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) ExportItems(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.ItemExportReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	buf := new(bytes.Buffer)
+	if err := h.itemUsecase.ExportItems(ctx, req, buf); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	contentType := echo.MIMEApplicationJSON
+	if req.Format == "csv" {
+		contentType = "text/csv"
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=items.%s", req.Format))
+
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+func (h *itemHttpHandler) CreateLootBox(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.CreateLootBoxReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.CreateLootBox(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *itemHttpHandler) FindLootBoxOdds(c echo.Context) error {
+	ctx := context.Background()
+
+	originalParam := c.Param("item_id")
+
+	decodedParam, err := url.QueryUnescape(originalParam)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	itemId := strings.TrimPrefix(decodedParam, "item:")
+
+	res, err := h.itemUsecase.FindLootBoxOdds(ctx, itemId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) CreateRecipe(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.CreateRecipeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.CreateRecipe(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *itemHttpHandler) FindOneRecipe(c echo.Context) error {
+	ctx := context.Background()
+
+	recipeId := c.Param("recipe_id")
+
+	res, err := h.itemUsecase.FindOneRecipe(ctx, recipeId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) FindManyRecipes(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.itemUsecase.FindManyRecipes(ctx)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func playerIdParam(c echo.Context) (string, error) {
+	return url.QueryUnescape(c.Param("player_id"))
+}
+
+func (h *itemHttpHandler) AddWishlistItem(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId, err := playerIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.AddWishlistItemReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.AddWishlistItem(ctx, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *itemHttpHandler) FindWishlist(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId, err := playerIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.itemUsecase.FindWishlist(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) RemoveWishlistItem(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId, err := playerIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	itemId, err := url.QueryUnescape(c.Param("item_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	if err := h.itemUsecase.RemoveWishlistItem(ctx, playerId, itemId); err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Item removed from wishlist",
+	})
+}
+
+func (h *itemHttpHandler) FindWishlistNotifications(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId, err := playerIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.itemUsecase.FindWishlistNotifications(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
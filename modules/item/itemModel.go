@@ -1,21 +1,114 @@
 package item
 
-import "github.com/Supakornn/mmorpg-shop/modules/models"
+import (
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+)
 
 type (
 	CreateItemReq struct {
-		Title    string  `json:"title" validate:"required,max=64"`
-		Price    float64 `json:"price" validate:"required"`
-		ImageUrl string  `json:"image_url" validate:"required,max=255"`
-		Damage   int     `json:"damage" validate:"required"`
+		Sku             string         `json:"sku" validate:"omitempty,max=64"`
+		Title           string         `json:"title" validate:"required,max=64"`
+		Price           float64        `json:"price" validate:"required"`
+		ImageUrl        string         `json:"image_url" validate:"required,max=255"`
+		Damage          int            `json:"damage" validate:"required"`
+		Slot            string         `json:"slot" validate:"omitempty,oneof=head body hands legs feet weapon offhand"`
+		LevelRequired   int            `json:"level_required" validate:"min=0"`
+		Stock           *int64         `json:"stock" validate:"omitempty,min=0"`
+		PurchaseLimit   int            `json:"purchase_limit" validate:"min=0"`
+		Effect          *ItemEffectReq `json:"effect" validate:"omitempty"`
+		CooldownSeconds int            `json:"cooldown_seconds" validate:"min=0"`
+		DurationHours   int            `json:"duration_hours" validate:"min=0"`
+	}
+
+	ItemEffectReq struct {
+		Type            string  `json:"type" validate:"required,max=32"`
+		Value           float64 `json:"value"`
+		DurationSeconds int     `json:"duration_seconds" validate:"min=0"`
+	}
+
+	CreateBundleReq struct {
+		Sku        string                `json:"sku" validate:"omitempty,max=64"`
+		Title      string                `json:"title" validate:"required,max=64"`
+		Price      float64               `json:"price" validate:"required"`
+		ImageUrl   string                `json:"image_url" validate:"required,max=255"`
+		Components []*BundleComponentReq `json:"components" validate:"required,min=1,dive"`
+	}
+
+	BundleComponentReq struct {
+		ItemId   string `json:"item_id" validate:"required,max=64"`
+		Quantity int    `json:"quantity" validate:"required,min=1,max=99"`
+	}
+
+	CreateLootBoxReq struct {
+		Sku           string          `json:"sku" validate:"omitempty,max=64"`
+		Title         string          `json:"title" validate:"required,max=64"`
+		Price         float64         `json:"price" validate:"required"`
+		ImageUrl      string          `json:"image_url" validate:"required,max=255"`
+		Entries       []*LootEntryReq `json:"entries" validate:"required,min=1,max=100,dive"`
+		PityThreshold int             `json:"pity_threshold" validate:"min=0"`
+	}
+
+	LootEntryReq struct {
+		ItemId string `json:"item_id" validate:"required,max=64"`
+		Weight int    `json:"weight" validate:"required,min=1"`
+		Rare   bool   `json:"rare"`
+	}
+
+	// LootBoxOddsRes publishes the chance of each entry of a loot box.
+	LootBoxOddsRes struct {
+		ItemId        string      `json:"item_id"`
+		Title         string      `json:"title"`
+		PityThreshold int         `json:"pity_threshold,omitempty"`
+		Entries       []*LootOdds `json:"entries"`
+	}
+
+	LootOdds struct {
+		ItemId      string  `json:"item_id"`
+		Title       string  `json:"title"`
+		Weight      int     `json:"weight"`
+		Probability float64 `json:"probability"`
+		Rare        bool    `json:"rare"`
+	}
+
+	CreateRecipeReq struct {
+		Title             string                `json:"title" validate:"required,max=64"`
+		Inputs            []*BundleComponentReq `json:"inputs" validate:"required,min=1,max=10,dive"`
+		OutputItemId      string                `json:"output_item_id" validate:"required,max=64"`
+		OutputQuantity    int                   `json:"output_quantity" validate:"omitempty,min=1,max=99"`
+		Cost              float64               `json:"cost" validate:"min=0"`
+		SuccessRate       float64               `json:"success_rate" validate:"required,gt=0,lte=1"`
+		ConsolationItemId string                `json:"consolation_item_id" validate:"omitempty,max=64"`
+	}
+
+	RecipeShowCase struct {
+		RecipeId          string             `json:"recipe_id"`
+		Title             string             `json:"title"`
+		Inputs            []*BundleComponent `json:"inputs"`
+		OutputItemId      string             `json:"output_item_id"`
+		OutputQuantity    int                `json:"output_quantity"`
+		Cost              float64            `json:"cost"`
+		SuccessRate       float64            `json:"success_rate"`
+		ConsolationItemId string             `json:"consolation_item_id,omitempty"`
 	}
 
 	ItemShowCase struct {
-		ItemId   string  `json:"item_id"`
-		Title    string  `json:"title"`
-		Price    float64 `json:"price"`
-		ImageUrl string  `json:"image_url"`
-		Damage   int     `json:"damage"`
+		ItemId          string             `json:"item_id"`
+		Sku             string             `json:"sku,omitempty"`
+		Title           string             `json:"title"`
+		Price           float64            `json:"price"`
+		ImageUrl        string             `json:"image_url"`
+		Damage          int                `json:"damage"`
+		Slot            string             `json:"slot,omitempty"`
+		LevelRequired   int                `json:"level_required,omitempty"`
+		Components      []*BundleComponent `json:"components,omitempty"`
+		Stock           *int64             `json:"stock,omitempty"`
+		PurchaseLimit   int                `json:"purchase_limit,omitempty"`
+		Effect          *ItemEffect        `json:"effect,omitempty"`
+		CooldownSeconds int                `json:"cooldown_seconds,omitempty"`
+		DurationHours   int                `json:"duration_hours,omitempty"`
+		LootTable       *LootTable         `json:"loot_table,omitempty"`
 	}
 
 	ItemSearchReq struct {
@@ -24,10 +117,109 @@ type (
 	}
 
 	ItemUpdateReq struct {
-		Title    string  `json:"title" validate:"required,max=64"`
-		Price    float64 `json:"price" validate:"required"`
-		ImageUrl string  `json:"image_url" validate:"required,max=255"`
-		Damage   int     `json:"damage" validate:"required"`
+		Title           string         `json:"title" validate:"required,max=64"`
+		Price           float64        `json:"price" validate:"required"`
+		ImageUrl        string         `json:"image_url" validate:"required,max=255"`
+		Damage          int            `json:"damage" validate:"required"`
+		Slot            string         `json:"slot" validate:"omitempty,oneof=head body hands legs feet weapon offhand"`
+		LevelRequired   *int           `json:"level_required" validate:"omitempty,min=0"`
+		Stock           *int64         `json:"stock" validate:"omitempty,min=-1"`
+		PurchaseLimit   *int           `json:"purchase_limit" validate:"omitempty,min=0"`
+		Effect          *ItemEffectReq `json:"effect" validate:"omitempty"`
+		CooldownSeconds *int           `json:"cooldown_seconds" validate:"omitempty,min=0"`
+		DurationHours   *int           `json:"duration_hours" validate:"omitempty,min=0"`
+	}
+
+	AddWishlistItemReq struct {
+		ItemId string `json:"item_id" validate:"required,max=64"`
+	}
+
+	WishlistItemRes struct {
+		ItemId      string    `json:"item_id"`
+		Title       string    `json:"title"`
+		Price       float64   `json:"price"`
+		PriceAtAdd  float64   `json:"price_at_add"`
+		UsageStatus bool      `json:"usage_status"`
+		Stock       *int64    `json:"stock"`
+		AddedAt     time.Time `json:"added_at"`
+	}
+
+	// WishlistEvent is the payload of the "wishlist.price_drop",
+	// "wishlist.available" and "wishlist.restock" events.
+	WishlistEvent struct {
+		ItemId   string  `json:"item_id"`
+		Title    string  `json:"title"`
+		OldPrice float64 `json:"old_price"`
+		Price    float64 `json:"price"`
+	}
+
+	ItemPriceAtReq struct {
+		At string `query:"at" validate:"required"`
+	}
+
+	ItemPriceAtRes struct {
+		ItemId string    `json:"item_id"`
+		Price  float64   `json:"price"`
+		At     time.Time `json:"at"`
+	}
+
+	// ItemPlayerPriceRes is the price a player pays after VIP discounts.
+	ItemPlayerPriceRes struct {
+		ItemId       string  `json:"item_id"`
+		ListPrice    float64 `json:"list_price"`
+		Price        float64 `json:"price"`
+		DiscountRate float64 `json:"discount_rate"`
+		Vip          bool    `json:"vip"`
+	}
+
+	ItemImportReq struct {
+		Format string `query:"format" validate:"required,oneof=csv json"`
+		DryRun bool   `query:"dry_run"`
+	}
+
+	ItemExportReq struct {
+		Format string `query:"format" validate:"required,oneof=csv json"`
+	}
+
+	// ItemCatalogRow is one item of an imported or exported catalog file.
+	// A nil UsageStatus means active and a nil Stock means unlimited.
+	// ItemCatalogRow holds every field of a plain item. Bundles and loot
+	// boxes point at environment specific item ids and are not part of it.
+	ItemCatalogRow struct {
+		Sku             string         `json:"sku" validate:"required,max=64"`
+		Title           string         `json:"title" validate:"required,max=64"`
+		Price           float64        `json:"price" validate:"gt=0"`
+		ImageUrl        string         `json:"image_url" validate:"required,max=255"`
+		Damage          int            `json:"damage" validate:"min=0"`
+		UsageStatus     *bool          `json:"usage_status"`
+		Stock           *int64         `json:"stock" validate:"omitempty,min=0"`
+		PurchaseLimit   int            `json:"purchase_limit" validate:"min=0"`
+		Slot            string         `json:"slot,omitempty" validate:"omitempty,oneof=head body hands legs feet weapon offhand"`
+		LevelRequired   int            `json:"level_required" validate:"min=0"`
+		Effect          *ItemEffectReq `json:"effect,omitempty" validate:"omitempty"`
+		CooldownSeconds int            `json:"cooldown_seconds" validate:"min=0"`
+		DurationHours   int            `json:"duration_hours" validate:"min=0"`
+	}
+
+	ItemImportRes struct {
+		DryRun    bool                `json:"dry_run"`
+		Created   int                 `json:"created"`
+		Updated   int                 `json:"updated"`
+		Unchanged int                 `json:"unchanged"`
+		Changes   []*ItemImportChange `json:"changes"`
+		Errors    []*ItemImportError  `json:"errors,omitempty"`
+	}
+
+	ItemImportChange struct {
+		Sku    string              `json:"sku"`
+		Action string              `json:"action"`
+		Diff   []*ItemRevisionDiff `json:"diff,omitempty"`
+	}
+
+	ItemImportError struct {
+		Row     int    `json:"row"`
+		Sku     string `json:"sku,omitempty"`
+		Message string `json:"message"`
 	}
 
 	EnableorDisableItemReq struct {
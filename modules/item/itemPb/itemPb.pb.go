@@ -23,7 +23,9 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Structures
+//	Structures
+//
+// ids are either "item:<hex>" or "sku:<SKU>"
 type FindItemsInIdsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
@@ -119,6 +121,20 @@ type Item struct {
 	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
 	ImageUrl      string                 `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
 	Damage        int32                  `protobuf:"varint,5,opt,name=damage,proto3" json:"damage,omitempty"`
+	Components    []*BundleComponent     `protobuf:"bytes,6,rep,name=components,proto3" json:"components,omitempty"`
+	Stock         *int64                 `protobuf:"varint,7,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	PurchaseLimit int32                  `protobuf:"varint,8,opt,name=purchase_limit,json=purchaseLimit,proto3" json:"purchase_limit,omitempty"`
+	Sku           string                 `protobuf:"bytes,9,opt,name=sku,proto3" json:"sku,omitempty"`
+	// slot is empty for items that cannot be equipped
+	Slot          string `protobuf:"bytes,10,opt,name=slot,proto3" json:"slot,omitempty"`
+	LevelRequired int32  `protobuf:"varint,11,opt,name=level_required,json=levelRequired,proto3" json:"level_required,omitempty"`
+	// effect is only set on consumables
+	Effect          *ItemEffect `protobuf:"bytes,12,opt,name=effect,proto3" json:"effect,omitempty"`
+	CooldownSeconds int32       `protobuf:"varint,13,opt,name=cooldown_seconds,json=cooldownSeconds,proto3" json:"cooldown_seconds,omitempty"`
+	// duration_hours is how long a granted copy lasts, 0 for forever
+	DurationHours int32 `protobuf:"varint,14,opt,name=duration_hours,json=durationHours,proto3" json:"duration_hours,omitempty"`
+	// loot_table is only set on loot boxes
+	LootTable     *LootTable `protobuf:"bytes,15,opt,name=loot_table,json=lootTable,proto3" json:"loot_table,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -188,6 +204,637 @@ func (x *Item) GetDamage() int32 {
 	return 0
 }
 
+func (x *Item) GetComponents() []*BundleComponent {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *Item) GetStock() int64 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+func (x *Item) GetPurchaseLimit() int32 {
+	if x != nil {
+		return x.PurchaseLimit
+	}
+	return 0
+}
+
+func (x *Item) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Item) GetSlot() string {
+	if x != nil {
+		return x.Slot
+	}
+	return ""
+}
+
+func (x *Item) GetLevelRequired() int32 {
+	if x != nil {
+		return x.LevelRequired
+	}
+	return 0
+}
+
+func (x *Item) GetEffect() *ItemEffect {
+	if x != nil {
+		return x.Effect
+	}
+	return nil
+}
+
+func (x *Item) GetCooldownSeconds() int32 {
+	if x != nil {
+		return x.CooldownSeconds
+	}
+	return 0
+}
+
+func (x *Item) GetDurationHours() int32 {
+	if x != nil {
+		return x.DurationHours
+	}
+	return 0
+}
+
+func (x *Item) GetLootTable() *LootTable {
+	if x != nil {
+		return x.LootTable
+	}
+	return nil
+}
+
+type ItemEffect struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value           float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	DurationSeconds int32                  `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ItemEffect) Reset() {
+	*x = ItemEffect{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemEffect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemEffect) ProtoMessage() {}
+
+func (x *ItemEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemEffect.ProtoReflect.Descriptor instead.
+func (*ItemEffect) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ItemEffect) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ItemEffect) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *ItemEffect) GetDurationSeconds() int32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type LootTable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*LootEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	PityThreshold int32                  `protobuf:"varint,2,opt,name=pity_threshold,json=pityThreshold,proto3" json:"pity_threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LootTable) Reset() {
+	*x = LootTable{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LootTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LootTable) ProtoMessage() {}
+
+func (x *LootTable) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LootTable.ProtoReflect.Descriptor instead.
+func (*LootTable) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LootTable) GetEntries() []*LootEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *LootTable) GetPityThreshold() int32 {
+	if x != nil {
+		return x.PityThreshold
+	}
+	return 0
+}
+
+type LootEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Weight        int32                  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Rare          bool                   `protobuf:"varint,3,opt,name=rare,proto3" json:"rare,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LootEntry) Reset() {
+	*x = LootEntry{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LootEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LootEntry) ProtoMessage() {}
+
+func (x *LootEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LootEntry.ProtoReflect.Descriptor instead.
+func (*LootEntry) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LootEntry) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *LootEntry) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *LootEntry) GetRare() bool {
+	if x != nil {
+		return x.Rare
+	}
+	return false
+}
+
+type BundleComponent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleComponent) Reset() {
+	*x = BundleComponent{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleComponent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleComponent) ProtoMessage() {}
+
+func (x *BundleComponent) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleComponent.ProtoReflect.Descriptor instead.
+func (*BundleComponent) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BundleComponent) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *BundleComponent) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveItemsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Ids           []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveItemsReq) Reset() {
+	*x = ReserveItemsReq{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveItemsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveItemsReq) ProtoMessage() {}
+
+func (x *ReserveItemsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveItemsReq.ProtoReflect.Descriptor instead.
+func (*ReserveItemsReq) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReserveItemsReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *ReserveItemsReq) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ReserveItemsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveItemsRes) Reset() {
+	*x = ReserveItemsRes{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveItemsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveItemsRes) ProtoMessage() {}
+
+func (x *ReserveItemsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveItemsRes.ProtoReflect.Descriptor instead.
+func (*ReserveItemsRes) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReserveItemsRes) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ReleaseItemsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Ids           []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseItemsReq) Reset() {
+	*x = ReleaseItemsReq{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseItemsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseItemsReq) ProtoMessage() {}
+
+func (x *ReleaseItemsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseItemsReq.ProtoReflect.Descriptor instead.
+func (*ReleaseItemsReq) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReleaseItemsReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *ReleaseItemsReq) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ReleaseItemsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseItemsRes) Reset() {
+	*x = ReleaseItemsRes{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseItemsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseItemsRes) ProtoMessage() {}
+
+func (x *ReleaseItemsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseItemsRes.ProtoReflect.Descriptor instead.
+func (*ReleaseItemsRes) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReleaseItemsRes) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// A failed craft grants one consolation_item_id, if the recipe has one.
+type Recipe struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title             string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Inputs            []*BundleComponent     `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	OutputItemId      string                 `protobuf:"bytes,4,opt,name=output_item_id,json=outputItemId,proto3" json:"output_item_id,omitempty"`
+	OutputQuantity    int32                  `protobuf:"varint,5,opt,name=output_quantity,json=outputQuantity,proto3" json:"output_quantity,omitempty"`
+	Cost              float64                `protobuf:"fixed64,6,opt,name=cost,proto3" json:"cost,omitempty"`
+	SuccessRate       float64                `protobuf:"fixed64,7,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	ConsolationItemId string                 `protobuf:"bytes,8,opt,name=consolation_item_id,json=consolationItemId,proto3" json:"consolation_item_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Recipe) Reset() {
+	*x = Recipe{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recipe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recipe) ProtoMessage() {}
+
+func (x *Recipe) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recipe.ProtoReflect.Descriptor instead.
+func (*Recipe) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Recipe) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Recipe) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Recipe) GetInputs() []*BundleComponent {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *Recipe) GetOutputItemId() string {
+	if x != nil {
+		return x.OutputItemId
+	}
+	return ""
+}
+
+func (x *Recipe) GetOutputQuantity() int32 {
+	if x != nil {
+		return x.OutputQuantity
+	}
+	return 0
+}
+
+func (x *Recipe) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *Recipe) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *Recipe) GetConsolationItemId() string {
+	if x != nil {
+		return x.ConsolationItemId
+	}
+	return ""
+}
+
+type FindOneRecipeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecipeId      string                 `protobuf:"bytes,1,opt,name=recipe_id,json=recipeId,proto3" json:"recipe_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindOneRecipeReq) Reset() {
+	*x = FindOneRecipeReq{}
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindOneRecipeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindOneRecipeReq) ProtoMessage() {}
+
+func (x *FindOneRecipeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindOneRecipeReq.ProtoReflect.Descriptor instead.
+func (*FindOneRecipeReq) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FindOneRecipeReq) GetRecipeId() string {
+	if x != nil {
+		return x.RecipeId
+	}
+	return ""
+}
+
 var File_modules_item_itemPb_itemPb_proto protoreflect.FileDescriptor
 
 const file_modules_item_itemPb_itemPb_proto_rawDesc = "" +
@@ -196,15 +843,71 @@ const file_modules_item_itemPb_itemPb_proto_rawDesc = "" +
 	"\x11FindItemsInIdsReq\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\tR\x03ids\"0\n" +
 	"\x11FindItemsInIdsRes\x12\x1b\n" +
-	"\x05items\x18\x01 \x03(\v2\x05.ItemR\x05items\"w\n" +
+	"\x05items\x18\x01 \x03(\v2\x05.ItemR\x05items\"\xe4\x03\n" +
 	"\x04Item\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
 	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1b\n" +
 	"\timage_url\x18\x04 \x01(\tR\bimageUrl\x12\x16\n" +
-	"\x06damage\x18\x05 \x01(\x05R\x06damage2K\n" +
+	"\x06damage\x18\x05 \x01(\x05R\x06damage\x120\n" +
+	"\n" +
+	"components\x18\x06 \x03(\v2\x10.BundleComponentR\n" +
+	"components\x12\x19\n" +
+	"\x05stock\x18\a \x01(\x03H\x00R\x05stock\x88\x01\x01\x12%\n" +
+	"\x0epurchase_limit\x18\b \x01(\x05R\rpurchaseLimit\x12\x10\n" +
+	"\x03sku\x18\t \x01(\tR\x03sku\x12\x12\n" +
+	"\x04slot\x18\n" +
+	" \x01(\tR\x04slot\x12%\n" +
+	"\x0elevel_required\x18\v \x01(\x05R\rlevelRequired\x12#\n" +
+	"\x06effect\x18\f \x01(\v2\v.ItemEffectR\x06effect\x12)\n" +
+	"\x10cooldown_seconds\x18\r \x01(\x05R\x0fcooldownSeconds\x12%\n" +
+	"\x0eduration_hours\x18\x0e \x01(\x05R\rdurationHours\x12)\n" +
+	"\n" +
+	"loot_table\x18\x0f \x01(\v2\n" +
+	".LootTableR\tlootTableB\b\n" +
+	"\x06_stock\"a\n" +
+	"\n" +
+	"ItemEffect\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x05R\x0fdurationSeconds\"X\n" +
+	"\tLootTable\x12$\n" +
+	"\aentries\x18\x01 \x03(\v2\n" +
+	".LootEntryR\aentries\x12%\n" +
+	"\x0epity_threshold\x18\x02 \x01(\x05R\rpityThreshold\"P\n" +
+	"\tLootEntry\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x05R\x06weight\x12\x12\n" +
+	"\x04rare\x18\x03 \x01(\bR\x04rare\"F\n" +
+	"\x0fBundleComponent\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"@\n" +
+	"\x0fReserveItemsReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\"#\n" +
+	"\x0fReserveItemsRes\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"@\n" +
+	"\x0fReleaseItemsReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\"#\n" +
+	"\x0fReleaseItemsRes\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\x8e\x02\n" +
+	"\x06Recipe\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12(\n" +
+	"\x06inputs\x18\x03 \x03(\v2\x10.BundleComponentR\x06inputs\x12$\n" +
+	"\x0eoutput_item_id\x18\x04 \x01(\tR\foutputItemId\x12'\n" +
+	"\x0foutput_quantity\x18\x05 \x01(\x05R\x0eoutputQuantity\x12\x12\n" +
+	"\x04cost\x18\x06 \x01(\x01R\x04cost\x12!\n" +
+	"\fsuccess_rate\x18\a \x01(\x01R\vsuccessRate\x12.\n" +
+	"\x13consolation_item_id\x18\b \x01(\tR\x11consolationItemId\"/\n" +
+	"\x10FindOneRecipeReq\x12\x1b\n" +
+	"\trecipe_id\x18\x01 \x01(\tR\brecipeId2\xe0\x01\n" +
 	"\x0fItemGrpcService\x128\n" +
-	"\x0eFindItemsInIds\x12\x12.FindItemsInIdsReq\x1a\x12.FindItemsInIdsResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+	"\x0eFindItemsInIds\x12\x12.FindItemsInIdsReq\x1a\x12.FindItemsInIdsRes\x122\n" +
+	"\fReserveItems\x12\x10.ReserveItemsReq\x1a\x10.ReserveItemsRes\x122\n" +
+	"\fReleaseItems\x12\x10.ReleaseItemsReq\x1a\x10.ReleaseItemsRes\x12+\n" +
+	"\rFindOneRecipe\x12\x11.FindOneRecipeReq\x1a\a.RecipeB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
 
 var (
 	file_modules_item_itemPb_itemPb_proto_rawDescOnce sync.Once
@@ -218,21 +921,42 @@ func file_modules_item_itemPb_itemPb_proto_rawDescGZIP() []byte {
 	return file_modules_item_itemPb_itemPb_proto_rawDescData
 }
 
-var file_modules_item_itemPb_itemPb_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_modules_item_itemPb_itemPb_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_modules_item_itemPb_itemPb_proto_goTypes = []any{
 	(*FindItemsInIdsReq)(nil), // 0: FindItemsInIdsReq
 	(*FindItemsInIdsRes)(nil), // 1: FindItemsInIdsRes
 	(*Item)(nil),              // 2: Item
+	(*ItemEffect)(nil),        // 3: ItemEffect
+	(*LootTable)(nil),         // 4: LootTable
+	(*LootEntry)(nil),         // 5: LootEntry
+	(*BundleComponent)(nil),   // 6: BundleComponent
+	(*ReserveItemsReq)(nil),   // 7: ReserveItemsReq
+	(*ReserveItemsRes)(nil),   // 8: ReserveItemsRes
+	(*ReleaseItemsReq)(nil),   // 9: ReleaseItemsReq
+	(*ReleaseItemsRes)(nil),   // 10: ReleaseItemsRes
+	(*Recipe)(nil),            // 11: Recipe
+	(*FindOneRecipeReq)(nil),  // 12: FindOneRecipeReq
 }
 var file_modules_item_itemPb_itemPb_proto_depIdxs = []int32{
-	2, // 0: FindItemsInIdsRes.items:type_name -> Item
-	0, // 1: ItemGrpcService.FindItemsInIds:input_type -> FindItemsInIdsReq
-	1, // 2: ItemGrpcService.FindItemsInIds:output_type -> FindItemsInIdsRes
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2,  // 0: FindItemsInIdsRes.items:type_name -> Item
+	6,  // 1: Item.components:type_name -> BundleComponent
+	3,  // 2: Item.effect:type_name -> ItemEffect
+	4,  // 3: Item.loot_table:type_name -> LootTable
+	5,  // 4: LootTable.entries:type_name -> LootEntry
+	6,  // 5: Recipe.inputs:type_name -> BundleComponent
+	0,  // 6: ItemGrpcService.FindItemsInIds:input_type -> FindItemsInIdsReq
+	7,  // 7: ItemGrpcService.ReserveItems:input_type -> ReserveItemsReq
+	9,  // 8: ItemGrpcService.ReleaseItems:input_type -> ReleaseItemsReq
+	12, // 9: ItemGrpcService.FindOneRecipe:input_type -> FindOneRecipeReq
+	1,  // 10: ItemGrpcService.FindItemsInIds:output_type -> FindItemsInIdsRes
+	8,  // 11: ItemGrpcService.ReserveItems:output_type -> ReserveItemsRes
+	10, // 12: ItemGrpcService.ReleaseItems:output_type -> ReleaseItemsRes
+	11, // 13: ItemGrpcService.FindOneRecipe:output_type -> Recipe
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_modules_item_itemPb_itemPb_proto_init() }
@@ -240,13 +964,14 @@ func file_modules_item_itemPb_itemPb_proto_init() {
 	if File_modules_item_itemPb_itemPb_proto != nil {
 		return
 	}
+	file_modules_item_itemPb_itemPb_proto_msgTypes[2].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_item_itemPb_itemPb_proto_rawDesc), len(file_modules_item_itemPb_itemPb_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
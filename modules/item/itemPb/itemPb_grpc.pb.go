@@ -22,6 +22,9 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	ItemGrpcService_FindItemsInIds_FullMethodName = "/ItemGrpcService/FindItemsInIds"
+	ItemGrpcService_ReserveItems_FullMethodName   = "/ItemGrpcService/ReserveItems"
+	ItemGrpcService_ReleaseItems_FullMethodName   = "/ItemGrpcService/ReleaseItems"
+	ItemGrpcService_FindOneRecipe_FullMethodName  = "/ItemGrpcService/FindOneRecipe"
 )
 
 // ItemGrpcServiceClient is the client API for ItemGrpcService service.
@@ -31,6 +34,9 @@ const (
 // Methods
 type ItemGrpcServiceClient interface {
 	FindItemsInIds(ctx context.Context, in *FindItemsInIdsReq, opts ...grpc.CallOption) (*FindItemsInIdsRes, error)
+	ReserveItems(ctx context.Context, in *ReserveItemsReq, opts ...grpc.CallOption) (*ReserveItemsRes, error)
+	ReleaseItems(ctx context.Context, in *ReleaseItemsReq, opts ...grpc.CallOption) (*ReleaseItemsRes, error)
+	FindOneRecipe(ctx context.Context, in *FindOneRecipeReq, opts ...grpc.CallOption) (*Recipe, error)
 }
 
 type itemGrpcServiceClient struct {
@@ -51,6 +57,36 @@ func (c *itemGrpcServiceClient) FindItemsInIds(ctx context.Context, in *FindItem
 	return out, nil
 }
 
+func (c *itemGrpcServiceClient) ReserveItems(ctx context.Context, in *ReserveItemsReq, opts ...grpc.CallOption) (*ReserveItemsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveItemsRes)
+	err := c.cc.Invoke(ctx, ItemGrpcService_ReserveItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemGrpcServiceClient) ReleaseItems(ctx context.Context, in *ReleaseItemsReq, opts ...grpc.CallOption) (*ReleaseItemsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseItemsRes)
+	err := c.cc.Invoke(ctx, ItemGrpcService_ReleaseItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemGrpcServiceClient) FindOneRecipe(ctx context.Context, in *FindOneRecipeReq, opts ...grpc.CallOption) (*Recipe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Recipe)
+	err := c.cc.Invoke(ctx, ItemGrpcService_FindOneRecipe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ItemGrpcServiceServer is the server API for ItemGrpcService service.
 // All implementations must embed UnimplementedItemGrpcServiceServer
 // for forward compatibility.
@@ -58,6 +94,9 @@ func (c *itemGrpcServiceClient) FindItemsInIds(ctx context.Context, in *FindItem
 // Methods
 type ItemGrpcServiceServer interface {
 	FindItemsInIds(context.Context, *FindItemsInIdsReq) (*FindItemsInIdsRes, error)
+	ReserveItems(context.Context, *ReserveItemsReq) (*ReserveItemsRes, error)
+	ReleaseItems(context.Context, *ReleaseItemsReq) (*ReleaseItemsRes, error)
+	FindOneRecipe(context.Context, *FindOneRecipeReq) (*Recipe, error)
 	mustEmbedUnimplementedItemGrpcServiceServer()
 }
 
@@ -71,6 +110,15 @@ type UnimplementedItemGrpcServiceServer struct{}
 func (UnimplementedItemGrpcServiceServer) FindItemsInIds(context.Context, *FindItemsInIdsReq) (*FindItemsInIdsRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindItemsInIds not implemented")
 }
+func (UnimplementedItemGrpcServiceServer) ReserveItems(context.Context, *ReserveItemsReq) (*ReserveItemsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveItems not implemented")
+}
+func (UnimplementedItemGrpcServiceServer) ReleaseItems(context.Context, *ReleaseItemsReq) (*ReleaseItemsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseItems not implemented")
+}
+func (UnimplementedItemGrpcServiceServer) FindOneRecipe(context.Context, *FindOneRecipeReq) (*Recipe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindOneRecipe not implemented")
+}
 func (UnimplementedItemGrpcServiceServer) mustEmbedUnimplementedItemGrpcServiceServer() {}
 func (UnimplementedItemGrpcServiceServer) testEmbeddedByValue()                         {}
 
@@ -110,6 +158,60 @@ func _ItemGrpcService_FindItemsInIds_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ItemGrpcService_ReserveItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveItemsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemGrpcServiceServer).ReserveItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemGrpcService_ReserveItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemGrpcServiceServer).ReserveItems(ctx, req.(*ReserveItemsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemGrpcService_ReleaseItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseItemsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemGrpcServiceServer).ReleaseItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemGrpcService_ReleaseItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemGrpcServiceServer).ReleaseItems(ctx, req.(*ReleaseItemsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemGrpcService_FindOneRecipe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindOneRecipeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemGrpcServiceServer).FindOneRecipe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemGrpcService_FindOneRecipe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemGrpcServiceServer).FindOneRecipe(ctx, req.(*FindOneRecipeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ItemGrpcService_ServiceDesc is the grpc.ServiceDesc for ItemGrpcService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,6 +223,18 @@ var ItemGrpcService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindItemsInIds",
 			Handler:    _ItemGrpcService_FindItemsInIds_Handler,
 		},
+		{
+			MethodName: "ReserveItems",
+			Handler:    _ItemGrpcService_ReserveItems_Handler,
+		},
+		{
+			MethodName: "ReleaseItems",
+			Handler:    _ItemGrpcService_ReleaseItems_Handler,
+		},
+		{
+			MethodName: "FindOneRecipe",
+			Handler:    _ItemGrpcService_FindOneRecipe_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "modules/item/itemPb/itemPb.proto",
@@ -3,7 +3,10 @@ package itemRepository
 import (
 	"context"
 
+	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -32,6 +35,21 @@ func (m *ItemRepositoryMock) FindOneItem(pctx context.Context, itemId string) (*
 	return args.Get(0).(*item.Item), args.Error(1)
 }
 
+func (m *ItemRepositoryMock) IsUniqueSku(pctx context.Context, sku string) bool {
+	args := m.Called(pctx, sku)
+	return args.Bool(0)
+}
+
+func (m *ItemRepositoryMock) FindOneItemBySku(pctx context.Context, sku string) (*item.Item, error) {
+	args := m.Called(pctx, sku)
+	return args.Get(0).(*item.Item), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindManyItemDocuments(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.Item, error) {
+	args := m.Called(pctx, filter, opts)
+	return args.Get(0).([]*item.Item), args.Error(1)
+}
+
 func (m *ItemRepositoryMock) FindManyItems(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemShowCase, error) {
 	args := m.Called(pctx, filter, opts)
 	return args.Get(0).([]*item.ItemShowCase), args.Error(1)
@@ -51,3 +69,93 @@ func (m *ItemRepositoryMock) UpdateOneItemUsageStatus(pctx context.Context, item
 	args := m.Called(pctx, itemId, usageStatus)
 	return args.Error(0)
 }
+
+func (m *ItemRepositoryMock) InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindItemRevisions(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemRevision, error) {
+	args := m.Called(pctx, filter, opts)
+	return args.Get(0).([]*item.ItemRevision), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) ReserveItemStock(pctx context.Context, itemId string) error {
+	args := m.Called(pctx, itemId)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) ReleaseItemStock(pctx context.Context, itemId string) error {
+	args := m.Called(pctx, itemId)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) ReservePlayerPurchase(pctx context.Context, itemId, playerId string, limit int) error {
+	args := m.Called(pctx, itemId, playerId, limit)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) ReleasePlayerPurchase(pctx context.Context, itemId, playerId string) error {
+	args := m.Called(pctx, itemId, playerId)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) InsertOneRecipe(pctx context.Context, req *item.Recipe) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindOneRecipe(pctx context.Context, recipeId string) (*item.Recipe, error) {
+	args := m.Called(pctx, recipeId)
+	return args.Get(0).(*item.Recipe), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindManyRecipes(pctx context.Context, filter bson.D) ([]*item.Recipe, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*item.Recipe), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*subscriptionPb.EntitlementsRes), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) InsertOneWishlistItem(pctx context.Context, req *item.WishlistItem) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindWishlistItems(pctx context.Context, filter bson.D) ([]*item.WishlistItem, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*item.WishlistItem), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) DeleteOneWishlistItem(pctx context.Context, playerId, itemId string) error {
+	args := m.Called(pctx, playerId, itemId)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) InsertOneWishlistNotification(pctx context.Context, req *item.WishlistNotification) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) FindWishlistNotifications(pctx context.Context, playerId string) ([]*item.WishlistNotification, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).([]*item.WishlistNotification), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) GetWishlistWatch(pctx context.Context) (bson.ObjectID, error) {
+	args := m.Called(pctx)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *ItemRepositoryMock) UpsertWishlistWatch(pctx context.Context, revisionId bson.ObjectID) error {
+	args := m.Called(pctx, revisionId)
+	return args.Error(0)
+}
+
+func (m *ItemRepositoryMock) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	args := m.Called(pctx, cfg, req)
+	return args.Error(0)
+}
@@ -2,26 +2,55 @@ package itemRepository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"time"
 
+	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
 )
 
 type (
 	ItemRepositoryService interface {
 		IsUniqueItem(pctx context.Context, title string) bool
+		IsUniqueSku(pctx context.Context, sku string) bool
 		InsertOneItem(pctx context.Context, req *item.Item) (bson.ObjectID, error)
 		FindOneItem(pctx context.Context, itemId string) (*item.Item, error)
+		FindOneItemBySku(pctx context.Context, sku string) (*item.Item, error)
 		FindManyItems(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemShowCase, error)
+		FindManyItemDocuments(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.Item, error)
 		CountItems(pctx context.Context, filter bson.D) (int64, error)
 		UpdateOneItem(pctx context.Context, itemId string, req bson.M) error
 		UpdateOneItemUsageStatus(pctx context.Context, itemId string, usageStatus bool) error
+		InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) (bson.ObjectID, error)
+		FindItemRevisions(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemRevision, error)
+		ReserveItemStock(pctx context.Context, itemId string) error
+		ReleaseItemStock(pctx context.Context, itemId string) error
+		ReservePlayerPurchase(pctx context.Context, itemId, playerId string, limit int) error
+		ReleasePlayerPurchase(pctx context.Context, itemId, playerId string) error
+		InsertOneRecipe(pctx context.Context, req *item.Recipe) (bson.ObjectID, error)
+		FindOneRecipe(pctx context.Context, recipeId string) (*item.Recipe, error)
+		FindManyRecipes(pctx context.Context, filter bson.D) ([]*item.Recipe, error)
+		FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error)
+		InsertOneWishlistItem(pctx context.Context, req *item.WishlistItem) (bson.ObjectID, error)
+		FindWishlistItems(pctx context.Context, filter bson.D) ([]*item.WishlistItem, error)
+		DeleteOneWishlistItem(pctx context.Context, playerId, itemId string) error
+		InsertOneWishlistNotification(pctx context.Context, req *item.WishlistNotification) (bson.ObjectID, error)
+		FindWishlistNotifications(pctx context.Context, playerId string) ([]*item.WishlistNotification, error)
+		GetWishlistWatch(pctx context.Context) (bson.ObjectID, error)
+		UpsertWishlistWatch(pctx context.Context, revisionId bson.ObjectID) error
+		PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error
 	}
 
 	itemRepository struct {
@@ -52,6 +81,21 @@ func (r *itemRepository) IsUniqueItem(pctx context.Context, title string) bool {
 	return false
 }
 
+func (r *itemRepository) IsUniqueSku(pctx context.Context, sku string) bool {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	item := new(item.Item)
+	if err := col.FindOne(ctx, bson.M{"sku": sku}).Decode(item); err != nil {
+		log.Printf("error: is unique sku: %v", err.Error())
+		return true
+	}
+	return false
+}
+
 func (r *itemRepository) InsertOneItem(pctx context.Context, req *item.Item) (bson.ObjectID, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -84,6 +128,22 @@ func (r *itemRepository) FindOneItem(pctx context.Context, itemId string) (*item
 	return result, nil
 }
 
+func (r *itemRepository) FindOneItemBySku(pctx context.Context, sku string) (*item.Item, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	result := new(item.Item)
+	if err := col.FindOne(ctx, bson.M{"sku": sku}).Decode(result); err != nil {
+		log.Printf("error: find one item by sku: %v", err.Error())
+		return nil, errors.New("error: item not found")
+	}
+
+	return result, nil
+}
+
 func (r *itemRepository) FindManyItems(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemShowCase, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -107,17 +167,55 @@ func (r *itemRepository) FindManyItems(pctx context.Context, filter bson.D, opts
 		}
 
 		results = append(results, &item.ItemShowCase{
-			ItemId:   "item:" + result.Id.Hex(),
-			Title:    result.Title,
-			Price:    result.Price,
-			ImageUrl: result.ImageUrl,
-			Damage:   result.Damage,
+			ItemId:          "item:" + result.Id.Hex(),
+			Sku:             result.Sku,
+			Title:           result.Title,
+			Price:           result.Price,
+			ImageUrl:        result.ImageUrl,
+			Damage:          result.Damage,
+			Slot:            result.Slot,
+			LevelRequired:   result.LevelRequired,
+			Components:      result.Components,
+			Stock:           result.Stock,
+			PurchaseLimit:   result.PurchaseLimit,
+			Effect:          result.Effect,
+			CooldownSeconds: result.CooldownSeconds,
+			DurationHours:   result.DurationHours,
+			LootTable:       result.LootTable,
 		})
 	}
 
 	return results, nil
 }
 
+func (r *itemRepository) FindManyItemDocuments(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.Item, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("error: find many item documents: %v", err.Error())
+		return nil, errors.New("error: find many items failed")
+	}
+
+	results := make([]*item.Item, 0)
+
+	for cursors.Next(ctx) {
+		result := new(item.Item)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode item document: %v", err.Error())
+			return nil, errors.New("error: decode item failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 func (r *itemRepository) CountItems(pctx context.Context, filter bson.D) (int64, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -169,3 +267,383 @@ func (r *itemRepository) UpdateOneItemUsageStatus(pctx context.Context, itemId s
 
 	return nil
 }
+
+func (r *itemRepository) InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one item revision: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one item revision failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *itemRepository) FindItemRevisions(pctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]*item.ItemRevision, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("error: find item revisions: %v", err.Error())
+		return make([]*item.ItemRevision, 0), errors.New("error: find item revisions failed")
+	}
+
+	results := make([]*item.ItemRevision, 0)
+
+	for cursors.Next(ctx) {
+		result := new(item.ItemRevision)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode item revision: %v", err.Error())
+			return make([]*item.ItemRevision, 0), errors.New("error: decode item revision failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) ReserveItemStock(pctx context.Context, itemId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(itemId), "stock": bson.M{"$gte": 1}},
+		bson.M{"$inc": bson.M{"stock": -1}},
+	)
+	if err != nil {
+		log.Printf("error: reserve item stock: %v", err.Error())
+		return errors.New("error: reserve item stock failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		log.Printf("error: reserve item stock: item %s is out of stock", itemId)
+		return errors.New("error: item is out of stock")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) ReleaseItemStock(pctx context.Context, itemId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(itemId), "stock": bson.M{"$ne": nil}},
+		bson.M{"$inc": bson.M{"stock": 1}},
+	)
+	if err != nil {
+		log.Printf("error: release item stock: %v", err.Error())
+		return errors.New("error: release item stock failed")
+	}
+
+	log.Printf("ReleaseItemStock: %v", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *itemRepository) ReservePlayerPurchase(pctx context.Context, itemId, playerId string, limit int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_purchases")
+
+	// When the player is already at the limit the filter misses and the upsert
+	// collides with the unique (item_id, player_id) index.
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"item_id": itemId, "player_id": playerId, "count": bson.M{"$lt": limit}},
+		bson.M{"$inc": bson.M{"count": 1}},
+		options.UpdateOne().SetUpsert(true),
+	); err != nil {
+		log.Printf("error: reserve player purchase: %v", err.Error())
+		return errors.New("error: item purchase limit reached")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) ReleasePlayerPurchase(pctx context.Context, itemId, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_purchases")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"item_id": itemId, "player_id": playerId, "count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"count": -1}},
+	)
+	if err != nil {
+		log.Printf("error: release player purchase: %v", err.Error())
+		return errors.New("error: release player purchase failed")
+	}
+
+	log.Printf("ReleasePlayerPurchase: %v", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *itemRepository) InsertOneRecipe(pctx context.Context, req *item.Recipe) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("recipes")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one recipe: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one recipe failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *itemRepository) FindOneRecipe(pctx context.Context, recipeId string) (*item.Recipe, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("recipes")
+
+	result := new(item.Recipe)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(recipeId)}).Decode(result); err != nil {
+		log.Printf("error: find one recipe: %v", err.Error())
+		return nil, errors.New("error: recipe not found")
+	}
+
+	return result, nil
+}
+
+func (r *itemRepository) FindManyRecipes(pctx context.Context, filter bson.D) ([]*item.Recipe, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("recipes")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "title", Value: 1}}))
+	if err != nil {
+		log.Printf("error: find many recipes: %v", err.Error())
+		return nil, errors.New("error: find many recipes failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*item.Recipe, 0)
+	for cursors.Next(ctx) {
+		result := new(item.Recipe)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode recipe: %v", err.Error())
+			return nil, errors.New("error: decode recipe failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Subscription().FindEntitlements(ctx, req)
+	if err != nil {
+		log.Printf("error: find entitlements failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *itemRepository) InsertOneWishlistItem(pctx context.Context, req *item.WishlistItem) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlists")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: item already in wishlist")
+		}
+		log.Printf("error: insert one wishlist item: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one wishlist item failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *itemRepository) FindWishlistItems(pctx context.Context, filter bson.D) ([]*item.WishlistItem, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlists")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("error: find wishlist items: %v", err.Error())
+		return nil, errors.New("error: find wishlist items failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*item.WishlistItem, 0)
+	for cursors.Next(ctx) {
+		result := new(item.WishlistItem)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode wishlist item: %v", err.Error())
+			return nil, errors.New("error: decode wishlist item failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) DeleteOneWishlistItem(pctx context.Context, playerId, itemId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlists")
+
+	result, err := col.DeleteOne(ctx, bson.M{"player_id": playerId, "item_id": itemId})
+	if err != nil {
+		log.Printf("error: delete one wishlist item: %v", err.Error())
+		return errors.New("error: delete one wishlist item failed")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("error: item not in wishlist")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) InsertOneWishlistNotification(pctx context.Context, req *item.WishlistNotification) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlist_notifications")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: player already notified")
+		}
+		log.Printf("error: insert one wishlist notification: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one wishlist notification failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *itemRepository) FindWishlistNotifications(pctx context.Context, playerId string) ([]*item.WishlistNotification, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlist_notifications")
+
+	cursors, err := col.Find(ctx, bson.M{"player_id": playerId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100))
+	if err != nil {
+		log.Printf("error: find wishlist notifications: %v", err.Error())
+		return nil, errors.New("error: find wishlist notifications failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*item.WishlistNotification, 0)
+	for cursors.Next(ctx) {
+		result := new(item.WishlistNotification)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode wishlist notification: %v", err.Error())
+			return nil, errors.New("error: decode wishlist notification failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetWishlistWatch returns bson.NilObjectID before the watcher's first run.
+func (r *itemRepository) GetWishlistWatch(pctx context.Context) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlist_watch")
+
+	result := new(item.WishlistWatch)
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return bson.NilObjectID, nil
+		}
+		log.Printf("error: get wishlist watch: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: get wishlist watch failed")
+	}
+
+	return result.RevisionId, nil
+}
+
+func (r *itemRepository) UpsertWishlistWatch(pctx context.Context, revisionId bson.ObjectID) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("wishlist_watch")
+
+	if _, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"revision_id": revisionId}}, options.UpdateOne().SetUpsert(true)); err != nil {
+		log.Printf("error: upsert wishlist watch: %v", err.Error())
+		return errors.New("error: upsert wishlist watch failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "events", req.Type, reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package itemUsecase
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/modules/item"
+)
+
+var itemCatalogHeader = []string{
+	"sku", "title", "price", "damage", "image_url", "usage_status", "stock", "purchase_limit",
+	"slot", "level_required", "effect_type", "effect_value", "effect_duration_seconds", "cooldown_seconds", "duration_hours",
+}
+
+func readItemCatalog(format string, r io.Reader) ([]*item.ItemCatalogRow, error) {
+	switch format {
+	case "json":
+		rows := make([]*item.ItemCatalogRow, 0)
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("error: invalid json catalog: %s", err.Error())
+		}
+		return rows, nil
+	case "csv":
+		return readItemCatalogCsv(r)
+	}
+
+	return nil, errors.New("error: unsupported catalog format")
+}
+
+// readItemCatalogCsv maps columns by header name, so designers may reorder
+// them or leave optional ones out.
+func readItemCatalogCsv(r io.Reader) ([]*item.ItemCatalogRow, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error: invalid csv catalog: %s", err.Error())
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("error: csv catalog has no header")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isItemCatalogColumn(name) {
+			return nil, fmt.Errorf("error: unknown csv column: %s", name)
+		}
+		columns[name] = i
+	}
+
+	rows := make([]*item.ItemCatalogRow, 0)
+	for n, record := range records[1:] {
+		get := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := &item.ItemCatalogRow{
+			Sku:      get("sku"),
+			Title:    get("title"),
+			ImageUrl: get("image_url"),
+			Slot:     get("slot"),
+		}
+
+		// Line 1 is the header
+		line := n + 2
+
+		if v := get("price"); v != "" {
+			if row.Price, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("error: line %d: invalid price", line)
+			}
+		}
+
+		if v := get("damage"); v != "" {
+			if row.Damage, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("error: line %d: invalid damage", line)
+			}
+		}
+
+		if v := get("usage_status"); v != "" {
+			usageStatus, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("error: line %d: invalid usage_status", line)
+			}
+			row.UsageStatus = &usageStatus
+		}
+
+		if v := get("stock"); v != "" {
+			stock, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error: line %d: invalid stock", line)
+			}
+			row.Stock = &stock
+		}
+
+		if v := get("purchase_limit"); v != "" {
+			if row.PurchaseLimit, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("error: line %d: invalid purchase_limit", line)
+			}
+		}
+
+		for name, field := range map[string]*int{
+			"level_required":   &row.LevelRequired,
+			"cooldown_seconds": &row.CooldownSeconds,
+			"duration_hours":   &row.DurationHours,
+		} {
+			if v := get(name); v != "" {
+				if *field, err = strconv.Atoi(v); err != nil {
+					return nil, fmt.Errorf("error: line %d: invalid %s", line, name)
+				}
+			}
+		}
+
+		// An effect is present when it has a type
+		if v := get("effect_type"); v != "" {
+			row.Effect = &item.ItemEffectReq{Type: v}
+			if v := get("effect_value"); v != "" {
+				if row.Effect.Value, err = strconv.ParseFloat(v, 64); err != nil {
+					return nil, fmt.Errorf("error: line %d: invalid effect_value", line)
+				}
+			}
+			if v := get("effect_duration_seconds"); v != "" {
+				if row.Effect.DurationSeconds, err = strconv.Atoi(v); err != nil {
+					return nil, fmt.Errorf("error: line %d: invalid effect_duration_seconds", line)
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func isItemCatalogColumn(name string) bool {
+	for _, column := range itemCatalogHeader {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeItemCatalog(format string, w io.Writer, rows []*item.ItemCatalogRow) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(itemCatalogHeader); err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			record := []string{
+				row.Sku,
+				row.Title,
+				strconv.FormatFloat(row.Price, 'f', -1, 64),
+				strconv.Itoa(row.Damage),
+				row.ImageUrl,
+				"true",
+				"",
+				strconv.Itoa(row.PurchaseLimit),
+				row.Slot,
+				strconv.Itoa(row.LevelRequired),
+				"",
+				"",
+				"",
+				strconv.Itoa(row.CooldownSeconds),
+				strconv.Itoa(row.DurationHours),
+			}
+			if row.UsageStatus != nil {
+				record[5] = strconv.FormatBool(*row.UsageStatus)
+			}
+			if row.Stock != nil {
+				record[6] = strconv.FormatInt(*row.Stock, 10)
+			}
+			if row.Effect != nil {
+				record[10] = row.Effect.Type
+				record[11] = strconv.FormatFloat(row.Effect.Value, 'f', -1, 64)
+				record[12] = strconv.Itoa(row.Effect.DurationSeconds)
+			}
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return errors.New("error: unsupported catalog format")
+}
+
+var (
+	skuPattern      = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{0,63}$`)
+	skuInvalidChars = regexp.MustCompile(`[^A-Z0-9_-]`)
+)
+
+// normalizeSku upper-cases a SKU and joins words with dashes, so
+// "iron sword" and "IRON-SWORD" name the same item.
+func normalizeSku(sku string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(sku), "-"))
+}
+
+// skuFromTitle derives a SKU for items created without one.
+func skuFromTitle(title string) string {
+	sku := skuInvalidChars.ReplaceAllString(normalizeSku(title), "")
+	if len(sku) > 64 {
+		sku = sku[:64]
+	}
+	return sku
+}
+
+func isValidSku(sku string) bool {
+	return skuPattern.MatchString(sku)
+}
@@ -4,13 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/item/itemRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/models"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -18,11 +26,30 @@ import (
 type (
 	ItemUsecaseService interface {
 		CreateItem(pctx context.Context, req *item.CreateItemReq) (*item.ItemShowCase, error)
+		CreateBundle(pctx context.Context, req *item.CreateBundleReq) (*item.ItemShowCase, error)
+		CreateLootBox(pctx context.Context, req *item.CreateLootBoxReq) (*item.ItemShowCase, error)
+		FindLootBoxOdds(pctx context.Context, itemId string) (*item.LootBoxOddsRes, error)
 		FindOneItem(pctx context.Context, itemId string) (*item.ItemShowCase, error)
 		FindManyItems(pctx context.Context, req *item.ItemSearchReq, basePaginateUrl string) (*models.PaginateRes, error)
-		EditItem(pctx context.Context, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error)
-		ToggleItemUsageStatus(pctx context.Context, itemId string) (bool, error)
+		EditItem(pctx context.Context, itemId, playerId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error)
+		ToggleItemUsageStatus(pctx context.Context, itemId, playerId string) (bool, error)
+		FindItemHistory(pctx context.Context, itemId string) ([]*item.ItemRevision, error)
+		FindItemPriceAt(pctx context.Context, itemId string, at time.Time) (*item.ItemPriceAtRes, error)
+		FindItemPriceFor(pctx context.Context, cfg *config.Config, itemId, playerId string) (*item.ItemPlayerPriceRes, error)
+		ImportItems(pctx context.Context, playerId string, req *item.ItemImportReq, data io.Reader) (*item.ItemImportRes, error)
+		ExportItems(pctx context.Context, req *item.ItemExportReq, w io.Writer) error
 		FindItemsInIds(pctx context.Context, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		ReserveItems(pctx context.Context, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error)
+		ReleaseItems(pctx context.Context, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error)
+		CreateRecipe(pctx context.Context, req *item.CreateRecipeReq) (*item.RecipeShowCase, error)
+		FindOneRecipe(pctx context.Context, recipeId string) (*item.RecipeShowCase, error)
+		FindManyRecipes(pctx context.Context) ([]*item.RecipeShowCase, error)
+		FindOneRecipePb(pctx context.Context, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error)
+		AddWishlistItem(pctx context.Context, playerId string, req *item.AddWishlistItemReq) (*item.WishlistItem, error)
+		FindWishlist(pctx context.Context, playerId string) ([]*item.WishlistItemRes, error)
+		RemoveWishlistItem(pctx context.Context, playerId, itemId string) error
+		FindWishlistNotifications(pctx context.Context, playerId string) ([]*item.WishlistNotification, error)
+		WatchWishlists(pctx context.Context, cfg *config.Config) error
 	}
 
 	itemUsecase struct {
@@ -39,12 +66,167 @@ func (u *itemUsecase) CreateItem(pctx context.Context, req *item.CreateItemReq)
 		return nil, errors.New("error: item already exists")
 	}
 
+	sku, err := u.newItemSku(pctx, req.Sku, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	itemId, err := u.itemRepository.InsertOneItem(pctx, &item.Item{
+		Sku:             sku,
+		Title:           req.Title,
+		Price:           req.Price,
+		Damage:          req.Damage,
+		Slot:            req.Slot,
+		LevelRequired:   req.LevelRequired,
+		Effect:          itemEffect(req.Effect),
+		CooldownSeconds: req.CooldownSeconds,
+		DurationHours:   req.DurationHours,
+		UsageStatus:     true,
+		ImageUrl:        req.ImageUrl,
+		Stock:           req.Stock,
+		PurchaseLimit:   req.PurchaseLimit,
+		CreatedAt:       utils.LocalTime(),
+		UpdatedAt:       utils.LocalTime(),
+	})
+	if err != nil {
+		return nil, errors.New("error: insert one item failed")
+	}
+
+	return u.FindOneItem(pctx, itemId.Hex())
+}
+
+func (u *itemUsecase) CreateBundle(pctx context.Context, req *item.CreateBundleReq) (*item.ItemShowCase, error) {
+	if !u.itemRepository.IsUniqueItem(pctx, req.Title) {
+		return nil, errors.New("error: item already exists")
+	}
+
+	sku, err := u.newItemSku(pctx, req.Sku, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]*item.BundleComponent, 0)
+	setIds := make(map[string]bool)
+	for _, c := range req.Components {
+		itemId := "item:" + strings.TrimPrefix(c.ItemId, "item:")
+		if setIds[itemId] {
+			return nil, errors.New("error: duplicate bundle component")
+		}
+		setIds[itemId] = true
+
+		components = append(components, &item.BundleComponent{
+			ItemId:   itemId,
+			Quantity: c.Quantity,
+		})
+	}
+
+	filter := bson.D{}
+
+	objectIds := make([]bson.ObjectID, 0)
+	for itemId := range setIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:")))
+	}
+
+	filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}})
+	filter = append(filter, bson.E{Key: "usage_status", Value: true})
+
+	results, err := u.itemRepository.FindManyItems(pctx, filter)
+	if err != nil {
+		return nil, errors.New("error: find many items failed")
+	}
+
+	if len(results) != len(setIds) {
+		return nil, errors.New("error: bundle component not found")
+	}
+
+	for _, result := range results {
+		if len(result.Components) > 0 {
+			return nil, errors.New("error: bundle cannot contain another bundle")
+		}
+	}
+
+	itemId, err := u.itemRepository.InsertOneItem(pctx, &item.Item{
+		Sku:         sku,
+		Title:       req.Title,
+		Price:       req.Price,
+		UsageStatus: true,
+		ImageUrl:    req.ImageUrl,
+		Components:  components,
+		CreatedAt:   utils.LocalTime(),
+		UpdatedAt:   utils.LocalTime(),
+	})
+	if err != nil {
+		return nil, errors.New("error: insert one item failed")
+	}
+
+	return u.FindOneItem(pctx, itemId.Hex())
+}
+
+func (u *itemUsecase) CreateLootBox(pctx context.Context, req *item.CreateLootBoxReq) (*item.ItemShowCase, error) {
+	if !u.itemRepository.IsUniqueItem(pctx, req.Title) {
+		return nil, errors.New("error: item already exists")
+	}
+
+	sku, err := u.newItemSku(pctx, req.Sku, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &item.LootTable{
+		Entries:       make([]*item.LootEntry, 0),
+		PityThreshold: req.PityThreshold,
+	}
+	setIds := make(map[string]bool)
+	hasRare := false
+	for _, v := range req.Entries {
+		itemId := "item:" + strings.TrimPrefix(v.ItemId, "item:")
+		if setIds[itemId] {
+			return nil, errors.New("error: duplicate loot entry")
+		}
+		setIds[itemId] = true
+		hasRare = hasRare || v.Rare
+
+		table.Entries = append(table.Entries, &item.LootEntry{
+			ItemId: itemId,
+			Weight: v.Weight,
+			Rare:   v.Rare,
+		})
+	}
+
+	if table.PityThreshold > 0 && !hasRare {
+		return nil, errors.New("error: pity needs at least one rare entry")
+	}
+
+	objectIds := make([]bson.ObjectID, 0)
+	for itemId := range setIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:")))
+	}
+
+	results, err := u.itemRepository.FindManyItems(pctx, bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}},
+		{Key: "usage_status", Value: true},
+	})
+	if err != nil {
+		return nil, errors.New("error: find many items failed")
+	}
+
+	if len(results) != len(setIds) {
+		return nil, errors.New("error: loot entry not found")
+	}
+
+	for _, result := range results {
+		if len(result.Components) > 0 || result.LootTable != nil {
+			return nil, errors.New("error: loot box cannot drop a bundle or another loot box")
+		}
+	}
+
 	itemId, err := u.itemRepository.InsertOneItem(pctx, &item.Item{
+		Sku:         sku,
 		Title:       req.Title,
 		Price:       req.Price,
-		Damage:      req.Damage,
 		UsageStatus: true,
 		ImageUrl:    req.ImageUrl,
+		LootTable:   table,
 		CreatedAt:   utils.LocalTime(),
 		UpdatedAt:   utils.LocalTime(),
 	})
@@ -55,21 +237,118 @@ func (u *itemUsecase) CreateItem(pctx context.Context, req *item.CreateItemReq)
 	return u.FindOneItem(pctx, itemId.Hex())
 }
 
+// FindLootBoxOdds returns the published chance of every entry of a loot box.
+func (u *itemUsecase) FindLootBoxOdds(pctx context.Context, itemId string) (*item.LootBoxOddsRes, error) {
+	result, err := u.findItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.LootTable == nil {
+		return nil, errors.New("error: item is not a loot box")
+	}
+
+	objectIds := make([]bson.ObjectID, 0)
+	totalWeight := 0
+	for _, v := range result.LootTable.Entries {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(v.ItemId, "item:")))
+		totalWeight += v.Weight
+	}
+
+	entries, err := u.itemRepository.FindManyItems(pctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}}})
+	if err != nil {
+		return nil, errors.New("error: find many items failed")
+	}
+
+	titles := make(map[string]string)
+	for _, v := range entries {
+		titles[v.ItemId] = v.Title
+	}
+
+	res := &item.LootBoxOddsRes{
+		ItemId:        "item:" + result.Id.Hex(),
+		Title:         result.Title,
+		PityThreshold: result.LootTable.PityThreshold,
+		Entries:       make([]*item.LootOdds, 0),
+	}
+	for _, v := range result.LootTable.Entries {
+		res.Entries = append(res.Entries, &item.LootOdds{
+			ItemId:      v.ItemId,
+			Title:       titles[v.ItemId],
+			Weight:      v.Weight,
+			Probability: float64(v.Weight) / float64(totalWeight),
+			Rare:        v.Rare,
+		})
+	}
+
+	return res, nil
+}
+
+// newItemSku normalizes the requested SKU, or derives one from the title.
+func (u *itemUsecase) newItemSku(pctx context.Context, sku, title string) (string, error) {
+	if sku == "" {
+		sku = skuFromTitle(title)
+	} else {
+		sku = normalizeSku(sku)
+	}
+
+	if !isValidSku(sku) {
+		return "", errors.New("error: invalid sku")
+	}
+
+	if !u.itemRepository.IsUniqueSku(pctx, sku) {
+		return "", errors.New("error: sku already exists")
+	}
+
+	return sku, nil
+}
+
+// findItem looks an item up by its hex id or by "sku:<SKU>".
+func (u *itemUsecase) findItem(pctx context.Context, itemId string) (*item.Item, error) {
+	if sku, ok := strings.CutPrefix(itemId, "sku:"); ok {
+		return u.itemRepository.FindOneItemBySku(pctx, normalizeSku(sku))
+	}
+
+	return u.itemRepository.FindOneItem(pctx, itemId)
+}
+
 func (u *itemUsecase) FindOneItem(pctx context.Context, itemId string) (*item.ItemShowCase, error) {
-	result, err := u.itemRepository.FindOneItem(pctx, itemId)
+	result, err := u.findItem(pctx, itemId)
 	if err != nil {
 		return nil, err
 	}
 
 	return &item.ItemShowCase{
-		ItemId:   "item:" + result.Id.Hex(),
-		Title:    result.Title,
-		Price:    result.Price,
-		ImageUrl: result.ImageUrl,
-		Damage:   result.Damage,
+		ItemId:          "item:" + result.Id.Hex(),
+		Sku:             result.Sku,
+		Title:           result.Title,
+		Price:           result.Price,
+		ImageUrl:        result.ImageUrl,
+		Damage:          result.Damage,
+		Slot:            result.Slot,
+		LevelRequired:   result.LevelRequired,
+		Components:      result.Components,
+		Stock:           result.Stock,
+		PurchaseLimit:   result.PurchaseLimit,
+		Effect:          result.Effect,
+		CooldownSeconds: result.CooldownSeconds,
+		DurationHours:   result.DurationHours,
+		LootTable:       result.LootTable,
 	}, nil
 }
 
+func itemEffect(req *item.ItemEffectReq) *item.ItemEffect {
+	if req == nil {
+		return nil
+	}
+
+	return &item.ItemEffect{
+		Type:            req.Type,
+		Value:           req.Value,
+		DurationSeconds: req.DurationSeconds,
+	}
+}
+
 func (u *itemUsecase) FindManyItems(pctx context.Context, req *item.ItemSearchReq, basePaginateUrl string) (*models.PaginateRes, error) {
 	itemFilter := bson.D{}
 	opts := make([]options.Lister[options.FindOptions], 0)
@@ -130,7 +409,13 @@ func (u *itemUsecase) FindManyItems(pctx context.Context, req *item.ItemSearchRe
 	}, nil
 }
 
-func (u *itemUsecase) EditItem(pctx context.Context, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error) {
+func (u *itemUsecase) EditItem(pctx context.Context, itemId, playerId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error) {
+	before, err := u.findItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+	itemId = before.Id.Hex()
+
 	updateReq := bson.M{}
 
 	if req.Title != "" {
@@ -153,37 +438,492 @@ func (u *itemUsecase) EditItem(pctx context.Context, itemId string, req *item.It
 		updateReq["price"] = req.Price
 	}
 
+	if req.Slot != "" {
+		updateReq["slot"] = req.Slot
+	}
+
+	if req.LevelRequired != nil {
+		updateReq["level_required"] = *req.LevelRequired
+	}
+
+	if req.Stock != nil {
+		// -1 lifts the stock limit
+		if *req.Stock < 0 {
+			updateReq["stock"] = nil
+		} else {
+			updateReq["stock"] = *req.Stock
+		}
+	}
+
+	if req.PurchaseLimit != nil {
+		updateReq["purchase_limit"] = *req.PurchaseLimit
+	}
+
+	if req.Effect != nil {
+		updateReq["effect"] = *itemEffect(req.Effect)
+	}
+
+	if req.CooldownSeconds != nil {
+		updateReq["cooldown_seconds"] = *req.CooldownSeconds
+	}
+
+	if req.DurationHours != nil {
+		updateReq["duration_hours"] = *req.DurationHours
+	}
+
 	updateReq["updated_at"] = utils.LocalTime()
 
 	if err := u.itemRepository.UpdateOneItem(pctx, itemId, updateReq); err != nil {
 		return nil, errors.New("error: update one item failed")
 	}
 
+	u.insertItemRevision(pctx, before, "edit", playerId, updateReq)
+
 	return u.FindOneItem(pctx, itemId)
 }
 
-func (u *itemUsecase) ToggleItemUsageStatus(pctx context.Context, itemId string) (bool, error) {
-	result, err := u.itemRepository.FindOneItem(pctx, itemId)
+func (u *itemUsecase) ToggleItemUsageStatus(pctx context.Context, itemId, playerId string) (bool, error) {
+	result, err := u.findItem(pctx, itemId)
 	if err != nil {
 		return false, errors.New("error: find one item failed")
 	}
+	itemId = result.Id.Hex()
 
 	if err := u.itemRepository.UpdateOneItemUsageStatus(pctx, itemId, !result.UsageStatus); err != nil {
 		return false, errors.New("error: update one item usage status failed")
 	}
 
+	u.insertItemRevision(pctx, result, "toggle_status", playerId, bson.M{"usage_status": !result.UsageStatus})
+
 	return !result.UsageStatus, nil
 }
 
+// insertItemRevision records the fields in updateReq that differ from before.
+// A failed write is logged rather than returned because the item has already
+// been updated by the time it runs.
+func (u *itemUsecase) insertItemRevision(pctx context.Context, before *item.Item, action, playerId string, updateReq bson.M) {
+	diff := itemRevisionDiff(before, updateReq)
+	if len(diff) == 0 {
+		return
+	}
+
+	price := before.Price
+	if newPrice, ok := updateReq["price"].(float64); ok {
+		price = newPrice
+	}
+
+	if _, err := u.itemRepository.InsertOneItemRevision(pctx, &item.ItemRevision{
+		ItemId:        "item:" + before.Id.Hex(),
+		Action:        action,
+		ChangedBy:     playerId,
+		Diff:          diff,
+		PreviousPrice: before.Price,
+		Price:         price,
+		CreatedAt:     utils.LocalTime(),
+	}); err != nil {
+		log.Printf("error: insert item revision: %s: %v", before.Id.Hex(), err.Error())
+	}
+}
+
+func itemRevisionDiff(before *item.Item, updateReq bson.M) []*item.ItemRevisionDiff {
+	oldValues := map[string]any{
+		"title":            before.Title,
+		"price":            before.Price,
+		"damage":           before.Damage,
+		"slot":             before.Slot,
+		"level_required":   before.LevelRequired,
+		"image_url":        before.ImageUrl,
+		"usage_status":     before.UsageStatus,
+		"purchase_limit":   before.PurchaseLimit,
+		"cooldown_seconds": before.CooldownSeconds,
+		"duration_hours":   before.DurationHours,
+		"effect": func() any {
+			if before.Effect == nil {
+				return nil
+			}
+			return *before.Effect
+		}(),
+		"stock": func() any {
+			if before.Stock == nil {
+				return nil
+			}
+			return *before.Stock
+		}(),
+	}
+
+	fields := make([]string, 0)
+	for field := range updateReq {
+		if _, ok := oldValues[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	diff := make([]*item.ItemRevisionDiff, 0)
+	for _, field := range fields {
+		if oldValues[field] == updateReq[field] {
+			continue
+		}
+
+		diff = append(diff, &item.ItemRevisionDiff{
+			Field: field,
+			Old:   oldValues[field],
+			New:   updateReq[field],
+		})
+	}
+
+	return diff
+}
+
+// ImportItems upserts catalog rows by SKU. Nothing is written unless every
+// row is valid, and a dry run only reports the changes it would make.
+func (u *itemUsecase) ImportItems(pctx context.Context, playerId string, req *item.ItemImportReq, data io.Reader) (*item.ItemImportRes, error) {
+	rows, err := readItemCatalog(req.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("error: catalog is empty")
+	}
+
+	res := &item.ItemImportRes{
+		DryRun:  req.DryRun,
+		Changes: make([]*item.ItemImportChange, 0),
+		Errors:  make([]*item.ItemImportError, 0),
+	}
+
+	validate := validator.New()
+	skus := make([]string, 0)
+	titles := make([]string, 0)
+	setSkus := make(map[string]bool)
+	setTitles := make(map[string]bool)
+	for i, row := range rows {
+		row.Sku = normalizeSku(row.Sku)
+		row.Title = strings.TrimSpace(row.Title)
+
+		rowErr := func(message string) {
+			res.Errors = append(res.Errors, &item.ItemImportError{Row: i + 1, Sku: row.Sku, Message: message})
+		}
+
+		if err := validate.Struct(row); err != nil {
+			rowErr(err.Error())
+			continue
+		}
+
+		if !isValidSku(row.Sku) {
+			rowErr("invalid sku")
+			continue
+		}
+
+		if setSkus[row.Sku] {
+			rowErr("duplicate sku in catalog")
+			continue
+		}
+		if setTitles[row.Title] {
+			rowErr("duplicate title in catalog")
+			continue
+		}
+
+		setSkus[row.Sku] = true
+		setTitles[row.Title] = true
+		skus = append(skus, row.Sku)
+		titles = append(titles, row.Title)
+	}
+
+	if len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	existing, err := u.itemRepository.FindManyItemDocuments(pctx, bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "sku", Value: bson.D{{Key: "$in", Value: skus}}}},
+			bson.D{{Key: "title", Value: bson.D{{Key: "$in", Value: titles}}}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bySku := make(map[string]*item.Item)
+	byTitle := make(map[string]*item.Item)
+	for _, result := range existing {
+		if result.Sku != "" {
+			bySku[result.Sku] = result
+		}
+		byTitle[result.Title] = result
+	}
+
+	for i, row := range rows {
+		if other, ok := byTitle[row.Title]; ok && other.Sku != row.Sku {
+			res.Errors = append(res.Errors, &item.ItemImportError{
+				Row:     i + 1,
+				Sku:     row.Sku,
+				Message: fmt.Sprintf("title already used by item:%s", other.Id.Hex()),
+			})
+			continue
+		}
+
+		// The catalog cannot carry components or loot tables, so applying
+		// it would turn the item into a plain one
+		if before, ok := bySku[row.Sku]; ok && (len(before.Components) > 0 || before.LootTable != nil) {
+			res.Errors = append(res.Errors, &item.ItemImportError{
+				Row:     i + 1,
+				Sku:     row.Sku,
+				Message: "bundles and loot boxes cannot be imported",
+			})
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	// Rows are applied one by one; re-running the same import after a
+	// failure only applies what is still missing.
+	for _, row := range rows {
+		updateReq := bson.M{
+			"title":            row.Title,
+			"price":            row.Price,
+			"damage":           row.Damage,
+			"image_url":        row.ImageUrl,
+			"usage_status":     row.UsageStatus == nil || *row.UsageStatus,
+			"purchase_limit":   row.PurchaseLimit,
+			"stock":            nil,
+			"slot":             row.Slot,
+			"level_required":   row.LevelRequired,
+			"effect":           nil,
+			"cooldown_seconds": row.CooldownSeconds,
+			"duration_hours":   row.DurationHours,
+		}
+		if row.Stock != nil {
+			updateReq["stock"] = *row.Stock
+		}
+		if row.Effect != nil {
+			updateReq["effect"] = *itemEffect(row.Effect)
+		}
+
+		before, ok := bySku[row.Sku]
+		if !ok {
+			res.Created++
+			res.Changes = append(res.Changes, &item.ItemImportChange{Sku: row.Sku, Action: "create"})
+
+			if req.DryRun {
+				continue
+			}
+
+			if _, err := u.itemRepository.InsertOneItem(pctx, &item.Item{
+				Sku:             row.Sku,
+				Title:           row.Title,
+				Price:           row.Price,
+				Damage:          row.Damage,
+				Slot:            row.Slot,
+				LevelRequired:   row.LevelRequired,
+				Effect:          itemEffect(row.Effect),
+				CooldownSeconds: row.CooldownSeconds,
+				DurationHours:   row.DurationHours,
+				ImageUrl:        row.ImageUrl,
+				UsageStatus:     updateReq["usage_status"].(bool),
+				Stock:           row.Stock,
+				PurchaseLimit:   row.PurchaseLimit,
+				CreatedAt:       utils.LocalTime(),
+				UpdatedAt:       utils.LocalTime(),
+			}); err != nil {
+				return nil, fmt.Errorf("error: import item %s failed", row.Sku)
+			}
+			continue
+		}
+
+		diff := itemRevisionDiff(before, updateReq)
+		if len(diff) == 0 {
+			res.Unchanged++
+			continue
+		}
+
+		res.Updated++
+		res.Changes = append(res.Changes, &item.ItemImportChange{Sku: row.Sku, Action: "update", Diff: diff})
+
+		if req.DryRun {
+			continue
+		}
+
+		updateReq["updated_at"] = utils.LocalTime()
+		if err := u.itemRepository.UpdateOneItem(pctx, before.Id.Hex(), updateReq); err != nil {
+			return nil, fmt.Errorf("error: import item %s failed", row.Sku)
+		}
+
+		u.insertItemRevision(pctx, before, "import", playerId, updateReq)
+	}
+
+	return res, nil
+}
+
+// ExportItems writes the catalog in the import format. Bundles and loot
+// boxes are managed through their own endpoints and are left out.
+func (u *itemUsecase) ExportItems(pctx context.Context, req *item.ItemExportReq, w io.Writer) error {
+	results, err := u.itemRepository.FindManyItemDocuments(
+		pctx,
+		bson.D{
+			{Key: "components", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "loot_table", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+		options.Find().SetSort(bson.D{{Key: "sku", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]*item.ItemCatalogRow, 0)
+	for _, result := range results {
+		usageStatus := result.UsageStatus
+		row := &item.ItemCatalogRow{
+			Sku:             result.Sku,
+			Title:           result.Title,
+			Price:           result.Price,
+			ImageUrl:        result.ImageUrl,
+			Damage:          result.Damage,
+			UsageStatus:     &usageStatus,
+			Stock:           result.Stock,
+			PurchaseLimit:   result.PurchaseLimit,
+			Slot:            result.Slot,
+			LevelRequired:   result.LevelRequired,
+			CooldownSeconds: result.CooldownSeconds,
+			DurationHours:   result.DurationHours,
+		}
+		if result.Effect != nil {
+			row.Effect = &item.ItemEffectReq{
+				Type:            result.Effect.Type,
+				Value:           result.Effect.Value,
+				DurationSeconds: result.Effect.DurationSeconds,
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return writeItemCatalog(req.Format, w, rows)
+}
+
+func (u *itemUsecase) FindItemHistory(pctx context.Context, itemId string) ([]*item.ItemRevision, error) {
+	result, err := u.findItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := u.itemRepository.FindItemRevisions(
+		pctx,
+		bson.D{{Key: "item_id", Value: "item:" + result.Id.Hex()}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, errors.New("error: find item revisions failed")
+	}
+
+	return results, nil
+}
+
+func (u *itemUsecase) FindItemPriceAt(pctx context.Context, itemId string, at time.Time) (*item.ItemPriceAtRes, error) {
+	result, err := u.findItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.CreatedAt.After(at) {
+		return nil, errors.New("error: item did not exist at the given time")
+	}
+
+	res := &item.ItemPriceAtRes{
+		ItemId: "item:" + result.Id.Hex(),
+		Price:  result.Price,
+		At:     at,
+	}
+
+	// The latest revision at or before the timestamp holds the list price of that moment
+	before, err := u.itemRepository.FindItemRevisions(
+		pctx,
+		bson.D{
+			{Key: "item_id", Value: res.ItemId},
+			{Key: "created_at", Value: bson.D{{Key: "$lte", Value: at}}},
+		},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+		options.Find().SetLimit(1),
+	)
+	if err != nil {
+		return nil, errors.New("error: find item revisions failed")
+	}
+
+	if len(before) > 0 {
+		res.Price = before[0].Price
+		return res, nil
+	}
+
+	// Otherwise the first revision after it remembers the price it replaced
+	after, err := u.itemRepository.FindItemRevisions(
+		pctx,
+		bson.D{
+			{Key: "item_id", Value: res.ItemId},
+			{Key: "created_at", Value: bson.D{{Key: "$gt", Value: at}}},
+		},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+		options.Find().SetLimit(1),
+	)
+	if err != nil {
+		return nil, errors.New("error: find item revisions failed")
+	}
+
+	if len(after) > 0 {
+		res.Price = after[0].PreviousPrice
+	}
+
+	return res, nil
+}
+
+// FindItemPriceFor applies the player's VIP discount the same way the
+// payment service does at checkout. Without entitlements the list price is
+// returned.
+func (u *itemUsecase) FindItemPriceFor(pctx context.Context, cfg *config.Config, itemId, playerId string) (*item.ItemPlayerPriceRes, error) {
+	result, err := u.findItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &item.ItemPlayerPriceRes{
+		ItemId:    "item:" + result.Id.Hex(),
+		ListPrice: result.Price,
+		Price:     result.Price,
+	}
+
+	vip, err := u.itemRepository.FindEntitlements(pctx, cfg.Grpc.SubscriptionUrl, &subscriptionPb.FindEntitlementsReq{PlayerId: playerId})
+	if err != nil {
+		log.Printf("Error: find entitlements failed: %v", err.Error())
+		return res, nil
+	}
+
+	res.Vip = vip.Active
+	if vip.DiscountRate > 0 {
+		res.DiscountRate = vip.DiscountRate
+		res.Price = math.Round(result.Price*(1-vip.DiscountRate)*100) / 100
+	}
+
+	return res, nil
+}
+
 func (u *itemUsecase) FindItemsInIds(pctx context.Context, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
 	filter := bson.D{}
 
 	objectIds := make([]bson.ObjectID, 0)
+	skus := make([]string, 0)
 	for _, itemId := range req.Ids {
+		if sku, ok := strings.CutPrefix(itemId, "sku:"); ok {
+			skus = append(skus, normalizeSku(sku))
+			continue
+		}
 		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:")))
 	}
 
-	filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}})
+	filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}}},
+		bson.D{{Key: "sku", Value: bson.D{{Key: "$in", Value: skus}}}},
+	}})
 	filter = append(filter, bson.E{Key: "usage_status", Value: true})
 
 	results, err := u.itemRepository.FindManyItems(pctx, filter, nil)
@@ -194,12 +934,39 @@ func (u *itemUsecase) FindItemsInIds(pctx context.Context, req *itemPb.FindItems
 	resultsToRes := make([]*itemPb.Item, 0)
 
 	for _, result := range results {
+		components := make([]*itemPb.BundleComponent, 0)
+		for _, c := range result.Components {
+			components = append(components, &itemPb.BundleComponent{
+				ItemId:   c.ItemId,
+				Quantity: int32(c.Quantity),
+			})
+		}
+
 		resultsToRes = append(resultsToRes, &itemPb.Item{
-			Id:       result.ItemId,
-			Title:    result.Title,
-			Price:    result.Price,
-			ImageUrl: result.ImageUrl,
-			Damage:   int32(result.Damage),
+			Id:            result.ItemId,
+			Sku:           result.Sku,
+			Title:         result.Title,
+			Price:         result.Price,
+			ImageUrl:      result.ImageUrl,
+			Damage:        int32(result.Damage),
+			Slot:          result.Slot,
+			LevelRequired: int32(result.LevelRequired),
+			Components:    components,
+			Stock:         result.Stock,
+			PurchaseLimit: int32(result.PurchaseLimit),
+			Effect: func() *itemPb.ItemEffect {
+				if result.Effect == nil {
+					return nil
+				}
+				return &itemPb.ItemEffect{
+					Type:            result.Effect.Type,
+					Value:           result.Effect.Value,
+					DurationSeconds: int32(result.Effect.DurationSeconds),
+				}
+			}(),
+			CooldownSeconds: int32(result.CooldownSeconds),
+			DurationHours:   int32(result.DurationHours),
+			LootTable:       lootTableToPb(result.LootTable),
 		})
 	}
 
@@ -207,3 +974,462 @@ func (u *itemUsecase) FindItemsInIds(pctx context.Context, req *itemPb.FindItems
 		Items: resultsToRes,
 	}, nil
 }
+
+func (u *itemUsecase) ReserveItems(pctx context.Context, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error) {
+	reserved := make([]string, 0)
+
+	for _, id := range req.Ids {
+		if err := u.reserveItem(pctx, req.PlayerId, strings.TrimPrefix(id, "item:")); err != nil {
+			u.ReleaseItems(pctx, &itemPb.ReleaseItemsReq{
+				PlayerId: req.PlayerId,
+				Ids:      reserved,
+			})
+
+			return nil, err
+		}
+
+		reserved = append(reserved, id)
+	}
+
+	return &itemPb.ReserveItemsRes{
+		Ids: reserved,
+	}, nil
+}
+
+func (u *itemUsecase) reserveItem(pctx context.Context, playerId, itemId string) error {
+	result, err := u.itemRepository.FindOneItem(pctx, itemId)
+	if err != nil {
+		return err
+	}
+
+	if result.PurchaseLimit > 0 {
+		if err := u.itemRepository.ReservePlayerPurchase(pctx, itemId, playerId, result.PurchaseLimit); err != nil {
+			return err
+		}
+	}
+
+	if result.Stock != nil {
+		if err := u.itemRepository.ReserveItemStock(pctx, itemId); err != nil {
+			if result.PurchaseLimit > 0 {
+				u.itemRepository.ReleasePlayerPurchase(pctx, itemId, playerId)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *itemUsecase) ReleaseItems(pctx context.Context, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error) {
+	released := make([]string, 0)
+
+	for _, id := range req.Ids {
+		itemId := strings.TrimPrefix(id, "item:")
+
+		result, err := u.itemRepository.FindOneItem(pctx, itemId)
+		if err != nil {
+			continue
+		}
+
+		if result.Stock != nil {
+			if err := u.itemRepository.ReleaseItemStock(pctx, itemId); err != nil {
+				continue
+			}
+		}
+
+		if result.PurchaseLimit > 0 {
+			if err := u.itemRepository.ReleasePlayerPurchase(pctx, itemId, req.PlayerId); err != nil {
+				continue
+			}
+		}
+
+		released = append(released, id)
+	}
+
+	return &itemPb.ReleaseItemsRes{
+		Ids: released,
+	}, nil
+}
+
+func lootTableToPb(table *item.LootTable) *itemPb.LootTable {
+	if table == nil {
+		return nil
+	}
+
+	entries := make([]*itemPb.LootEntry, 0)
+	for _, v := range table.Entries {
+		entries = append(entries, &itemPb.LootEntry{
+			ItemId: v.ItemId,
+			Weight: int32(v.Weight),
+			Rare:   v.Rare,
+		})
+	}
+
+	return &itemPb.LootTable{
+		Entries:       entries,
+		PityThreshold: int32(table.PityThreshold),
+	}
+}
+
+func (u *itemUsecase) CreateRecipe(pctx context.Context, req *item.CreateRecipeReq) (*item.RecipeShowCase, error) {
+	recipe := &item.Recipe{
+		Title:          req.Title,
+		Inputs:         make([]*item.BundleComponent, 0),
+		OutputItemId:   "item:" + strings.TrimPrefix(req.OutputItemId, "item:"),
+		OutputQuantity: max(req.OutputQuantity, 1),
+		Cost:           req.Cost,
+		SuccessRate:    req.SuccessRate,
+		UsageStatus:    true,
+		CreatedAt:      utils.LocalTime(),
+		UpdatedAt:      utils.LocalTime(),
+	}
+	if req.ConsolationItemId != "" {
+		recipe.ConsolationItemId = "item:" + strings.TrimPrefix(req.ConsolationItemId, "item:")
+	}
+
+	setIds := map[string]bool{recipe.OutputItemId: true}
+	for _, v := range req.Inputs {
+		itemId := "item:" + strings.TrimPrefix(v.ItemId, "item:")
+		if setIds[itemId] {
+			return nil, errors.New("error: duplicate recipe item")
+		}
+		setIds[itemId] = true
+
+		recipe.Inputs = append(recipe.Inputs, &item.BundleComponent{
+			ItemId:   itemId,
+			Quantity: v.Quantity,
+		})
+	}
+	if recipe.ConsolationItemId != "" {
+		setIds[recipe.ConsolationItemId] = true
+	}
+
+	objectIds := make([]bson.ObjectID, 0)
+	for itemId := range setIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:")))
+	}
+
+	results, err := u.itemRepository.FindManyItems(pctx, bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: objectIds}}},
+		{Key: "usage_status", Value: true},
+	})
+	if err != nil {
+		return nil, errors.New("error: find many items failed")
+	}
+
+	if len(results) != len(setIds) {
+		return nil, errors.New("error: recipe item not found")
+	}
+
+	for _, result := range results {
+		if len(result.Components) > 0 {
+			return nil, errors.New("error: recipe cannot use a bundle")
+		}
+	}
+
+	recipeId, err := u.itemRepository.InsertOneRecipe(pctx, recipe)
+	if err != nil {
+		return nil, err
+	}
+	recipe.Id = recipeId
+
+	return recipeShowCase(recipe), nil
+}
+
+func (u *itemUsecase) FindOneRecipe(pctx context.Context, recipeId string) (*item.RecipeShowCase, error) {
+	result, err := u.itemRepository.FindOneRecipe(pctx, strings.TrimPrefix(recipeId, "recipe:"))
+	if err != nil {
+		return nil, err
+	}
+
+	return recipeShowCase(result), nil
+}
+
+func (u *itemUsecase) FindManyRecipes(pctx context.Context) ([]*item.RecipeShowCase, error) {
+	results, err := u.itemRepository.FindManyRecipes(pctx, bson.D{{Key: "usage_status", Value: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*item.RecipeShowCase, 0)
+	for _, v := range results {
+		recipes = append(recipes, recipeShowCase(v))
+	}
+
+	return recipes, nil
+}
+
+func (u *itemUsecase) FindOneRecipePb(pctx context.Context, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error) {
+	result, err := u.itemRepository.FindOneRecipe(pctx, strings.TrimPrefix(req.RecipeId, "recipe:"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.UsageStatus {
+		return nil, errors.New("error: recipe is not available")
+	}
+
+	inputs := make([]*itemPb.BundleComponent, 0)
+	for _, v := range result.Inputs {
+		inputs = append(inputs, &itemPb.BundleComponent{
+			ItemId:   v.ItemId,
+			Quantity: int32(v.Quantity),
+		})
+	}
+
+	return &itemPb.Recipe{
+		Id:                "recipe:" + result.Id.Hex(),
+		Title:             result.Title,
+		Inputs:            inputs,
+		OutputItemId:      result.OutputItemId,
+		OutputQuantity:    int32(result.OutputQuantity),
+		Cost:              result.Cost,
+		SuccessRate:       result.SuccessRate,
+		ConsolationItemId: result.ConsolationItemId,
+	}, nil
+}
+
+func recipeShowCase(recipe *item.Recipe) *item.RecipeShowCase {
+	return &item.RecipeShowCase{
+		RecipeId:          "recipe:" + recipe.Id.Hex(),
+		Title:             recipe.Title,
+		Inputs:            recipe.Inputs,
+		OutputItemId:      recipe.OutputItemId,
+		OutputQuantity:    recipe.OutputQuantity,
+		Cost:              recipe.Cost,
+		SuccessRate:       recipe.SuccessRate,
+		ConsolationItemId: recipe.ConsolationItemId,
+	}
+}
+
+// maxWishlistItems caps each player's wishlist.
+const maxWishlistItems = 100
+
+func (u *itemUsecase) AddWishlistItem(pctx context.Context, playerId string, req *item.AddWishlistItemReq) (*item.WishlistItem, error) {
+	result, err := u.findItem(pctx, strings.TrimPrefix(req.ItemId, "item:"))
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist, err := u.itemRepository.FindWishlistItems(pctx, bson.D{{Key: "player_id", Value: playerId}})
+	if err != nil {
+		return nil, err
+	}
+	if len(wishlist) >= maxWishlistItems {
+		return nil, fmt.Errorf("error: wishlist is limited to %d items", maxWishlistItems)
+	}
+
+	res := &item.WishlistItem{
+		PlayerId:   playerId,
+		ItemId:     "item:" + result.Id.Hex(),
+		PriceAtAdd: result.Price,
+		CreatedAt:  utils.LocalTime(),
+	}
+
+	wishlistId, err := u.itemRepository.InsertOneWishlistItem(pctx, res)
+	if err != nil {
+		return nil, err
+	}
+	res.Id = wishlistId
+
+	return res, nil
+}
+
+// FindWishlist lists the wishlisted items with their current price and
+// availability. Items deleted from the catalog are left out.
+func (u *itemUsecase) FindWishlist(pctx context.Context, playerId string) ([]*item.WishlistItemRes, error) {
+	wishlist, err := u.itemRepository.FindWishlistItems(pctx, bson.D{{Key: "player_id", Value: playerId}})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*item.WishlistItemRes, 0)
+	if len(wishlist) == 0 {
+		return results, nil
+	}
+
+	objectIds := make([]bson.ObjectID, 0)
+	for _, v := range wishlist {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(v.ItemId, "item:")))
+	}
+
+	items, err := u.itemRepository.FindManyItemDocuments(pctx, bson.D{{Key: "_id", Value: bson.M{"$in": objectIds}}})
+	if err != nil {
+		return nil, err
+	}
+
+	itemMap := make(map[string]*item.Item)
+	for _, v := range items {
+		itemMap["item:"+v.Id.Hex()] = v
+	}
+
+	for _, v := range wishlist {
+		result, ok := itemMap[v.ItemId]
+		if !ok {
+			continue
+		}
+
+		results = append(results, &item.WishlistItemRes{
+			ItemId:      v.ItemId,
+			Title:       result.Title,
+			Price:       result.Price,
+			PriceAtAdd:  v.PriceAtAdd,
+			UsageStatus: result.UsageStatus,
+			Stock:       result.Stock,
+			AddedAt:     v.CreatedAt,
+		})
+	}
+
+	return results, nil
+}
+
+func (u *itemUsecase) RemoveWishlistItem(pctx context.Context, playerId, itemId string) error {
+	return u.itemRepository.DeleteOneWishlistItem(pctx, playerId, "item:"+strings.TrimPrefix(itemId, "item:"))
+}
+
+func (u *itemUsecase) FindWishlistNotifications(pctx context.Context, playerId string) ([]*item.WishlistNotification, error) {
+	return u.itemRepository.FindWishlistNotifications(pctx, playerId)
+}
+
+// WatchWishlists reads the item revisions written since its last run and
+// notifies the players wishlisting an item that went on sale, was
+// re-enabled or came back into stock. The position only moves past a
+// revision once its players are notified, and the unique notification
+// index keeps a retried revision from notifying anyone twice.
+func (u *itemUsecase) WatchWishlists(pctx context.Context, cfg *config.Config) error {
+	after, err := u.itemRepository.GetWishlistWatch(pctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{}
+	if !after.IsZero() {
+		filter = bson.D{{Key: "_id", Value: bson.M{"$gt": after}}}
+	}
+
+	revisions, err := u.itemRepository.FindItemRevisions(
+		pctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}),
+		options.Find().SetLimit(500),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range revisions {
+		if kind := wishlistChange(v); kind != "" {
+			if err := u.notifyWishlist(pctx, cfg, v, kind); err != nil {
+				return err
+			}
+		}
+
+		if err := u.itemRepository.UpsertWishlistWatch(pctx, v.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *itemUsecase) notifyWishlist(pctx context.Context, cfg *config.Config, revision *item.ItemRevision, kind string) error {
+	result, err := u.itemRepository.FindOneItem(pctx, strings.TrimPrefix(revision.ItemId, "item:"))
+	if err != nil {
+		return err
+	}
+
+	// Nothing to tell while the item cannot be bought
+	if !result.UsageStatus {
+		return nil
+	}
+
+	wishlist, err := u.itemRepository.FindWishlistItems(pctx, bson.D{{Key: "item_id", Value: revision.ItemId}})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range wishlist {
+		notification := &item.WishlistNotification{
+			PlayerId:   v.PlayerId,
+			ItemId:     revision.ItemId,
+			RevisionId: revision.Id.Hex(),
+			Kind:       kind,
+			OldPrice:   revision.PreviousPrice,
+			Price:      revision.Price,
+			CreatedAt:  utils.LocalTime(),
+		}
+
+		if _, err := u.itemRepository.InsertOneWishlistNotification(pctx, notification); err != nil {
+			continue
+		}
+
+		if err := u.itemRepository.PushEvent(pctx, cfg, &models.DomainEvent{
+			Type:     "wishlist." + kind,
+			PlayerId: v.PlayerId,
+			Payload: &item.WishlistEvent{
+				ItemId:   revision.ItemId,
+				Title:    result.Title,
+				OldPrice: revision.PreviousPrice,
+				Price:    revision.Price,
+			},
+			OccurredAt: notification.CreatedAt,
+		}); err != nil {
+			log.Printf("error: push wishlist event: %s: %v", v.PlayerId, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// wishlistChange returns what a revision means to a player wishlisting the
+// item: "available" when it was re-enabled, "restock" when its stock went
+// from 0 to some or unlimited, "price_drop" when it got cheaper, or "".
+func wishlistChange(revision *item.ItemRevision) string {
+	for _, v := range revision.Diff {
+		if v.Field == "usage_status" && v.Old == false && v.New == true {
+			return "available"
+		}
+	}
+
+	for _, v := range revision.Diff {
+		if v.Field != "stock" {
+			continue
+		}
+
+		old, ok := revisionNumber(v.Old)
+		if !ok || old != 0 {
+			continue
+		}
+
+		if v.New == nil {
+			return "restock"
+		}
+		if stock, ok := revisionNumber(v.New); ok && stock > 0 {
+			return "restock"
+		}
+	}
+
+	if revision.Price < revision.PreviousPrice {
+		return "price_drop"
+	}
+
+	return ""
+}
+
+// revisionNumber reads a diff value, which comes back from mongo as int32,
+// int64 or float64 depending on how it was written.
+func revisionNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package marketplace
+
+import (
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// Listing holds one inventory item in escrow while it is for sale.
+	// Fixed listings sell at Price; auctions start at Price and go to the
+	// highest bid when they expire.
+	Listing struct {
+		Id          bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		SellerId    string        `json:"seller_id" bson:"seller_id"`
+		ItemId      string        `json:"item_id" bson:"item_id"`
+		InventoryId string        `json:"inventory_id" bson:"inventory_id"`
+		// Instance is the escrowed copy's state, handed on to the buyer
+		Instance   *inventory.ItemInstance `json:"instance,omitempty" bson:"instance,omitempty"`
+		Kind       string                  `json:"kind" bson:"kind"`
+		Price      float64                 `json:"price" bson:"price"`
+		HighestBid *ListingBid             `json:"highest_bid,omitempty" bson:"highest_bid,omitempty"`
+		BidCount   int                     `json:"bid_count" bson:"bid_count"`
+		ListingFee float64                 `json:"listing_fee" bson:"listing_fee"`
+		BuyerId    string                  `json:"buyer_id,omitempty" bson:"buyer_id,omitempty"`
+		SoldPrice  float64                 `json:"sold_price,omitempty" bson:"sold_price,omitempty"`
+		Tax        float64                 `json:"tax,omitempty" bson:"tax,omitempty"`
+		Status     string                  `json:"status" bson:"status"`
+		Settlement *ListingSettlement      `json:"settlement,omitempty" bson:"settlement,omitempty"`
+		Error      string                  `json:"error,omitempty" bson:"error,omitempty"`
+		ExpiresAt  time.Time               `json:"expires_at" bson:"expires_at"`
+		CreatedAt  time.Time               `json:"created_at" bson:"created_at"`
+		UpdatedAt  time.Time               `json:"updated_at" bson:"updated_at"`
+	}
+
+	// ListingSettlement records what a processing listing has done so far,
+	// so an interrupted buy, bid or close can be undone or finished later.
+	// Payment and Proceeds are the buyer's and seller's transactions for a
+	// sale; Granting is set once the item is being handed over, after which
+	// the sale is only ever finished. Refund is an outbid bid still owed
+	// back, and Close the status a listing moves to once its item is back
+	// with the seller.
+	ListingSettlement struct {
+		BuyerId  string  `json:"buyer_id,omitempty" bson:"buyer_id,omitempty"`
+		Price    float64 `json:"price,omitempty" bson:"price,omitempty"`
+		Tax      float64 `json:"tax,omitempty" bson:"tax,omitempty"`
+		Payment  string  `json:"-" bson:"payment,omitempty"`
+		Proceeds string  `json:"-" bson:"proceeds,omitempty"`
+		Granting bool    `json:"granting,omitempty" bson:"granting,omitempty"`
+		Refund   string  `json:"-" bson:"refund,omitempty"`
+		Close    string  `json:"close,omitempty" bson:"close,omitempty"`
+	}
+
+	// ListingBid is the money a bidder has in escrow for an auction.
+	ListingBid struct {
+		PlayerId      string    `json:"player_id" bson:"player_id"`
+		Amount        float64   `json:"amount" bson:"amount"`
+		TransactionId string    `json:"-" bson:"transaction_id"`
+		CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	}
+)
@@ -0,0 +1,154 @@
+package marketplaceHandler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	MarketplaceHttpHandlerService interface {
+		CreateListing(c echo.Context) error
+		FindOneListing(c echo.Context) error
+		FindListings(c echo.Context) error
+		BuyListing(c echo.Context) error
+		PlaceBid(c echo.Context) error
+		CancelListing(c echo.Context) error
+	}
+
+	marketplaceHttpHandler struct {
+		cfg                *config.Config
+		marketplaceUsecase marketplaceUsecase.MarketplaceUsecaseService
+	}
+)
+
+func NewMarketplaceHttpHandler(cfg *config.Config, marketplaceUsecase marketplaceUsecase.MarketplaceUsecaseService) MarketplaceHttpHandlerService {
+	return &marketplaceHttpHandler{cfg, marketplaceUsecase}
+}
+
+func listingIdParam(c echo.Context) (string, error) {
+	decodedParam, err := url.QueryUnescape(c.Param("listing_id"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(decodedParam, "listing:"), nil
+}
+
+func (h *marketplaceHttpHandler) CreateListing(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(marketplace.CreateListingReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.marketplaceUsecase.CreateListing(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *marketplaceHttpHandler) FindOneListing(c echo.Context) error {
+	ctx := context.Background()
+
+	listingId, err := listingIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.marketplaceUsecase.FindOneListing(ctx, listingId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *marketplaceHttpHandler) FindListings(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(marketplace.ListingSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.marketplaceUsecase.FindListings(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *marketplaceHttpHandler) BuyListing(c echo.Context) error {
+	ctx := context.Background()
+
+	listingId, err := listingIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.marketplaceUsecase.BuyListing(ctx, h.cfg, c.Get("player_id").(string), listingId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *marketplaceHttpHandler) PlaceBid(c echo.Context) error {
+	ctx := context.Background()
+
+	listingId, err := listingIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(marketplace.PlaceBidReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.marketplaceUsecase.PlaceBid(ctx, h.cfg, c.Get("player_id").(string), listingId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *marketplaceHttpHandler) CancelListing(c echo.Context) error {
+	ctx := context.Background()
+
+	listingId, err := listingIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.marketplaceUsecase.CancelListing(ctx, h.cfg, c.Get("player_id").(string), listingId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
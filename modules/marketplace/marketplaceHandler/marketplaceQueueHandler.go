@@ -0,0 +1,94 @@
+package marketplaceHandler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IBM/sarama"
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+)
+
+type (
+	MarketplaceQueueHandlerService interface {
+		SettleListing()
+	}
+
+	marketplaceQueueHandler struct {
+		cfg                *config.Config
+		marketplaceUsecase marketplaceUsecase.MarketplaceUsecaseService
+	}
+)
+
+func NewMarketplaceQueueHandler(cfg *config.Config, marketplaceUsecase marketplaceUsecase.MarketplaceUsecaseService) MarketplaceQueueHandlerService {
+	return &marketplaceQueueHandler{cfg, marketplaceUsecase}
+}
+
+func (h *marketplaceQueueHandler) MarketplaceConsumer(pctx context.Context) (sarama.PartitionConsumer, error) {
+	worker, err := queue.ConnectConsumer([]string{h.cfg.Kafka.Url}, h.cfg.Kafka.ApiKey, h.cfg.Kafka.Secret)
+	if err != nil {
+		return nil, errors.New("error: connect consumer failed")
+	}
+
+	offset, err := h.marketplaceUsecase.GetOffset(pctx)
+	if err != nil {
+		return nil, errors.New("error: get offset failed")
+	}
+
+	consumer, err := worker.ConsumePartition("marketplace", 0, offset)
+	if err != nil {
+		log.Printf("Error: Try to consume partition with offset: %v", err.Error())
+		consumer, err = worker.ConsumePartition("marketplace", 0, 0)
+		if err != nil {
+			log.Printf("Error: consume partition failed: %v", err.Error())
+			return nil, errors.New("error: consume partition failed")
+		}
+	}
+
+	return consumer, nil
+}
+
+func (h *marketplaceQueueHandler) SettleListing() {
+	ctx := context.Background()
+
+	consumer, err := h.MarketplaceConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Settle listing consumer started")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Printf("Error: settle listing consumer failed: %v", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == "settle" {
+				h.marketplaceUsecase.UpsertOffset(ctx, msg.Offset+1)
+				req := new(marketplace.SettleListingReq)
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					continue
+				}
+
+				if err := h.marketplaceUsecase.SettleListing(ctx, h.cfg, req); err != nil {
+					log.Printf("Error: settle listing %s failed: %v", req.ListingId, err.Error())
+				}
+				log.Printf("info: settle listing: topic: %s, offset: %d, value: %s", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigChan:
+			log.Println("Settle listing consumer stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package marketplace
+
+type (
+	// CreateListingReq lists one item. Duration is in minutes and defaults
+	// to one day.
+	CreateListingReq struct {
+		ItemId   string  `json:"item_id" validate:"required,max=64"`
+		Kind     string  `json:"kind" validate:"required,oneof=fixed auction"`
+		Price    float64 `json:"price" validate:"required,gt=0"`
+		Duration int     `json:"duration" validate:"min=0,max=10080"`
+	}
+
+	PlaceBidReq struct {
+		Amount float64 `json:"amount" validate:"required,gt=0"`
+	}
+
+	ListingSearchReq struct {
+		ItemId   string `query:"item_id" validate:"max=64"`
+		SellerId string `query:"seller_id" validate:"max=64"`
+		Kind     string `query:"kind" validate:"omitempty,oneof=fixed auction"`
+		Status   string `query:"status" validate:"omitempty,oneof=active sold expired cancelled"`
+	}
+
+	SettleListingReq struct {
+		ListingId string `json:"listing_id" validate:"required,max=64"`
+	}
+)
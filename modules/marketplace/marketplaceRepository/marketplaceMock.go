@@ -0,0 +1,96 @@
+package marketplaceRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MarketplaceRepositoryMock struct {
+	mock.Mock
+}
+
+func NewMarketplaceRepositoryMock() MarketplaceRepositoryService {
+	return &MarketplaceRepositoryMock{}
+}
+
+func (m *MarketplaceRepositoryMock) InsertOneListing(pctx context.Context, req *marketplace.Listing) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) FindOneListing(pctx context.Context, listingId string) (*marketplace.Listing, error) {
+	args := m.Called(pctx, listingId)
+	return args.Get(0).(*marketplace.Listing), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) FindListings(pctx context.Context, filter bson.D) ([]*marketplace.Listing, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*marketplace.Listing), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) FindExpiredListings(pctx context.Context, now time.Time) ([]*marketplace.Listing, error) {
+	args := m.Called(pctx, now)
+	return args.Get(0).([]*marketplace.Listing), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) FindStaleListings(pctx context.Context, before time.Time) ([]*marketplace.Listing, error) {
+	args := m.Called(pctx, before)
+	return args.Get(0).([]*marketplace.Listing), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) ClaimStaleListing(pctx context.Context, listingId string, updatedAt time.Time) error {
+	args := m.Called(pctx, listingId, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MarketplaceRepositoryMock) LockListing(pctx context.Context, listingId string, bidCount int) error {
+	args := m.Called(pctx, listingId, bidCount)
+	return args.Error(0)
+}
+
+func (m *MarketplaceRepositoryMock) UpdateOneListing(pctx context.Context, listingId string, req bson.M) error {
+	args := m.Called(pctx, listingId, req)
+	return args.Error(0)
+}
+
+func (m *MarketplaceRepositoryMock) GetOffset(pctx context.Context) (int64, error) {
+	args := m.Called(pctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) UpsertOffset(pctx context.Context, offset int64) error {
+	args := m.Called(pctx, offset)
+	return args.Error(0)
+}
+
+func (m *MarketplaceRepositoryMock) SettleListing(pctx context.Context, cfg *config.Config, req *marketplace.SettleListingReq) error {
+	args := m.Called(pctx, cfg, req)
+	return args.Error(0)
+}
+
+func (m *MarketplaceRepositoryMock) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchGrantRes), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchRevokeRes), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.CreatePlayerTransactionRes), args.Error(1)
+}
+
+func (m *MarketplaceRepositoryMock) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Error(0)
+}
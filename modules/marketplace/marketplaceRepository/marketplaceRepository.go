@@ -0,0 +1,332 @@
+package marketplaceRepository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	MarketplaceRepositoryService interface {
+		InsertOneListing(pctx context.Context, req *marketplace.Listing) (bson.ObjectID, error)
+		FindOneListing(pctx context.Context, listingId string) (*marketplace.Listing, error)
+		FindListings(pctx context.Context, filter bson.D) ([]*marketplace.Listing, error)
+		FindExpiredListings(pctx context.Context, now time.Time) ([]*marketplace.Listing, error)
+		FindStaleListings(pctx context.Context, before time.Time) ([]*marketplace.Listing, error)
+		LockListing(pctx context.Context, listingId string, bidCount int) error
+		ClaimStaleListing(pctx context.Context, listingId string, updatedAt time.Time) error
+		UpdateOneListing(pctx context.Context, listingId string, req bson.M) error
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+		SettleListing(pctx context.Context, cfg *config.Config, req *marketplace.SettleListingReq) error
+		BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error)
+		BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error)
+		CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error)
+		RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error
+	}
+
+	marketplaceRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewMarketplaceRepository(db *mongo.Client) MarketplaceRepositoryService {
+	return &marketplaceRepository{db}
+}
+
+func (r *marketplaceRepository) marketplaceDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("marketplace_db")
+}
+
+func (r *marketplaceRepository) InsertOneListing(pctx context.Context, req *marketplace.Listing) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one listing: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one listing failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *marketplaceRepository) FindOneListing(pctx context.Context, listingId string) (*marketplace.Listing, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	result := new(marketplace.Listing)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(listingId)}).Decode(result); err != nil {
+		log.Printf("error: find one listing: %v", err.Error())
+		return nil, errors.New("error: listing not found")
+	}
+
+	return result, nil
+}
+
+func (r *marketplaceRepository) FindListings(pctx context.Context, filter bson.D) ([]*marketplace.Listing, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(100))
+	if err != nil {
+		log.Printf("error: find listings: %v", err.Error())
+		return nil, errors.New("error: find listings failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*marketplace.Listing, 0)
+	for cursors.Next(ctx) {
+		result := new(marketplace.Listing)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode listing: %v", err.Error())
+			return nil, errors.New("error: decode listing failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *marketplaceRepository) FindExpiredListings(pctx context.Context, now time.Time) ([]*marketplace.Listing, error) {
+	return r.FindListings(pctx, bson.D{
+		{Key: "status", Value: "active"},
+		{Key: "expires_at", Value: bson.M{"$lte": now}},
+	})
+}
+
+// FindStaleListings returns listings left processing since before, e.g. by
+// a crash or a failed compensation.
+func (r *marketplaceRepository) FindStaleListings(pctx context.Context, before time.Time) ([]*marketplace.Listing, error) {
+	return r.FindListings(pctx, bson.D{
+		{Key: "status", Value: "processing"},
+		{Key: "updated_at", Value: bson.M{"$lte": before}},
+	})
+}
+
+// LockListing moves an active listing into processing so only one buy, bid,
+// cancel or settlement runs at a time. bidCount guards against acting on a
+// highest bid that was outbid in the meantime.
+func (r *marketplaceRepository) LockListing(pctx context.Context, listingId string, bidCount int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(listingId), "status": "active", "bid_count": bidCount},
+		bson.M{"$set": bson.M{"status": "processing", "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: lock listing: %v", err.Error())
+		return errors.New("error: lock listing failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: listing is no longer active")
+	}
+
+	return nil
+}
+
+// ClaimStaleListing takes over a stale processing listing for recovery. It
+// only matches while updated_at is unchanged, so two runs never recover the
+// same listing at once.
+func (r *marketplaceRepository) ClaimStaleListing(pctx context.Context, listingId string, updatedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(listingId), "status": "processing", "updated_at": updatedAt},
+		bson.M{"$set": bson.M{"updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: claim stale listing: %v", err.Error())
+		return errors.New("error: claim stale listing failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: listing is already being recovered")
+	}
+
+	return nil
+}
+
+func (r *marketplaceRepository) UpdateOneListing(pctx context.Context, listingId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("listings")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(listingId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one listing: %v", err.Error())
+		return errors.New("error: update one listing failed")
+	}
+
+	return nil
+}
+
+func (r *marketplaceRepository) GetOffset(pctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("marketplace_queue")
+
+	result := new(models.KafkaOffset)
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		log.Printf("error: get offset failed: %v", err.Error())
+		return -1, errors.New("error: get offset failed")
+	}
+
+	return result.Offset, nil
+}
+
+func (r *marketplaceRepository) UpsertOffset(pctx context.Context, offset int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.marketplaceDbConn(ctx)
+	col := db.Collection("marketplace_queue")
+
+	result, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"offset": offset}}, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		log.Printf("error: upsert offset failed: %v", err.Error())
+		return errors.New("error: upsert offset failed")
+	}
+
+	log.Printf("info: upsert offset: %v", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *marketplaceRepository) SettleListing(pctx context.Context, cfg *config.Config, req *marketplace.SettleListingReq) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "marketplace", "settle", reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
+
+func (r *marketplaceRepository) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchGrant(ctx, req)
+	if err != nil {
+		log.Printf("error: batch grant failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *marketplaceRepository) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchRevoke(ctx, req)
+	if err != nil {
+		log.Printf("error: batch revoke failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *marketplaceRepository) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().CreatePlayerTransaction(ctx, req)
+	if err != nil {
+		log.Printf("error: create player transaction failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *marketplaceRepository) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	if _, err := conn.Player().RollbackPlayerTransaction(ctx, req); err != nil {
+		log.Printf("error: rollback player transaction failed: %v", err.Error())
+		return errors.New(status.Convert(err).Message())
+	}
+
+	return nil
+}
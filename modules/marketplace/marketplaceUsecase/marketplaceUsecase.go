@@ -0,0 +1,593 @@
+package marketplaceUsecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceRepository"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// staleListingAfter is how long a listing may sit in processing before
+// RecoverListings takes it over.
+const staleListingAfter = 5 * time.Minute
+
+type (
+	MarketplaceUsecaseService interface {
+		CreateListing(pctx context.Context, cfg *config.Config, playerId string, req *marketplace.CreateListingReq) (*marketplace.Listing, error)
+		FindOneListing(pctx context.Context, listingId string) (*marketplace.Listing, error)
+		FindListings(pctx context.Context, req *marketplace.ListingSearchReq) ([]*marketplace.Listing, error)
+		BuyListing(pctx context.Context, cfg *config.Config, playerId, listingId string) (*marketplace.Listing, error)
+		PlaceBid(pctx context.Context, cfg *config.Config, playerId, listingId string, req *marketplace.PlaceBidReq) (*marketplace.Listing, error)
+		CancelListing(pctx context.Context, cfg *config.Config, playerId, listingId string) (*marketplace.Listing, error)
+		QueueExpiredListings(pctx context.Context, cfg *config.Config) error
+		SettleListing(pctx context.Context, cfg *config.Config, req *marketplace.SettleListingReq) error
+		RecoverListings(pctx context.Context, cfg *config.Config) error
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+	}
+
+	marketplaceUsecase struct {
+		marketplaceRepository marketplaceRepository.MarketplaceRepositoryService
+	}
+)
+
+func NewMarketplaceUsecase(marketplaceRepository marketplaceRepository.MarketplaceRepositoryService) MarketplaceUsecaseService {
+	return &marketplaceUsecase{marketplaceRepository}
+}
+
+// CreateListing charges the listing fee and moves the item out of the
+// seller's inventory. The fee is kept whether or not the item sells.
+func (u *marketplaceUsecase) CreateListing(pctx context.Context, cfg *config.Config, playerId string, req *marketplace.CreateListingReq) (*marketplace.Listing, error) {
+	if req.Kind != "fixed" && req.Kind != "auction" {
+		return nil, errors.New("error: kind must be fixed or auction")
+	}
+
+	if req.Price <= 0 {
+		return nil, errors.New("error: price must be greater than 0")
+	}
+
+	duration := req.Duration
+	if duration == 0 {
+		duration = 24 * 60
+	}
+
+	feeTransactionId := ""
+	if cfg.Marketplace.ListingFee > 0 {
+		res, err := u.marketplaceRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: playerId,
+			Amount:   -cfg.Marketplace.ListingFee,
+		})
+		if err != nil {
+			return nil, err
+		}
+		feeTransactionId = res.TransactionId
+	}
+
+	refundFee := func() {
+		if feeTransactionId == "" {
+			return
+		}
+
+		if err := u.marketplaceRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: feeTransactionId,
+		}); err != nil {
+			log.Printf("Error: refund listing fee of player %s failed: %v", playerId, err.Error())
+		}
+	}
+
+	revoked, err := u.marketplaceRepository.BatchRevoke(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{
+		Items: []*inventoryPb.GrantItem{{PlayerId: playerId, ItemId: strings.TrimSpace(req.ItemId), Quantity: 1}},
+	})
+	if err != nil {
+		refundFee()
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	result := &marketplace.Listing{
+		SellerId:    playerId,
+		ItemId:      revoked.Items[0].ItemId,
+		InventoryId: revoked.Items[0].InventoryId,
+		Instance:    inventory.InstanceFromPb(revoked.Items[0].Instance),
+		Kind:        req.Kind,
+		Price:       req.Price,
+		ListingFee:  cfg.Marketplace.ListingFee,
+		Status:      "active",
+		ExpiresAt:   now.Add(time.Duration(duration) * time.Minute),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	listingId, err := u.marketplaceRepository.InsertOneListing(pctx, result)
+	if err != nil {
+		u.returnItem(pctx, cfg, result)
+		refundFee()
+		return nil, err
+	}
+	result.Id = listingId
+
+	return result, nil
+}
+
+func (u *marketplaceUsecase) FindOneListing(pctx context.Context, listingId string) (*marketplace.Listing, error) {
+	return u.marketplaceRepository.FindOneListing(pctx, listingId)
+}
+
+func (u *marketplaceUsecase) FindListings(pctx context.Context, req *marketplace.ListingSearchReq) ([]*marketplace.Listing, error) {
+	status := req.Status
+	if status == "" {
+		status = "active"
+	}
+
+	filter := bson.D{{Key: "status", Value: status}}
+	if req.ItemId != "" {
+		filter = append(filter, bson.E{Key: "item_id", Value: "item:" + strings.TrimPrefix(req.ItemId, "item:")})
+	}
+	if req.SellerId != "" {
+		filter = append(filter, bson.E{Key: "seller_id", Value: "player:" + strings.TrimPrefix(req.SellerId, "player:")})
+	}
+	if req.Kind != "" {
+		filter = append(filter, bson.E{Key: "kind", Value: req.Kind})
+	}
+
+	return u.marketplaceRepository.FindListings(pctx, filter)
+}
+
+// BuyListing takes the price from the buyer, hands over the item and pays
+// the seller the price less tax. Each failed step undoes the earlier ones.
+func (u *marketplaceUsecase) BuyListing(pctx context.Context, cfg *config.Config, playerId, listingId string) (*marketplace.Listing, error) {
+	result, err := u.findActiveListing(pctx, listingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Kind != "fixed" {
+		return nil, errors.New("error: auctions can only be bid on")
+	}
+
+	if result.SellerId == playerId {
+		return nil, errors.New("error: cannot buy your own listing")
+	}
+
+	if err := u.marketplaceRepository.LockListing(pctx, listingId, result.BidCount); err != nil {
+		return nil, err
+	}
+
+	paid, err := u.marketplaceRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+		PlayerId: playerId,
+		Amount:   -result.Price,
+	})
+	if err != nil {
+		u.unlockListing(pctx, listingId, err)
+		return nil, err
+	}
+
+	settlement := &marketplace.ListingSettlement{
+		BuyerId: playerId,
+		Price:   result.Price,
+		Payment: paid.TransactionId,
+	}
+	if err := u.saveSettlement(pctx, listingId, settlement); err != nil {
+		u.compensate(pctx, cfg, listingId, settlement, err)
+		return nil, err
+	}
+
+	if err := u.completeSale(pctx, cfg, result, settlement); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PlaceBid holds the bid amount from the bidder and refunds the bid it
+// replaces once the new one is saved, so a failed write never leaves the
+// listing pointing at a refunded bid.
+func (u *marketplaceUsecase) PlaceBid(pctx context.Context, cfg *config.Config, playerId, listingId string, req *marketplace.PlaceBidReq) (*marketplace.Listing, error) {
+	result, err := u.findActiveListing(pctx, listingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Kind != "auction" {
+		return nil, errors.New("error: fixed price listings can only be bought")
+	}
+
+	if result.SellerId == playerId {
+		return nil, errors.New("error: cannot bid on your own listing")
+	}
+
+	if req.Amount < result.Price {
+		return nil, errors.New("error: bid is lower than the starting price")
+	}
+
+	if result.HighestBid != nil && req.Amount <= result.HighestBid.Amount {
+		return nil, errors.New("error: bid must be higher than the current bid")
+	}
+
+	if err := u.marketplaceRepository.LockListing(pctx, listingId, result.BidCount); err != nil {
+		return nil, err
+	}
+
+	held, err := u.marketplaceRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+		PlayerId: playerId,
+		Amount:   -req.Amount,
+	})
+	if err != nil {
+		u.unlockListing(pctx, listingId, err)
+		return nil, err
+	}
+
+	previous := result.HighestBid
+	result.HighestBid = &marketplace.ListingBid{
+		PlayerId:      playerId,
+		Amount:        req.Amount,
+		TransactionId: held.TransactionId,
+		CreatedAt:     utils.LocalTime(),
+	}
+	result.BidCount++
+	result.Status = "processing"
+	result.Settlement = &marketplace.ListingSettlement{}
+	if previous != nil {
+		result.Settlement.Refund = previous.TransactionId
+	}
+	result.Error = ""
+	result.UpdatedAt = utils.LocalTime()
+
+	if err := u.marketplaceRepository.UpdateOneListing(pctx, listingId, bson.M{
+		"highest_bid": result.HighestBid,
+		"bid_count":   result.BidCount,
+		"settlement":  result.Settlement,
+		"error":       result.Error,
+		"updated_at":  result.UpdatedAt,
+	}); err != nil {
+		u.rollbackTransaction(pctx, cfg, held.TransactionId)
+		u.unlockListing(pctx, listingId, err)
+		return nil, err
+	}
+
+	// The bid stands from here, a failed refund is retried by
+	// RecoverListings
+	if err := u.finishRefund(pctx, cfg, result); err != nil {
+		log.Printf("Error: refund previous bid of listing %s failed: %v", listingId, err.Error())
+	}
+
+	return result, nil
+}
+
+// CancelListing returns the item to a seller whose listing has no bids.
+func (u *marketplaceUsecase) CancelListing(pctx context.Context, cfg *config.Config, playerId, listingId string) (*marketplace.Listing, error) {
+	result, err := u.marketplaceRepository.FindOneListing(pctx, listingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.SellerId != playerId {
+		return nil, errors.New("error: listing not found")
+	}
+
+	if result.Status != "active" {
+		return nil, errors.New("error: listing is no longer active")
+	}
+
+	if result.BidCount > 0 {
+		return nil, errors.New("error: cannot cancel a listing with bids")
+	}
+
+	if err := u.closeListing(pctx, cfg, result, "cancelled"); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueueExpiredListings publishes a settle message per expired listing, so
+// settlement runs in the queue consumer rather than the scan.
+func (u *marketplaceUsecase) QueueExpiredListings(pctx context.Context, cfg *config.Config) error {
+	results, err := u.marketplaceRepository.FindExpiredListings(pctx, utils.LocalTime())
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if err := u.marketplaceRepository.SettleListing(pctx, cfg, &marketplace.SettleListingReq{
+			ListingId: result.Id.Hex(),
+		}); err != nil {
+			log.Printf("Error: queue settle listing %s failed: %v", result.Id.Hex(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SettleListing ends an expired listing. An auction with a bid goes to the
+// highest bidder, whose money is already held; anything else goes back to
+// the seller. Failures leave the listing active for the next scan.
+func (u *marketplaceUsecase) SettleListing(pctx context.Context, cfg *config.Config, req *marketplace.SettleListingReq) error {
+	listingId := strings.TrimPrefix(req.ListingId, "listing:")
+
+	result, err := u.marketplaceRepository.FindOneListing(pctx, listingId)
+	if err != nil {
+		return err
+	}
+
+	if result.Status != "active" || utils.LocalTime().Before(result.ExpiresAt) {
+		return nil
+	}
+
+	if result.HighestBid == nil {
+		return u.closeListing(pctx, cfg, result, "expired")
+	}
+
+	if err := u.marketplaceRepository.LockListing(pctx, listingId, result.BidCount); err != nil {
+		return err
+	}
+
+	// The winning bid is already held and stays held if the sale is undone
+	return u.completeSale(pctx, cfg, result, &marketplace.ListingSettlement{
+		BuyerId: result.HighestBid.PlayerId,
+		Price:   result.HighestBid.Amount,
+	})
+}
+
+// RecoverListings finishes listings left processing by a crash or a failed
+// compensation. Owed refunds and returns are paid out and sales whose item
+// was handed over are marked sold; anything else is undone and the listing
+// goes back to active.
+func (u *marketplaceUsecase) RecoverListings(pctx context.Context, cfg *config.Config) error {
+	results, err := u.marketplaceRepository.FindStaleListings(pctx, utils.LocalTime().Add(-staleListingAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		listingId := result.Id.Hex()
+
+		if err := u.marketplaceRepository.ClaimStaleListing(pctx, listingId, result.UpdatedAt); err != nil {
+			continue
+		}
+
+		settlement := result.Settlement
+		if settlement == nil {
+			settlement = new(marketplace.ListingSettlement)
+		}
+		result.Settlement = settlement
+
+		switch {
+		case settlement.Refund != "":
+			err = u.finishRefund(pctx, cfg, result)
+		case settlement.Close != "":
+			err = u.finishClose(pctx, result)
+		case settlement.Granting:
+			err = u.finishSale(pctx, result)
+		default:
+			err = u.compensate(pctx, cfg, listingId, settlement, errors.New("error: listing was interrupted"))
+		}
+		if err != nil {
+			log.Printf("Error: recover listing %s failed: %v", listingId, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (u *marketplaceUsecase) GetOffset(pctx context.Context) (int64, error) {
+	return u.marketplaceRepository.GetOffset(pctx)
+}
+
+func (u *marketplaceUsecase) UpsertOffset(pctx context.Context, offset int64) error {
+	return u.marketplaceRepository.UpsertOffset(pctx, offset)
+}
+
+// completeSale runs on a locked listing whose buyer has already paid. The
+// seller is paid before the item is granted, so once the item is handed
+// over the sale is only ever finished, never undone.
+func (u *marketplaceUsecase) completeSale(pctx context.Context, cfg *config.Config, result *marketplace.Listing, settlement *marketplace.ListingSettlement) error {
+	listingId := result.Id.Hex()
+
+	settlement.Tax = math.Round(settlement.Price*cfg.Marketplace.TaxRate*100) / 100
+	if proceeds := settlement.Price - settlement.Tax; proceeds > 0 {
+		res, err := u.marketplaceRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: result.SellerId,
+			Amount:   proceeds,
+		})
+		if err != nil {
+			u.compensate(pctx, cfg, listingId, settlement, err)
+			return err
+		}
+		settlement.Proceeds = res.TransactionId
+	}
+
+	settlement.Granting = true
+	if err := u.saveSettlement(pctx, listingId, settlement); err != nil {
+		settlement.Granting = false
+		u.compensate(pctx, cfg, listingId, settlement, err)
+		return err
+	}
+
+	if _, err := u.marketplaceRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+		Items:             []*inventoryPb.GrantItem{{PlayerId: settlement.BuyerId, ItemId: result.ItemId, Quantity: 1, Instance: inventory.InstanceToPb(result.Instance)}},
+		OverflowToMailbox: true,
+		Source:            "marketplace",
+	}); err != nil {
+		settlement.Granting = false
+		u.compensate(pctx, cfg, listingId, settlement, err)
+		return err
+	}
+
+	result.Settlement = settlement
+	if err := u.finishSale(pctx, result); err != nil {
+		log.Printf("Error: update listing %s failed: %v", listingId, err.Error())
+	}
+
+	return nil
+}
+
+// finishSale marks a listing whose item went to the buyer as sold.
+func (u *marketplaceUsecase) finishSale(pctx context.Context, result *marketplace.Listing) error {
+	result.BuyerId = result.Settlement.BuyerId
+	result.SoldPrice = result.Settlement.Price
+	result.Tax = result.Settlement.Tax
+	result.Status = "sold"
+	result.Settlement = nil
+	result.Error = ""
+	result.UpdatedAt = utils.LocalTime()
+
+	return u.marketplaceRepository.UpdateOneListing(pctx, result.Id.Hex(), bson.M{
+		"buyer_id":   result.BuyerId,
+		"sold_price": result.SoldPrice,
+		"tax":        result.Tax,
+		"status":     result.Status,
+		"settlement": result.Settlement,
+		"error":      result.Error,
+		"updated_at": result.UpdatedAt,
+	})
+}
+
+// closeListing gives the item back to the seller and ends the listing. The
+// close is saved before the item is returned, so from then on it is only
+// ever finished.
+func (u *marketplaceUsecase) closeListing(pctx context.Context, cfg *config.Config, result *marketplace.Listing, status string) error {
+	listingId := result.Id.Hex()
+
+	if err := u.marketplaceRepository.LockListing(pctx, listingId, result.BidCount); err != nil {
+		return err
+	}
+
+	settlement := &marketplace.ListingSettlement{Close: status}
+	if err := u.saveSettlement(pctx, listingId, settlement); err != nil {
+		u.unlockListing(pctx, listingId, err)
+		return err
+	}
+
+	if _, err := u.marketplaceRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+		Items:             []*inventoryPb.GrantItem{{PlayerId: result.SellerId, ItemId: result.ItemId, Quantity: 1, Instance: inventory.InstanceToPb(result.Instance)}},
+		OverflowToMailbox: true,
+	}); err != nil {
+		u.unlockListing(pctx, listingId, err)
+		return err
+	}
+
+	result.Settlement = settlement
+	return u.finishClose(pctx, result)
+}
+
+func (u *marketplaceUsecase) finishClose(pctx context.Context, result *marketplace.Listing) error {
+	result.Status = result.Settlement.Close
+	result.Settlement = nil
+	result.UpdatedAt = utils.LocalTime()
+
+	return u.marketplaceRepository.UpdateOneListing(pctx, result.Id.Hex(), bson.M{
+		"status":     result.Status,
+		"settlement": result.Settlement,
+		"updated_at": result.UpdatedAt,
+	})
+}
+
+// finishRefund returns an outbid bid and reopens the auction.
+func (u *marketplaceUsecase) finishRefund(pctx context.Context, cfg *config.Config, result *marketplace.Listing) error {
+	if result.Settlement.Refund != "" {
+		if err := u.marketplaceRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: result.Settlement.Refund,
+		}); err != nil {
+			return err
+		}
+	}
+
+	result.Status = "active"
+	result.Settlement = nil
+	result.UpdatedAt = utils.LocalTime()
+
+	return u.marketplaceRepository.UpdateOneListing(pctx, result.Id.Hex(), bson.M{
+		"status":     result.Status,
+		"settlement": result.Settlement,
+		"updated_at": result.UpdatedAt,
+	})
+}
+
+// compensate undoes a sale that never handed the item over: the seller's
+// proceeds, then the buyer's payment. A failure leaves the listing
+// processing with only what is left to undo, for RecoverListings to retry.
+func (u *marketplaceUsecase) compensate(pctx context.Context, cfg *config.Config, listingId string, settlement *marketplace.ListingSettlement, cause error) error {
+	for _, transactionId := range []*string{&settlement.Proceeds, &settlement.Payment} {
+		if *transactionId == "" {
+			continue
+		}
+
+		if err := u.marketplaceRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: *transactionId,
+		}); err != nil {
+			log.Printf("Error: compensate listing %s failed: %v", listingId, err.Error())
+			if err := u.saveSettlement(pctx, listingId, settlement); err != nil {
+				log.Printf("Error: save settlement of listing %s failed: %v", listingId, err.Error())
+			}
+			return err
+		}
+		*transactionId = ""
+	}
+
+	u.unlockListing(pctx, listingId, cause)
+	return nil
+}
+
+func (u *marketplaceUsecase) saveSettlement(pctx context.Context, listingId string, settlement *marketplace.ListingSettlement) error {
+	return u.marketplaceRepository.UpdateOneListing(pctx, listingId, bson.M{
+		"settlement": settlement,
+		"updated_at": utils.LocalTime(),
+	})
+}
+
+func (u *marketplaceUsecase) findActiveListing(pctx context.Context, listingId string) (*marketplace.Listing, error) {
+	result, err := u.marketplaceRepository.FindOneListing(pctx, listingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status != "active" {
+		return nil, errors.New("error: listing is no longer active")
+	}
+
+	if !utils.LocalTime().Before(result.ExpiresAt) {
+		return nil, errors.New("error: listing has expired")
+	}
+
+	return result, nil
+}
+
+func (u *marketplaceUsecase) unlockListing(pctx context.Context, listingId string, cause error) {
+	if err := u.marketplaceRepository.UpdateOneListing(pctx, listingId, bson.M{
+		"status":     "active",
+		"settlement": nil,
+		"error":      cause.Error(),
+		"updated_at": utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: unlock listing %s failed: %v", listingId, err.Error())
+	}
+}
+
+func (u *marketplaceUsecase) returnItem(pctx context.Context, cfg *config.Config, result *marketplace.Listing) {
+	if _, err := u.marketplaceRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+		Items:             []*inventoryPb.GrantItem{{PlayerId: result.SellerId, ItemId: result.ItemId, Quantity: 1, Instance: inventory.InstanceToPb(result.Instance)}},
+		OverflowToMailbox: true,
+	}); err != nil {
+		log.Printf("Error: return item %s to player %s failed: %v", result.ItemId, result.SellerId, err.Error())
+	}
+}
+
+func (u *marketplaceUsecase) rollbackTransaction(pctx context.Context, cfg *config.Config, transactionId string) {
+	if err := u.marketplaceRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+		TransactionId: transactionId,
+	}); err != nil {
+		log.Printf("Error: rollback player transaction %s failed: %v", transactionId, err.Error())
+	}
+}
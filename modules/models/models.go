@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type (
 	PaginateReq struct {
 		Start string `query:"start" validate:"max=64"`
@@ -26,4 +28,13 @@ type (
 	KafkaOffset struct {
 		Offset int64 `json:"offset" bson:"offset"`
 	}
+
+	// DomainEvent is published on the "events" topic, keyed by Type, for
+	// game servers and other consumers outside the shop.
+	DomainEvent struct {
+		Type       string    `json:"type"`
+		PlayerId   string    `json:"player_id"`
+		Payload    any       `json:"payload"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
 )
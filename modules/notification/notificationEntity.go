@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// Notification is one message in a player's inbox, built from a domain
+	// event. Offset is the Kafka offset of that event, so a replayed message
+	// is only stored once.
+	Notification struct {
+		Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId  string        `json:"player_id" bson:"player_id"`
+		Type      string        `json:"type" bson:"type"`
+		Title     string        `json:"title" bson:"title"`
+		Message   string        `json:"message" bson:"message"`
+		Payload   any           `json:"payload,omitempty" bson:"payload,omitempty"`
+		Offset    int64         `json:"-" bson:"offset"`
+		Read      bool          `json:"read" bson:"read"`
+		ReadAt    *time.Time    `json:"read_at,omitempty" bson:"read_at,omitempty"`
+		CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	}
+)
@@ -0,0 +1,174 @@
+package notificationHandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/notification"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+type (
+	NotificationHttpHandlerService interface {
+		FindNotifications(c echo.Context) error
+		CountUnread(c echo.Context) error
+		MarkRead(c echo.Context) error
+		MarkAllRead(c echo.Context) error
+		Stream(c echo.Context) error
+		Updates(c echo.Context) error
+	}
+
+	notificationHttpHandler struct {
+		cfg                 *config.Config
+		notificationUsecase notificationUsecase.NotificationUsecaseService
+	}
+)
+
+func NewNotificationHttpHandler(cfg *config.Config, notificationUsecase notificationUsecase.NotificationUsecaseService) NotificationHttpHandlerService {
+	return &notificationHttpHandler{cfg, notificationUsecase}
+}
+
+func (h *notificationHttpHandler) FindNotifications(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(notification.NotificationSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.notificationUsecase.FindNotifications(ctx, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *notificationHttpHandler) CountUnread(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.notificationUsecase.CountUnread(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *notificationHttpHandler) MarkRead(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	if err := h.notificationUsecase.MarkRead(ctx, playerId, c.Param("notification_id")); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{Message: "notification marked as read"})
+}
+
+func (h *notificationHttpHandler) MarkAllRead(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	count, err := h.notificationUsecase.MarkAllRead(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{Message: fmt.Sprintf("%d notifications marked as read", count)})
+}
+
+// Stream sends new notifications as server-sent events until the client goes
+// away. Comment lines keep idle connections open through proxies.
+func (h *notificationHttpHandler) Stream(c echo.Context) error {
+	playerId := c.Get("player_id").(string)
+
+	messages, unsubscribe := h.notificationUsecase.Subscribe(playerId)
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case message := <-messages:
+			fmt.Fprintf(res, "event: notification\ndata: %s\n\n", message)
+			res.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": ping\n\n")
+			res.Flush()
+		}
+	}
+}
+
+// Updates upgrades to a WebSocket that pushes balance, inventory, saga and
+// notification updates as JSON text messages. Messages from the client are
+// ignored; a failed read means it went away.
+func (h *notificationHttpHandler) Updates(c echo.Context) error {
+	playerId := c.Get("player_id").(string)
+
+	server := websocket.Server{
+		// Origins are not checked, like CORS for the rest of the API
+		Handshake: func(config *websocket.Config, req *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			updates, unsubscribe := h.notificationUsecase.SubscribeUpdates(playerId)
+			defer unsubscribe()
+
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			heartbeat := time.NewTicker(30 * time.Second)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case message := <-updates:
+					if err := websocket.Message.Send(ws, string(message)); err != nil {
+						return
+					}
+				case <-heartbeat.C:
+					if err := websocket.Message.Send(ws, `{"type":"ping"}`); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
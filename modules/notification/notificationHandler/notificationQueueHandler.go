@@ -0,0 +1,160 @@
+package notificationHandler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IBM/sarama"
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationUsecase"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+)
+
+type (
+	NotificationQueueHandlerService interface {
+		StoreEvents()
+		PushSagaSteps()
+	}
+
+	notificationQueueHandler struct {
+		cfg                 *config.Config
+		notificationUsecase notificationUsecase.NotificationUsecaseService
+	}
+)
+
+func NewNotificationQueueHandler(cfg *config.Config, notificationUsecase notificationUsecase.NotificationUsecaseService) NotificationQueueHandlerService {
+	return &notificationQueueHandler{cfg, notificationUsecase}
+}
+
+func (h *notificationQueueHandler) NotificationConsumer(pctx context.Context) (sarama.PartitionConsumer, error) {
+	worker, err := queue.ConnectConsumer([]string{h.cfg.Kafka.Url}, h.cfg.Kafka.ApiKey, h.cfg.Kafka.Secret)
+	if err != nil {
+		return nil, errors.New("error: connect consumer failed")
+	}
+
+	offset, err := h.notificationUsecase.GetOffset(pctx)
+	if err != nil {
+		return nil, errors.New("error: get offset failed")
+	}
+
+	consumer, err := worker.ConsumePartition("events", 0, offset)
+	if err != nil {
+		log.Printf("Error: Try to consume partition with offset: %v", err.Error())
+		consumer, err = worker.ConsumePartition("events", 0, 0)
+		if err != nil {
+			log.Printf("Error: consume partition failed: %v", err.Error())
+			return nil, errors.New("error: consume partition failed")
+		}
+	}
+
+	return consumer, nil
+}
+
+func (h *notificationQueueHandler) StoreEvents() {
+	ctx := context.Background()
+
+	consumer, err := h.NotificationConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Notification event consumer started")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Printf("Error: notification event consumer failed: %v", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			h.notificationUsecase.UpsertOffset(ctx, msg.Offset+1)
+			event := new(models.DomainEvent)
+			if err := queue.DecodeMessage(event, msg.Value); err != nil {
+				continue
+			}
+
+			h.notificationUsecase.PushDomainEvent(event)
+
+			if _, err := h.notificationUsecase.StoreEvent(ctx, msg.Offset, event); err != nil {
+				log.Printf("Error: store %s event at offset %d failed: %v", event.Type, msg.Offset, err.Error())
+			}
+		case <-sigChan:
+			log.Println("Notification event consumer stopped")
+			return
+		}
+	}
+}
+
+// LatestConsumer reads topic from its newest message. Saga steps are only
+// pushed to clients connected right now, so no offset is stored for them.
+func (h *notificationQueueHandler) LatestConsumer(topic string) (sarama.PartitionConsumer, error) {
+	worker, err := queue.ConnectConsumer([]string{h.cfg.Kafka.Url}, h.cfg.Kafka.ApiKey, h.cfg.Kafka.Secret)
+	if err != nil {
+		return nil, errors.New("error: connect consumer failed")
+	}
+
+	consumer, err := worker.ConsumePartition(topic, 0, sarama.OffsetNewest)
+	if err != nil {
+		log.Printf("Error: consume partition failed: %v", err.Error())
+		return nil, errors.New("error: consume partition failed")
+	}
+
+	return consumer, nil
+}
+
+// PushSagaSteps forwards the replies the player and inventory services send
+// to the payment and reward sagas.
+func (h *notificationQueueHandler) PushSagaSteps() {
+	payments, err := h.LatestConsumer("payment")
+	if err != nil {
+		return
+	}
+	defer payments.Close()
+
+	rewards, err := h.LatestConsumer("reward")
+	if err != nil {
+		return
+	}
+	defer rewards.Close()
+
+	log.Println("Saga step consumer started")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	push := func(msg *sarama.ConsumerMessage) {
+		res := new(payment.PaymentTransferRes)
+		if err := queue.DecodeMessage(res, msg.Value); err != nil {
+			return
+		}
+
+		h.notificationUsecase.PushSagaStep(string(msg.Key), res)
+	}
+
+	for {
+		select {
+		case err := <-payments.Errors():
+			log.Printf("Error: saga step consumer failed: %v", err.Error())
+			continue
+		case err := <-rewards.Errors():
+			log.Printf("Error: saga step consumer failed: %v", err.Error())
+			continue
+		case msg := <-payments.Messages():
+			push(msg)
+		case msg := <-rewards.Messages():
+			push(msg)
+		case <-sigChan:
+			log.Println("Saga step consumer stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package notification
+
+import "time"
+
+type (
+	NotificationSearchReq struct {
+		Unread bool  `query:"unread"`
+		Limit  int64 `query:"limit" validate:"min=0,max=100"`
+	}
+
+	UnreadCountRes struct {
+		Count int64 `json:"count"`
+	}
+
+	// Update is one message sent over the WebSocket. Type is a domain event
+	// type, "notification" or one of the saga step types below.
+	Update struct {
+		Type       string    `json:"type"`
+		Data       any       `json:"data"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+
+	// BalanceUpdate is the data of a "balance.changed" update.
+	BalanceUpdate struct {
+		TransactionId string  `json:"transaction_id"`
+		Amount        float64 `json:"amount"`
+		ReferenceId   string  `json:"reference_id,omitempty"`
+	}
+
+	// InventoryUpdate is the data of "inventory.added" and "inventory.removed".
+	InventoryUpdate struct {
+		ItemId       string   `json:"item_id"`
+		InventoryIds []string `json:"inventory_ids"`
+		ReferenceId  string   `json:"reference_id,omitempty"`
+	}
+
+	// SagaStepUpdate is the data of a "saga.step_failed" update.
+	SagaStepUpdate struct {
+		Step        string `json:"step"`
+		ItemId      string `json:"item_id,omitempty"`
+		Error       string `json:"error"`
+		ReferenceId string `json:"reference_id,omitempty"`
+	}
+)
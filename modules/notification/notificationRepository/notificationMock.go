@@ -0,0 +1,52 @@
+package notificationRepository
+
+import (
+	"context"
+
+	"github.com/Supakornn/mmorpg-shop/modules/notification"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type NotificationRepositoryMock struct {
+	mock.Mock
+}
+
+func NewNotificationRepositoryMock() NotificationRepositoryService {
+	return &NotificationRepositoryMock{}
+}
+
+func (m *NotificationRepositoryMock) InsertOneNotification(pctx context.Context, req *notification.Notification) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) FindNotifications(pctx context.Context, filter bson.D, limit int64) ([]*notification.Notification, error) {
+	args := m.Called(pctx, filter, limit)
+	return args.Get(0).([]*notification.Notification), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) CountNotifications(pctx context.Context, filter bson.D) (int64, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) MarkRead(pctx context.Context, playerId, notificationId string) error {
+	args := m.Called(pctx, playerId, notificationId)
+	return args.Error(0)
+}
+
+func (m *NotificationRepositoryMock) MarkAllRead(pctx context.Context, playerId string) (int64, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) GetOffset(pctx context.Context) (int64, error) {
+	args := m.Called(pctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) UpsertOffset(pctx context.Context, offset int64) error {
+	args := m.Called(pctx, offset)
+	return args.Error(0)
+}
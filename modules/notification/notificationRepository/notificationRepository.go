@@ -0,0 +1,180 @@
+package notificationRepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/notification"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type (
+	NotificationRepositoryService interface {
+		InsertOneNotification(pctx context.Context, req *notification.Notification) (bson.ObjectID, error)
+		FindNotifications(pctx context.Context, filter bson.D, limit int64) ([]*notification.Notification, error)
+		CountNotifications(pctx context.Context, filter bson.D) (int64, error)
+		MarkRead(pctx context.Context, playerId, notificationId string) error
+		MarkAllRead(pctx context.Context, playerId string) (int64, error)
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+	}
+
+	notificationRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewNotificationRepository(db *mongo.Client) NotificationRepositoryService {
+	return &notificationRepository{db}
+}
+
+func (r *notificationRepository) notificationDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("notification_db")
+}
+
+func (r *notificationRepository) InsertOneNotification(pctx context.Context, req *notification.Notification) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notifications")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: notification already stored")
+		}
+		log.Printf("error: insert one notification: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one notification failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *notificationRepository) FindNotifications(pctx context.Context, filter bson.D, limit int64) ([]*notification.Notification, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notifications")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		log.Printf("error: find notifications: %v", err.Error())
+		return nil, errors.New("error: find notifications failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*notification.Notification, 0)
+	for cursors.Next(ctx) {
+		result := new(notification.Notification)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode notification: %v", err.Error())
+			return nil, errors.New("error: decode notification failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *notificationRepository) CountNotifications(pctx context.Context, filter bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notifications")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("error: count notifications: %v", err.Error())
+		return -1, errors.New("error: count notifications failed")
+	}
+
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(pctx context.Context, playerId, notificationId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notifications")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(notificationId), "player_id": playerId},
+		bson.M{"$set": bson.M{"read": true, "read_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: mark notification read: %v", err.Error())
+		return errors.New("error: mark notification read failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: notification not found")
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(pctx context.Context, playerId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notifications")
+
+	result, err := col.UpdateMany(
+		ctx,
+		bson.M{"player_id": playerId, "read": false},
+		bson.M{"$set": bson.M{"read": true, "read_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: mark all notifications read: %v", err.Error())
+		return -1, errors.New("error: mark all notifications read failed")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *notificationRepository) GetOffset(pctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notification_queue")
+
+	result := new(models.KafkaOffset)
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		log.Printf("error: get offset failed: %v", err.Error())
+		return -1, errors.New("error: get offset failed")
+	}
+
+	return result.Offset, nil
+}
+
+func (r *notificationRepository) UpsertOffset(pctx context.Context, offset int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.notificationDbConn(ctx)
+	col := db.Collection("notification_queue")
+
+	result, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"offset": offset}}, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		log.Printf("error: upsert offset failed: %v", err.Error())
+		return errors.New("error: upsert offset failed")
+	}
+
+	log.Printf("info: upsert offset: %v", result.ModifiedCount)
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package notificationUsecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/notification"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/Supakornn/mmorpg-shop/pkg/stream"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	NotificationUsecaseService interface {
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+		StoreEvent(pctx context.Context, offset int64, event *models.DomainEvent) (*notification.Notification, error)
+		FindNotifications(pctx context.Context, playerId string, req *notification.NotificationSearchReq) ([]*notification.Notification, error)
+		CountUnread(pctx context.Context, playerId string) (*notification.UnreadCountRes, error)
+		MarkRead(pctx context.Context, playerId, notificationId string) error
+		MarkAllRead(pctx context.Context, playerId string) (int64, error)
+		Subscribe(playerId string) (<-chan []byte, func())
+		PushDomainEvent(event *models.DomainEvent)
+		PushSagaStep(key string, res *payment.PaymentTransferRes)
+		SubscribeUpdates(playerId string) (<-chan []byte, func())
+	}
+
+	// notifications feeds the SSE stream and updates feeds the WebSocket.
+	notificationUsecase struct {
+		notificationRepository notificationRepository.NotificationRepositoryService
+		notifications          *stream.Hub
+		updates                *stream.Hub
+	}
+)
+
+func NewNotificationUsecase(notificationRepository notificationRepository.NotificationRepositoryService) NotificationUsecaseService {
+	return &notificationUsecase{notificationRepository, stream.NewHub(), stream.NewHub()}
+}
+
+func playerKey(playerId string) string {
+	return "player:" + strings.TrimPrefix(playerId, "player:")
+}
+
+func (u *notificationUsecase) GetOffset(pctx context.Context) (int64, error) {
+	return u.notificationRepository.GetOffset(pctx)
+}
+
+func (u *notificationUsecase) UpsertOffset(pctx context.Context, offset int64) error {
+	return u.notificationRepository.UpsertOffset(pctx, offset)
+}
+
+// StoreEvent turns a domain event into an inbox message and pushes it to the
+// player's open streams. Events players are not told about return nil.
+func (u *notificationUsecase) StoreEvent(pctx context.Context, offset int64, event *models.DomainEvent) (*notification.Notification, error) {
+	if event.PlayerId == "" {
+		return nil, nil
+	}
+
+	title, message, ok := notificationText(event)
+	if !ok {
+		return nil, nil
+	}
+
+	result := &notification.Notification{
+		PlayerId:  playerKey(event.PlayerId),
+		Type:      event.Type,
+		Title:     title,
+		Message:   message,
+		Payload:   event.Payload,
+		Offset:    offset,
+		CreatedAt: utils.LocalTime(),
+	}
+
+	notificationId, err := u.notificationRepository.InsertOneNotification(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = notificationId
+
+	if message, err := json.Marshal(result); err != nil {
+		log.Printf("error: marshal notification %s: %v", notificationId.Hex(), err.Error())
+	} else {
+		u.notifications.Publish(result.PlayerId, message)
+	}
+	u.pushUpdate(result.PlayerId, "notification", result)
+
+	return result, nil
+}
+
+func (u *notificationUsecase) FindNotifications(pctx context.Context, playerId string, req *notification.NotificationSearchReq) ([]*notification.Notification, error) {
+	filter := bson.D{{Key: "player_id", Value: playerKey(playerId)}}
+	if req.Unread {
+		filter = append(filter, bson.E{Key: "read", Value: false})
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	return u.notificationRepository.FindNotifications(pctx, filter, limit)
+}
+
+func (u *notificationUsecase) CountUnread(pctx context.Context, playerId string) (*notification.UnreadCountRes, error) {
+	count, err := u.notificationRepository.CountNotifications(pctx, bson.D{
+		{Key: "player_id", Value: playerKey(playerId)},
+		{Key: "read", Value: false},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification.UnreadCountRes{Count: count}, nil
+}
+
+func (u *notificationUsecase) MarkRead(pctx context.Context, playerId, notificationId string) error {
+	return u.notificationRepository.MarkRead(pctx, playerKey(playerId), strings.TrimPrefix(notificationId, "notification:"))
+}
+
+func (u *notificationUsecase) MarkAllRead(pctx context.Context, playerId string) (int64, error) {
+	return u.notificationRepository.MarkAllRead(pctx, playerKey(playerId))
+}
+
+func (u *notificationUsecase) Subscribe(playerId string) (<-chan []byte, func()) {
+	return u.notifications.Subscribe(playerKey(playerId))
+}
+
+func (u *notificationUsecase) SubscribeUpdates(playerId string) (<-chan []byte, func()) {
+	return u.updates.Subscribe(playerKey(playerId))
+}
+
+func (u *notificationUsecase) pushUpdate(playerId, updateType string, data any) {
+	message, err := json.Marshal(&notification.Update{
+		Type:       updateType,
+		Data:       data,
+		OccurredAt: utils.LocalTime(),
+	})
+	if err != nil {
+		log.Printf("error: marshal %s update: %v", updateType, err.Error())
+		return
+	}
+
+	u.updates.Publish(playerKey(playerId), message)
+}
+
+// PushDomainEvent forwards every event of a player as an update of the same
+// type, whether or not it becomes a notification.
+func (u *notificationUsecase) PushDomainEvent(event *models.DomainEvent) {
+	if event.PlayerId == "" {
+		return
+	}
+
+	u.pushUpdate(event.PlayerId, event.Type, event.Payload)
+}
+
+// PushSagaStep turns a saga step reply of the player or inventory service
+// into an update. Replies with a transaction id moved money; the others added
+// ("buy") or removed ("sell") inventory items.
+func (u *notificationUsecase) PushSagaStep(key string, res *payment.PaymentTransferRes) {
+	if res.PlayerId == "" {
+		return
+	}
+
+	if res.Error != "" {
+		u.pushUpdate(res.PlayerId, "saga.step_failed", &notification.SagaStepUpdate{
+			Step:        key,
+			ItemId:      res.ItemId,
+			Error:       res.Error,
+			ReferenceId: res.ReferenceId,
+		})
+		return
+	}
+
+	if res.TransactionId != "" {
+		u.pushUpdate(res.PlayerId, "balance.changed", &notification.BalanceUpdate{
+			TransactionId: res.TransactionId,
+			Amount:        res.Amount,
+			ReferenceId:   res.ReferenceId,
+		})
+		return
+	}
+
+	inventoryIds := res.InventoryIds
+	if res.InventoryId != "" {
+		inventoryIds = append(inventoryIds, res.InventoryId)
+	}
+	if len(inventoryIds) == 0 {
+		return
+	}
+
+	updateType := "inventory.added"
+	if key == "sell" {
+		updateType = "inventory.removed"
+	}
+
+	u.pushUpdate(res.PlayerId, updateType, &notification.InventoryUpdate{
+		ItemId:       res.ItemId,
+		InventoryIds: inventoryIds,
+		ReferenceId:  res.ReferenceId,
+	})
+}
+
+// notificationText returns the title and message shown for an event. Payloads
+// arrive decoded from JSON, so they are read as maps.
+func notificationText(event *models.DomainEvent) (string, string, bool) {
+	payload, _ := event.Payload.(map[string]any)
+
+	text := func(key string) string {
+		v, _ := payload[key].(string)
+		return strings.TrimPrefix(v, "error: ")
+	}
+	number := func(key string) float64 {
+		v, _ := payload[key].(float64)
+		return v
+	}
+	count := func(key string) int {
+		v, _ := payload[key].([]any)
+		return len(v)
+	}
+
+	switch event.Type {
+	case "purchase.completed":
+		return "Purchase completed", fmt.Sprintf("You bought %d item(s) for %.2f.", count("item_ids"), number("amount")), true
+	case "purchase.failed":
+		return "Purchase failed", fmt.Sprintf("Your purchase could not be completed and you were not charged: %s.", text("error")), true
+	case "sale.completed":
+		return "Sale completed", fmt.Sprintf("You sold %d item(s) for %.2f.", count("item_ids"), number("amount")), true
+	case "sale.failed":
+		return "Sale failed", fmt.Sprintf("Your sale could not be completed and your items were kept: %s.", text("error")), true
+	case "payment.refunded":
+		return "Refund issued", fmt.Sprintf("%.2f was refunded to your account because the %s.", number("amount"), text("reason")), true
+	case "items.returned":
+		return "Items returned", fmt.Sprintf("%d item(s) were returned to your inventory.", count("item_ids")), true
+	case "items.granted":
+		return "Items received", fmt.Sprintf("You received %d item(s).", count("item_ids")), true
+	case "gift.received":
+		if message := text("message"); message != "" {
+			return "Gift received", fmt.Sprintf("You received a gift: %q", message), true
+		}
+		return "Gift received", "You received a gift.", true
+	case "item.expired":
+		return "Item expired", "An item in your inventory has expired.", true
+	case "item.updated_by_admin":
+		return "Item updated", "An administrator updated one of your items.", true
+	case "wishlist.price_drop":
+		return "Price drop", fmt.Sprintf("%s is now %.2f, down from %.2f.", text("title"), number("price"), number("old_price")), true
+	case "wishlist.available":
+		return "Available again", fmt.Sprintf("%s on your wishlist can be bought again.", text("title")), true
+	case "wishlist.restock":
+		return "Back in stock", fmt.Sprintf("%s on your wishlist is back in stock.", text("title")), true
+	}
+
+	return "", "", false
+}
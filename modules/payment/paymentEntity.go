@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// PaymentOrder records one buy, sell or grant. Status moves from
+	// "pending" to "completed" or "failed" once the saga finishes, when an
+	// async buy also calls its CallbackUrl.
+	PaymentOrder struct {
+		Id          bson.ObjectID       `json:"_id" bson:"_id,omitempty"`
+		PlayerId    string              `json:"player_id" bson:"player_id"`
+		Kind        string              `json:"kind" bson:"kind"`
+		Status      string              `json:"status" bson:"status"`
+		Items       []*PaymentOrderItem `json:"items" bson:"items"`
+		Amount      float64             `json:"amount" bson:"amount"`
+		Reason      string              `json:"reason,omitempty" bson:"reason,omitempty"`
+		Error       string              `json:"error,omitempty" bson:"error,omitempty"`
+		CallbackUrl string              `json:"callback_url,omitempty" bson:"callback_url,omitempty"`
+		// ReservedIds are the items the order holds stock and purchase limits
+		// for, so recovering it knows what to release
+		ReservedIds []string  `json:"-" bson:"reserved_ids,omitempty"`
+		CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	}
+
+	PaymentOrderItem struct {
+		ItemId        string   `json:"item_id" bson:"item_id"`
+		Price         float64  `json:"price" bson:"price"`
+		TransactionId string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		InventoryIds  []string `json:"inventory_ids,omitempty" bson:"inventory_ids,omitempty"`
+	}
+
+	// Gift is an item purchase or money transfer paid by SenderId for
+	// RecipientId.
+	Gift struct {
+		Id          bson.ObjectID       `json:"_id" bson:"_id,omitempty"`
+		SenderId    string              `json:"sender_id" bson:"sender_id"`
+		RecipientId string              `json:"recipient_id" bson:"recipient_id"`
+		Items       []*PaymentOrderItem `json:"items" bson:"items"`
+		Money       float64             `json:"money" bson:"money"`
+		Message     string              `json:"message,omitempty" bson:"message,omitempty"`
+		CreatedAt   time.Time           `json:"created_at" bson:"created_at"`
+	}
+
+	// GiftNotification tells the recipient a gift has arrived.
+	GiftNotification struct {
+		Id          bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		RecipientId string        `json:"recipient_id" bson:"recipient_id"`
+		SenderId    string        `json:"sender_id" bson:"sender_id"`
+		GiftId      string        `json:"gift_id" bson:"gift_id"`
+		Message     string        `json:"message,omitempty" bson:"message,omitempty"`
+		Read        bool          `json:"read" bson:"read"`
+		CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+	}
+
+	// GiftLimit holds when a player sent their gifts over the last day, so a
+	// send can be reserved against the daily limit in one update.
+	GiftLimit struct {
+		PlayerId string      `json:"player_id" bson:"player_id"`
+		Sent     []time.Time `json:"sent" bson:"sent"`
+	}
+
+	// LootRoll is the audit record of one loot box opening. Seed and the
+	// Entries snapshot are enough to replay the draw.
+	LootRoll struct {
+		Id            bson.ObjectID    `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string           `json:"player_id" bson:"player_id"`
+		BoxItemId     string           `json:"box_item_id" bson:"box_item_id"`
+		Price         float64          `json:"price" bson:"price"`
+		Seed          int64            `json:"seed" bson:"seed"`
+		Roll          int              `json:"roll" bson:"roll"`
+		TotalWeight   int              `json:"total_weight" bson:"total_weight"`
+		Entries       []*LootRollEntry `json:"entries" bson:"entries"`
+		Pity          int              `json:"pity" bson:"pity"`
+		PityApplied   bool             `json:"pity_applied" bson:"pity_applied"`
+		ItemId        string           `json:"item_id" bson:"item_id"`
+		Rare          bool             `json:"rare" bson:"rare"`
+		TransactionId string           `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		InventoryId   string           `json:"inventory_id,omitempty" bson:"inventory_id,omitempty"`
+		Status        string           `json:"status" bson:"status"`
+		Error         string           `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt     time.Time        `json:"created_at" bson:"created_at"`
+	}
+
+	LootRollEntry struct {
+		ItemId string `json:"item_id" bson:"item_id"`
+		Weight int    `json:"weight" bson:"weight"`
+		Rare   bool   `json:"rare" bson:"rare"`
+	}
+
+	// LootPity counts the opens of a box since the player's last rare drop.
+	LootPity struct {
+		PlayerId  string    `json:"player_id" bson:"player_id"`
+		BoxItemId string    `json:"box_item_id" bson:"box_item_id"`
+		Count     int       `json:"count" bson:"count"`
+		UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Craft records one crafting attempt. Success is false when the roll
+	// failed; ItemId is then the consolation item, if the recipe has one.
+	Craft struct {
+		Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string        `json:"player_id" bson:"player_id"`
+		RecipeId      string        `json:"recipe_id" bson:"recipe_id"`
+		Cost          float64       `json:"cost" bson:"cost"`
+		TransactionId string        `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		ConsumedIds   []string      `json:"consumed_ids,omitempty" bson:"consumed_ids,omitempty"`
+		Success       bool          `json:"success" bson:"success"`
+		ItemId        string        `json:"item_id,omitempty" bson:"item_id,omitempty"`
+		InventoryIds  []string      `json:"inventory_ids,omitempty" bson:"inventory_ids,omitempty"`
+		Status        string        `json:"status" bson:"status"`
+		Error         string        `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt     time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+)
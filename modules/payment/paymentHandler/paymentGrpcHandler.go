@@ -0,0 +1,40 @@
+package paymentHandler
+
+import (
+	"context"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	paymentPb "github.com/Supakornn/mmorpg-shop/modules/payment/paymentPb"
+	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentUsecase"
+)
+
+type (
+	paymentGrpcHandler struct {
+		paymentPb.UnimplementedPaymentGrpcServiceServer
+		cfg            *config.Config
+		paymentUsecase paymentUsecase.PaymentUsecaseService
+	}
+)
+
+func NewPaymentGrpcHandler(cfg *config.Config, paymentUsecase paymentUsecase.PaymentUsecaseService) *paymentGrpcHandler {
+	return &paymentGrpcHandler{
+		cfg:            cfg,
+		paymentUsecase: paymentUsecase,
+	}
+}
+
+func (g *paymentGrpcHandler) Buy(ctx context.Context, req *paymentPb.BuyReq) (*paymentPb.PaymentOrderRes, error) {
+	return g.paymentUsecase.Buy(ctx, g.cfg, req)
+}
+
+func (g *paymentGrpcHandler) Sell(ctx context.Context, req *paymentPb.SellReq) (*paymentPb.PaymentOrderRes, error) {
+	return g.paymentUsecase.Sell(ctx, g.cfg, req)
+}
+
+func (g *paymentGrpcHandler) Grant(ctx context.Context, req *paymentPb.GrantReq) (*paymentPb.PaymentOrderRes, error) {
+	return g.paymentUsecase.Grant(ctx, g.cfg, req)
+}
+
+func (g *paymentGrpcHandler) GetOrder(ctx context.Context, req *paymentPb.GetOrderReq) (*paymentPb.PaymentOrderRes, error) {
+	return g.paymentUsecase.GetOrder(ctx, req)
+}
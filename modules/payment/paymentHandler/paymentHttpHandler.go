@@ -15,7 +15,15 @@ import (
 type (
 	PaymentHttpHandlerService interface {
 		BuyItem(c echo.Context) error
+		FindOrderStatus(c echo.Context) error
 		SellItem(c echo.Context) error
+		GiftItem(c echo.Context) error
+		FindGiftNotifications(c echo.Context) error
+		OpenLootBox(c echo.Context) error
+		FindLootRolls(c echo.Context) error
+		VerifyLootRoll(c echo.Context) error
+		CraftItem(c echo.Context) error
+		BuySeasonPass(c echo.Context) error
 	}
 
 	paymentHttpHandler struct {
@@ -36,15 +44,26 @@ func (h *paymentHttpHandler) BuyItem(c echo.Context) error {
 
 	playerId := c.Get("player_id").(string)
 
-	req := &payment.ItemServiceReq{
-		Items: make([]*payment.ItemServiceReqDatum, 0),
+	req := &payment.BuyItemReq{
+		ItemServiceReq: payment.ItemServiceReq{
+			Items: make([]*payment.ItemServiceReqDatum, 0),
+		},
 	}
 
 	if err := wrapper.Bind(req); err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.paymentUsecase.BuyItem(ctx, h.cfg, playerId, req)
+	if req.Async {
+		res, err := h.paymentUsecase.BuyItemAsync(ctx, h.cfg, playerId, req)
+		if err != nil {
+			return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+		}
+
+		return response.SuccessResponse(c, http.StatusAccepted, res)
+	}
+
+	res, err := h.paymentUsecase.BuyItem(ctx, h.cfg, playerId, &req.ItemServiceReq)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -52,6 +71,19 @@ func (h *paymentHttpHandler) BuyItem(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusCreated, res)
 }
 
+func (h *paymentHttpHandler) FindOrderStatus(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.paymentUsecase.FindOrderStatus(ctx, playerId, c.Param("order_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
 func (h *paymentHttpHandler) SellItem(c echo.Context) error {
 	ctx := context.Background()
 
@@ -74,3 +106,120 @@ func (h *paymentHttpHandler) SellItem(c echo.Context) error {
 
 	return response.SuccessResponse(c, http.StatusCreated, res)
 }
+
+func (h *paymentHttpHandler) GiftItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	playerId := c.Get("player_id").(string)
+
+	req := &payment.GiftReq{
+		Items: make([]*payment.ItemServiceReqDatum, 0),
+	}
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.GiftItem(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *paymentHttpHandler) FindGiftNotifications(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.paymentUsecase.FindGiftNotifications(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) OpenLootBox(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	playerId := c.Get("player_id").(string)
+
+	req := new(payment.OpenLootBoxReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.OpenLootBox(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *paymentHttpHandler) FindLootRolls(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.paymentUsecase.FindLootRolls(ctx, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) VerifyLootRoll(c echo.Context) error {
+	ctx := context.Background()
+
+	rollId := c.Param("roll_id")
+
+	res, err := h.paymentUsecase.VerifyLootRoll(ctx, rollId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) CraftItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	playerId := c.Get("player_id").(string)
+
+	req := new(payment.CraftReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.CraftItem(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *paymentHttpHandler) BuySeasonPass(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.paymentUsecase.BuySeasonPass(ctx, h.cfg, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
@@ -1,13 +1,58 @@
 package payment
 
+import (
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	"github.com/Supakornn/mmorpg-shop/modules/item"
+)
+
 type (
 	ItemServiceReq struct {
 		Items []*ItemServiceReqDatum `json:"items" validate:"required"`
+		// OrderId is set when the saga runs for a stored order. Its steps are
+		// then named after the order so a stale order can be compensated.
+		OrderId string `json:"-"`
+	}
+
+	// BuyItemReq runs the purchase in the background when Async is set.
+	// CallbackUrl is then called with the final order status.
+	BuyItemReq struct {
+		ItemServiceReq
+		Async       bool   `json:"async"`
+		CallbackUrl string `json:"callback_url" validate:"omitempty,excluded_without=Async,http_url,max=512"`
+	}
+
+	AsyncOrderRes struct {
+		OrderId string `json:"order_id"`
+		Status  string `json:"status"`
+	}
+
+	OrderStatusRes struct {
+		OrderId   string              `json:"order_id"`
+		Kind      string              `json:"kind"`
+		Status    string              `json:"status"`
+		Items     []*PaymentOrderItem `json:"items"`
+		Amount    float64             `json:"amount"`
+		Error     string              `json:"error,omitempty"`
+		UpdatedAt time.Time           `json:"updated_at"`
 	}
 
 	ItemServiceReqDatum struct {
-		ItemId string  `json:"item_id" validate:"required,max=64"`
-		Price  float64 `json:"price"`
+		ItemId string `json:"item_id" validate:"required,max=64"`
+		// InventoryId picks the copy to sell; any unequipped copy is sold
+		// when it is empty.
+		InventoryId string                  `json:"inventory_id,omitempty" validate:"max=64"`
+		Price       float64                 `json:"price"`
+		Components  []*item.BundleComponent `json:"-"`
+	}
+
+	// GiftReq buys Items for RecipientId and/or sends them Money.
+	GiftReq struct {
+		RecipientId string                 `json:"recipient_id" validate:"required,max=64"`
+		Items       []*ItemServiceReqDatum `json:"items" validate:"max=20"`
+		Money       float64                `json:"money" validate:"min=0"`
+		Message     string                 `json:"message" validate:"max=200"`
 	}
 
 	PaymentTransferReq struct {
@@ -17,11 +62,51 @@ type (
 	}
 
 	PaymentTransferRes struct {
-		InventoryId   string  `json:"inventory_id"`
-		TransactionId string  `json:"transaction_id"`
-		PlayerId      string  `json:"player_id"`
-		ItemId        string  `json:"item_id"`
-		Amount        float64 `json:"amount"`
-		Error         string  `json:"error"`
+		InventoryId   string   `json:"inventory_id"`
+		InventoryIds  []string `json:"inventory_ids,omitempty"`
+		TransactionId string   `json:"transaction_id"`
+		PlayerId      string   `json:"player_id"`
+		ItemId        string   `json:"item_id"`
+		Amount        float64  `json:"amount"`
+		// ValueFactor scales the sell price by the condition of the sold copy
+		ValueFactor float64 `json:"value_factor,omitempty"`
+		// Instance is the removed copy, put back as-is if the sale rolls back
+		Instance    *inventory.ItemInstance `json:"instance,omitempty"`
+		ReferenceId string                  `json:"reference_id,omitempty"`
+		Error       string                  `json:"error"`
+	}
+
+	// OpenLootBoxReq opens one copy of ItemId the player owns.
+	OpenLootBoxReq struct {
+		ItemId string `json:"item_id" validate:"required,max=64"`
+	}
+
+	LootRollVerifyRes struct {
+		RollId string `json:"roll_id"`
+		ItemId string `json:"item_id"`
+		Roll   int    `json:"roll"`
+		Valid  bool   `json:"valid"`
+	}
+
+	CraftReq struct {
+		RecipeId string `json:"recipe_id" validate:"required,max=64"`
+	}
+
+	// PaymentEvent is the payload of the "purchase.*", "sale.*",
+	// "items.granted", "items.returned" and "payment.refunded" events.
+	PaymentEvent struct {
+		ItemIds []string `json:"item_ids,omitempty"`
+		Amount  float64  `json:"amount,omitempty"`
+		Reason  string   `json:"reason,omitempty"`
+		Error   string   `json:"error,omitempty"`
+	}
+
+	// GiftEvent is the payload of the "gift.received" event.
+	GiftEvent struct {
+		GiftId   string   `json:"gift_id"`
+		SenderId string   `json:"sender_id"`
+		ItemIds  []string `json:"item_ids,omitempty"`
+		Money    float64  `json:"money,omitempty"`
+		Message  string   `json:"message,omitempty"`
 	}
 )
@@ -0,0 +1,571 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: modules/payment/paymentPb/paymentPb.proto
+
+package mmorpg_shop
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+//	Structures
+//
+// item_ids are either "item:<hex>" or "sku:<SKU>"; repeat an id to trade several
+type BuyReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemIds       []string               `protobuf:"bytes,2,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuyReq) Reset() {
+	*x = BuyReq{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuyReq) ProtoMessage() {}
+
+func (x *BuyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuyReq.ProtoReflect.Descriptor instead.
+func (*BuyReq) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BuyReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *BuyReq) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+type SellReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemIds       []string               `protobuf:"bytes,2,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SellReq) Reset() {
+	*x = SellReq{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellReq) ProtoMessage() {}
+
+func (x *SellReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellReq.ProtoReflect.Descriptor instead.
+func (*SellReq) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SellReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *SellReq) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+type GrantReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	ItemIds       []string               `protobuf:"bytes,2,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantReq) Reset() {
+	*x = GrantReq{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantReq) ProtoMessage() {}
+
+func (x *GrantReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantReq.ProtoReflect.Descriptor instead.
+func (*GrantReq) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GrantReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *GrantReq) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+func (x *GrantReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetOrderReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderReq) Reset() {
+	*x = GetOrderReq{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderReq) ProtoMessage() {}
+
+func (x *GetOrderReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderReq.ProtoReflect.Descriptor instead.
+func (*GetOrderReq) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetOrderReq) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type PaymentOrderRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *PaymentOrder          `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentOrderRes) Reset() {
+	*x = PaymentOrderRes{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentOrderRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentOrderRes) ProtoMessage() {}
+
+func (x *PaymentOrderRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentOrderRes.ProtoReflect.Descriptor instead.
+func (*PaymentOrderRes) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PaymentOrderRes) GetOrder() *PaymentOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type PaymentOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Items         []*PaymentOrderItem    `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	Amount        float64                `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentOrder) Reset() {
+	*x = PaymentOrder{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentOrder) ProtoMessage() {}
+
+func (x *PaymentOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentOrder.ProtoReflect.Descriptor instead.
+func (*PaymentOrder) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PaymentOrder) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetItems() []*PaymentOrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PaymentOrder) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentOrder) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *PaymentOrder) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type PaymentOrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	InventoryIds  []string               `protobuf:"bytes,4,rep,name=inventory_ids,json=inventoryIds,proto3" json:"inventory_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentOrderItem) Reset() {
+	*x = PaymentOrderItem{}
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentOrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentOrderItem) ProtoMessage() {}
+
+func (x *PaymentOrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_payment_paymentPb_paymentPb_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentOrderItem.ProtoReflect.Descriptor instead.
+func (*PaymentOrderItem) Descriptor() ([]byte, []int) {
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PaymentOrderItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *PaymentOrderItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PaymentOrderItem) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PaymentOrderItem) GetInventoryIds() []string {
+	if x != nil {
+		return x.InventoryIds
+	}
+	return nil
+}
+
+var File_modules_payment_paymentPb_paymentPb_proto protoreflect.FileDescriptor
+
+const file_modules_payment_paymentPb_paymentPb_proto_rawDesc = "" +
+	"\n" +
+	")modules/payment/paymentPb/paymentPb.proto\"@\n" +
+	"\x06BuyReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x19\n" +
+	"\bitem_ids\x18\x02 \x03(\tR\aitemIds\"A\n" +
+	"\aSellReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x19\n" +
+	"\bitem_ids\x18\x02 \x03(\tR\aitemIds\"Z\n" +
+	"\bGrantReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x19\n" +
+	"\bitem_ids\x18\x02 \x03(\tR\aitemIds\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"(\n" +
+	"\vGetOrderReq\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"6\n" +
+	"\x0fPaymentOrderRes\x12#\n" +
+	"\x05order\x18\x01 \x01(\v2\r.PaymentOrderR\x05order\"\x9f\x02\n" +
+	"\fPaymentOrder\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12'\n" +
+	"\x05items\x18\x05 \x03(\v2\x11.PaymentOrderItemR\x05items\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\"\x8d\x01\n" +
+	"\x10PaymentOrderItem\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12#\n" +
+	"\rinventory_ids\x18\x04 \x03(\tR\finventoryIds2\xac\x01\n" +
+	"\x12PaymentGrpcService\x12 \n" +
+	"\x03Buy\x12\a.BuyReq\x1a\x10.PaymentOrderRes\x12\"\n" +
+	"\x04Sell\x12\b.SellReq\x1a\x10.PaymentOrderRes\x12$\n" +
+	"\x05Grant\x12\t.GrantReq\x1a\x10.PaymentOrderRes\x12*\n" +
+	"\bGetOrder\x12\f.GetOrderReq\x1a\x10.PaymentOrderResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+
+var (
+	file_modules_payment_paymentPb_paymentPb_proto_rawDescOnce sync.Once
+	file_modules_payment_paymentPb_paymentPb_proto_rawDescData []byte
+)
+
+func file_modules_payment_paymentPb_paymentPb_proto_rawDescGZIP() []byte {
+	file_modules_payment_paymentPb_paymentPb_proto_rawDescOnce.Do(func() {
+		file_modules_payment_paymentPb_paymentPb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_modules_payment_paymentPb_paymentPb_proto_rawDesc), len(file_modules_payment_paymentPb_paymentPb_proto_rawDesc)))
+	})
+	return file_modules_payment_paymentPb_paymentPb_proto_rawDescData
+}
+
+var file_modules_payment_paymentPb_paymentPb_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_modules_payment_paymentPb_paymentPb_proto_goTypes = []any{
+	(*BuyReq)(nil),           // 0: BuyReq
+	(*SellReq)(nil),          // 1: SellReq
+	(*GrantReq)(nil),         // 2: GrantReq
+	(*GetOrderReq)(nil),      // 3: GetOrderReq
+	(*PaymentOrderRes)(nil),  // 4: PaymentOrderRes
+	(*PaymentOrder)(nil),     // 5: PaymentOrder
+	(*PaymentOrderItem)(nil), // 6: PaymentOrderItem
+}
+var file_modules_payment_paymentPb_paymentPb_proto_depIdxs = []int32{
+	5, // 0: PaymentOrderRes.order:type_name -> PaymentOrder
+	6, // 1: PaymentOrder.items:type_name -> PaymentOrderItem
+	0, // 2: PaymentGrpcService.Buy:input_type -> BuyReq
+	1, // 3: PaymentGrpcService.Sell:input_type -> SellReq
+	2, // 4: PaymentGrpcService.Grant:input_type -> GrantReq
+	3, // 5: PaymentGrpcService.GetOrder:input_type -> GetOrderReq
+	4, // 6: PaymentGrpcService.Buy:output_type -> PaymentOrderRes
+	4, // 7: PaymentGrpcService.Sell:output_type -> PaymentOrderRes
+	4, // 8: PaymentGrpcService.Grant:output_type -> PaymentOrderRes
+	4, // 9: PaymentGrpcService.GetOrder:output_type -> PaymentOrderRes
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_modules_payment_paymentPb_paymentPb_proto_init() }
+func file_modules_payment_paymentPb_paymentPb_proto_init() {
+	if File_modules_payment_paymentPb_paymentPb_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_payment_paymentPb_paymentPb_proto_rawDesc), len(file_modules_payment_paymentPb_paymentPb_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modules_payment_paymentPb_paymentPb_proto_goTypes,
+		DependencyIndexes: file_modules_payment_paymentPb_paymentPb_proto_depIdxs,
+		MessageInfos:      file_modules_payment_paymentPb_paymentPb_proto_msgTypes,
+	}.Build()
+	File_modules_payment_paymentPb_paymentPb_proto = out.File
+	file_modules_payment_paymentPb_paymentPb_proto_goTypes = nil
+	file_modules_payment_paymentPb_paymentPb_proto_depIdxs = nil
+}
@@ -0,0 +1,241 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: modules/payment/paymentPb/paymentPb.proto
+
+package mmorpg_shop
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PaymentGrpcService_Buy_FullMethodName      = "/PaymentGrpcService/Buy"
+	PaymentGrpcService_Sell_FullMethodName     = "/PaymentGrpcService/Sell"
+	PaymentGrpcService_Grant_FullMethodName    = "/PaymentGrpcService/Grant"
+	PaymentGrpcService_GetOrder_FullMethodName = "/PaymentGrpcService/GetOrder"
+)
+
+// PaymentGrpcServiceClient is the client API for PaymentGrpcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Methods
+type PaymentGrpcServiceClient interface {
+	Buy(ctx context.Context, in *BuyReq, opts ...grpc.CallOption) (*PaymentOrderRes, error)
+	Sell(ctx context.Context, in *SellReq, opts ...grpc.CallOption) (*PaymentOrderRes, error)
+	Grant(ctx context.Context, in *GrantReq, opts ...grpc.CallOption) (*PaymentOrderRes, error)
+	GetOrder(ctx context.Context, in *GetOrderReq, opts ...grpc.CallOption) (*PaymentOrderRes, error)
+}
+
+type paymentGrpcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentGrpcServiceClient(cc grpc.ClientConnInterface) PaymentGrpcServiceClient {
+	return &paymentGrpcServiceClient{cc}
+}
+
+func (c *paymentGrpcServiceClient) Buy(ctx context.Context, in *BuyReq, opts ...grpc.CallOption) (*PaymentOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentOrderRes)
+	err := c.cc.Invoke(ctx, PaymentGrpcService_Buy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGrpcServiceClient) Sell(ctx context.Context, in *SellReq, opts ...grpc.CallOption) (*PaymentOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentOrderRes)
+	err := c.cc.Invoke(ctx, PaymentGrpcService_Sell_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGrpcServiceClient) Grant(ctx context.Context, in *GrantReq, opts ...grpc.CallOption) (*PaymentOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentOrderRes)
+	err := c.cc.Invoke(ctx, PaymentGrpcService_Grant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGrpcServiceClient) GetOrder(ctx context.Context, in *GetOrderReq, opts ...grpc.CallOption) (*PaymentOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentOrderRes)
+	err := c.cc.Invoke(ctx, PaymentGrpcService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentGrpcServiceServer is the server API for PaymentGrpcService service.
+// All implementations must embed UnimplementedPaymentGrpcServiceServer
+// for forward compatibility.
+//
+// Methods
+type PaymentGrpcServiceServer interface {
+	Buy(context.Context, *BuyReq) (*PaymentOrderRes, error)
+	Sell(context.Context, *SellReq) (*PaymentOrderRes, error)
+	Grant(context.Context, *GrantReq) (*PaymentOrderRes, error)
+	GetOrder(context.Context, *GetOrderReq) (*PaymentOrderRes, error)
+	mustEmbedUnimplementedPaymentGrpcServiceServer()
+}
+
+// UnimplementedPaymentGrpcServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPaymentGrpcServiceServer struct{}
+
+func (UnimplementedPaymentGrpcServiceServer) Buy(context.Context, *BuyReq) (*PaymentOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Buy not implemented")
+}
+func (UnimplementedPaymentGrpcServiceServer) Sell(context.Context, *SellReq) (*PaymentOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sell not implemented")
+}
+func (UnimplementedPaymentGrpcServiceServer) Grant(context.Context, *GrantReq) (*PaymentOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Grant not implemented")
+}
+func (UnimplementedPaymentGrpcServiceServer) GetOrder(context.Context, *GetOrderReq) (*PaymentOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedPaymentGrpcServiceServer) mustEmbedUnimplementedPaymentGrpcServiceServer() {}
+func (UnimplementedPaymentGrpcServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafePaymentGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentGrpcServiceServer will
+// result in compilation errors.
+type UnsafePaymentGrpcServiceServer interface {
+	mustEmbedUnimplementedPaymentGrpcServiceServer()
+}
+
+func RegisterPaymentGrpcServiceServer(s grpc.ServiceRegistrar, srv PaymentGrpcServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPaymentGrpcServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PaymentGrpcService_ServiceDesc, srv)
+}
+
+func _PaymentGrpcService_Buy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGrpcServiceServer).Buy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentGrpcService_Buy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGrpcServiceServer).Buy(ctx, req.(*BuyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGrpcService_Sell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SellReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGrpcServiceServer).Sell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentGrpcService_Sell_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGrpcServiceServer).Sell(ctx, req.(*SellReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGrpcService_Grant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGrpcServiceServer).Grant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentGrpcService_Grant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGrpcServiceServer).Grant(ctx, req.(*GrantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGrpcService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGrpcServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentGrpcService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGrpcServiceServer).GetOrder(ctx, req.(*GetOrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentGrpcService_ServiceDesc is the grpc.ServiceDesc for PaymentGrpcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentGrpcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "PaymentGrpcService",
+	HandlerType: (*PaymentGrpcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Buy",
+			Handler:    _PaymentGrpcService_Buy_Handler,
+		},
+		{
+			MethodName: "Sell",
+			Handler:    _PaymentGrpcService_Sell_Handler,
+		},
+		{
+			MethodName: "Grant",
+			Handler:    _PaymentGrpcService_Grant_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _PaymentGrpcService_GetOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modules/payment/paymentPb/paymentPb.proto",
+}
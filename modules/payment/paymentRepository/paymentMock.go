@@ -2,12 +2,20 @@ package paymentRepository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
 	"github.com/Supakornn/mmorpg-shop/modules/player"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type PaymentRepositoryMock struct {
@@ -23,11 +31,41 @@ func (m *PaymentRepositoryMock) FindItemsInIds(pctx context.Context, grpcUrl str
 	return args.Get(0).(*itemPb.FindItemsInIdsRes), args.Error(1)
 }
 
+func (m *PaymentRepositoryMock) HasItem(pctx context.Context, grpcUrl string, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.HasItemRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) ReserveItems(pctx context.Context, grpcUrl string, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*itemPb.ReserveItemsRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) ReleaseItems(pctx context.Context, grpcUrl string, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*itemPb.ReleaseItemsRes), args.Error(1)
+}
+
 func (m *PaymentRepositoryMock) GetOffset(pctx context.Context) (int64, error) {
 	args := m.Called(pctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *PaymentRepositoryMock) GetReplyOffset(pctx context.Context, cfg *config.Config) (int64, error) {
+	args := m.Called(pctx, cfg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindStaleOrders(pctx context.Context, before time.Time) ([]*payment.PaymentOrder, error) {
+	args := m.Called(pctx, before)
+	return args.Get(0).([]*payment.PaymentOrder), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) ClaimStaleOrder(pctx context.Context, orderId string, updatedAt time.Time) error {
+	args := m.Called(pctx, orderId, updatedAt)
+	return args.Error(0)
+}
+
 func (m *PaymentRepositoryMock) UpsertOffset(pctx context.Context, offset int64) error {
 	args := m.Called(pctx, offset)
 	return args.Error(0)
@@ -67,3 +105,138 @@ func (m *PaymentRepositoryMock) AddPlayerMoney(pctx context.Context, cfg *config
 	args := m.Called(pctx, cfg, req)
 	return args.Error(0)
 }
+
+func (m *PaymentRepositoryMock) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchGrantRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchRevokeRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) InsertOnePaymentOrder(pctx context.Context, req *payment.PaymentOrder) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindOnePaymentOrder(pctx context.Context, orderId string) (*payment.PaymentOrder, error) {
+	args := m.Called(pctx, orderId)
+	return args.Get(0).(*payment.PaymentOrder), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) UpdateOnePaymentOrder(pctx context.Context, orderId string, req bson.M) error {
+	args := m.Called(pctx, orderId, req)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.PlayerProfile), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.CreatePlayerTransactionRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) InsertOneGift(pctx context.Context, req *payment.Gift) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) InsertOneGiftNotification(pctx context.Context, req *payment.GiftNotification) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindGiftNotifications(pctx context.Context, recipientId string) ([]*payment.GiftNotification, error) {
+	args := m.Called(pctx, recipientId)
+	return args.Get(0).([]*payment.GiftNotification), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) ReserveGiftSlot(pctx context.Context, senderId string, now time.Time, limit int) (bool, error) {
+	args := m.Called(pctx, senderId, now, limit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) ReleaseGiftSlot(pctx context.Context, senderId string, sentAt time.Time) error {
+	args := m.Called(pctx, senderId, sentAt)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) GetInventoryCapacity(pctx context.Context, grpcUrl string, req *inventoryPb.GetInventoryCapacityReq) (*inventoryPb.InventoryCapacityRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.InventoryCapacityRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) IncLootPity(pctx context.Context, playerId, boxItemId string, delta int) (int, error) {
+	args := m.Called(pctx, playerId, boxItemId, delta)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) InsertOneLootRoll(pctx context.Context, req *payment.LootRoll) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) UpdateOneLootRoll(pctx context.Context, rollId string, req bson.M) error {
+	args := m.Called(pctx, rollId, req)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) FindOneLootRoll(pctx context.Context, rollId string) (*payment.LootRoll, error) {
+	args := m.Called(pctx, rollId)
+	return args.Get(0).(*payment.LootRoll), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindLootRolls(pctx context.Context, playerId string) ([]*payment.LootRoll, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).([]*payment.LootRoll), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindOneRecipe(pctx context.Context, grpcUrl string, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*itemPb.Recipe), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) InsertOneCraft(pctx context.Context, req *payment.Craft) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) UpdateOneCraft(pctx context.Context, craftId string, req bson.M) error {
+	args := m.Called(pctx, craftId, req)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) FindActiveSeason(pctx context.Context, grpcUrl string) (*seasonPb.SeasonRes, error) {
+	args := m.Called(pctx, grpcUrl)
+	return args.Get(0).(*seasonPb.SeasonRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) UnlockPremiumPass(pctx context.Context, grpcUrl string, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*seasonPb.SeasonPassRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*subscriptionPb.EntitlementsRes), args.Error(1)
+}
+
+func (m *PaymentRepositoryMock) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	args := m.Called(pctx, cfg, req)
+	return args.Error(0)
+}
+
+func (m *PaymentRepositoryMock) PostOrderCallback(pctx context.Context, cfg *config.Config, callbackUrl string, req *payment.OrderStatusRes) error {
+	args := m.Called(pctx, cfg, callbackUrl, req)
+	return args.Error(0)
+}
@@ -1,30 +1,77 @@
 package paymentRepository
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
 	"github.com/Supakornn/mmorpg-shop/modules/player"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
 	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
 	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
 )
 
 type (
 	PaymentRepositoryService interface {
 		FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		ReserveItems(pctx context.Context, grpcUrl string, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error)
+		FindOneRecipe(pctx context.Context, grpcUrl string, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error)
+		ReleaseItems(pctx context.Context, grpcUrl string, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error)
+		HasItem(pctx context.Context, grpcUrl string, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error)
+		BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error)
+		BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error)
+		GetInventoryCapacity(pctx context.Context, grpcUrl string, req *inventoryPb.GetInventoryCapacityReq) (*inventoryPb.InventoryCapacityRes, error)
+		InsertOnePaymentOrder(pctx context.Context, req *payment.PaymentOrder) (bson.ObjectID, error)
+		FindOnePaymentOrder(pctx context.Context, orderId string) (*payment.PaymentOrder, error)
+		UpdateOnePaymentOrder(pctx context.Context, orderId string, req bson.M) error
+		FindStaleOrders(pctx context.Context, before time.Time) ([]*payment.PaymentOrder, error)
+		ClaimStaleOrder(pctx context.Context, orderId string, updatedAt time.Time) error
+		FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error)
+		CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error)
+		RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error
+		FindActiveSeason(pctx context.Context, grpcUrl string) (*seasonPb.SeasonRes, error)
+		UnlockPremiumPass(pctx context.Context, grpcUrl string, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error)
+		FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error)
+		InsertOneGift(pctx context.Context, req *payment.Gift) (bson.ObjectID, error)
+		InsertOneGiftNotification(pctx context.Context, req *payment.GiftNotification) (bson.ObjectID, error)
+		FindGiftNotifications(pctx context.Context, recipientId string) ([]*payment.GiftNotification, error)
+		ReserveGiftSlot(pctx context.Context, senderId string, now time.Time, limit int) (bool, error)
+		ReleaseGiftSlot(pctx context.Context, senderId string, sentAt time.Time) error
+		IncLootPity(pctx context.Context, playerId, boxItemId string, delta int) (int, error)
+		InsertOneLootRoll(pctx context.Context, req *payment.LootRoll) (bson.ObjectID, error)
+		UpdateOneLootRoll(pctx context.Context, rollId string, req bson.M) error
+		FindOneLootRoll(pctx context.Context, rollId string) (*payment.LootRoll, error)
+		FindLootRolls(pctx context.Context, playerId string) ([]*payment.LootRoll, error)
+		InsertOneCraft(pctx context.Context, req *payment.Craft) (bson.ObjectID, error)
+		UpdateOneCraft(pctx context.Context, craftId string, req bson.M) error
 		GetOffset(pctx context.Context) (int64, error)
 		UpsertOffset(pctx context.Context, offset int64) error
+		GetReplyOffset(pctx context.Context, cfg *config.Config) (int64, error)
 		DockedPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error
 		RollbackTransaction(pctx context.Context, cfg *config.Config, req *player.RollbackPlayerTransactionReq) error
 		RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq) error
@@ -32,6 +79,8 @@ type (
 		RemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error
 		RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackInventoryReq) error
 		AddPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error
+		PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error
+		PostOrderCallback(pctx context.Context, cfg *config.Config, callbackUrl string, req *payment.OrderStatusRes) error
 	}
 
 	paymentRepository struct {
@@ -72,6 +121,153 @@ func (r *paymentRepository) FindItemsInIds(pctx context.Context, grpcUrl string,
 	return result, nil
 }
 
+func (r *paymentRepository) FindOneRecipe(pctx context.Context, grpcUrl string, req *itemPb.FindOneRecipeReq) (*itemPb.Recipe, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Item().FindOneRecipe(ctx, req)
+	if err != nil {
+		log.Printf("error: find one recipe failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) ReserveItems(pctx context.Context, grpcUrl string, req *itemPb.ReserveItemsReq) (*itemPb.ReserveItemsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Item().ReserveItems(ctx, req)
+	if err != nil {
+		log.Printf("error: reserve items failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) HasItem(pctx context.Context, grpcUrl string, req *inventoryPb.HasItemReq) (*inventoryPb.HasItemRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().HasItem(ctx, req)
+	if err != nil {
+		log.Printf("error: has item failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchGrant(ctx, req)
+	if err != nil {
+		log.Printf("error: batch grant failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchRevoke(ctx, req)
+	if err != nil {
+		log.Printf("error: batch revoke failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) GetInventoryCapacity(pctx context.Context, grpcUrl string, req *inventoryPb.GetInventoryCapacityReq) (*inventoryPb.InventoryCapacityRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().GetInventoryCapacity(ctx, req)
+	if err != nil {
+		log.Printf("error: get inventory capacity failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) ReleaseItems(pctx context.Context, grpcUrl string, req *itemPb.ReleaseItemsReq) (*itemPb.ReleaseItemsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Item().ReleaseItems(ctx, req)
+	if err != nil {
+		log.Printf("error: release items failed: %v", err.Error())
+		return nil, errors.New("error: release items failed")
+	}
+
+	return result, nil
+}
+
 func (r *paymentRepository) GetOffset(pctx context.Context) (int64, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -106,6 +302,13 @@ func (r *paymentRepository) UpsertOffset(pctx context.Context, offset int64) err
 	return nil
 }
 
+// GetReplyOffset returns where the next reply on the payment topic will be
+// written. A saga step reads it before sending its request, so its reply is
+// always at or after it.
+func (r *paymentRepository) GetReplyOffset(pctx context.Context, cfg *config.Config) (int64, error) {
+	return queue.GetNewestOffset([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "payment", 0)
+}
+
 func (r *paymentRepository) DockedPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error {
 	reqInBytes, err := json.Marshal(req)
 	if err != nil {
@@ -210,3 +413,561 @@ func (r *paymentRepository) RollbackRemovePlayerItem(pctx context.Context, cfg *
 
 	return nil
 }
+
+func (r *paymentRepository) InsertOnePaymentOrder(pctx context.Context, req *payment.PaymentOrder) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("payment_orders")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one payment order: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one payment order failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *paymentRepository) FindOnePaymentOrder(pctx context.Context, orderId string) (*payment.PaymentOrder, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("payment_orders")
+
+	result := new(payment.PaymentOrder)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(orderId)}).Decode(result); err != nil {
+		log.Printf("error: find one payment order: %v", err.Error())
+		return nil, errors.New("error: payment order not found")
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) UpdateOnePaymentOrder(pctx context.Context, orderId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("payment_orders")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(orderId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one payment order: %v", err.Error())
+		return errors.New("error: update one payment order failed")
+	}
+
+	return nil
+}
+
+// FindStaleOrders returns pending orders not touched since before. Their saga
+// is no longer running, e.g. because the service restarted.
+func (r *paymentRepository) FindStaleOrders(pctx context.Context, before time.Time) ([]*payment.PaymentOrder, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("payment_orders")
+
+	cursors, err := col.Find(ctx, bson.M{"status": "pending", "updated_at": bson.M{"$lte": before}})
+	if err != nil {
+		log.Printf("error: find stale orders: %v", err.Error())
+		return nil, errors.New("error: find stale orders failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*payment.PaymentOrder, 0)
+	for cursors.Next(ctx) {
+		result := new(payment.PaymentOrder)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode payment order: %v", err.Error())
+			return nil, errors.New("error: decode payment order failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ClaimStaleOrder lets one recovery run take a stale order. updatedAt must
+// still match, so two runs cannot both compensate it.
+func (r *paymentRepository) ClaimStaleOrder(pctx context.Context, orderId string, updatedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("payment_orders")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(orderId), "status": "pending", "updated_at": updatedAt},
+		bson.M{"$set": bson.M{"updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: claim stale order: %v", err.Error())
+		return errors.New("error: claim stale order failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: order is already being recovered")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().FindOnePlayerProfileToRefresh(ctx, req)
+	if err != nil {
+		log.Printf("error: find one player profile failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().CreatePlayerTransaction(ctx, req)
+	if err != nil {
+		log.Printf("error: create player transaction failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	if _, err := conn.Player().RollbackPlayerTransaction(ctx, req); err != nil {
+		log.Printf("error: rollback player transaction failed: %v", err.Error())
+		return errors.New(status.Convert(err).Message())
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) InsertOneGift(pctx context.Context, req *payment.Gift) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("gifts")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one gift: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one gift failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *paymentRepository) InsertOneGiftNotification(pctx context.Context, req *payment.GiftNotification) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("gift_notifications")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one gift notification: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one gift notification failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *paymentRepository) FindGiftNotifications(pctx context.Context, recipientId string) ([]*payment.GiftNotification, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("gift_notifications")
+
+	cursors, err := col.Find(ctx, bson.M{"recipient_id": recipientId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(50))
+	if err != nil {
+		log.Printf("error: find gift notifications: %v", err.Error())
+		return nil, errors.New("error: find gift notifications failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*payment.GiftNotification, 0)
+	for cursors.Next(ctx) {
+		result := new(payment.GiftNotification)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode gift notification: %v", err.Error())
+			return nil, errors.New("error: decode gift notification failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ReserveGiftSlot records a send at now unless the player already sent
+// limit gifts in the 24 hours before it. Sends older than that are dropped
+// in the same update.
+func (r *paymentRepository) ReserveGiftSlot(pctx context.Context, senderId string, now time.Time, limit int) (bool, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("gift_limits")
+
+	recent := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$sent", bson.A{}}},
+		"cond":  bson.M{"$gte": bson.A{"$$this", now.Add(-24 * time.Hour)}},
+	}}
+
+	// A full window fails the filter, so the upsert hits the unique
+	// player_id index instead of adding to it
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": senderId, "$expr": bson.M{"$lt": bson.A{bson.M{"$size": recent}, limit}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"sent": bson.M{"$concatArrays": bson.A{recent, bson.A{now}}}}}}},
+		options.UpdateOne().SetUpsert(true),
+	); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		log.Printf("error: reserve gift slot: %v", err.Error())
+		return false, errors.New("error: reserve gift slot failed")
+	}
+
+	return true, nil
+}
+
+// ReleaseGiftSlot gives back a send reserved at sentAt for a gift that was
+// not delivered.
+func (r *paymentRepository) ReleaseGiftSlot(pctx context.Context, senderId string, sentAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("gift_limits")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"player_id": senderId}, bson.M{"$pull": bson.M{"sent": sentAt}}); err != nil {
+		log.Printf("error: release gift slot: %v", err.Error())
+		return errors.New("error: release gift slot failed")
+	}
+
+	return nil
+}
+
+// IncLootPity adds delta to a player's opens of a box since their last rare
+// drop and returns the new count. Decrements never take the count below 0.
+func (r *paymentRepository) IncLootPity(pctx context.Context, playerId, boxItemId string, delta int) (int, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("loot_pity")
+
+	filter := bson.M{"player_id": playerId, "box_item_id": boxItemId}
+	if delta < 0 {
+		filter["count"] = bson.M{"$gte": -delta}
+	}
+
+	result := new(payment.LootPity)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		filter,
+		bson.M{"$inc": bson.M{"count": delta}, "$set": bson.M{"updated_at": utils.LocalTime()}},
+		options.FindOneAndUpdate().SetUpsert(delta > 0).SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		log.Printf("error: inc loot pity: %v", err.Error())
+		return 0, errors.New("error: update loot pity failed")
+	}
+
+	return result.Count, nil
+}
+
+func (r *paymentRepository) InsertOneLootRoll(pctx context.Context, req *payment.LootRoll) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("loot_rolls")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one loot roll: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one loot roll failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *paymentRepository) UpdateOneLootRoll(pctx context.Context, rollId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("loot_rolls")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(rollId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one loot roll: %v", err.Error())
+		return errors.New("error: update one loot roll failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindOneLootRoll(pctx context.Context, rollId string) (*payment.LootRoll, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("loot_rolls")
+
+	result := new(payment.LootRoll)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(rollId)}).Decode(result); err != nil {
+		log.Printf("error: find one loot roll: %v", err.Error())
+		return nil, errors.New("error: loot roll not found")
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) FindLootRolls(pctx context.Context, playerId string) ([]*payment.LootRoll, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("loot_rolls")
+
+	cursors, err := col.Find(ctx, bson.M{"player_id": playerId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100))
+	if err != nil {
+		log.Printf("error: find loot rolls: %v", err.Error())
+		return nil, errors.New("error: find loot rolls failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*payment.LootRoll, 0)
+	for cursors.Next(ctx) {
+		result := new(payment.LootRoll)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode loot roll: %v", err.Error())
+			return nil, errors.New("error: decode loot roll failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *paymentRepository) InsertOneCraft(pctx context.Context, req *payment.Craft) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("crafts")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one craft: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one craft failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *paymentRepository) UpdateOneCraft(pctx context.Context, craftId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("crafts")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(craftId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one craft: %v", err.Error())
+		return errors.New("error: update one craft failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindActiveSeason(pctx context.Context, grpcUrl string) (*seasonPb.SeasonRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Season().FindActiveSeason(ctx, &seasonPb.FindActiveSeasonReq{})
+	if err != nil {
+		log.Printf("error: find active season failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) UnlockPremiumPass(pctx context.Context, grpcUrl string, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Season().UnlockPremiumPass(ctx, req)
+	if err != nil {
+		log.Printf("error: unlock premium pass failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) FindEntitlements(pctx context.Context, grpcUrl string, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Subscription().FindEntitlements(ctx, req)
+	if err != nil {
+		log.Printf("error: find entitlements failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) PushEvent(pctx context.Context, cfg *config.Config, req *models.DomainEvent) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "events", req.Type, reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
+
+// callbackClient refuses to connect to loopback, private and link-local
+// addresses, even when an allowed host resolves to one, and does not follow
+// redirects.
+var callbackClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+
+				ip := net.ParseIP(host)
+				if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+					return fmt.Errorf("error: callback address %s is not public", host)
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// PostOrderCallback sends the order status signed with an HMAC-SHA256 of
+// "<timestamp>.<body>" so the receiver can check it came from us.
+func (r *paymentRepository) PostOrderCallback(pctx context.Context, cfg *config.Config, callbackUrl string, req *payment.OrderStatusRes) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackUrl, bytes.NewReader(reqInBytes))
+	if err != nil {
+		log.Printf("Error: create order callback request failed: %v", err.Error())
+		return errors.New("error: create order callback request failed")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(cfg.Callback.SigningSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(reqInBytes)
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Callback-Timestamp", timestamp)
+	httpReq.Header.Set("X-Callback-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	res, err := callbackClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Error: call order callback failed: %v", err.Error())
+		return errors.New("error: call order callback failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error: order callback responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -2,18 +2,35 @@ package paymentUsecase
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"log"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/Supakornn/mmorpg-shop/config"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	"github.com/Supakornn/mmorpg-shop/modules/item"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
 	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	paymentPb "github.com/Supakornn/mmorpg-shop/modules/payment/paymentPb"
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/player"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type (
@@ -22,7 +39,21 @@ type (
 		GetOffset(pctx context.Context) (int64, error)
 		UpsertOffset(pctx context.Context, offset int64) error
 		BuyItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error)
+		BuyItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.BuyItemReq) (*payment.AsyncOrderRes, error)
+		FindOrderStatus(pctx context.Context, playerId, orderId string) (*payment.OrderStatusRes, error)
 		SellItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error)
+		Buy(pctx context.Context, cfg *config.Config, req *paymentPb.BuyReq) (*paymentPb.PaymentOrderRes, error)
+		Sell(pctx context.Context, cfg *config.Config, req *paymentPb.SellReq) (*paymentPb.PaymentOrderRes, error)
+		Grant(pctx context.Context, cfg *config.Config, req *paymentPb.GrantReq) (*paymentPb.PaymentOrderRes, error)
+		GetOrder(pctx context.Context, req *paymentPb.GetOrderReq) (*paymentPb.PaymentOrderRes, error)
+		GiftItem(pctx context.Context, cfg *config.Config, senderId string, req *payment.GiftReq) (*payment.Gift, error)
+		FindGiftNotifications(pctx context.Context, recipientId string) ([]*payment.GiftNotification, error)
+		OpenLootBox(pctx context.Context, cfg *config.Config, playerId string, req *payment.OpenLootBoxReq) (*payment.LootRoll, error)
+		FindLootRolls(pctx context.Context, playerId string) ([]*payment.LootRoll, error)
+		VerifyLootRoll(pctx context.Context, rollId string) (*payment.LootRollVerifyRes, error)
+		CraftItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.CraftReq) (*payment.Craft, error)
+		BuySeasonPass(pctx context.Context, cfg *config.Config, playerId string) (*payment.PaymentOrder, error)
+		RecoverOrders(pctx context.Context, cfg *config.Config) error
 	}
 
 	paymentUsecase struct {
@@ -34,11 +65,22 @@ func NewPaymentUsecase(paymentRepository paymentRepository.PaymentRepositoryServ
 	return &paymentUsecase{paymentRepository}
 }
 
+// itemKey maps "item:<hex>", a bare hex id or "sku:<SKU>" to the key used to
+// match item service results.
+func itemKey(itemId string) string {
+	if sku, ok := strings.CutPrefix(itemId, "sku:"); ok {
+		return "sku:" + strings.ToUpper(strings.Join(strings.Fields(sku), "-"))
+	}
+	return "item:" + strings.TrimPrefix(itemId, "item:")
+}
+
+// FindeItemsInIds fills in prices and rewrites each ItemId to the canonical
+// "item:<hex>" form, so the rest of the saga never sees a SKU.
 func (u *paymentUsecase) FindeItemsInIds(pctx context.Context, grpcUrl string, req []*payment.ItemServiceReqDatum) error {
 	setIds := make(map[string]bool)
 	for _, v := range req {
-		if !setIds[v.ItemId] {
-			setIds[v.ItemId] = true
+		if !setIds[itemKey(v.ItemId)] {
+			setIds[itemKey(v.ItemId)] = true
 		}
 	}
 
@@ -58,22 +100,38 @@ func (u *paymentUsecase) FindeItemsInIds(pctx context.Context, grpcUrl string, r
 
 	itemMaps := make(map[string]*item.ItemShowCase)
 	for _, data := range itemData.Items {
+		components := make([]*item.BundleComponent, 0)
+		for _, c := range data.Components {
+			components = append(components, &item.BundleComponent{
+				ItemId:   c.ItemId,
+				Quantity: int(c.Quantity),
+			})
+		}
+
 		itemMaps[data.Id] = &item.ItemShowCase{
-			ItemId:   data.Id,
-			Title:    data.Title,
-			Price:    data.Price,
-			ImageUrl: data.ImageUrl,
-			Damage:   int(data.Damage),
+			ItemId:     data.Id,
+			Sku:        data.Sku,
+			Title:      data.Title,
+			Price:      data.Price,
+			ImageUrl:   data.ImageUrl,
+			Damage:     int(data.Damage),
+			Components: components,
+		}
+		if data.Sku != "" {
+			itemMaps["sku:"+data.Sku] = itemMaps[data.Id]
 		}
 	}
 
 	for i := range req {
-		if _, ok := itemMaps[req[i].ItemId]; !ok {
+		result, ok := itemMaps[itemKey(req[i].ItemId)]
+		if !ok {
 			log.Printf("Error: item not found: %v", req[i].ItemId)
 			return errors.New("error: item not found")
 		}
 
-		req[i].Price = itemMaps[req[i].ItemId].Price
+		req[i].ItemId = result.ItemId
+		req[i].Price = result.Price
+		req[i].Components = result.Components
 	}
 
 	return nil
@@ -87,89 +145,247 @@ func (u *paymentUsecase) UpsertOffset(pctx context.Context, offset int64) error
 	return u.paymentRepository.UpsertOffset(pctx, offset)
 }
 
-func (u *paymentUsecase) PaymentConsumer(pctx context.Context, cfg *config.Config) (sarama.PartitionConsumer, error) {
+func (u *paymentUsecase) PaymentConsumer(pctx context.Context, cfg *config.Config, offset int64) (sarama.PartitionConsumer, error) {
 	worker, err := queue.ConnectConsumer([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret)
 	if err != nil {
 		return nil, errors.New("error: connect consumer failed")
 	}
 
-	offset, err := u.GetOffset(pctx)
-	if err != nil {
-		return nil, errors.New("error: get offset failed")
-	}
-
 	consumer, err := worker.ConsumePartition("payment", 0, offset)
 	if err != nil {
-		log.Printf("Error: Try to consume partition with offset: %v", err.Error())
-		consumer, err = worker.ConsumePartition("payment", 0, 0)
-		if err != nil {
-			log.Printf("Error: consume partition failed: %v", err.Error())
-			return nil, errors.New("error: consume partition failed")
-		}
+		log.Printf("Error: consume partition failed: %v", err.Error())
+		return nil, errors.New("error: consume partition failed")
 	}
 
 	return consumer, nil
 }
 
-func (u *paymentUsecase) TransactionConsumer(pctx context.Context, key string, cfg *config.Config, resCh chan<- *payment.PaymentTransferRes) {
-	consumer, err := u.PaymentConsumer(pctx, cfg)
+// TransactionConsumer waits for the reply to one saga step, reading from the
+// offset taken before the step was sent. Replies of other steps and sagas
+// share the topic, so only the one carrying referenceId is taken. A step
+// that gets no reply in time fails so the saga rolls it back by reference.
+func (u *paymentUsecase) TransactionConsumer(pctx context.Context, key, referenceId string, offset int64, cfg *config.Config, resCh chan<- *payment.PaymentTransferRes) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	failed := func(reason string) {
+		resCh <- &payment.PaymentTransferRes{
+			ReferenceId: referenceId,
+			Error:       reason,
+		}
+	}
+
+	consumer, err := u.PaymentConsumer(ctx, cfg, offset)
 	if err != nil {
-		resCh <- nil
+		failed(err.Error())
 		return
 	}
 	defer consumer.Close()
 
 	log.Println("Transaction consumer started")
 
-	select {
-	case err := <-consumer.Errors():
-		log.Printf("Error: transaction consumer failed: %v", err.Error())
-		resCh <- nil
-		return
-	case msg := <-consumer.Messages():
-		if string(msg.Key) == key {
-			u.UpsertOffset(pctx, msg.Offset+1)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Error: transaction %s got no reply: %v", referenceId, ctx.Err())
+			failed("error: transaction timed out")
+			return
+		case err := <-consumer.Errors():
+			log.Printf("Error: transaction consumer failed: %v", err.Error())
+			failed("error: transaction consumer failed")
+			return
+		case msg := <-consumer.Messages():
+			if string(msg.Key) != key {
+				continue
+			}
+
 			req := new(payment.PaymentTransferRes)
 			if err := queue.DecodeMessage(req, msg.Value); err != nil {
-				resCh <- nil
-				return
+				continue
+			}
+			if req.ReferenceId != referenceId {
+				continue
 			}
 
 			resCh <- req
 			log.Printf("info: transaction: topic: %s, offset: %d, value: %s", msg.Topic, msg.Offset, string(msg.Value))
+			return
 		}
 	}
 }
 
+// runStep sends one saga step and waits for its reply. The reply never goes
+// missing: a step that could not be sent or answered comes back with an
+// error and its reference id.
+func (u *paymentUsecase) runStep(pctx context.Context, cfg *config.Config, key, referenceId string, send func() error) *payment.PaymentTransferRes {
+	offset, err := u.paymentRepository.GetReplyOffset(pctx, cfg)
+	if err != nil {
+		return &payment.PaymentTransferRes{ReferenceId: referenceId, Error: err.Error()}
+	}
+
+	if err := send(); err != nil {
+		return &payment.PaymentTransferRes{ReferenceId: referenceId, Error: err.Error()}
+	}
+
+	resCh := make(chan *payment.PaymentTransferRes)
+
+	go u.TransactionConsumer(pctx, key, referenceId, offset, cfg, resCh)
+
+	return <-resCh
+}
+
+// newReferenceId tags one saga so the replies to its steps can be told apart.
+func newReferenceId() string {
+	return "saga:" + bson.NewObjectID().Hex()
+}
+
+// sagaId names the steps of a saga after its order when it has one, so a
+// stale order can be rolled back step by step.
+func sagaId(req *payment.ItemServiceReq) string {
+	if req.OrderId != "" {
+		return "order:" + req.OrderId
+	}
+	return newReferenceId()
+}
+
+// stepReferenceId names step i of a saga, e.g. "order:<id>:debit:0".
+func stepReferenceId(sagaId, step string, i int) string {
+	return fmt.Sprintf("%s:%s:%d", sagaId, step, i)
+}
+
 func (u *paymentUsecase) BuyItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
+	results, err := u.buyItemFor(pctx, cfg, playerId, playerId, req)
+	u.publishResult(pctx, cfg, "purchase", playerId, req, results, err)
+	return results, err
+}
+
+// publish pushes a domain event for the player. Delivery is best effort, the
+// outcome of the saga never depends on it.
+func (u *paymentUsecase) publish(pctx context.Context, cfg *config.Config, eventType, playerId string, payload any) {
+	if err := u.paymentRepository.PushEvent(pctx, cfg, &models.DomainEvent{
+		Type:       eventType,
+		PlayerId:   playerId,
+		Payload:    payload,
+		OccurredAt: utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: publish %s event failed: %v", eventType, err.Error())
+	}
+}
+
+// publishResult publishes "<action>.completed" or "<action>.failed" for a
+// buy or sell saga.
+func (u *paymentUsecase) publishResult(pctx context.Context, cfg *config.Config, action, playerId string, req *payment.ItemServiceReq, results []*payment.PaymentTransferRes, err error) {
+	event := &payment.PaymentEvent{ItemIds: make([]string, 0)}
+	for _, v := range req.Items {
+		event.ItemIds = append(event.ItemIds, v.ItemId)
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+		u.publish(pctx, cfg, action+".failed", playerId, event)
+		return
+	}
+
+	for _, v := range results {
+		event.Amount += v.Amount
+	}
+	u.publish(pctx, cfg, action+".completed", playerId, event)
+}
+
+// buyItemFor charges payerId and delivers the items to recipientId. Stock and
+// purchase limits are reserved against the recipient, who ends up owning the
+// items.
+func (u *paymentUsecase) buyItemFor(pctx context.Context, cfg *config.Config, payerId, recipientId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
 	if err := u.FindeItemsInIds(pctx, cfg.Grpc.ItemUrl, req.Items); err != nil {
 		log.Printf("Error: find items in ids failed: %v", err.Error())
 		return nil, errors.New("error: find items in ids failed")
 	}
 
-	stage1 := make([]*payment.PaymentTransferRes, 0)
-	for _, item := range req.Items {
-		u.paymentRepository.DockedPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
-			PlayerId: playerId,
-			Amount:   -item.Price,
-		})
+	// VIP discount of the payer
+	if vip := u.entitlements(pctx, cfg, payerId); vip.DiscountRate > 0 {
+		for _, v := range req.Items {
+			v.Price = math.Round(v.Price*(1-vip.DiscountRate)*100) / 100
+		}
+	}
 
-		resCh := make(chan *payment.PaymentTransferRes)
+	// Fail before any money moves when the items will not fit
+	capacity, err := u.paymentRepository.GetInventoryCapacity(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.GetInventoryCapacityReq{
+		PlayerId: recipientId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if capacity.Capacity > 0 {
+		slots := int64(0)
+		for _, v := range req.Items {
+			if len(v.Components) == 0 {
+				slots++
+				continue
+			}
+			for _, c := range v.Components {
+				slots += int64(c.Quantity)
+			}
+		}
 
-		go u.TransactionConsumer(pctx, "buy", cfg, resCh)
+		if capacity.Used+slots > capacity.Capacity {
+			return nil, errors.New("error: inventory is full")
+		}
+	}
+
+	// Reserve stock and purchase limits up front so concurrent buyers cannot
+	// both take the last unit; every failure below releases the reservation.
+	reserveReq := &itemPb.ReserveItemsReq{
+		PlayerId: recipientId,
+		Ids: func() []string {
+			itemIds := make([]string, 0)
+			for _, v := range req.Items {
+				itemIds = append(itemIds, v.ItemId)
+			}
+			return itemIds
+		}(),
+	}
+
+	if _, err := u.paymentRepository.ReserveItems(pctx, cfg.Grpc.ItemUrl, reserveReq); err != nil {
+		log.Printf("Error: reserve items failed: %v", err.Error())
+		return nil, err
+	}
 
-		res := <-resCh
-		if res != nil {
-			log.Printf("info: %v", res)
-			stage1 = append(stage1, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: res.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        item.ItemId,
-				Amount:        item.Price,
-				Error:         res.Error,
+	u.markReserved(pctx, req.OrderId, reserveReq.Ids)
+
+	// Released without the saga's deadline so a timed out saga still frees stock
+	releaseItems := func() {
+		u.paymentRepository.ReleaseItems(context.WithoutCancel(pctx), cfg.Grpc.ItemUrl, &itemPb.ReleaseItemsReq{
+			PlayerId: reserveReq.PlayerId,
+			Ids:      reserveReq.Ids,
+		})
+		u.markReserved(context.WithoutCancel(pctx), req.OrderId, nil)
+	}
+
+	sagaId := sagaId(req)
+
+	// Rollbacks name the step, so a debit or add that replies after its
+	// timeout is still undone when it lands
+	stage1 := make([]*payment.PaymentTransferRes, 0)
+	for i, item := range req.Items {
+		referenceId := stepReferenceId(sagaId, "debit", i)
+		res := u.runStep(pctx, cfg, "buy", referenceId, func() error {
+			return u.paymentRepository.DockedPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+				PlayerId:    payerId,
+				Amount:      -item.Price,
+				ReferenceId: referenceId,
 			})
-		}
+		})
+		log.Printf("info: %v", res)
+
+		stage1 = append(stage1, &payment.PaymentTransferRes{
+			InventoryId:   "",
+			TransactionId: res.TransactionId,
+			PlayerId:      payerId,
+			ItemId:        item.ItemId,
+			Amount:        item.Price,
+			ReferenceId:   referenceId,
+			Error:         res.Error,
+		})
 	}
 
 	for _, v := range stage1 {
@@ -177,52 +393,67 @@ func (u *paymentUsecase) BuyItem(pctx context.Context, cfg *config.Config, playe
 			for _, v2 := range stage1 {
 				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
 					TransactionId: v2.TransactionId,
+					ReferenceId:   v2.ReferenceId,
 				})
 			}
 
+			releaseItems()
+
 			return nil, errors.New(v.Error)
 		}
 	}
 
+	bundles := make(map[string][]*item.BundleComponent)
+	for _, item := range req.Items {
+		if len(item.Components) > 0 {
+			bundles[item.ItemId] = item.Components
+		}
+	}
+
 	stage2 := make([]*payment.PaymentTransferRes, 0)
-	for _, s1 := range stage1 {
-		u.paymentRepository.AddPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
-			PlayerId: playerId,
-			ItemId:   s1.ItemId,
+	for i, s1 := range stage1 {
+		referenceId := stepReferenceId(sagaId, "add", i)
+		res := u.runStep(pctx, cfg, "buy", referenceId, func() error {
+			return u.paymentRepository.AddPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+				PlayerId:    recipientId,
+				ItemId:      s1.ItemId,
+				Components:  bundles[s1.ItemId],
+				ReferenceId: referenceId,
+			})
 		})
+		log.Printf("info: %v", res)
 
-		resCh := make(chan *payment.PaymentTransferRes)
-
-		go u.TransactionConsumer(pctx, "buy", cfg, resCh)
-
-		res := <-resCh
-		if res != nil {
-			log.Printf("info: %v", res)
-			stage2 = append(stage2, &payment.PaymentTransferRes{
-				InventoryId:   res.InventoryId,
-				TransactionId: s1.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        s1.ItemId,
-				Amount:        s1.Amount,
-				Error:         s1.Error,
-			})
-		}
+		stage2 = append(stage2, &payment.PaymentTransferRes{
+			InventoryId:   res.InventoryId,
+			InventoryIds:  res.InventoryIds,
+			TransactionId: s1.TransactionId,
+			PlayerId:      recipientId,
+			ItemId:        s1.ItemId,
+			Amount:        s1.Amount,
+			ReferenceId:   referenceId,
+			Error:         res.Error,
+		})
 	}
 
 	for _, v := range stage2 {
 		if v.Error != "" {
 			for _, s2 := range stage2 {
 				u.paymentRepository.RollbackAddPlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
-					InventoryId: s2.InventoryId,
+					InventoryId:  s2.InventoryId,
+					InventoryIds: s2.InventoryIds,
+					ReferenceId:  s2.ReferenceId,
 				})
 			}
 
-			for _, s2 := range stage2 {
+			for _, s1 := range stage1 {
 				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
-					TransactionId: s2.TransactionId,
+					TransactionId: s1.TransactionId,
+					ReferenceId:   s1.ReferenceId,
 				})
 			}
 
+			releaseItems()
+
 			return nil, errors.New(v.Error)
 		}
 	}
@@ -230,75 +461,143 @@ func (u *paymentUsecase) BuyItem(pctx context.Context, cfg *config.Config, playe
 	return stage2, nil
 }
 
+// entitlements returns the player's VIP benefits. The subscription service
+// being down must not block trading, so any error means no benefits.
+func (u *paymentUsecase) entitlements(pctx context.Context, cfg *config.Config, playerId string) *subscriptionPb.EntitlementsRes {
+	result, err := u.paymentRepository.FindEntitlements(pctx, cfg.Grpc.SubscriptionUrl, &subscriptionPb.FindEntitlementsReq{
+		PlayerId: playerId,
+	})
+	if err != nil {
+		log.Printf("Error: find entitlements failed: %v", err.Error())
+		return &subscriptionPb.EntitlementsRes{PlayerId: playerId}
+	}
+
+	return result
+}
+
+// checkPlayerItems asks the inventory service whether the player holds every
+// item being sold, so a sell saga never starts only to be rolled back.
+func (u *paymentUsecase) checkPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req []*payment.ItemServiceReqDatum) error {
+	quantities := make(map[string]int64)
+	for _, v := range req {
+		quantities[v.ItemId]++
+	}
+
+	for itemId, quantity := range quantities {
+		res, err := u.paymentRepository.HasItem(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.HasItemReq{
+			PlayerId: playerId,
+			ItemId:   itemId,
+			Quantity: quantity,
+		})
+		if err != nil {
+			log.Printf("Error: has item failed: %v", err.Error())
+			return errors.New("error: check player items failed")
+		}
+
+		if !res.Has {
+			return errors.New("error: player does not have enough unequipped items")
+		}
+	}
+
+	return nil
+}
+
 func (u *paymentUsecase) SellItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
+	results, err := u.sellItem(pctx, cfg, playerId, req)
+	u.publishResult(pctx, cfg, "sale", playerId, req, results, err)
+	return results, err
+}
+
+func (u *paymentUsecase) sellItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
 	if err := u.FindeItemsInIds(pctx, cfg.Grpc.ItemUrl, req.Items); err != nil {
 		log.Printf("Error: find items in ids failed: %v", err.Error())
 		return nil, errors.New("error: find items in ids failed")
 	}
 
-	stage1 := make([]*payment.PaymentTransferRes, 0)
-	for _, item := range req.Items {
-		u.paymentRepository.RemovePlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
-			PlayerId: playerId,
-			ItemId:   item.ItemId,
-		})
-
-		resCh := make(chan *payment.PaymentTransferRes)
+	if err := u.checkPlayerItems(pctx, cfg, playerId, req.Items); err != nil {
+		return nil, err
+	}
 
-		go u.TransactionConsumer(pctx, "sell", cfg, resCh)
+	sagaId := sagaId(req)
 
-		res := <-resCh
-		if res != nil {
-			log.Printf("info: %v", res)
-			stage1 = append(stage1, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: "",
-				PlayerId:      playerId,
-				ItemId:        item.ItemId,
-				Amount:        item.Price,
-				Error:         res.Error,
+	// Rollbacks name the step, so a remove or credit that replies after its
+	// timeout is still undone when it lands
+	stage1 := make([]*payment.PaymentTransferRes, 0)
+	for i, item := range req.Items {
+		referenceId := stepReferenceId(sagaId, "remove", i)
+		res := u.runStep(pctx, cfg, "sell", referenceId, func() error {
+			return u.paymentRepository.RemovePlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+				PlayerId:    playerId,
+				ItemId:      item.ItemId,
+				InventoryId: item.InventoryId,
+				ReferenceId: referenceId,
 			})
+		})
+		log.Printf("info: %v", res)
+
+		// Worn or enchanted copies sell below or above the list price
+		valueFactor := res.ValueFactor
+		if valueFactor == 0 {
+			valueFactor = 1
 		}
+
+		stage1 = append(stage1, &payment.PaymentTransferRes{
+			InventoryId:   res.InventoryId,
+			TransactionId: "",
+			PlayerId:      playerId,
+			ItemId:        item.ItemId,
+			Amount:        math.Round(item.Price*valueFactor*100) / 100,
+			Instance:      res.Instance,
+			ReferenceId:   referenceId,
+			Error:         res.Error,
+		})
 	}
 
 	for _, v := range stage1 {
 		if v.Error != "" {
 			for _, v2 := range stage1 {
-				if v2.Error == "" {
-					u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
-						PlayerId: playerId,
-						ItemId:   v2.ItemId,
-					})
-				}
+				u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
+					InventoryId: v2.InventoryId,
+					PlayerId:    playerId,
+					ItemId:      v2.ItemId,
+					Instance:    v2.Instance,
+					ReferenceId: v2.ReferenceId,
+				})
 			}
 
 			return nil, errors.New(v.Error)
 		}
 	}
 
-	stage2 := make([]*payment.PaymentTransferRes, 0)
-	for _, s1 := range stage1 {
-		u.paymentRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
-			PlayerId: playerId,
-			Amount:   s1.Amount * 0.8,
-		})
-
-		resCh := make(chan *payment.PaymentTransferRes)
+	vip := u.entitlements(pctx, cfg, playerId)
 
-		go u.TransactionConsumer(pctx, "sell", cfg, resCh)
+	stage2 := make([]*payment.PaymentTransferRes, 0)
+	for i, s1 := range stage1 {
+		amount := s1.Amount * 0.8
+		if vip.SellBonusRate > 0 {
+			amount = math.Round(amount*(1+vip.SellBonusRate)*100) / 100
+		}
 
-		res := <-resCh
-		if res != nil {
-			log.Printf("info: %v", res)
-			stage2 = append(stage2, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: s1.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        s1.ItemId,
-				Amount:        s1.Amount,
-				Error:         s1.Error,
+		referenceId := stepReferenceId(sagaId, "credit", i)
+		res := u.runStep(pctx, cfg, "sell", referenceId, func() error {
+			return u.paymentRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+				PlayerId:    playerId,
+				Amount:      amount,
+				ReferenceId: referenceId,
 			})
-		}
+		})
+		log.Printf("info: %v", res)
+
+		stage2 = append(stage2, &payment.PaymentTransferRes{
+			InventoryId:   s1.InventoryId,
+			TransactionId: res.TransactionId,
+			PlayerId:      playerId,
+			ItemId:        s1.ItemId,
+			Amount:        s1.Amount,
+			Instance:      s1.Instance,
+			ReferenceId:   referenceId,
+			Error:         res.Error,
+		})
 	}
 
 	for _, v := range stage2 {
@@ -306,15 +605,18 @@ func (u *paymentUsecase) SellItem(pctx context.Context, cfg *config.Config, play
 			for _, s2 := range stage2 {
 				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
 					TransactionId: s2.TransactionId,
+					ReferenceId:   s2.ReferenceId,
 				})
 			}
 
-			for _, s2 := range stage2 {
-				if s2.Error == "" {
-					u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
-						InventoryId: s2.InventoryId,
-					})
-				}
+			for _, s1 := range stage1 {
+				u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
+					InventoryId: s1.InventoryId,
+					PlayerId:    s1.PlayerId,
+					ItemId:      s1.ItemId,
+					Instance:    s1.Instance,
+					ReferenceId: s1.ReferenceId,
+				})
 			}
 
 			return nil, errors.New(v.Error)
@@ -323,3 +625,999 @@ func (u *paymentUsecase) SellItem(pctx context.Context, cfg *config.Config, play
 
 	return stage2, nil
 }
+
+// grantItems adds items to a player without charging them, e.g. quest rewards.
+// Bundles are granted as their components like a bought bundle would be.
+func (u *paymentUsecase) grantItems(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
+	if err := u.FindeItemsInIds(pctx, cfg.Grpc.ItemUrl, req.Items); err != nil {
+		log.Printf("Error: find items in ids failed: %v", err.Error())
+		return nil, errors.New("error: find items in ids failed")
+	}
+
+	grantReq := &inventoryPb.BatchGrantReq{
+		Items:             make([]*inventoryPb.GrantItem, 0),
+		OverflowToMailbox: true,
+	}
+	for _, v := range req.Items {
+		if len(v.Components) == 0 {
+			grantReq.Items = append(grantReq.Items, &inventoryPb.GrantItem{PlayerId: playerId, ItemId: v.ItemId, Quantity: 1})
+			continue
+		}
+
+		for _, c := range v.Components {
+			grantReq.Items = append(grantReq.Items, &inventoryPb.GrantItem{PlayerId: playerId, ItemId: c.ItemId, Quantity: int32(c.Quantity)})
+		}
+	}
+
+	granted, err := u.paymentRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, grantReq)
+	if err != nil {
+		log.Printf("Error: batch grant failed: %v", err.Error())
+		return nil, err
+	}
+
+	// Granted entries come back in request order; items that overflowed to
+	// the mailbox are the last ones and have no inventory id
+	results := make([]*payment.PaymentTransferRes, 0)
+	next := 0
+	for _, v := range req.Items {
+		count := 1
+		if len(v.Components) > 0 {
+			count = 0
+			for _, c := range v.Components {
+				count += c.Quantity
+			}
+		}
+
+		inventoryIds := make([]string, 0)
+		for _, g := range granted.Items[next:min(next+count, len(granted.Items))] {
+			inventoryIds = append(inventoryIds, g.InventoryId)
+		}
+		next += count
+
+		results = append(results, &payment.PaymentTransferRes{
+			InventoryIds: inventoryIds,
+			PlayerId:     playerId,
+			ItemId:       v.ItemId,
+		})
+	}
+
+	return results, nil
+}
+
+// placeOrder records a pending order, runs the saga for its kind and stores
+// the terminal state. A failed saga still returns the order, with its error.
+func (u *paymentUsecase) placeOrder(pctx context.Context, cfg *config.Config, kind, playerId, reason string, itemIds []string) (*payment.PaymentOrder, error) {
+	if playerId == "" {
+		return nil, errors.New("error: player_id is required")
+	}
+
+	if len(itemIds) == 0 {
+		return nil, errors.New("error: item_ids is required")
+	}
+
+	playerId = "player:" + strings.TrimPrefix(playerId, "player:")
+
+	req := &payment.ItemServiceReq{
+		Items: make([]*payment.ItemServiceReqDatum, 0),
+	}
+	for _, itemId := range itemIds {
+		req.Items = append(req.Items, &payment.ItemServiceReqDatum{ItemId: itemId})
+	}
+
+	order := &payment.PaymentOrder{
+		PlayerId: playerId,
+		Kind:     kind,
+		Status:   "pending",
+		Items: func() []*payment.PaymentOrderItem {
+			items := make([]*payment.PaymentOrderItem, 0)
+			for _, itemId := range itemIds {
+				items = append(items, &payment.PaymentOrderItem{ItemId: itemId})
+			}
+			return items
+		}(),
+		Reason:    reason,
+		CreatedAt: utils.LocalTime(),
+		UpdatedAt: utils.LocalTime(),
+	}
+
+	orderId, err := u.paymentRepository.InsertOnePaymentOrder(pctx, order)
+	if err != nil {
+		return nil, err
+	}
+	order.Id = orderId
+	req.OrderId = orderId.Hex()
+
+	var results []*payment.PaymentTransferRes
+	switch kind {
+	case "buy":
+		results, err = u.BuyItem(pctx, cfg, playerId, req)
+	case "sell":
+		results, err = u.SellItem(pctx, cfg, playerId, req)
+	case "grant":
+		results, err = u.grantItems(pctx, cfg, playerId, req)
+	default:
+		err = errors.New("error: unknown order kind")
+	}
+
+	u.completeOrder(pctx, order, results, err)
+
+	return order, nil
+}
+
+// completeOrder stores the terminal state of an order from its saga result.
+func (u *paymentUsecase) completeOrder(pctx context.Context, order *payment.PaymentOrder, results []*payment.PaymentTransferRes, err error) {
+	if err != nil {
+		order.Status = "failed"
+		order.Error = err.Error()
+	} else {
+		order.Status = "completed"
+		order.Items = make([]*payment.PaymentOrderItem, 0)
+		for _, v := range results {
+			order.Items = append(order.Items, &payment.PaymentOrderItem{
+				ItemId:        v.ItemId,
+				Price:         v.Amount,
+				TransactionId: v.TransactionId,
+				InventoryIds:  grantedInventoryIds(v),
+			})
+			order.Amount += v.Amount
+		}
+	}
+	order.UpdatedAt = utils.LocalTime()
+
+	if err := u.paymentRepository.UpdateOnePaymentOrder(pctx, order.Id.Hex(), bson.M{
+		"status":     order.Status,
+		"items":      order.Items,
+		"amount":     order.Amount,
+		"error":      order.Error,
+		"updated_at": order.UpdatedAt,
+	}); err != nil {
+		log.Printf("Error: update payment order %s failed: %v", order.Id.Hex(), err.Error())
+	}
+}
+
+// grantedInventoryIds lists the copies a purchase granted: one for a plain
+// item, one per component for a bundle.
+func grantedInventoryIds(res *payment.PaymentTransferRes) []string {
+	inventoryIds := append(make([]string, 0), res.InventoryIds...)
+	if res.InventoryId != "" {
+		inventoryIds = append(inventoryIds, res.InventoryId)
+	}
+	return inventoryIds
+}
+
+// markReserved records the items an order holds, or clears them once they
+// are released. It also keeps a running order from looking stale.
+func (u *paymentUsecase) markReserved(pctx context.Context, orderId string, itemIds []string) {
+	if orderId == "" {
+		return
+	}
+
+	if err := u.paymentRepository.UpdateOnePaymentOrder(pctx, orderId, bson.M{
+		"reserved_ids": itemIds,
+		"updated_at":   utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: update payment order %s failed: %v", orderId, err.Error())
+	}
+}
+
+// staleOrderAfter is well past the deadline of any saga, so an order still
+// pending by then is no longer being worked on.
+const staleOrderAfter = 15 * time.Minute
+
+// RecoverOrders fails orders left pending by a saga that stopped, e.g. on a
+// restart. Every step the saga may have sent is rolled back by its reference
+// id, which is a no-op for steps that never ran, and held stock is released.
+func (u *paymentUsecase) RecoverOrders(pctx context.Context, cfg *config.Config) error {
+	orders, err := u.paymentRepository.FindStaleOrders(pctx, utils.LocalTime().Add(-staleOrderAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if err := u.paymentRepository.ClaimStaleOrder(pctx, order.Id.Hex(), order.UpdatedAt); err != nil {
+			log.Printf("Error: claim payment order %s failed: %v", order.Id.Hex(), err.Error())
+			continue
+		}
+
+		sagaId := "order:" + order.Id.Hex()
+		for i := range order.Items {
+			switch order.Kind {
+			case "buy":
+				u.paymentRepository.RollbackAddPlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
+					ReferenceId: stepReferenceId(sagaId, "add", i),
+				})
+				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
+					ReferenceId: stepReferenceId(sagaId, "debit", i),
+				})
+			case "sell":
+				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
+					ReferenceId: stepReferenceId(sagaId, "credit", i),
+				})
+				u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackInventoryReq{
+					PlayerId:    order.PlayerId,
+					ReferenceId: stepReferenceId(sagaId, "remove", i),
+				})
+			}
+		}
+
+		if len(order.ReservedIds) > 0 {
+			if _, err := u.paymentRepository.ReleaseItems(pctx, cfg.Grpc.ItemUrl, &itemPb.ReleaseItemsReq{
+				PlayerId: order.PlayerId,
+				Ids:      order.ReservedIds,
+			}); err != nil {
+				log.Printf("Error: release items of payment order %s failed: %v", order.Id.Hex(), err.Error())
+			}
+		}
+
+		u.completeOrder(pctx, order, nil, errors.New("error: order was interrupted"))
+
+		if order.CallbackUrl != "" {
+			if err := u.paymentRepository.PostOrderCallback(pctx, cfg, order.CallbackUrl, orderStatus(order)); err != nil {
+				log.Printf("Error: callback of payment order %s failed: %v", order.Id.Hex(), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// BuyItemAsync records a pending buy order and runs the purchase in the
+// background, so a large cart is not bound by the request timeout. The order
+// is completed like a gRPC buy and its callback, if any, is called after.
+func (u *paymentUsecase) BuyItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.BuyItemReq) (*payment.AsyncOrderRes, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("error: items is required")
+	}
+
+	if req.CallbackUrl != "" {
+		if err := checkCallbackUrl(cfg, req.CallbackUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	order := &payment.PaymentOrder{
+		PlayerId:    playerId,
+		Kind:        "buy",
+		Status:      "pending",
+		Items:       make([]*payment.PaymentOrderItem, 0),
+		CallbackUrl: req.CallbackUrl,
+		CreatedAt:   utils.LocalTime(),
+		UpdatedAt:   utils.LocalTime(),
+	}
+	for _, v := range req.Items {
+		order.Items = append(order.Items, &payment.PaymentOrderItem{ItemId: v.ItemId})
+	}
+
+	orderId, err := u.paymentRepository.InsertOnePaymentOrder(pctx, order)
+	if err != nil {
+		return nil, err
+	}
+	order.Id = orderId
+	req.OrderId = orderId.Hex()
+
+	go func() {
+		results, err := u.runBuyOrder(cfg, playerId, &req.ItemServiceReq)
+
+		// The saga context may be done by now, the outcome is still recorded
+		ctx := context.Background()
+		u.completeOrder(ctx, order, results, err)
+
+		if order.CallbackUrl != "" {
+			if err := u.paymentRepository.PostOrderCallback(ctx, cfg, order.CallbackUrl, orderStatus(order)); err != nil {
+				log.Printf("Error: callback of payment order %s failed: %v", orderId.Hex(), err.Error())
+			}
+		}
+	}()
+
+	return &payment.AsyncOrderRes{OrderId: "order:" + orderId.Hex(), Status: "pending"}, nil
+}
+
+// checkCallbackUrl only accepts hosts allowed in the config, so players cannot
+// point the payment service at the internal network.
+func checkCallbackUrl(cfg *config.Config, callbackUrl string) error {
+	if len(cfg.Callback.AllowedHosts) == 0 || cfg.Callback.SigningSecret == "" {
+		return errors.New("error: order callbacks are not enabled")
+	}
+
+	parsed, err := url.Parse(callbackUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.User != nil {
+		return errors.New("error: invalid callback url")
+	}
+
+	if !slices.Contains(cfg.Callback.AllowedHosts, strings.ToLower(parsed.Hostname())) {
+		return errors.New("error: callback host is not allowed")
+	}
+
+	return nil
+}
+
+// runBuyOrder runs the saga of an async order under a deadline. A panic fails
+// the order rather than the payment service.
+func (u *paymentUsecase) runBuyOrder(cfg *config.Config, playerId string, req *payment.ItemServiceReq) (results []*payment.PaymentTransferRes, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error: buy order of player %s panicked: %v", playerId, r)
+			results, err = nil, errors.New("error: purchase failed")
+		}
+	}()
+
+	results, err = u.BuyItem(ctx, cfg, playerId, req)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, errors.New("error: purchase timed out")
+	}
+
+	return results, err
+}
+
+// FindOrderStatus returns the status of one of the player's orders.
+func (u *paymentUsecase) FindOrderStatus(pctx context.Context, playerId, orderId string) (*payment.OrderStatusRes, error) {
+	order, err := u.paymentRepository.FindOnePaymentOrder(pctx, strings.TrimPrefix(orderId, "order:"))
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimPrefix(order.PlayerId, "player:") != strings.TrimPrefix(playerId, "player:") {
+		return nil, errors.New("error: payment order not found")
+	}
+
+	return orderStatus(order), nil
+}
+
+func orderStatus(order *payment.PaymentOrder) *payment.OrderStatusRes {
+	return &payment.OrderStatusRes{
+		OrderId:   "order:" + order.Id.Hex(),
+		Kind:      order.Kind,
+		Status:    order.Status,
+		Items:     order.Items,
+		Amount:    order.Amount,
+		Error:     order.Error,
+		UpdatedAt: order.UpdatedAt,
+	}
+}
+
+func (u *paymentUsecase) Buy(pctx context.Context, cfg *config.Config, req *paymentPb.BuyReq) (*paymentPb.PaymentOrderRes, error) {
+	order, err := u.placeOrder(pctx, cfg, "buy", req.PlayerId, "", req.ItemIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentPb.PaymentOrderRes{Order: paymentOrderToPb(order)}, nil
+}
+
+func (u *paymentUsecase) Sell(pctx context.Context, cfg *config.Config, req *paymentPb.SellReq) (*paymentPb.PaymentOrderRes, error) {
+	order, err := u.placeOrder(pctx, cfg, "sell", req.PlayerId, "", req.ItemIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentPb.PaymentOrderRes{Order: paymentOrderToPb(order)}, nil
+}
+
+func (u *paymentUsecase) Grant(pctx context.Context, cfg *config.Config, req *paymentPb.GrantReq) (*paymentPb.PaymentOrderRes, error) {
+	order, err := u.placeOrder(pctx, cfg, "grant", req.PlayerId, req.Reason, req.ItemIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == "completed" {
+		event := &payment.PaymentEvent{ItemIds: make([]string, 0), Reason: req.Reason}
+		for _, v := range order.Items {
+			event.ItemIds = append(event.ItemIds, v.ItemId)
+		}
+		u.publish(pctx, cfg, "items.granted", order.PlayerId, event)
+	}
+
+	return &paymentPb.PaymentOrderRes{Order: paymentOrderToPb(order)}, nil
+}
+
+func (u *paymentUsecase) GetOrder(pctx context.Context, req *paymentPb.GetOrderReq) (*paymentPb.PaymentOrderRes, error) {
+	order, err := u.paymentRepository.FindOnePaymentOrder(pctx, strings.TrimPrefix(req.OrderId, "order:"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentPb.PaymentOrderRes{Order: paymentOrderToPb(order)}, nil
+}
+
+// GiftItem charges the sender for the gifted items and money and delivers them
+// to the recipient. Money moves first and is rolled back if the item purchase
+// fails, so the sender is never charged for a gift that did not arrive.
+func (u *paymentUsecase) GiftItem(pctx context.Context, cfg *config.Config, senderId string, req *payment.GiftReq) (*payment.Gift, error) {
+	recipientId := "player:" + strings.TrimPrefix(req.RecipientId, "player:")
+	if recipientId == senderId {
+		return nil, errors.New("error: cannot gift to yourself")
+	}
+	if len(req.Items) == 0 && req.Money <= 0 {
+		return nil, errors.New("error: gift has nothing to send")
+	}
+
+	sender, err := u.paymentRepository.FindOnePlayerProfile(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+		PlayerId: strings.TrimPrefix(senderId, "player:"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Gift.MinAccountAgeDays > 0 {
+		minAge := time.Duration(cfg.Gift.MinAccountAgeDays) * 24 * time.Hour
+		if utils.LocalTime().Sub(utils.ConvertStringToTime(sender.CreatedAt)) < minAge {
+			return nil, errors.New("error: account is too new to send gifts")
+		}
+	}
+
+	if _, err := u.paymentRepository.FindOnePlayerProfile(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+		PlayerId: strings.TrimPrefix(recipientId, "player:"),
+	}); err != nil {
+		return nil, errors.New("error: recipient not found")
+	}
+
+	// The send is reserved up front so concurrent gifts cannot both pass the
+	// limit, and given back if the gift is not delivered
+	releaseSlot := func() {}
+	if cfg.Gift.DailyLimit > 0 {
+		sentAt := utils.LocalTime()
+		ok, err := u.paymentRepository.ReserveGiftSlot(pctx, senderId, sentAt, cfg.Gift.DailyLimit)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("error: daily gift limit reached")
+		}
+
+		releaseSlot = func() {
+			if err := u.paymentRepository.ReleaseGiftSlot(pctx, senderId, sentAt); err != nil {
+				log.Printf("Error: release gift slot of player %s failed: %v", senderId, err.Error())
+			}
+		}
+	}
+
+	rollbackMoney := func() {}
+	if req.Money > 0 {
+		debit, err := u.paymentRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: senderId,
+			Amount:   -req.Money,
+		})
+		if err != nil {
+			releaseSlot()
+			return nil, err
+		}
+
+		credit, err := u.paymentRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: recipientId,
+			Amount:   req.Money,
+		})
+		if err != nil {
+			u.paymentRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: debit.TransactionId})
+			releaseSlot()
+			return nil, err
+		}
+
+		rollbackMoney = func() {
+			for _, transactionId := range []string{credit.TransactionId, debit.TransactionId} {
+				u.paymentRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: transactionId})
+			}
+			u.publish(pctx, cfg, "payment.refunded", senderId, &payment.PaymentEvent{Amount: req.Money, Reason: "gift could not be delivered"})
+		}
+	}
+
+	items := make([]*payment.PaymentOrderItem, 0)
+	if len(req.Items) > 0 {
+		results, err := u.buyItemFor(pctx, cfg, senderId, recipientId, &payment.ItemServiceReq{Items: req.Items})
+		if err != nil {
+			rollbackMoney()
+			releaseSlot()
+			return nil, err
+		}
+
+		for _, v := range results {
+			items = append(items, &payment.PaymentOrderItem{
+				ItemId:        v.ItemId,
+				Price:         v.Amount,
+				TransactionId: v.TransactionId,
+				InventoryIds:  grantedInventoryIds(v),
+			})
+		}
+	}
+
+	gift := &payment.Gift{
+		SenderId:    senderId,
+		RecipientId: recipientId,
+		Items:       items,
+		Money:       req.Money,
+		Message:     req.Message,
+		CreatedAt:   utils.LocalTime(),
+	}
+
+	event := &payment.GiftEvent{
+		SenderId: senderId,
+		ItemIds:  make([]string, 0),
+		Money:    req.Money,
+		Message:  req.Message,
+	}
+	for _, v := range items {
+		event.ItemIds = append(event.ItemIds, v.ItemId)
+	}
+
+	// The gift has already been delivered, so a failed insert is only logged.
+	giftId, err := u.paymentRepository.InsertOneGift(pctx, gift)
+	if err != nil {
+		log.Printf("Error: insert gift failed: %v", err.Error())
+		u.publish(pctx, cfg, "gift.received", recipientId, event)
+		return gift, nil
+	}
+	gift.Id = giftId
+	event.GiftId = "gift:" + giftId.Hex()
+
+	if _, err := u.paymentRepository.InsertOneGiftNotification(pctx, &payment.GiftNotification{
+		RecipientId: recipientId,
+		SenderId:    senderId,
+		GiftId:      event.GiftId,
+		Message:     req.Message,
+		CreatedAt:   gift.CreatedAt,
+	}); err != nil {
+		log.Printf("Error: insert gift notification failed: %v", err.Error())
+	}
+
+	u.publish(pctx, cfg, "gift.received", recipientId, event)
+
+	return gift, nil
+}
+
+func (u *paymentUsecase) FindGiftNotifications(pctx context.Context, recipientId string) ([]*payment.GiftNotification, error) {
+	return u.paymentRepository.FindGiftNotifications(pctx, recipientId)
+}
+
+// OpenLootBox consumes a copy of the box the player owns, bound or not,
+// charges its opening price, draws an entry with a fresh seed and grants it.
+// The pity count is bumped before the draw so concurrent opens each see their
+// own count. The roll is stored before the grant so every paid draw can be
+// audited.
+func (u *paymentUsecase) OpenLootBox(pctx context.Context, cfg *config.Config, playerId string, req *payment.OpenLootBoxReq) (*payment.LootRoll, error) {
+	itemData, err := u.paymentRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
+		Ids: []string{itemKey(req.ItemId)},
+	})
+	if err != nil {
+		log.Printf("Error: find items in ids failed: %v", err.Error())
+		return nil, errors.New("error: find items in ids failed")
+	}
+	if len(itemData.Items) == 0 {
+		return nil, errors.New("error: item not found")
+	}
+
+	box := itemData.Items[0]
+	if box.LootTable == nil || len(box.LootTable.Entries) == 0 {
+		return nil, errors.New("error: item is not a loot box")
+	}
+
+	entries := make([]*payment.LootRollEntry, 0)
+	for _, v := range box.LootTable.Entries {
+		entries = append(entries, &payment.LootRollEntry{
+			ItemId: v.ItemId,
+			Weight: int(v.Weight),
+			Rare:   v.Rare,
+		})
+	}
+
+	pity, err := u.paymentRepository.IncLootPity(pctx, playerId, box.Id, 1)
+	if err != nil {
+		return nil, err
+	}
+	releasePity := func() {
+		if _, err := u.paymentRepository.IncLootPity(pctx, playerId, box.Id, -1); err != nil {
+			log.Printf("Error: release loot pity failed: %v", err.Error())
+		}
+	}
+
+	roll := &payment.LootRoll{
+		PlayerId:    playerId,
+		BoxItemId:   box.Id,
+		Entries:     entries,
+		Pity:        pity - 1,
+		PityApplied: box.LootTable.PityThreshold > 0 && pity >= int(box.LootTable.PityThreshold),
+		Status:      "pending",
+		CreatedAt:   utils.LocalTime(),
+	}
+
+	revoked, err := u.paymentRepository.BatchRevoke(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{
+		Items:        []*inventoryPb.GrantItem{{PlayerId: playerId, ItemId: box.Id, Quantity: 1}},
+		IncludeBound: true,
+	})
+	if err != nil {
+		releasePity()
+		return nil, err
+	}
+	roll.InventoryId = revoked.Items[0].InventoryId
+
+	refund := func() {
+		if roll.TransactionId != "" {
+			if err := u.paymentRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+				TransactionId: roll.TransactionId,
+			}); err != nil {
+				log.Printf("Error: rollback loot box payment failed: %v", err.Error())
+			} else {
+				u.publish(pctx, cfg, "payment.refunded", playerId, &payment.PaymentEvent{
+					ItemIds: []string{box.Id},
+					Amount:  box.Price,
+					Reason:  "loot box could not be opened",
+				})
+			}
+		}
+		u.returnRevokedItems(pctx, cfg, revoked.Items)
+		releasePity()
+	}
+
+	if box.Price > 0 {
+		transaction, err := u.paymentRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: playerId,
+			Amount:   -box.Price,
+		})
+		if err != nil {
+			refund()
+			return nil, err
+		}
+
+		roll.Price = box.Price
+		roll.TransactionId = transaction.TransactionId
+	}
+
+	var seed [8]byte
+	if _, err := cryptorand.Read(seed[:]); err != nil {
+		refund()
+		return nil, errors.New("error: generate loot seed failed")
+	}
+	roll.Seed = int64(binary.BigEndian.Uint64(seed[:]) >> 1)
+
+	result, value, total := drawLoot(roll.Seed, roll.Entries, roll.PityApplied)
+	if result == nil {
+		refund()
+		return nil, errors.New("error: loot box has no drawable entries")
+	}
+	roll.Roll = value
+	roll.TotalWeight = total
+	roll.ItemId = result.ItemId
+	roll.Rare = result.Rare
+
+	rollId, err := u.paymentRepository.InsertOneLootRoll(pctx, roll)
+	if err != nil {
+		refund()
+		return nil, err
+	}
+	roll.Id = rollId
+
+	if _, err := u.paymentRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+		Items:             []*inventoryPb.GrantItem{{PlayerId: playerId, ItemId: result.ItemId, Quantity: 1}},
+		Source:            "lootbox",
+		OverflowToMailbox: true,
+	}); err != nil {
+		refund()
+
+		roll.Status = "failed"
+		roll.Error = err.Error()
+		if err := u.paymentRepository.UpdateOneLootRoll(pctx, rollId.Hex(), bson.M{"status": roll.Status, "error": roll.Error}); err != nil {
+			log.Printf("Error: update loot roll failed: %v", err.Error())
+		}
+		return nil, err
+	}
+
+	roll.Status = "completed"
+	if err := u.paymentRepository.UpdateOneLootRoll(pctx, rollId.Hex(), bson.M{"status": roll.Status}); err != nil {
+		log.Printf("Error: update loot roll failed: %v", err.Error())
+	}
+
+	// A rare drop takes back the opens counted up to this one; opens that
+	// raced past it keep counting towards the next pity
+	if result.Rare {
+		if _, err := u.paymentRepository.IncLootPity(pctx, playerId, box.Id, -pity); err != nil {
+			log.Printf("Error: reset loot pity failed: %v", err.Error())
+		}
+	}
+
+	return roll, nil
+}
+
+func (u *paymentUsecase) FindLootRolls(pctx context.Context, playerId string) ([]*payment.LootRoll, error) {
+	return u.paymentRepository.FindLootRolls(pctx, playerId)
+}
+
+// VerifyLootRoll replays a stored roll from its seed and entry snapshot.
+func (u *paymentUsecase) VerifyLootRoll(pctx context.Context, rollId string) (*payment.LootRollVerifyRes, error) {
+	roll, err := u.paymentRepository.FindOneLootRoll(pctx, strings.TrimPrefix(rollId, "loot_roll:"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, value, total := drawLoot(roll.Seed, roll.Entries, roll.PityApplied)
+	if result == nil {
+		return &payment.LootRollVerifyRes{RollId: roll.Id.Hex()}, nil
+	}
+
+	return &payment.LootRollVerifyRes{
+		RollId: roll.Id.Hex(),
+		ItemId: result.ItemId,
+		Roll:   value,
+		Valid:  result.ItemId == roll.ItemId && value == roll.Roll && total == roll.TotalWeight,
+	}, nil
+}
+
+// CraftItem consumes the recipe inputs, charges its cost and grants the
+// output, or the consolation item when the success roll fails. Like the buy
+// saga, a failed step undoes the ones before it.
+func (u *paymentUsecase) CraftItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.CraftReq) (*payment.Craft, error) {
+	recipe, err := u.paymentRepository.FindOneRecipe(pctx, cfg.Grpc.ItemUrl, &itemPb.FindOneRecipeReq{
+		RecipeId: "recipe:" + strings.TrimPrefix(req.RecipeId, "recipe:"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	craft := &payment.Craft{
+		PlayerId:  playerId,
+		RecipeId:  recipe.Id,
+		Cost:      recipe.Cost,
+		Status:    "pending",
+		CreatedAt: utils.LocalTime(),
+		UpdatedAt: utils.LocalTime(),
+	}
+
+	craftId, err := u.paymentRepository.InsertOneCraft(pctx, craft)
+	if err != nil {
+		return nil, err
+	}
+	craft.Id = craftId
+
+	fail := func(err error) (*payment.Craft, error) {
+		if err := u.paymentRepository.UpdateOneCraft(pctx, craftId.Hex(), bson.M{
+			"status":     "failed",
+			"error":      err.Error(),
+			"updated_at": utils.LocalTime(),
+		}); err != nil {
+			log.Printf("Error: update craft %s failed: %v", craftId.Hex(), err.Error())
+		}
+		return nil, err
+	}
+
+	revokeReq := &inventoryPb.BatchRevokeReq{Items: make([]*inventoryPb.GrantItem, 0)}
+	for _, v := range recipe.Inputs {
+		revokeReq.Items = append(revokeReq.Items, &inventoryPb.GrantItem{PlayerId: playerId, ItemId: v.ItemId, Quantity: v.Quantity})
+	}
+
+	revoked, err := u.paymentRepository.BatchRevoke(pctx, cfg.Grpc.InventoryUrl, revokeReq)
+	if err != nil {
+		return fail(err)
+	}
+	for _, v := range revoked.Items {
+		craft.ConsumedIds = append(craft.ConsumedIds, v.InventoryId)
+	}
+
+	rollbackMoney := func() {}
+	if recipe.Cost > 0 {
+		transaction, err := u.paymentRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: playerId,
+			Amount:   -recipe.Cost,
+		})
+		if err != nil {
+			u.returnRevokedItems(pctx, cfg, revoked.Items)
+			return fail(err)
+		}
+
+		craft.TransactionId = transaction.TransactionId
+		rollbackMoney = func() {
+			if err := u.paymentRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+				TransactionId: transaction.TransactionId,
+			}); err != nil {
+				log.Printf("Error: rollback craft payment failed: %v", err.Error())
+				return
+			}
+			u.publish(pctx, cfg, "payment.refunded", playerId, &payment.PaymentEvent{
+				Amount: recipe.Cost,
+				Reason: "craft could not be completed",
+			})
+		}
+	}
+
+	craft.Success = rand.Float64() < recipe.SuccessRate
+
+	grant := &inventoryPb.GrantItem{PlayerId: playerId, ItemId: recipe.OutputItemId, Quantity: max(recipe.OutputQuantity, 1)}
+	if !craft.Success {
+		grant = &inventoryPb.GrantItem{PlayerId: playerId, ItemId: recipe.ConsolationItemId, Quantity: 1}
+	}
+
+	if grant.ItemId != "" {
+		granted, err := u.paymentRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+			Items:             []*inventoryPb.GrantItem{grant},
+			Source:            "craft",
+			OverflowToMailbox: true,
+		})
+		if err != nil {
+			rollbackMoney()
+			u.returnRevokedItems(pctx, cfg, revoked.Items)
+			return fail(err)
+		}
+
+		craft.ItemId = grant.ItemId
+		for _, v := range granted.Items {
+			craft.InventoryIds = append(craft.InventoryIds, v.InventoryId)
+		}
+	}
+
+	craft.Status = "completed"
+	craft.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.UpdateOneCraft(pctx, craftId.Hex(), bson.M{
+		"status":         craft.Status,
+		"transaction_id": craft.TransactionId,
+		"consumed_ids":   craft.ConsumedIds,
+		"success":        craft.Success,
+		"item_id":        craft.ItemId,
+		"inventory_ids":  craft.InventoryIds,
+		"updated_at":     craft.UpdatedAt,
+	}); err != nil {
+		log.Printf("Error: update craft %s failed: %v", craftId.Hex(), err.Error())
+	}
+
+	return craft, nil
+}
+
+// BuySeasonPass charges the premium price of the active season and unlocks
+// the player's premium pass, refunding the charge when the unlock fails. It
+// is recorded as a "season_pass" payment order.
+func (u *paymentUsecase) BuySeasonPass(pctx context.Context, cfg *config.Config, playerId string) (*payment.PaymentOrder, error) {
+	season, err := u.paymentRepository.FindActiveSeason(pctx, cfg.Grpc.SeasonUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &payment.PaymentOrder{
+		PlayerId:  playerId,
+		Kind:      "season_pass",
+		Status:    "pending",
+		Items:     []*payment.PaymentOrderItem{{ItemId: "season:" + season.SeasonId, Price: season.PremiumPrice}},
+		Amount:    season.PremiumPrice,
+		CreatedAt: utils.LocalTime(),
+		UpdatedAt: utils.LocalTime(),
+	}
+
+	orderId, err := u.paymentRepository.InsertOnePaymentOrder(pctx, order)
+	if err != nil {
+		return nil, err
+	}
+	order.Id = orderId
+
+	fail := func(err error) (*payment.PaymentOrder, error) {
+		if err := u.paymentRepository.UpdateOnePaymentOrder(pctx, orderId.Hex(), bson.M{
+			"status":     "failed",
+			"items":      order.Items,
+			"error":      err.Error(),
+			"updated_at": utils.LocalTime(),
+		}); err != nil {
+			log.Printf("Error: update payment order %s failed: %v", orderId.Hex(), err.Error())
+		}
+		return nil, err
+	}
+
+	transaction, err := u.paymentRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+		PlayerId: playerId,
+		Amount:   -season.PremiumPrice,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	order.Items[0].TransactionId = transaction.TransactionId
+
+	if _, err := u.paymentRepository.UnlockPremiumPass(pctx, cfg.Grpc.SeasonUrl, &seasonPb.UnlockPremiumPassReq{
+		SeasonId:      season.SeasonId,
+		PlayerId:      playerId,
+		TransactionId: transaction.TransactionId,
+	}); err != nil {
+		if err := u.paymentRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: transaction.TransactionId,
+		}); err != nil {
+			log.Printf("Error: rollback season pass payment failed: %v", err.Error())
+		} else {
+			u.publish(pctx, cfg, "payment.refunded", playerId, &payment.PaymentEvent{
+				ItemIds: []string{"season:" + season.SeasonId},
+				Amount:  season.PremiumPrice,
+				Reason:  "premium pass could not be unlocked",
+			})
+		}
+		return fail(err)
+	}
+
+	order.Status = "completed"
+	order.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.UpdateOnePaymentOrder(pctx, orderId.Hex(), bson.M{
+		"status":     order.Status,
+		"items":      order.Items,
+		"updated_at": order.UpdatedAt,
+	}); err != nil {
+		log.Printf("Error: update payment order %s failed: %v", orderId.Hex(), err.Error())
+	}
+
+	return order, nil
+}
+
+// returnRevokedItems grants revoked copies back with their instance state and
+// lets the owner know they were returned.
+func (u *paymentUsecase) returnRevokedItems(pctx context.Context, cfg *config.Config, items []*inventoryPb.InventoryItem) {
+	grantReq := &inventoryPb.BatchGrantReq{
+		Items:             make([]*inventoryPb.GrantItem, 0),
+		OverflowToMailbox: true,
+	}
+	for _, v := range items {
+		grantReq.Items = append(grantReq.Items, &inventoryPb.GrantItem{
+			PlayerId: v.PlayerId,
+			ItemId:   v.ItemId,
+			Quantity: 1,
+			Instance: v.Instance,
+		})
+	}
+
+	if _, err := u.paymentRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, grantReq); err != nil {
+		log.Printf("Error: return revoked items failed: %v", err.Error())
+		return
+	}
+
+	if len(items) > 0 {
+		event := &payment.PaymentEvent{ItemIds: make([]string, 0)}
+		for _, v := range items {
+			event.ItemIds = append(event.ItemIds, v.ItemId)
+		}
+		u.publish(pctx, cfg, "items.returned", items[0].PlayerId, event)
+	}
+}
+
+// drawLoot picks an entry by cumulative weight. When rareOnly is set only
+// rare entries take part, which is how pity guarantees a rare drop.
+func drawLoot(seed int64, entries []*payment.LootRollEntry, rareOnly bool) (*payment.LootRollEntry, int, int) {
+	pool := make([]*payment.LootRollEntry, 0)
+	total := 0
+	for _, v := range entries {
+		if v.Weight < 1 || (rareOnly && !v.Rare) {
+			continue
+		}
+		pool = append(pool, v)
+		total += v.Weight
+	}
+	if total == 0 {
+		return nil, 0, 0
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	value := rng.IntN(total)
+
+	cursor := value
+	for _, v := range pool {
+		if cursor < v.Weight {
+			return v, value, total
+		}
+		cursor -= v.Weight
+	}
+
+	return nil, value, total
+}
+
+func paymentOrderToPb(order *payment.PaymentOrder) *paymentPb.PaymentOrder {
+	items := make([]*paymentPb.PaymentOrderItem, 0)
+	for _, v := range order.Items {
+		items = append(items, &paymentPb.PaymentOrderItem{
+			ItemId:        v.ItemId,
+			Price:         v.Price,
+			TransactionId: v.TransactionId,
+			InventoryIds:  v.InventoryIds,
+		})
+	}
+
+	return &paymentPb.PaymentOrder{
+		OrderId:   "order:" + order.Id.Hex(),
+		PlayerId:  order.PlayerId,
+		Kind:      order.Kind,
+		Status:    order.Status,
+		Items:     items,
+		Amount:    order.Amount,
+		Reason:    order.Reason,
+		Error:     order.Error,
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: order.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -12,6 +12,7 @@ type (
 		Email       string        `json:"email" bson:"email"`
 		Username    string        `json:"username" bson:"username"`
 		Password    string        `json:"password" bson:"password"`
+		Level       int           `json:"level" bson:"level"`
 		CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
 		UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
 		PlayerRoles []PlayerRole  `bson:"player_roles"`
@@ -27,6 +28,7 @@ type (
 		PlayerId  string        `json:"player_id" bson:"player_id"`
 		Email     string        `json:"email" bson:"email"`
 		Username  string        `json:"username" bson:"username"`
+		Level     int           `json:"level" bson:"level"`
 		CreatedAt time.Time     `json:"created_at" bson:"created_at"`
 		UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
 	}
@@ -37,8 +39,12 @@ type (
 	}
 
 	PlayerTransaction struct {
-		PlayerId  string    `bson:"player_id"`
-		Amount    float64   `bson:"amount"`
-		CreatedAt time.Time `bson:"created_at"`
+		PlayerId string  `bson:"player_id"`
+		Amount   float64 `bson:"amount"`
+		// ReferenceId is the saga step that made the transaction. It is
+		// unique, so a step voided before it arrived cannot be applied.
+		ReferenceId string    `bson:"reference_id,omitempty"`
+		Voided      bool      `bson:"voided,omitempty"`
+		CreatedAt   time.Time `bson:"created_at"`
 	}
 )
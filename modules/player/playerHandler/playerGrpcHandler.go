@@ -3,6 +3,7 @@ package playerHandler
 import (
 	"context"
 
+	"github.com/Supakornn/mmorpg-shop/modules/player"
 	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
 	"github.com/Supakornn/mmorpg-shop/modules/player/playerUsecase"
 )
@@ -29,5 +30,25 @@ func (g *playerGrpcHandler) FindOnePlayerProfileToRefresh(ctx context.Context, r
 }
 
 func (g *playerGrpcHandler) GetPlayerSavingAccount(ctx context.Context, req *playerPb.GetPlayerSavingAccountReq) (*playerPb.GetPlayerSavingAccountRes, error) {
-	return nil, nil
+	result, err := g.playerUsecase.GetPlayerSavingAccount(ctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &playerPb.GetPlayerSavingAccountRes{
+		PlayerId: result.PlayerId,
+		Balance:  result.Balance,
+	}, nil
+}
+
+func (g *playerGrpcHandler) CreatePlayerTransaction(ctx context.Context, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	return g.playerUsecase.CreatePlayerTransaction(ctx, req)
+}
+
+func (g *playerGrpcHandler) RollbackPlayerTransaction(ctx context.Context, req *playerPb.RollbackPlayerTransactionReq) (*playerPb.RollbackPlayerTransactionRes, error) {
+	g.playerUsecase.RollbackPlayerTransaction(ctx, &player.RollbackPlayerTransactionReq{
+		TransactionId: req.TransactionId,
+	})
+
+	return &playerPb.RollbackPlayerTransactionRes{}, nil
 }
@@ -7,6 +7,7 @@ type (
 		Id        string    `json:"_id"`
 		Email     string    `json:"email"`
 		Username  string    `json:"username"`
+		Level     int       `json:"level"`
 		CreatedAt time.Time `json:"created_at"`
 		UpdatedAt time.Time `json:"updated_at"`
 	}
@@ -22,12 +23,19 @@ type (
 		Username string `json:"username" form:"username" validate:"required,max=64"`
 	}
 
+	// CreatePlayerTransactionReq is also a queue message. The reply goes to
+	// ReplyTopic ("payment" when empty) and carries ReferenceId back.
 	CreatePlayerTransactionReq struct {
-		PlayerId string  `json:"player_id" validate:"required,max=64"`
-		Amount   float64 `json:"amount" validate:"required"`
+		PlayerId    string  `json:"player_id" validate:"required,max=64"`
+		Amount      float64 `json:"amount" validate:"required"`
+		ReplyTopic  string  `json:"reply_topic,omitempty"`
+		ReferenceId string  `json:"reference_id,omitempty"`
 	}
 
+	// RollbackPlayerTransactionReq undoes a transaction by id, or by the
+	// saga step that made it when ReferenceId is set.
 	RollbackPlayerTransactionReq struct {
 		TransactionId string `json:"transaction_id"`
+		ReferenceId   string `json:"reference_id,omitempty"`
 	}
 )
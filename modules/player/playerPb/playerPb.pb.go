@@ -9,12 +9,11 @@
 package mmorpg_shop
 
 import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
-
-	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
-	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
 
 const (
@@ -26,13 +25,15 @@ const (
 
 // Structures
 type PlayerProfile struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
-	RoleCode      int32                  `protobuf:"varint,4,opt,name=roleCode,proto3" json:"roleCode,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Username  string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	RoleCode  int32                  `protobuf:"varint,4,opt,name=roleCode,proto3" json:"roleCode,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// players created before levels existed report level 1
+	Level         int32 `protobuf:"varint,7,opt,name=level,proto3" json:"level,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,6 +110,13 @@ func (x *PlayerProfile) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *PlayerProfile) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
 type CredentialSearchReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
@@ -301,11 +309,196 @@ func (x *GetPlayerSavingAccountRes) GetBalance() float64 {
 	return 0
 }
 
+// amount is negative to take money out; taking more than the balance fails
+type CreatePlayerTransactionReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=playerId,proto3" json:"playerId,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePlayerTransactionReq) Reset() {
+	*x = CreatePlayerTransactionReq{}
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlayerTransactionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlayerTransactionReq) ProtoMessage() {}
+
+func (x *CreatePlayerTransactionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlayerTransactionReq.ProtoReflect.Descriptor instead.
+func (*CreatePlayerTransactionReq) Descriptor() ([]byte, []int) {
+	return file_modules_player_playerPb_playerPb_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreatePlayerTransactionReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *CreatePlayerTransactionReq) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CreatePlayerTransactionRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transactionId,proto3" json:"transactionId,omitempty"`
+	Balance       float64                `protobuf:"fixed64,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePlayerTransactionRes) Reset() {
+	*x = CreatePlayerTransactionRes{}
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlayerTransactionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlayerTransactionRes) ProtoMessage() {}
+
+func (x *CreatePlayerTransactionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlayerTransactionRes.ProtoReflect.Descriptor instead.
+func (*CreatePlayerTransactionRes) Descriptor() ([]byte, []int) {
+	return file_modules_player_playerPb_playerPb_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreatePlayerTransactionRes) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *CreatePlayerTransactionRes) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type RollbackPlayerTransactionReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transactionId,proto3" json:"transactionId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackPlayerTransactionReq) Reset() {
+	*x = RollbackPlayerTransactionReq{}
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackPlayerTransactionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackPlayerTransactionReq) ProtoMessage() {}
+
+func (x *RollbackPlayerTransactionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackPlayerTransactionReq.ProtoReflect.Descriptor instead.
+func (*RollbackPlayerTransactionReq) Descriptor() ([]byte, []int) {
+	return file_modules_player_playerPb_playerPb_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RollbackPlayerTransactionReq) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type RollbackPlayerTransactionRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackPlayerTransactionRes) Reset() {
+	*x = RollbackPlayerTransactionRes{}
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackPlayerTransactionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackPlayerTransactionRes) ProtoMessage() {}
+
+func (x *RollbackPlayerTransactionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_player_playerPb_playerPb_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackPlayerTransactionRes.ProtoReflect.Descriptor instead.
+func (*RollbackPlayerTransactionRes) Descriptor() ([]byte, []int) {
+	return file_modules_player_playerPb_playerPb_proto_rawDescGZIP(), []int{8}
+}
+
 var File_modules_player_playerPb_playerPb_proto protoreflect.FileDescriptor
 
 const file_modules_player_playerPb_playerPb_proto_rawDesc = "" +
 	"\n" +
-	"&modules/player/playerPb/playerPb.proto\"\xab\x01\n" +
+	"&modules/player/playerPb/playerPb.proto\"\xc1\x01\n" +
 	"\rPlayerProfile\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
@@ -314,7 +507,8 @@ const file_modules_player_playerPb_playerPb_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"G\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12\x14\n" +
+	"\x05level\x18\a \x01(\x05R\x05level\"G\n" +
 	"\x13CredentialSearchReq\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\">\n" +
@@ -324,11 +518,22 @@ const file_modules_player_playerPb_playerPb_proto_rawDesc = "" +
 	"\bplayerId\x18\x01 \x01(\tR\bplayerId\"Q\n" +
 	"\x19GetPlayerSavingAccountRes\x12\x1a\n" +
 	"\bplayerId\x18\x01 \x01(\tR\bplayerId\x12\x18\n" +
-	"\abalance\x18\x02 \x01(\x01R\abalance2\xf3\x01\n" +
+	"\abalance\x18\x02 \x01(\x01R\abalance\"P\n" +
+	"\x1aCreatePlayerTransactionReq\x12\x1a\n" +
+	"\bplayerId\x18\x01 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"\\\n" +
+	"\x1aCreatePlayerTransactionRes\x12$\n" +
+	"\rtransactionId\x18\x01 \x01(\tR\rtransactionId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x01R\abalance\"D\n" +
+	"\x1cRollbackPlayerTransactionReq\x12$\n" +
+	"\rtransactionId\x18\x01 \x01(\tR\rtransactionId\"\x1e\n" +
+	"\x1cRollbackPlayerTransactionRes2\xa3\x03\n" +
 	"\x11PlayerGrpcService\x128\n" +
 	"\x10CredentialSearch\x12\x14.CredentialSearchReq\x1a\x0e.PlayerProfile\x12R\n" +
 	"\x1dFindOnePlayerProfileToRefresh\x12!.FindOnePlayerProfileToRefreshReq\x1a\x0e.PlayerProfile\x12P\n" +
-	"\x16GetPlayerSavingAccount\x12\x1a.GetPlayerSavingAccountReq\x1a\x1a.GetPlayerSavingAccountResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+	"\x16GetPlayerSavingAccount\x12\x1a.GetPlayerSavingAccountReq\x1a\x1a.GetPlayerSavingAccountRes\x12S\n" +
+	"\x17CreatePlayerTransaction\x12\x1b.CreatePlayerTransactionReq\x1a\x1b.CreatePlayerTransactionRes\x12Y\n" +
+	"\x19RollbackPlayerTransaction\x12\x1d.RollbackPlayerTransactionReq\x1a\x1d.RollbackPlayerTransactionResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
 
 var (
 	file_modules_player_playerPb_playerPb_proto_rawDescOnce sync.Once
@@ -342,23 +547,31 @@ func file_modules_player_playerPb_playerPb_proto_rawDescGZIP() []byte {
 	return file_modules_player_playerPb_playerPb_proto_rawDescData
 }
 
-var file_modules_player_playerPb_playerPb_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_modules_player_playerPb_playerPb_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_modules_player_playerPb_playerPb_proto_goTypes = []any{
 	(*PlayerProfile)(nil),                    // 0: PlayerProfile
 	(*CredentialSearchReq)(nil),              // 1: CredentialSearchReq
 	(*FindOnePlayerProfileToRefreshReq)(nil), // 2: FindOnePlayerProfileToRefreshReq
 	(*GetPlayerSavingAccountReq)(nil),        // 3: GetPlayerSavingAccountReq
 	(*GetPlayerSavingAccountRes)(nil),        // 4: GetPlayerSavingAccountRes
+	(*CreatePlayerTransactionReq)(nil),       // 5: CreatePlayerTransactionReq
+	(*CreatePlayerTransactionRes)(nil),       // 6: CreatePlayerTransactionRes
+	(*RollbackPlayerTransactionReq)(nil),     // 7: RollbackPlayerTransactionReq
+	(*RollbackPlayerTransactionRes)(nil),     // 8: RollbackPlayerTransactionRes
 }
 var file_modules_player_playerPb_playerPb_proto_depIdxs = []int32{
 	1, // 0: PlayerGrpcService.CredentialSearch:input_type -> CredentialSearchReq
 	2, // 1: PlayerGrpcService.FindOnePlayerProfileToRefresh:input_type -> FindOnePlayerProfileToRefreshReq
 	3, // 2: PlayerGrpcService.GetPlayerSavingAccount:input_type -> GetPlayerSavingAccountReq
-	0, // 3: PlayerGrpcService.CredentialSearch:output_type -> PlayerProfile
-	0, // 4: PlayerGrpcService.FindOnePlayerProfileToRefresh:output_type -> PlayerProfile
-	4, // 5: PlayerGrpcService.GetPlayerSavingAccount:output_type -> GetPlayerSavingAccountRes
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	5, // 3: PlayerGrpcService.CreatePlayerTransaction:input_type -> CreatePlayerTransactionReq
+	7, // 4: PlayerGrpcService.RollbackPlayerTransaction:input_type -> RollbackPlayerTransactionReq
+	0, // 5: PlayerGrpcService.CredentialSearch:output_type -> PlayerProfile
+	0, // 6: PlayerGrpcService.FindOnePlayerProfileToRefresh:output_type -> PlayerProfile
+	4, // 7: PlayerGrpcService.GetPlayerSavingAccount:output_type -> GetPlayerSavingAccountRes
+	6, // 8: PlayerGrpcService.CreatePlayerTransaction:output_type -> CreatePlayerTransactionRes
+	8, // 9: PlayerGrpcService.RollbackPlayerTransaction:output_type -> RollbackPlayerTransactionRes
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -375,7 +588,7 @@ func file_modules_player_playerPb_playerPb_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_player_playerPb_playerPb_proto_rawDesc), len(file_modules_player_playerPb_playerPb_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
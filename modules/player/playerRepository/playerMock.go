@@ -68,12 +68,17 @@ func (m *PlayerRepositoryMock) DeleteOnePlayerTransaction(pctx context.Context,
 	return args.Error(0)
 }
 
+func (m *PlayerRepositoryMock) VoidPlayerTransaction(pctx context.Context, referenceId string) error {
+	args := m.Called(pctx, referenceId)
+	return args.Error(0)
+}
+
 func (m *PlayerRepositoryMock) DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
 	args := m.Called(pctx, cfg, req)
 	return args.Error(0)
 }
 
-func (m *PlayerRepositoryMock) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
-	args := m.Called(pctx, cfg, req)
+func (m *PlayerRepositoryMock) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error {
+	args := m.Called(pctx, cfg, topic, req)
 	return args.Error(0)
 }
@@ -30,8 +30,9 @@ type (
 		GetOffset(pctx context.Context) (int64, error)
 		UpsertOffset(pctx context.Context, offset int64) error
 		DeleteOnePlayerTransaction(pctx context.Context, transactionId string) error
+		VoidPlayerTransaction(pctx context.Context, referenceId string) error
 		DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
-		AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
+		AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error
 	}
 
 	playerRepository struct {
@@ -107,6 +108,7 @@ func (r *playerRepository) FindOnePlayerProfile(pctx context.Context, playerId s
 			"_id":        1,
 			"username":   1,
 			"email":      1,
+			"level":      1,
 			"created_at": 1,
 			"updated_at": 1,
 		}),
@@ -201,6 +203,32 @@ func (r *playerRepository) DeleteOnePlayerTransaction(pctx context.Context, tran
 	return nil
 }
 
+// VoidPlayerTransaction zeroes the transaction of a saga step, or stores a
+// zero one in its place when the step has not arrived yet so it is refused
+// when it does.
+func (r *playerRepository) VoidPlayerTransaction(pctx context.Context, referenceId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_transactions")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"reference_id": referenceId},
+		bson.M{
+			"$set":         bson.M{"amount": 0, "voided": true},
+			"$setOnInsert": bson.M{"created_at": utils.LocalTime()},
+		},
+		options.UpdateOne().SetUpsert(true),
+	); err != nil {
+		log.Printf("error: void player transaction: %v", err.Error())
+		return errors.New("error: void player transaction failed")
+	}
+
+	return nil
+}
+
 func (r *playerRepository) FindOnePlayerCredential(pctx context.Context, email string) (*player.Player, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -284,14 +312,18 @@ func (r *playerRepository) DockedPlayerMoneyRes(pctx context.Context, cfg *confi
 	return nil
 }
 
-func (r *playerRepository) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
+func (r *playerRepository) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, topic string, req *payment.PaymentTransferRes) error {
+	if topic == "" {
+		topic = "payment"
+	}
+
 	reqInBytes, err := json.Marshal(req)
 	if err != nil {
 		log.Printf("Error: marshal request failed: %v", err.Error())
 		return errors.New("error: marshal request failed")
 	}
 
-	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "payment", "sell", reqInBytes); err != nil {
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, topic, "sell", reqInBytes); err != nil {
 		log.Printf("Error: push message with key to queue failed: %v", err.Error())
 		return errors.New("error: push message with key to queue failed")
 	}
@@ -29,6 +29,7 @@ type (
 		RollbackPlayerTransaction(pctx context.Context, req *player.RollbackPlayerTransactionReq)
 		DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq)
 		AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq)
+		CreatePlayerTransaction(pctx context.Context, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error)
 	}
 
 	playerUsecase struct {
@@ -56,6 +57,7 @@ func (u *playerUsecase) CreatePlayer(pctx context.Context, req *player.CreatePla
 		Email:     req.Email,
 		Password:  string(hashedPassword),
 		Username:  req.Username,
+		Level:     1,
 		CreatedAt: utils.LocalTime(),
 		UpdatedAt: utils.LocalTime(),
 		PlayerRoles: []player.PlayerRole{
@@ -90,6 +92,7 @@ func (u *playerUsecase) FindOnePlayerProfile(pctx context.Context, playerId stri
 		Id:        result.Id.Hex(),
 		Username:  result.Username,
 		Email:     result.Email,
+		Level:     max(result.Level, 1),
 		CreatedAt: result.CreatedAt.In(loc),
 		UpdatedAt: result.UpdatedAt.In(loc),
 	}, nil
@@ -139,6 +142,7 @@ func (u *playerUsecase) FindOnePlayerCredential(pctx context.Context, email stri
 		Email:     result.Email,
 		Username:  result.Username,
 		RoleCode:  int32(roleCode),
+		Level:     int32(max(result.Level, 1)),
 		CreatedAt: result.CreatedAt.In(loc).String(),
 		UpdatedAt: result.UpdatedAt.In(loc).String(),
 	}, nil
@@ -162,6 +166,7 @@ func (u *playerUsecase) FindOnePlayerProfileToRefresh(pctx context.Context, play
 		Email:     result.Email,
 		Username:  result.Username,
 		RoleCode:  int32(roleCode),
+		Level:     int32(max(result.Level, 1)),
 		CreatedAt: result.CreatedAt.In(loc).String(),
 		UpdatedAt: result.UpdatedAt.In(loc).String(),
 	}, nil
@@ -176,6 +181,11 @@ func (u *playerUsecase) UpsertOffset(pctx context.Context, offset int64) error {
 }
 
 func (u *playerUsecase) RollbackPlayerTransaction(pctx context.Context, req *player.RollbackPlayerTransactionReq) {
+	if req.ReferenceId != "" {
+		u.playerRepository.VoidPlayerTransaction(pctx, req.ReferenceId)
+		return
+	}
+
 	u.playerRepository.DeleteOnePlayerTransaction(pctx, req.TransactionId)
 }
 
@@ -183,6 +193,7 @@ func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.C
 	savingAccount, err := u.playerRepository.GetPlayerSavingAccount(pctx, req.PlayerId)
 	if err != nil {
 		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
 			InventoryId:   "",
@@ -196,6 +207,7 @@ func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.C
 	if savingAccount.Balance < math.Abs(req.Amount) {
 		log.Println("Error: player balance is not enough")
 		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
 			InventoryId:   "",
@@ -207,13 +219,15 @@ func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.C
 	}
 
 	transactionId, err := u.playerRepository.InsertOnePlayerTransaction(pctx, &player.PlayerTransaction{
-		PlayerId:  req.PlayerId,
-		Amount:    req.Amount,
-		CreatedAt: utils.LocalTime(),
+		PlayerId:    req.PlayerId,
+		Amount:      req.Amount,
+		ReferenceId: req.ReferenceId,
+		CreatedAt:   utils.LocalTime(),
 	})
 	if err != nil {
 		log.Println("Error: insert one player transaction failed")
 		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
 			InventoryId:   "",
@@ -225,6 +239,7 @@ func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.C
 	}
 
 	u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+		ReferenceId:   req.ReferenceId,
 		TransactionId: transactionId.Hex(),
 		PlayerId:      req.PlayerId,
 		InventoryId:   "",
@@ -236,13 +251,15 @@ func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.C
 
 func (u *playerUsecase) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) {
 	transactionId, err := u.playerRepository.InsertOnePlayerTransaction(pctx, &player.PlayerTransaction{
-		PlayerId:  req.PlayerId,
-		Amount:    req.Amount,
-		CreatedAt: utils.LocalTime(),
+		PlayerId:    req.PlayerId,
+		Amount:      req.Amount,
+		ReferenceId: req.ReferenceId,
+		CreatedAt:   utils.LocalTime(),
 	})
 	if err != nil {
 		log.Println("Error: insert one player transaction failed")
-		u.playerRepository.AddPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+		u.playerRepository.AddPlayerMoneyRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+			ReferenceId:   req.ReferenceId,
 			TransactionId: "",
 			PlayerId:      req.PlayerId,
 			InventoryId:   "",
@@ -253,7 +270,8 @@ func (u *playerUsecase) AddPlayerMoneyRes(pctx context.Context, cfg *config.Conf
 		return
 	}
 
-	u.playerRepository.AddPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+	u.playerRepository.AddPlayerMoneyRes(pctx, cfg, req.ReplyTopic, &payment.PaymentTransferRes{
+		ReferenceId:   req.ReferenceId,
 		TransactionId: transactionId.Hex(),
 		PlayerId:      req.PlayerId,
 		InventoryId:   "",
@@ -262,3 +280,39 @@ func (u *playerUsecase) AddPlayerMoneyRes(pctx context.Context, cfg *config.Conf
 		Error:         "",
 	})
 }
+
+func (u *playerUsecase) CreatePlayerTransaction(pctx context.Context, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	if req.PlayerId == "" || req.Amount == 0 {
+		return nil, errors.New("error: invalid player transaction")
+	}
+
+	if req.Amount < 0 {
+		savingAccount, err := u.playerRepository.GetPlayerSavingAccount(pctx, req.PlayerId)
+		if err != nil {
+			return nil, err
+		}
+
+		if savingAccount.Balance < math.Abs(req.Amount) {
+			return nil, errors.New("error: player balance is not enough")
+		}
+	}
+
+	transactionId, err := u.playerRepository.InsertOnePlayerTransaction(pctx, &player.PlayerTransaction{
+		PlayerId:  req.PlayerId,
+		Amount:    req.Amount,
+		CreatedAt: utils.LocalTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	savingAccount, err := u.playerRepository.GetPlayerSavingAccount(pctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &playerPb.CreatePlayerTransactionRes{
+		TransactionId: transactionId.Hex(),
+		Balance:       savingAccount.Balance,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package reward
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// RewardCalendar hands out Days in order, one claim per day. A daily
+	// calendar moves to the next day on every claim, a streak calendar
+	// starts over after a missed day and an event calendar follows the
+	// days since StartsAt.
+	RewardCalendar struct {
+		Id          bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title       string        `json:"title" bson:"title"`
+		Kind        string        `json:"kind" bson:"kind"`
+		Days        []*RewardDay  `json:"days" bson:"days"`
+		StartsAt    *time.Time    `json:"starts_at,omitempty" bson:"starts_at,omitempty"`
+		EndsAt      *time.Time    `json:"ends_at,omitempty" bson:"ends_at,omitempty"`
+		UsageStatus bool          `json:"usage_status" bson:"usage_status"`
+		CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	RewardDay struct {
+		Day    int     `json:"day" bson:"day"`
+		Money  float64 `json:"money,omitempty" bson:"money,omitempty"`
+		ItemId string  `json:"item_id,omitempty" bson:"item_id,omitempty"`
+	}
+
+	// RewardClaim is unique per player, calendar and Period (the claim
+	// date). MoneyStatus and ItemStatus move from "pending" to "granted" or
+	// "failed" as the player and inventory services reply.
+	RewardClaim struct {
+		Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string        `json:"player_id" bson:"player_id"`
+		CalendarId    string        `json:"calendar_id" bson:"calendar_id"`
+		Period        string        `json:"period" bson:"period"`
+		Day           int           `json:"day" bson:"day"`
+		Money         float64       `json:"money,omitempty" bson:"money,omitempty"`
+		ItemId        string        `json:"item_id,omitempty" bson:"item_id,omitempty"`
+		MoneyStatus   string        `json:"money_status,omitempty" bson:"money_status,omitempty"`
+		ItemStatus    string        `json:"item_status,omitempty" bson:"item_status,omitempty"`
+		TransactionId string        `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		InventoryId   string        `json:"inventory_id,omitempty" bson:"inventory_id,omitempty"`
+		Error         string        `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt     time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	// PromoCode grants Money and/or ItemId to each player that redeems it,
+	// at most MaxRedemptions times in total (0 means unlimited).
+	PromoCode struct {
+		Id             bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Code           string        `json:"code" bson:"code"`
+		Campaign       string        `json:"campaign" bson:"campaign"`
+		Money          float64       `json:"money,omitempty" bson:"money,omitempty"`
+		ItemId         string        `json:"item_id,omitempty" bson:"item_id,omitempty"`
+		MaxRedemptions int           `json:"max_redemptions" bson:"max_redemptions"`
+		Redemptions    int           `json:"redemptions" bson:"redemptions"`
+		ExpiresAt      *time.Time    `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+		UsageStatus    bool          `json:"usage_status" bson:"usage_status"`
+		CreatedAt      time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt      time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	// PromoRedemption is unique per code and player and tracks its grants
+	// like a RewardClaim.
+	PromoRedemption struct {
+		Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Code          string        `json:"code" bson:"code"`
+		Campaign      string        `json:"campaign" bson:"campaign"`
+		PlayerId      string        `json:"player_id" bson:"player_id"`
+		Money         float64       `json:"money,omitempty" bson:"money,omitempty"`
+		ItemId        string        `json:"item_id,omitempty" bson:"item_id,omitempty"`
+		MoneyStatus   string        `json:"money_status,omitempty" bson:"money_status,omitempty"`
+		ItemStatus    string        `json:"item_status,omitempty" bson:"item_status,omitempty"`
+		TransactionId string        `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		InventoryId   string        `json:"inventory_id,omitempty" bson:"inventory_id,omitempty"`
+		Error         string        `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt     time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+)
@@ -0,0 +1,196 @@
+package rewardHandler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/reward"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	RewardHttpHandlerService interface {
+		CreateCalendar(c echo.Context) error
+		FindCalendars(c echo.Context) error
+		FindCalendarStatus(c echo.Context) error
+		ClaimReward(c echo.Context) error
+		FindClaims(c echo.Context) error
+		CreatePromoCodes(c echo.Context) error
+		FindPromoCodes(c echo.Context) error
+		RedeemCode(c echo.Context) error
+		FindRedemptions(c echo.Context) error
+	}
+
+	rewardHttpHandler struct {
+		cfg           *config.Config
+		rewardUsecase rewardUsecase.RewardUsecaseService
+	}
+)
+
+func NewRewardHttpHandler(cfg *config.Config, rewardUsecase rewardUsecase.RewardUsecaseService) RewardHttpHandlerService {
+	return &rewardHttpHandler{cfg, rewardUsecase}
+}
+
+func calendarIdParam(c echo.Context) (string, error) {
+	decodedParam, err := url.QueryUnescape(c.Param("calendar_id"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(decodedParam, "calendar:"), nil
+}
+
+func (h *rewardHttpHandler) CreateCalendar(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(reward.CreateCalendarReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.rewardUsecase.CreateCalendar(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *rewardHttpHandler) FindCalendars(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.rewardUsecase.FindCalendars(ctx)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) FindCalendarStatus(c echo.Context) error {
+	ctx := context.Background()
+
+	calendarId, err := calendarIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.rewardUsecase.FindCalendarStatus(ctx, c.Get("player_id").(string), calendarId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) ClaimReward(c echo.Context) error {
+	ctx := context.Background()
+
+	calendarId, err := calendarIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.rewardUsecase.ClaimReward(ctx, h.cfg, c.Get("player_id").(string), calendarId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) FindClaims(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.rewardUsecase.FindClaims(ctx, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) CreatePromoCodes(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(reward.CreatePromoCodesReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.rewardUsecase.CreatePromoCodes(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *rewardHttpHandler) FindPromoCodes(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(reward.PromoSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.rewardUsecase.FindPromoCodes(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) RedeemCode(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(reward.RedeemCodeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.rewardUsecase.RedeemCode(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *rewardHttpHandler) FindRedemptions(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(reward.PromoSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.rewardUsecase.FindRedemptions(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
@@ -0,0 +1,95 @@
+package rewardHandler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IBM/sarama"
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+)
+
+type (
+	RewardQueueHandlerService interface {
+		GrantResult()
+	}
+
+	rewardQueueHandler struct {
+		cfg           *config.Config
+		rewardUsecase rewardUsecase.RewardUsecaseService
+	}
+)
+
+func NewRewardQueueHandler(cfg *config.Config, rewardUsecase rewardUsecase.RewardUsecaseService) RewardQueueHandlerService {
+	return &rewardQueueHandler{cfg, rewardUsecase}
+}
+
+func (h *rewardQueueHandler) RewardConsumer(pctx context.Context) (sarama.PartitionConsumer, error) {
+	worker, err := queue.ConnectConsumer([]string{h.cfg.Kafka.Url}, h.cfg.Kafka.ApiKey, h.cfg.Kafka.Secret)
+	if err != nil {
+		return nil, errors.New("error: connect consumer failed")
+	}
+
+	offset, err := h.rewardUsecase.GetOffset(pctx)
+	if err != nil {
+		return nil, errors.New("error: get offset failed")
+	}
+
+	consumer, err := worker.ConsumePartition("reward", 0, offset)
+	if err != nil {
+		log.Printf("Error: Try to consume partition with offset: %v", err.Error())
+		consumer, err = worker.ConsumePartition("reward", 0, 0)
+		if err != nil {
+			log.Printf("Error: consume partition failed: %v", err.Error())
+			return nil, errors.New("error: consume partition failed")
+		}
+	}
+
+	return consumer, nil
+}
+
+func (h *rewardQueueHandler) GrantResult() {
+	ctx := context.Background()
+
+	consumer, err := h.RewardConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Reward grant result consumer started")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Printf("Error: reward grant result consumer failed: %v", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			key := string(msg.Key)
+			if key == "sell" || key == "buy" {
+				h.rewardUsecase.UpsertOffset(ctx, msg.Offset+1)
+				res := new(payment.PaymentTransferRes)
+				if err := queue.DecodeMessage(res, msg.Value); err != nil {
+					continue
+				}
+
+				if err := h.rewardUsecase.GrantResult(ctx, key, res); err != nil {
+					log.Printf("Error: reward grant result %s failed: %v", res.ReferenceId, err.Error())
+				}
+				log.Printf("info: reward grant result: topic: %s, offset: %d, value: %s", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigChan:
+			log.Println("Reward grant result consumer stopped")
+			return
+		}
+	}
+}
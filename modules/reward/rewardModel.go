@@ -0,0 +1,54 @@
+package reward
+
+import "time"
+
+type (
+	// CreateCalendarReq lists the reward of each day in order. Event
+	// calendars need StartsAt and EndsAt.
+	CreateCalendarReq struct {
+		Title    string          `json:"title" validate:"required,max=64"`
+		Kind     string          `json:"kind" validate:"required,oneof=daily streak event"`
+		Days     []*RewardDayReq `json:"days" validate:"required,min=1,max=31,dive"`
+		StartsAt *time.Time      `json:"starts_at"`
+		EndsAt   *time.Time      `json:"ends_at"`
+	}
+
+	RewardDayReq struct {
+		Money  float64 `json:"money" validate:"min=0"`
+		ItemId string  `json:"item_id" validate:"max=64"`
+	}
+
+	CalendarStatusRes struct {
+		CalendarId string       `json:"calendar_id"`
+		Kind       string       `json:"kind"`
+		Period     string       `json:"period"`
+		Claimed    bool         `json:"claimed"`
+		NextDay    int          `json:"next_day,omitempty"`
+		NextReward *RewardDay   `json:"next_reward,omitempty"`
+		LastClaim  *RewardClaim `json:"last_claim,omitempty"`
+		Error      string       `json:"error,omitempty"`
+	}
+
+	// CreatePromoCodesReq generates Count random codes, or the single Code
+	// when it is given.
+	CreatePromoCodesReq struct {
+		Campaign       string     `json:"campaign" validate:"required,max=64"`
+		Code           string     `json:"code" validate:"omitempty,alphanum,min=4,max=32"`
+		Prefix         string     `json:"prefix" validate:"omitempty,alphanum,max=8"`
+		Count          int        `json:"count" validate:"min=0,max=1000"`
+		Money          float64    `json:"money" validate:"min=0"`
+		ItemId         string     `json:"item_id" validate:"max=64"`
+		MaxRedemptions int        `json:"max_redemptions" validate:"min=0"`
+		ExpiresAt      *time.Time `json:"expires_at"`
+	}
+
+	RedeemCodeReq struct {
+		Code string `json:"code" validate:"required,max=48"`
+	}
+
+	PromoSearchReq struct {
+		Campaign string `query:"campaign" validate:"max=64"`
+		Code     string `query:"code" validate:"max=48"`
+		PlayerId string `query:"player_id" validate:"max=64"`
+	}
+)
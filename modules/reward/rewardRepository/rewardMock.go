@@ -0,0 +1,126 @@
+package rewardRepository
+
+import (
+	"context"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/player"
+	"github.com/Supakornn/mmorpg-shop/modules/reward"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type RewardRepositoryMock struct {
+	mock.Mock
+}
+
+func NewRewardRepositoryMock() RewardRepositoryService {
+	return &RewardRepositoryMock{}
+}
+
+func (m *RewardRepositoryMock) InsertOneCalendar(pctx context.Context, req *reward.RewardCalendar) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindOneCalendar(pctx context.Context, calendarId string) (*reward.RewardCalendar, error) {
+	args := m.Called(pctx, calendarId)
+	return args.Get(0).(*reward.RewardCalendar), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindCalendars(pctx context.Context, filter bson.D) ([]*reward.RewardCalendar, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*reward.RewardCalendar), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) InsertOneClaim(pctx context.Context, req *reward.RewardClaim) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindLastClaim(pctx context.Context, playerId, calendarId string) (*reward.RewardClaim, error) {
+	args := m.Called(pctx, playerId, calendarId)
+	return args.Get(0).(*reward.RewardClaim), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindClaims(pctx context.Context, playerId string) ([]*reward.RewardClaim, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).([]*reward.RewardClaim), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) UpdateOneClaim(pctx context.Context, claimId string, req bson.M) error {
+	args := m.Called(pctx, claimId, req)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) RetryClaim(pctx context.Context, claimId, statusField string) error {
+	args := m.Called(pctx, claimId, statusField)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) InsertManyPromoCodes(pctx context.Context, req []*reward.PromoCode) error {
+	args := m.Called(pctx, req)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) FindOnePromoCode(pctx context.Context, code string) (*reward.PromoCode, error) {
+	args := m.Called(pctx, code)
+	return args.Get(0).(*reward.PromoCode), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindPromoCodes(pctx context.Context, filter bson.D) ([]*reward.PromoCode, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*reward.PromoCode), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) ReservePromoCode(pctx context.Context, code string) error {
+	args := m.Called(pctx, code)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) ReleasePromoCode(pctx context.Context, code string) error {
+	args := m.Called(pctx, code)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) InsertOneRedemption(pctx context.Context, req *reward.PromoRedemption) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) FindRedemptions(pctx context.Context, filter bson.D) ([]*reward.PromoRedemption, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*reward.PromoRedemption), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) UpdateOneRedemption(pctx context.Context, redemptionId string, req bson.M) error {
+	args := m.Called(pctx, redemptionId, req)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*itemPb.FindItemsInIdsRes), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) AddPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error {
+	args := m.Called(pctx, cfg, req)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) AddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error {
+	args := m.Called(pctx, cfg, req)
+	return args.Error(0)
+}
+
+func (m *RewardRepositoryMock) GetOffset(pctx context.Context) (int64, error) {
+	args := m.Called(pctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RewardRepositoryMock) UpsertOffset(pctx context.Context, offset int64) error {
+	args := m.Called(pctx, offset)
+	return args.Error(0)
+}
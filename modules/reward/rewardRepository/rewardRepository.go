@@ -0,0 +1,509 @@
+package rewardRepository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/player"
+	"github.com/Supakornn/mmorpg-shop/modules/reward"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/queue"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type (
+	RewardRepositoryService interface {
+		InsertOneCalendar(pctx context.Context, req *reward.RewardCalendar) (bson.ObjectID, error)
+		FindOneCalendar(pctx context.Context, calendarId string) (*reward.RewardCalendar, error)
+		FindCalendars(pctx context.Context, filter bson.D) ([]*reward.RewardCalendar, error)
+		InsertOneClaim(pctx context.Context, req *reward.RewardClaim) (bson.ObjectID, error)
+		FindLastClaim(pctx context.Context, playerId, calendarId string) (*reward.RewardClaim, error)
+		FindClaims(pctx context.Context, playerId string) ([]*reward.RewardClaim, error)
+		UpdateOneClaim(pctx context.Context, claimId string, req bson.M) error
+		RetryClaim(pctx context.Context, claimId, statusField string) error
+		InsertManyPromoCodes(pctx context.Context, req []*reward.PromoCode) error
+		FindOnePromoCode(pctx context.Context, code string) (*reward.PromoCode, error)
+		FindPromoCodes(pctx context.Context, filter bson.D) ([]*reward.PromoCode, error)
+		ReservePromoCode(pctx context.Context, code string) error
+		ReleasePromoCode(pctx context.Context, code string) error
+		InsertOneRedemption(pctx context.Context, req *reward.PromoRedemption) (bson.ObjectID, error)
+		FindRedemptions(pctx context.Context, filter bson.D) ([]*reward.PromoRedemption, error)
+		UpdateOneRedemption(pctx context.Context, redemptionId string, req bson.M) error
+		FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		AddPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error
+		AddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+	}
+
+	rewardRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewRewardRepository(db *mongo.Client) RewardRepositoryService {
+	return &rewardRepository{db}
+}
+
+func (r *rewardRepository) rewardDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("reward_db")
+}
+
+func (r *rewardRepository) InsertOneCalendar(pctx context.Context, req *reward.RewardCalendar) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_calendars")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one calendar: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one calendar failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *rewardRepository) FindOneCalendar(pctx context.Context, calendarId string) (*reward.RewardCalendar, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_calendars")
+
+	result := new(reward.RewardCalendar)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(calendarId)}).Decode(result); err != nil {
+		log.Printf("error: find one calendar: %v", err.Error())
+		return nil, errors.New("error: calendar not found")
+	}
+
+	return result, nil
+}
+
+func (r *rewardRepository) FindCalendars(pctx context.Context, filter bson.D) ([]*reward.RewardCalendar, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_calendars")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("error: find calendars: %v", err.Error())
+		return nil, errors.New("error: find calendars failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*reward.RewardCalendar, 0)
+	for cursors.Next(ctx) {
+		result := new(reward.RewardCalendar)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode calendar: %v", err.Error())
+			return nil, errors.New("error: decode calendar failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *rewardRepository) InsertOneClaim(pctx context.Context, req *reward.RewardClaim) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_claims")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: reward already claimed")
+		}
+		log.Printf("error: insert one claim: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one claim failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+// FindLastClaim returns nil when the player has never claimed from the
+// calendar.
+func (r *rewardRepository) FindLastClaim(pctx context.Context, playerId, calendarId string) (*reward.RewardClaim, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_claims")
+
+	result := new(reward.RewardClaim)
+	if err := col.FindOne(
+		ctx,
+		bson.M{"player_id": playerId, "calendar_id": calendarId},
+		options.FindOne().SetSort(bson.D{{Key: "period", Value: -1}}),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("error: find last claim: %v", err.Error())
+		return nil, errors.New("error: find last claim failed")
+	}
+
+	return result, nil
+}
+
+func (r *rewardRepository) FindClaims(pctx context.Context, playerId string) ([]*reward.RewardClaim, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_claims")
+
+	cursors, err := col.Find(ctx, bson.M{"player_id": playerId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100))
+	if err != nil {
+		log.Printf("error: find claims: %v", err.Error())
+		return nil, errors.New("error: find claims failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*reward.RewardClaim, 0)
+	for cursors.Next(ctx) {
+		result := new(reward.RewardClaim)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode claim: %v", err.Error())
+			return nil, errors.New("error: decode claim failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *rewardRepository) UpdateOneClaim(pctx context.Context, claimId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_claims")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(claimId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one claim: %v", err.Error())
+		return errors.New("error: update one claim failed")
+	}
+
+	return nil
+}
+
+// RetryClaim moves a failed grant of a claim back to pending, so only one
+// retry of it runs at a time.
+func (r *rewardRepository) RetryClaim(pctx context.Context, claimId, statusField string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_claims")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(claimId), statusField: "failed"},
+		bson.M{"$set": bson.M{statusField: "pending", "error": "", "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: retry claim: %v", err.Error())
+		return errors.New("error: retry claim failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: reward already claimed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) InsertManyPromoCodes(pctx context.Context, req []*reward.PromoCode) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_codes")
+
+	docs := make([]any, 0)
+	for _, v := range req {
+		docs = append(docs, v)
+	}
+
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("error: promo code already exists")
+		}
+		log.Printf("error: insert many promo codes: %v", err.Error())
+		return errors.New("error: insert many promo codes failed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) FindOnePromoCode(pctx context.Context, code string) (*reward.PromoCode, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_codes")
+
+	result := new(reward.PromoCode)
+	if err := col.FindOne(ctx, bson.M{"code": code}).Decode(result); err != nil {
+		log.Printf("error: find one promo code: %v", err.Error())
+		return nil, errors.New("error: promo code not found")
+	}
+
+	return result, nil
+}
+
+func (r *rewardRepository) FindPromoCodes(pctx context.Context, filter bson.D) ([]*reward.PromoCode, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_codes")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(1000))
+	if err != nil {
+		log.Printf("error: find promo codes: %v", err.Error())
+		return nil, errors.New("error: find promo codes failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*reward.PromoCode, 0)
+	for cursors.Next(ctx) {
+		result := new(reward.PromoCode)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode promo code: %v", err.Error())
+			return nil, errors.New("error: decode promo code failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ReservePromoCode counts one redemption of a code in a single update, so
+// concurrent redemptions can never go past MaxRedemptions.
+func (r *rewardRepository) ReservePromoCode(pctx context.Context, code string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_codes")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{
+			"code":         code,
+			"usage_status": true,
+			"$and": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"expires_at": nil},
+					bson.M{"expires_at": bson.M{"$gt": utils.LocalTime()}},
+				}},
+				bson.M{"$or": bson.A{
+					bson.M{"max_redemptions": 0},
+					bson.M{"$expr": bson.M{"$lt": bson.A{"$redemptions", "$max_redemptions"}}},
+				}},
+			},
+		},
+		bson.M{"$inc": bson.M{"redemptions": 1}, "$set": bson.M{"updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: reserve promo code: %v", err.Error())
+		return errors.New("error: reserve promo code failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: promo code is expired or fully redeemed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) ReleasePromoCode(pctx context.Context, code string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_codes")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"code": code, "redemptions": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"redemptions": -1}},
+	)
+	if err != nil {
+		log.Printf("error: release promo code: %v", err.Error())
+		return errors.New("error: release promo code failed")
+	}
+
+	log.Printf("ReleasePromoCode: %v", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *rewardRepository) InsertOneRedemption(pctx context.Context, req *reward.PromoRedemption) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_redemptions")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: promo code already redeemed")
+		}
+		log.Printf("error: insert one redemption: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one redemption failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *rewardRepository) FindRedemptions(pctx context.Context, filter bson.D) ([]*reward.PromoRedemption, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_redemptions")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(1000))
+	if err != nil {
+		log.Printf("error: find redemptions: %v", err.Error())
+		return nil, errors.New("error: find redemptions failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*reward.PromoRedemption, 0)
+	for cursors.Next(ctx) {
+		result := new(reward.PromoRedemption)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode redemption: %v", err.Error())
+			return nil, errors.New("error: decode redemption failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *rewardRepository) UpdateOneRedemption(pctx context.Context, redemptionId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("promo_redemptions")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(redemptionId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one redemption: %v", err.Error())
+		return errors.New("error: update one redemption failed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Item().FindItemsInIds(ctx, req)
+	if err != nil {
+		log.Printf("error: find items in ids failed: %v", err.Error())
+		return nil, errors.New("error: find items in ids failed")
+	}
+
+	return result, nil
+}
+
+func (r *rewardRepository) AddPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "player", "sell", reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) AddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: marshal request failed: %v", err.Error())
+		return errors.New("error: marshal request failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue([]string{cfg.Kafka.Url}, cfg.Kafka.ApiKey, cfg.Kafka.Secret, "inventory", "buy", reqInBytes); err != nil {
+		log.Printf("Error: push message with key to queue failed: %v", err.Error())
+		return errors.New("error: push message with key to queue failed")
+	}
+
+	return nil
+}
+
+func (r *rewardRepository) GetOffset(pctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_queue")
+
+	result := new(models.KafkaOffset)
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		log.Printf("error: get offset failed: %v", err.Error())
+		return -1, errors.New("error: get offset failed")
+	}
+
+	return result.Offset, nil
+}
+
+func (r *rewardRepository) UpsertOffset(pctx context.Context, offset int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.rewardDbConn(ctx)
+	col := db.Collection("reward_queue")
+
+	result, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"offset": offset}}, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		log.Printf("error: upsert offset failed: %v", err.Error())
+		return errors.New("error: upsert offset failed")
+	}
+
+	log.Printf("info: upsert offset: %v", result.ModifiedCount)
+
+	return nil
+}
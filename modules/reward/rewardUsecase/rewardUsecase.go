@@ -0,0 +1,532 @@
+package rewardUsecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/Supakornn/mmorpg-shop/modules/player"
+	"github.com/Supakornn/mmorpg-shop/modules/reward"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardRepository"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const periodLayout = "2006-01-02"
+
+type (
+	RewardUsecaseService interface {
+		CreateCalendar(pctx context.Context, cfg *config.Config, req *reward.CreateCalendarReq) (*reward.RewardCalendar, error)
+		FindCalendars(pctx context.Context) ([]*reward.RewardCalendar, error)
+		FindCalendarStatus(pctx context.Context, playerId, calendarId string) (*reward.CalendarStatusRes, error)
+		ClaimReward(pctx context.Context, cfg *config.Config, playerId, calendarId string) (*reward.RewardClaim, error)
+		FindClaims(pctx context.Context, playerId string) ([]*reward.RewardClaim, error)
+		CreatePromoCodes(pctx context.Context, cfg *config.Config, req *reward.CreatePromoCodesReq) ([]*reward.PromoCode, error)
+		FindPromoCodes(pctx context.Context, req *reward.PromoSearchReq) ([]*reward.PromoCode, error)
+		RedeemCode(pctx context.Context, cfg *config.Config, playerId string, req *reward.RedeemCodeReq) (*reward.PromoRedemption, error)
+		FindRedemptions(pctx context.Context, req *reward.PromoSearchReq) ([]*reward.PromoRedemption, error)
+		GrantResult(pctx context.Context, key string, res *payment.PaymentTransferRes) error
+		GetOffset(pctx context.Context) (int64, error)
+		UpsertOffset(pctx context.Context, offset int64) error
+	}
+
+	rewardUsecase struct {
+		rewardRepository rewardRepository.RewardRepositoryService
+	}
+)
+
+func NewRewardUsecase(rewardRepository rewardRepository.RewardRepositoryService) RewardUsecaseService {
+	return &rewardUsecase{rewardRepository}
+}
+
+func (u *rewardUsecase) CreateCalendar(pctx context.Context, cfg *config.Config, req *reward.CreateCalendarReq) (*reward.RewardCalendar, error) {
+	if req.Kind == "event" {
+		if req.StartsAt == nil || req.EndsAt == nil || !req.EndsAt.After(*req.StartsAt) {
+			return nil, errors.New("error: event needs starts_at before ends_at")
+		}
+	}
+
+	calendar := &reward.RewardCalendar{
+		Title:       req.Title,
+		Kind:        req.Kind,
+		Days:        make([]*reward.RewardDay, 0),
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		UsageStatus: true,
+		CreatedAt:   utils.LocalTime(),
+		UpdatedAt:   utils.LocalTime(),
+	}
+
+	setIds := make(map[string]bool)
+	for i, v := range req.Days {
+		if v.Money <= 0 && v.ItemId == "" {
+			return nil, errors.New("error: every day needs money or an item")
+		}
+
+		day := &reward.RewardDay{Day: i + 1, Money: v.Money}
+		if v.ItemId != "" {
+			day.ItemId = "item:" + strings.TrimPrefix(v.ItemId, "item:")
+			setIds[day.ItemId] = true
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+
+	if err := u.checkItems(pctx, cfg, setIds); err != nil {
+		return nil, err
+	}
+
+	calendarId, err := u.rewardRepository.InsertOneCalendar(pctx, calendar)
+	if err != nil {
+		return nil, err
+	}
+	calendar.Id = calendarId
+
+	return calendar, nil
+}
+
+func (u *rewardUsecase) checkItems(pctx context.Context, cfg *config.Config, setIds map[string]bool) error {
+	if len(setIds) == 0 {
+		return nil
+	}
+
+	itemIds := make([]string, 0)
+	for itemId := range setIds {
+		itemIds = append(itemIds, itemId)
+	}
+
+	items, err := u.rewardRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: itemIds})
+	if err != nil {
+		return err
+	}
+	if len(items.Items) != len(setIds) {
+		return errors.New("error: reward item not found")
+	}
+
+	return nil
+}
+
+func (u *rewardUsecase) FindCalendars(pctx context.Context) ([]*reward.RewardCalendar, error) {
+	return u.rewardRepository.FindCalendars(pctx, bson.D{{Key: "usage_status", Value: true}})
+}
+
+func (u *rewardUsecase) FindCalendarStatus(pctx context.Context, playerId, calendarId string) (*reward.CalendarStatusRes, error) {
+	calendar, err := u.rewardRepository.FindOneCalendar(pctx, calendarId)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := u.rewardRepository.FindLastClaim(pctx, playerId, calendar.Id.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	res := &reward.CalendarStatusRes{
+		CalendarId: calendar.Id.Hex(),
+		Kind:       calendar.Kind,
+		Period:     now.Format(periodLayout),
+		LastClaim:  last,
+	}
+
+	if last != nil && last.Period == res.Period {
+		res.Claimed = true
+		return res, nil
+	}
+
+	day, err := nextRewardDay(calendar, last, now)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.NextDay = day
+	res.NextReward = calendar.Days[day-1]
+
+	return res, nil
+}
+
+// ClaimReward records today's claim before granting anything, so the unique
+// claim per period holds across retries. Claiming again the same day only
+// retries the grants that failed.
+func (u *rewardUsecase) ClaimReward(pctx context.Context, cfg *config.Config, playerId, calendarId string) (*reward.RewardClaim, error) {
+	calendar, err := u.rewardRepository.FindOneCalendar(pctx, calendarId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !calendar.UsageStatus {
+		return nil, errors.New("error: calendar is not available")
+	}
+
+	last, err := u.rewardRepository.FindLastClaim(pctx, playerId, calendar.Id.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	if last != nil && last.Period == now.Format(periodLayout) {
+		return u.retryClaim(pctx, cfg, last)
+	}
+
+	day, err := nextRewardDay(calendar, last, now)
+	if err != nil {
+		return nil, err
+	}
+	rewardDay := calendar.Days[day-1]
+
+	claim := &reward.RewardClaim{
+		PlayerId:   playerId,
+		CalendarId: calendar.Id.Hex(),
+		Period:     now.Format(periodLayout),
+		Day:        day,
+		Money:      rewardDay.Money,
+		ItemId:     rewardDay.ItemId,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if claim.Money > 0 {
+		claim.MoneyStatus = "pending"
+	}
+	if claim.ItemId != "" {
+		claim.ItemStatus = "pending"
+	}
+
+	claimId, err := u.rewardRepository.InsertOneClaim(pctx, claim)
+	if err != nil {
+		return nil, err
+	}
+	claim.Id = claimId
+
+	u.pushGrants(pctx, cfg, claim, claim.MoneyStatus == "pending", claim.ItemStatus == "pending")
+
+	return claim, nil
+}
+
+func (u *rewardUsecase) retryClaim(pctx context.Context, cfg *config.Config, claim *reward.RewardClaim) (*reward.RewardClaim, error) {
+	if claim.MoneyStatus != "failed" && claim.ItemStatus != "failed" {
+		return nil, errors.New("error: reward already claimed")
+	}
+
+	retryMoney := false
+	if claim.MoneyStatus == "failed" {
+		if err := u.rewardRepository.RetryClaim(pctx, claim.Id.Hex(), "money_status"); err == nil {
+			claim.MoneyStatus = "pending"
+			retryMoney = true
+		}
+	}
+
+	retryItem := false
+	if claim.ItemStatus == "failed" {
+		if err := u.rewardRepository.RetryClaim(pctx, claim.Id.Hex(), "item_status"); err == nil {
+			claim.ItemStatus = "pending"
+			retryItem = true
+		}
+	}
+
+	if !retryMoney && !retryItem {
+		return nil, errors.New("error: reward already claimed")
+	}
+	claim.Error = ""
+
+	u.pushGrants(pctx, cfg, claim, retryMoney, retryItem)
+
+	return claim, nil
+}
+
+func (u *rewardUsecase) pushGrants(pctx context.Context, cfg *config.Config, claim *reward.RewardClaim, money, item bool) {
+	req := bson.M{}
+
+	moneyErr, itemErr := u.grant(pctx, cfg, claim.PlayerId, money, claim.Money, item, claim.ItemId, "reward", claim.Id.Hex())
+	if moneyErr != nil {
+		claim.MoneyStatus = "failed"
+		claim.Error = moneyErr.Error()
+		req["money_status"] = claim.MoneyStatus
+	}
+	if itemErr != nil {
+		claim.ItemStatus = "failed"
+		claim.Error = itemErr.Error()
+		req["item_status"] = claim.ItemStatus
+	}
+
+	if len(req) > 0 {
+		req["error"] = claim.Error
+		u.updateClaim(pctx, claim.Id.Hex(), req)
+	}
+}
+
+// grant queues money and an item to a player. The player and inventory
+// services reply on the reward topic with referenceId.
+func (u *rewardUsecase) grant(pctx context.Context, cfg *config.Config, playerId string, money bool, amount float64, item bool, itemId, source, referenceId string) (moneyErr, itemErr error) {
+	if money {
+		moneyErr = u.rewardRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+			PlayerId:    playerId,
+			Amount:      amount,
+			ReplyTopic:  "reward",
+			ReferenceId: referenceId,
+		})
+	}
+
+	if item {
+		itemErr = u.rewardRepository.AddPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+			PlayerId:    playerId,
+			ItemId:      itemId,
+			Source:      source,
+			ReplyTopic:  "reward",
+			ReferenceId: referenceId,
+		})
+	}
+
+	return moneyErr, itemErr
+}
+
+func (u *rewardUsecase) updateClaim(pctx context.Context, claimId string, req bson.M) {
+	req["updated_at"] = utils.LocalTime()
+	if err := u.rewardRepository.UpdateOneClaim(pctx, claimId, req); err != nil {
+		log.Printf("Error: update claim %s failed: %v", claimId, err.Error())
+	}
+}
+
+func (u *rewardUsecase) FindClaims(pctx context.Context, playerId string) ([]*reward.RewardClaim, error) {
+	return u.rewardRepository.FindClaims(pctx, playerId)
+}
+
+func (u *rewardUsecase) CreatePromoCodes(pctx context.Context, cfg *config.Config, req *reward.CreatePromoCodesReq) ([]*reward.PromoCode, error) {
+	if req.Money <= 0 && req.ItemId == "" {
+		return nil, errors.New("error: promo code needs money or an item")
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(utils.LocalTime()) {
+		return nil, errors.New("error: expires_at must be in the future")
+	}
+
+	codes := make([]string, 0)
+	if req.Code != "" {
+		codes = append(codes, strings.ToUpper(req.Code))
+	} else {
+		if req.Count < 1 {
+			return nil, errors.New("error: count must be at least 1")
+		}
+
+		prefix := ""
+		if req.Prefix != "" {
+			prefix = strings.ToUpper(req.Prefix) + "-"
+		}
+		for range req.Count {
+			codes = append(codes, prefix+rand.Text()[:12])
+		}
+	}
+
+	itemId := ""
+	if req.ItemId != "" {
+		itemId = "item:" + strings.TrimPrefix(req.ItemId, "item:")
+		if err := u.checkItems(pctx, cfg, map[string]bool{itemId: true}); err != nil {
+			return nil, err
+		}
+	}
+
+	promos := make([]*reward.PromoCode, 0)
+	for _, code := range codes {
+		promos = append(promos, &reward.PromoCode{
+			Id:             bson.NewObjectID(),
+			Code:           code,
+			Campaign:       req.Campaign,
+			Money:          req.Money,
+			ItemId:         itemId,
+			MaxRedemptions: req.MaxRedemptions,
+			ExpiresAt:      req.ExpiresAt,
+			UsageStatus:    true,
+			CreatedAt:      utils.LocalTime(),
+			UpdatedAt:      utils.LocalTime(),
+		})
+	}
+
+	if err := u.rewardRepository.InsertManyPromoCodes(pctx, promos); err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+func (u *rewardUsecase) FindPromoCodes(pctx context.Context, req *reward.PromoSearchReq) ([]*reward.PromoCode, error) {
+	filter := bson.D{}
+	if req.Campaign != "" {
+		filter = append(filter, bson.E{Key: "campaign", Value: req.Campaign})
+	}
+	if req.Code != "" {
+		filter = append(filter, bson.E{Key: "code", Value: strings.ToUpper(req.Code)})
+	}
+
+	return u.rewardRepository.FindPromoCodes(pctx, filter)
+}
+
+// RedeemCode counts the redemption against the code before recording it.
+// The unique code and player index makes a second redemption fail, which
+// gives the count back.
+func (u *rewardUsecase) RedeemCode(pctx context.Context, cfg *config.Config, playerId string, req *reward.RedeemCodeReq) (*reward.PromoRedemption, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+
+	promo, err := u.rewardRepository.FindOnePromoCode(pctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !promo.UsageStatus {
+		return nil, errors.New("error: promo code is not available")
+	}
+
+	if err := u.rewardRepository.ReservePromoCode(pctx, code); err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	redemption := &reward.PromoRedemption{
+		Code:      promo.Code,
+		Campaign:  promo.Campaign,
+		PlayerId:  playerId,
+		Money:     promo.Money,
+		ItemId:    promo.ItemId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if redemption.Money > 0 {
+		redemption.MoneyStatus = "pending"
+	}
+	if redemption.ItemId != "" {
+		redemption.ItemStatus = "pending"
+	}
+
+	redemptionId, err := u.rewardRepository.InsertOneRedemption(pctx, redemption)
+	if err != nil {
+		if err := u.rewardRepository.ReleasePromoCode(pctx, code); err != nil {
+			log.Printf("Error: release promo code %s failed: %v", code, err.Error())
+		}
+		return nil, err
+	}
+	redemption.Id = redemptionId
+
+	moneyErr, itemErr := u.grant(pctx, cfg, playerId, redemption.Money > 0, redemption.Money, redemption.ItemId != "", redemption.ItemId, "promo", "redemption:"+redemptionId.Hex())
+
+	update := bson.M{}
+	if moneyErr != nil {
+		redemption.MoneyStatus = "failed"
+		redemption.Error = moneyErr.Error()
+		update["money_status"] = redemption.MoneyStatus
+	}
+	if itemErr != nil {
+		redemption.ItemStatus = "failed"
+		redemption.Error = itemErr.Error()
+		update["item_status"] = redemption.ItemStatus
+	}
+	if len(update) > 0 {
+		update["error"] = redemption.Error
+		update["updated_at"] = utils.LocalTime()
+		if err := u.rewardRepository.UpdateOneRedemption(pctx, redemptionId.Hex(), update); err != nil {
+			log.Printf("Error: update redemption %s failed: %v", redemptionId.Hex(), err.Error())
+		}
+	}
+
+	return redemption, nil
+}
+
+func (u *rewardUsecase) FindRedemptions(pctx context.Context, req *reward.PromoSearchReq) ([]*reward.PromoRedemption, error) {
+	filter := bson.D{}
+	if req.Campaign != "" {
+		filter = append(filter, bson.E{Key: "campaign", Value: req.Campaign})
+	}
+	if req.Code != "" {
+		filter = append(filter, bson.E{Key: "code", Value: strings.ToUpper(req.Code)})
+	}
+	if req.PlayerId != "" {
+		filter = append(filter, bson.E{Key: "player_id", Value: "player:" + strings.TrimPrefix(req.PlayerId, "player:")})
+	}
+
+	return u.rewardRepository.FindRedemptions(pctx, filter)
+}
+
+// GrantResult stores a reply from the player ("sell" key) or inventory
+// ("buy" key) service on the claim or promo redemption it references.
+func (u *rewardUsecase) GrantResult(pctx context.Context, key string, res *payment.PaymentTransferRes) error {
+	if res.ReferenceId == "" {
+		return errors.New("error: reply has no reference id")
+	}
+
+	status := "granted"
+	if res.Error != "" {
+		status = "failed"
+	}
+
+	req := bson.M{}
+	switch key {
+	case "sell":
+		req["money_status"] = status
+		req["transaction_id"] = res.TransactionId
+	case "buy":
+		req["item_status"] = status
+		req["inventory_id"] = res.InventoryId
+	default:
+		return errors.New("error: unknown reply key")
+	}
+	if res.Error != "" {
+		req["error"] = res.Error
+	}
+	req["updated_at"] = utils.LocalTime()
+
+	if redemptionId, ok := strings.CutPrefix(res.ReferenceId, "redemption:"); ok {
+		return u.rewardRepository.UpdateOneRedemption(pctx, redemptionId, req)
+	}
+
+	return u.rewardRepository.UpdateOneClaim(pctx, res.ReferenceId, req)
+}
+
+func (u *rewardUsecase) GetOffset(pctx context.Context) (int64, error) {
+	return u.rewardRepository.GetOffset(pctx)
+}
+
+func (u *rewardUsecase) UpsertOffset(pctx context.Context, offset int64) error {
+	return u.rewardRepository.UpsertOffset(pctx, offset)
+}
+
+// nextRewardDay returns the 1-based day a player claims at now, given their
+// last claim from the calendar.
+func nextRewardDay(calendar *reward.RewardCalendar, last *reward.RewardClaim, now time.Time) (int, error) {
+	days := len(calendar.Days)
+	if days == 0 {
+		return 0, errors.New("error: calendar has no rewards")
+	}
+
+	switch calendar.Kind {
+	case "event":
+		if calendar.StartsAt == nil || calendar.EndsAt == nil || now.Before(*calendar.StartsAt) || !now.Before(*calendar.EndsAt) {
+			return 0, errors.New("error: event is not running")
+		}
+
+		day := daysBetween(*calendar.StartsAt, now) + 1
+		if day > days {
+			return 0, errors.New("error: no reward for today")
+		}
+		return day, nil
+	case "streak":
+		if last != nil && last.Period == now.AddDate(0, 0, -1).Format(periodLayout) {
+			return last.Day%days + 1, nil
+		}
+		return 1, nil
+	default:
+		if last != nil {
+			return last.Day%days + 1, nil
+		}
+		return 1, nil
+	}
+}
+
+func daysBetween(from, to time.Time) int {
+	y1, m1, d1 := from.In(to.Location()).Date()
+	y2, m2, d2 := to.Date()
+
+	return int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
@@ -0,0 +1,58 @@
+package season
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// Season runs from StartsAt to EndsAt and is "active" until the sweeper
+	// moves its passes to the archive and marks it "archived".
+	Season struct {
+		Id           bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title        string        `json:"title" bson:"title"`
+		Tiers        []*SeasonTier `json:"tiers" bson:"tiers"`
+		PremiumPrice float64       `json:"premium_price" bson:"premium_price"`
+		StartsAt     time.Time     `json:"starts_at" bson:"starts_at"`
+		EndsAt       time.Time     `json:"ends_at" bson:"ends_at"`
+		Status       string        `json:"status" bson:"status"`
+		ArchivedAt   *time.Time    `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+		CreatedAt    time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt    time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	// SeasonTier is reached at Xp total season xp.
+	SeasonTier struct {
+		Tier          int    `json:"tier" bson:"tier"`
+		Xp            int64  `json:"xp" bson:"xp"`
+		FreeItemId    string `json:"free_item_id,omitempty" bson:"free_item_id,omitempty"`
+		PremiumItemId string `json:"premium_item_id,omitempty" bson:"premium_item_id,omitempty"`
+	}
+
+	// SeasonPass is a player's progress in one season, unique per season
+	// and player.
+	SeasonPass struct {
+		Id                   bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		SeasonId             string        `json:"season_id" bson:"season_id"`
+		PlayerId             string        `json:"player_id" bson:"player_id"`
+		Xp                   int64         `json:"xp" bson:"xp"`
+		Premium              bool          `json:"premium" bson:"premium"`
+		PremiumTransactionId string        `json:"premium_transaction_id,omitempty" bson:"premium_transaction_id,omitempty"`
+		ClaimedFree          []int         `json:"claimed_free" bson:"claimed_free"`
+		ClaimedPremium       []int         `json:"claimed_premium" bson:"claimed_premium"`
+		ArchivedAt           *time.Time    `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+		CreatedAt            time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt            time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	SeasonXpEvent struct {
+		Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		SeasonId  string        `json:"season_id" bson:"season_id"`
+		PlayerId  string        `json:"player_id" bson:"player_id"`
+		EventId   string        `json:"event_id,omitempty" bson:"event_id,omitempty"`
+		Xp        int64         `json:"xp" bson:"xp"`
+		Source    string        `json:"source,omitempty" bson:"source,omitempty"`
+		CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	}
+)
@@ -0,0 +1,31 @@
+package seasonHandler
+
+import (
+	"context"
+
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonUsecase"
+)
+
+type (
+	seasonGrpcHandler struct {
+		seasonPb.UnimplementedSeasonGrpcServiceServer
+		seasonUsecase seasonUsecase.SeasonUsecaseService
+	}
+)
+
+func NewSeasonGrpcHandler(seasonUsecase seasonUsecase.SeasonUsecaseService) *seasonGrpcHandler {
+	return &seasonGrpcHandler{seasonUsecase: seasonUsecase}
+}
+
+func (g *seasonGrpcHandler) AddSeasonXp(ctx context.Context, req *seasonPb.AddSeasonXpReq) (*seasonPb.SeasonPassRes, error) {
+	return g.seasonUsecase.AddSeasonXp(ctx, req)
+}
+
+func (g *seasonGrpcHandler) FindActiveSeason(ctx context.Context, req *seasonPb.FindActiveSeasonReq) (*seasonPb.SeasonRes, error) {
+	return g.seasonUsecase.FindActiveSeason(ctx)
+}
+
+func (g *seasonGrpcHandler) UnlockPremiumPass(ctx context.Context, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error) {
+	return g.seasonUsecase.UnlockPremiumPass(ctx, req)
+}
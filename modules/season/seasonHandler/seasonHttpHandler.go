@@ -0,0 +1,124 @@
+package seasonHandler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/season"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	SeasonHttpHandlerService interface {
+		CreateSeason(c echo.Context) error
+		FindSeasons(c echo.Context) error
+		FindActiveProgress(c echo.Context) error
+		FindProgress(c echo.Context) error
+		ClaimTier(c echo.Context) error
+	}
+
+	seasonHttpHandler struct {
+		cfg           *config.Config
+		seasonUsecase seasonUsecase.SeasonUsecaseService
+	}
+)
+
+func NewSeasonHttpHandler(cfg *config.Config, seasonUsecase seasonUsecase.SeasonUsecaseService) SeasonHttpHandlerService {
+	return &seasonHttpHandler{cfg, seasonUsecase}
+}
+
+func seasonIdParam(c echo.Context) (string, error) {
+	decodedParam, err := url.QueryUnescape(c.Param("season_id"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(decodedParam, "season:"), nil
+}
+
+func (h *seasonHttpHandler) CreateSeason(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(season.CreateSeasonReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.seasonUsecase.CreateSeason(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *seasonHttpHandler) FindSeasons(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.seasonUsecase.FindSeasons(ctx)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *seasonHttpHandler) FindActiveProgress(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.seasonUsecase.FindProgress(ctx, c.Get("player_id").(string), "")
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *seasonHttpHandler) FindProgress(c echo.Context) error {
+	ctx := context.Background()
+
+	seasonId, err := seasonIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.seasonUsecase.FindProgress(ctx, c.Get("player_id").(string), seasonId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *seasonHttpHandler) ClaimTier(c echo.Context) error {
+	ctx := context.Background()
+
+	seasonId, err := seasonIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(season.ClaimTierReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.seasonUsecase.ClaimTier(ctx, h.cfg, c.Get("player_id").(string), seasonId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
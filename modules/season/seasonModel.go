@@ -0,0 +1,45 @@
+package season
+
+import "time"
+
+type (
+	// CreateSeasonReq lists tiers in order; the xp of each tier must be
+	// higher than the one before.
+	CreateSeasonReq struct {
+		Title        string           `json:"title" validate:"required,max=64"`
+		Tiers        []*SeasonTierReq `json:"tiers" validate:"required,min=1,max=200,dive"`
+		PremiumPrice float64          `json:"premium_price" validate:"gt=0"`
+		StartsAt     time.Time        `json:"starts_at" validate:"required"`
+		EndsAt       time.Time        `json:"ends_at" validate:"required"`
+	}
+
+	SeasonTierReq struct {
+		Xp            int64  `json:"xp" validate:"gt=0"`
+		FreeItemId    string `json:"free_item_id" validate:"max=64"`
+		PremiumItemId string `json:"premium_item_id" validate:"max=64"`
+	}
+
+	ClaimTierReq struct {
+		Tier  int    `json:"tier" validate:"required,min=1"`
+		Track string `json:"track" validate:"required,oneof=free premium"`
+	}
+
+	SeasonProgressRes struct {
+		Season         *Season `json:"season"`
+		Xp             int64   `json:"xp"`
+		Tier           int     `json:"tier"`
+		NextTierXp     int64   `json:"next_tier_xp,omitempty"`
+		Premium        bool    `json:"premium"`
+		ClaimedFree    []int   `json:"claimed_free"`
+		ClaimedPremium []int   `json:"claimed_premium"`
+	}
+
+	SeasonClaimRes struct {
+		SeasonId     string   `json:"season_id"`
+		Tier         int      `json:"tier"`
+		Track        string   `json:"track"`
+		ItemId       string   `json:"item_id"`
+		InventoryIds []string `json:"inventory_ids,omitempty"`
+		MailIds      []string `json:"mail_ids,omitempty"`
+	}
+)
@@ -0,0 +1,434 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: modules/season/seasonPb/seasonPb.proto
+
+package mmorpg_shop
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+//	Structures
+//
+// event_id is optional; an event id already counted in the season is ignored
+type AddSeasonXpReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Xp            int64                  `protobuf:"varint,2,opt,name=xp,proto3" json:"xp,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	EventId       string                 `protobuf:"bytes,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSeasonXpReq) Reset() {
+	*x = AddSeasonXpReq{}
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSeasonXpReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSeasonXpReq) ProtoMessage() {}
+
+func (x *AddSeasonXpReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSeasonXpReq.ProtoReflect.Descriptor instead.
+func (*AddSeasonXpReq) Descriptor() ([]byte, []int) {
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddSeasonXpReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *AddSeasonXpReq) GetXp() int64 {
+	if x != nil {
+		return x.Xp
+	}
+	return 0
+}
+
+func (x *AddSeasonXpReq) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AddSeasonXpReq) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type FindActiveSeasonReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindActiveSeasonReq) Reset() {
+	*x = FindActiveSeasonReq{}
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindActiveSeasonReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindActiveSeasonReq) ProtoMessage() {}
+
+func (x *FindActiveSeasonReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindActiveSeasonReq.ProtoReflect.Descriptor instead.
+func (*FindActiveSeasonReq) Descriptor() ([]byte, []int) {
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP(), []int{1}
+}
+
+type SeasonRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeasonId      string                 `protobuf:"bytes,1,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	PremiumPrice  float64                `protobuf:"fixed64,3,opt,name=premium_price,json=premiumPrice,proto3" json:"premium_price,omitempty"`
+	StartsAt      string                 `protobuf:"bytes,4,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        string                 `protobuf:"bytes,5,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeasonRes) Reset() {
+	*x = SeasonRes{}
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeasonRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeasonRes) ProtoMessage() {}
+
+func (x *SeasonRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeasonRes.ProtoReflect.Descriptor instead.
+func (*SeasonRes) Descriptor() ([]byte, []int) {
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SeasonRes) GetSeasonId() string {
+	if x != nil {
+		return x.SeasonId
+	}
+	return ""
+}
+
+func (x *SeasonRes) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SeasonRes) GetPremiumPrice() float64 {
+	if x != nil {
+		return x.PremiumPrice
+	}
+	return 0
+}
+
+func (x *SeasonRes) GetStartsAt() string {
+	if x != nil {
+		return x.StartsAt
+	}
+	return ""
+}
+
+func (x *SeasonRes) GetEndsAt() string {
+	if x != nil {
+		return x.EndsAt
+	}
+	return ""
+}
+
+type UnlockPremiumPassReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeasonId      string                 `protobuf:"bytes,1,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockPremiumPassReq) Reset() {
+	*x = UnlockPremiumPassReq{}
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockPremiumPassReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockPremiumPassReq) ProtoMessage() {}
+
+func (x *UnlockPremiumPassReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockPremiumPassReq.ProtoReflect.Descriptor instead.
+func (*UnlockPremiumPassReq) Descriptor() ([]byte, []int) {
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UnlockPremiumPassReq) GetSeasonId() string {
+	if x != nil {
+		return x.SeasonId
+	}
+	return ""
+}
+
+func (x *UnlockPremiumPassReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *UnlockPremiumPassReq) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type SeasonPassRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeasonId      string                 `protobuf:"bytes,1,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Xp            int64                  `protobuf:"varint,3,opt,name=xp,proto3" json:"xp,omitempty"`
+	Tier          int32                  `protobuf:"varint,4,opt,name=tier,proto3" json:"tier,omitempty"`
+	Premium       bool                   `protobuf:"varint,5,opt,name=premium,proto3" json:"premium,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeasonPassRes) Reset() {
+	*x = SeasonPassRes{}
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeasonPassRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeasonPassRes) ProtoMessage() {}
+
+func (x *SeasonPassRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_season_seasonPb_seasonPb_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeasonPassRes.ProtoReflect.Descriptor instead.
+func (*SeasonPassRes) Descriptor() ([]byte, []int) {
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SeasonPassRes) GetSeasonId() string {
+	if x != nil {
+		return x.SeasonId
+	}
+	return ""
+}
+
+func (x *SeasonPassRes) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *SeasonPassRes) GetXp() int64 {
+	if x != nil {
+		return x.Xp
+	}
+	return 0
+}
+
+func (x *SeasonPassRes) GetTier() int32 {
+	if x != nil {
+		return x.Tier
+	}
+	return 0
+}
+
+func (x *SeasonPassRes) GetPremium() bool {
+	if x != nil {
+		return x.Premium
+	}
+	return false
+}
+
+var File_modules_season_seasonPb_seasonPb_proto protoreflect.FileDescriptor
+
+const file_modules_season_seasonPb_seasonPb_proto_rawDesc = "" +
+	"\n" +
+	"&modules/season/seasonPb/seasonPb.proto\"p\n" +
+	"\x0eAddSeasonXpReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x0e\n" +
+	"\x02xp\x18\x02 \x01(\x03R\x02xp\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\tR\aeventId\"\x15\n" +
+	"\x13FindActiveSeasonReq\"\x99\x01\n" +
+	"\tSeasonRes\x12\x1b\n" +
+	"\tseason_id\x18\x01 \x01(\tR\bseasonId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12#\n" +
+	"\rpremium_price\x18\x03 \x01(\x01R\fpremiumPrice\x12\x1b\n" +
+	"\tstarts_at\x18\x04 \x01(\tR\bstartsAt\x12\x17\n" +
+	"\aends_at\x18\x05 \x01(\tR\x06endsAt\"w\n" +
+	"\x14UnlockPremiumPassReq\x12\x1b\n" +
+	"\tseason_id\x18\x01 \x01(\tR\bseasonId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"\x87\x01\n" +
+	"\rSeasonPassRes\x12\x1b\n" +
+	"\tseason_id\x18\x01 \x01(\tR\bseasonId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x0e\n" +
+	"\x02xp\x18\x03 \x01(\x03R\x02xp\x12\x12\n" +
+	"\x04tier\x18\x04 \x01(\x05R\x04tier\x12\x18\n" +
+	"\apremium\x18\x05 \x01(\bR\apremium2\xb5\x01\n" +
+	"\x11SeasonGrpcService\x12.\n" +
+	"\vAddSeasonXp\x12\x0f.AddSeasonXpReq\x1a\x0e.SeasonPassRes\x124\n" +
+	"\x10FindActiveSeason\x12\x14.FindActiveSeasonReq\x1a\n" +
+	".SeasonRes\x12:\n" +
+	"\x11UnlockPremiumPass\x12\x15.UnlockPremiumPassReq\x1a\x0e.SeasonPassResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+
+var (
+	file_modules_season_seasonPb_seasonPb_proto_rawDescOnce sync.Once
+	file_modules_season_seasonPb_seasonPb_proto_rawDescData []byte
+)
+
+func file_modules_season_seasonPb_seasonPb_proto_rawDescGZIP() []byte {
+	file_modules_season_seasonPb_seasonPb_proto_rawDescOnce.Do(func() {
+		file_modules_season_seasonPb_seasonPb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_modules_season_seasonPb_seasonPb_proto_rawDesc), len(file_modules_season_seasonPb_seasonPb_proto_rawDesc)))
+	})
+	return file_modules_season_seasonPb_seasonPb_proto_rawDescData
+}
+
+var file_modules_season_seasonPb_seasonPb_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_modules_season_seasonPb_seasonPb_proto_goTypes = []any{
+	(*AddSeasonXpReq)(nil),       // 0: AddSeasonXpReq
+	(*FindActiveSeasonReq)(nil),  // 1: FindActiveSeasonReq
+	(*SeasonRes)(nil),            // 2: SeasonRes
+	(*UnlockPremiumPassReq)(nil), // 3: UnlockPremiumPassReq
+	(*SeasonPassRes)(nil),        // 4: SeasonPassRes
+}
+var file_modules_season_seasonPb_seasonPb_proto_depIdxs = []int32{
+	0, // 0: SeasonGrpcService.AddSeasonXp:input_type -> AddSeasonXpReq
+	1, // 1: SeasonGrpcService.FindActiveSeason:input_type -> FindActiveSeasonReq
+	3, // 2: SeasonGrpcService.UnlockPremiumPass:input_type -> UnlockPremiumPassReq
+	4, // 3: SeasonGrpcService.AddSeasonXp:output_type -> SeasonPassRes
+	2, // 4: SeasonGrpcService.FindActiveSeason:output_type -> SeasonRes
+	4, // 5: SeasonGrpcService.UnlockPremiumPass:output_type -> SeasonPassRes
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_modules_season_seasonPb_seasonPb_proto_init() }
+func file_modules_season_seasonPb_seasonPb_proto_init() {
+	if File_modules_season_seasonPb_seasonPb_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_season_seasonPb_seasonPb_proto_rawDesc), len(file_modules_season_seasonPb_seasonPb_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modules_season_seasonPb_seasonPb_proto_goTypes,
+		DependencyIndexes: file_modules_season_seasonPb_seasonPb_proto_depIdxs,
+		MessageInfos:      file_modules_season_seasonPb_seasonPb_proto_msgTypes,
+	}.Build()
+	File_modules_season_seasonPb_seasonPb_proto = out.File
+	file_modules_season_seasonPb_seasonPb_proto_goTypes = nil
+	file_modules_season_seasonPb_seasonPb_proto_depIdxs = nil
+}
@@ -0,0 +1,203 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: modules/season/seasonPb/seasonPb.proto
+
+package mmorpg_shop
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SeasonGrpcService_AddSeasonXp_FullMethodName       = "/SeasonGrpcService/AddSeasonXp"
+	SeasonGrpcService_FindActiveSeason_FullMethodName  = "/SeasonGrpcService/FindActiveSeason"
+	SeasonGrpcService_UnlockPremiumPass_FullMethodName = "/SeasonGrpcService/UnlockPremiumPass"
+)
+
+// SeasonGrpcServiceClient is the client API for SeasonGrpcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Methods
+type SeasonGrpcServiceClient interface {
+	AddSeasonXp(ctx context.Context, in *AddSeasonXpReq, opts ...grpc.CallOption) (*SeasonPassRes, error)
+	FindActiveSeason(ctx context.Context, in *FindActiveSeasonReq, opts ...grpc.CallOption) (*SeasonRes, error)
+	UnlockPremiumPass(ctx context.Context, in *UnlockPremiumPassReq, opts ...grpc.CallOption) (*SeasonPassRes, error)
+}
+
+type seasonGrpcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSeasonGrpcServiceClient(cc grpc.ClientConnInterface) SeasonGrpcServiceClient {
+	return &seasonGrpcServiceClient{cc}
+}
+
+func (c *seasonGrpcServiceClient) AddSeasonXp(ctx context.Context, in *AddSeasonXpReq, opts ...grpc.CallOption) (*SeasonPassRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeasonPassRes)
+	err := c.cc.Invoke(ctx, SeasonGrpcService_AddSeasonXp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seasonGrpcServiceClient) FindActiveSeason(ctx context.Context, in *FindActiveSeasonReq, opts ...grpc.CallOption) (*SeasonRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeasonRes)
+	err := c.cc.Invoke(ctx, SeasonGrpcService_FindActiveSeason_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seasonGrpcServiceClient) UnlockPremiumPass(ctx context.Context, in *UnlockPremiumPassReq, opts ...grpc.CallOption) (*SeasonPassRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeasonPassRes)
+	err := c.cc.Invoke(ctx, SeasonGrpcService_UnlockPremiumPass_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SeasonGrpcServiceServer is the server API for SeasonGrpcService service.
+// All implementations must embed UnimplementedSeasonGrpcServiceServer
+// for forward compatibility.
+//
+// Methods
+type SeasonGrpcServiceServer interface {
+	AddSeasonXp(context.Context, *AddSeasonXpReq) (*SeasonPassRes, error)
+	FindActiveSeason(context.Context, *FindActiveSeasonReq) (*SeasonRes, error)
+	UnlockPremiumPass(context.Context, *UnlockPremiumPassReq) (*SeasonPassRes, error)
+	mustEmbedUnimplementedSeasonGrpcServiceServer()
+}
+
+// UnimplementedSeasonGrpcServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSeasonGrpcServiceServer struct{}
+
+func (UnimplementedSeasonGrpcServiceServer) AddSeasonXp(context.Context, *AddSeasonXpReq) (*SeasonPassRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSeasonXp not implemented")
+}
+func (UnimplementedSeasonGrpcServiceServer) FindActiveSeason(context.Context, *FindActiveSeasonReq) (*SeasonRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindActiveSeason not implemented")
+}
+func (UnimplementedSeasonGrpcServiceServer) UnlockPremiumPass(context.Context, *UnlockPremiumPassReq) (*SeasonPassRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockPremiumPass not implemented")
+}
+func (UnimplementedSeasonGrpcServiceServer) mustEmbedUnimplementedSeasonGrpcServiceServer() {}
+func (UnimplementedSeasonGrpcServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeSeasonGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SeasonGrpcServiceServer will
+// result in compilation errors.
+type UnsafeSeasonGrpcServiceServer interface {
+	mustEmbedUnimplementedSeasonGrpcServiceServer()
+}
+
+func RegisterSeasonGrpcServiceServer(s grpc.ServiceRegistrar, srv SeasonGrpcServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSeasonGrpcServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SeasonGrpcService_ServiceDesc, srv)
+}
+
+func _SeasonGrpcService_AddSeasonXp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSeasonXpReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeasonGrpcServiceServer).AddSeasonXp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SeasonGrpcService_AddSeasonXp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeasonGrpcServiceServer).AddSeasonXp(ctx, req.(*AddSeasonXpReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SeasonGrpcService_FindActiveSeason_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindActiveSeasonReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeasonGrpcServiceServer).FindActiveSeason(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SeasonGrpcService_FindActiveSeason_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeasonGrpcServiceServer).FindActiveSeason(ctx, req.(*FindActiveSeasonReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SeasonGrpcService_UnlockPremiumPass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockPremiumPassReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeasonGrpcServiceServer).UnlockPremiumPass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SeasonGrpcService_UnlockPremiumPass_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeasonGrpcServiceServer).UnlockPremiumPass(ctx, req.(*UnlockPremiumPassReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SeasonGrpcService_ServiceDesc is the grpc.ServiceDesc for SeasonGrpcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SeasonGrpcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "SeasonGrpcService",
+	HandlerType: (*SeasonGrpcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddSeasonXp",
+			Handler:    _SeasonGrpcService_AddSeasonXp_Handler,
+		},
+		{
+			MethodName: "FindActiveSeason",
+			Handler:    _SeasonGrpcService_FindActiveSeason_Handler,
+		},
+		{
+			MethodName: "UnlockPremiumPass",
+			Handler:    _SeasonGrpcService_UnlockPremiumPass_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modules/season/seasonPb/seasonPb.proto",
+}
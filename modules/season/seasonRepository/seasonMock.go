@@ -0,0 +1,94 @@
+package seasonRepository
+
+import (
+	"context"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SeasonRepositoryMock struct {
+	mock.Mock
+}
+
+func NewSeasonRepositoryMock() SeasonRepositoryService {
+	return &SeasonRepositoryMock{}
+}
+
+func (m *SeasonRepositoryMock) InsertOneSeason(pctx context.Context, req *season.Season) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) FindOneSeason(pctx context.Context, seasonId string) (*season.Season, error) {
+	args := m.Called(pctx, seasonId)
+	return args.Get(0).(*season.Season), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) FindSeasons(pctx context.Context, filter bson.D) ([]*season.Season, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*season.Season), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) FindActiveSeason(pctx context.Context) (*season.Season, error) {
+	args := m.Called(pctx)
+	return args.Get(0).(*season.Season), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) UpdateOneSeason(pctx context.Context, seasonId string, req bson.M) error {
+	args := m.Called(pctx, seasonId, req)
+	return args.Error(0)
+}
+
+func (m *SeasonRepositoryMock) InsertOneXpEvent(pctx context.Context, req *season.SeasonXpEvent) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) DeleteOneXpEvent(pctx context.Context, eventId bson.ObjectID) error {
+	args := m.Called(pctx, eventId)
+	return args.Error(0)
+}
+
+func (m *SeasonRepositoryMock) AddPassXp(pctx context.Context, seasonId, playerId string, xp int64) (*season.SeasonPass, error) {
+	args := m.Called(pctx, seasonId, playerId, xp)
+	return args.Get(0).(*season.SeasonPass), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) UnlockPremiumPass(pctx context.Context, seasonId, playerId, transactionId string) (*season.SeasonPass, error) {
+	args := m.Called(pctx, seasonId, playerId, transactionId)
+	return args.Get(0).(*season.SeasonPass), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) FindOnePass(pctx context.Context, seasonId, playerId string) (*season.SeasonPass, error) {
+	args := m.Called(pctx, seasonId, playerId)
+	return args.Get(0).(*season.SeasonPass), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) ReserveTierClaim(pctx context.Context, seasonId, playerId, track string, tier int, xp int64) error {
+	args := m.Called(pctx, seasonId, playerId, track, tier, xp)
+	return args.Error(0)
+}
+
+func (m *SeasonRepositoryMock) ReleaseTierClaim(pctx context.Context, seasonId, playerId, track string, tier int) error {
+	args := m.Called(pctx, seasonId, playerId, track, tier)
+	return args.Error(0)
+}
+
+func (m *SeasonRepositoryMock) ArchiveSeasonPasses(pctx context.Context, seasonId string) error {
+	args := m.Called(pctx, seasonId)
+	return args.Error(0)
+}
+
+func (m *SeasonRepositoryMock) FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*itemPb.FindItemsInIdsRes), args.Error(1)
+}
+
+func (m *SeasonRepositoryMock) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchGrantRes), args.Error(1)
+}
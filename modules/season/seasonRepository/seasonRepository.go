@@ -0,0 +1,407 @@
+package seasonRepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	SeasonRepositoryService interface {
+		InsertOneSeason(pctx context.Context, req *season.Season) (bson.ObjectID, error)
+		FindOneSeason(pctx context.Context, seasonId string) (*season.Season, error)
+		FindSeasons(pctx context.Context, filter bson.D) ([]*season.Season, error)
+		FindActiveSeason(pctx context.Context) (*season.Season, error)
+		UpdateOneSeason(pctx context.Context, seasonId string, req bson.M) error
+		InsertOneXpEvent(pctx context.Context, req *season.SeasonXpEvent) (bson.ObjectID, error)
+		DeleteOneXpEvent(pctx context.Context, eventId bson.ObjectID) error
+		AddPassXp(pctx context.Context, seasonId, playerId string, xp int64) (*season.SeasonPass, error)
+		UnlockPremiumPass(pctx context.Context, seasonId, playerId, transactionId string) (*season.SeasonPass, error)
+		FindOnePass(pctx context.Context, seasonId, playerId string) (*season.SeasonPass, error)
+		ReserveTierClaim(pctx context.Context, seasonId, playerId, track string, tier int, xp int64) error
+		ReleaseTierClaim(pctx context.Context, seasonId, playerId, track string, tier int) error
+		ArchiveSeasonPasses(pctx context.Context, seasonId string) error
+		FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error)
+	}
+
+	seasonRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewSeasonRepository(db *mongo.Client) SeasonRepositoryService {
+	return &seasonRepository{db}
+}
+
+func (r *seasonRepository) seasonDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("season_db")
+}
+
+func (r *seasonRepository) InsertOneSeason(pctx context.Context, req *season.Season) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("seasons")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one season: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one season failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *seasonRepository) FindOneSeason(pctx context.Context, seasonId string) (*season.Season, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("seasons")
+
+	result := new(season.Season)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(seasonId)}).Decode(result); err != nil {
+		log.Printf("error: find one season: %v", err.Error())
+		return nil, errors.New("error: season not found")
+	}
+
+	return result, nil
+}
+
+func (r *seasonRepository) FindSeasons(pctx context.Context, filter bson.D) ([]*season.Season, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("seasons")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "starts_at", Value: -1}}))
+	if err != nil {
+		log.Printf("error: find seasons: %v", err.Error())
+		return nil, errors.New("error: find seasons failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*season.Season, 0)
+	for cursors.Next(ctx) {
+		result := new(season.Season)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode season: %v", err.Error())
+			return nil, errors.New("error: decode season failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *seasonRepository) FindActiveSeason(pctx context.Context) (*season.Season, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("seasons")
+
+	now := utils.LocalTime()
+
+	result := new(season.Season)
+	if err := col.FindOne(ctx, bson.M{
+		"status":    "active",
+		"starts_at": bson.M{"$lte": now},
+		"ends_at":   bson.M{"$gt": now},
+	}).Decode(result); err != nil {
+		log.Printf("error: find active season: %v", err.Error())
+		return nil, errors.New("error: no active season")
+	}
+
+	return result, nil
+}
+
+func (r *seasonRepository) UpdateOneSeason(pctx context.Context, seasonId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("seasons")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(seasonId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one season: %v", err.Error())
+		return errors.New("error: update one season failed")
+	}
+
+	return nil
+}
+
+func (r *seasonRepository) InsertOneXpEvent(pctx context.Context, req *season.SeasonXpEvent) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_xp_events")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: xp event already counted")
+		}
+		log.Printf("error: insert one xp event: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one xp event failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *seasonRepository) DeleteOneXpEvent(pctx context.Context, eventId bson.ObjectID) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_xp_events")
+
+	if _, err := col.DeleteOne(ctx, bson.M{"_id": eventId}); err != nil {
+		log.Printf("error: delete one xp event: %v", err.Error())
+		return errors.New("error: delete one xp event failed")
+	}
+
+	return nil
+}
+
+// AddPassXp creates the pass on the player's first xp of the season.
+func (r *seasonRepository) AddPassXp(pctx context.Context, seasonId, playerId string, xp int64) (*season.SeasonPass, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_passes")
+
+	result := new(season.SeasonPass)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"season_id": seasonId, "player_id": playerId},
+		bson.M{
+			"$inc": bson.M{"xp": xp},
+			"$set": bson.M{"updated_at": utils.LocalTime()},
+			"$setOnInsert": bson.M{
+				"premium":         false,
+				"claimed_free":    bson.A{},
+				"claimed_premium": bson.A{},
+				"created_at":      utils.LocalTime(),
+			},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		log.Printf("error: add pass xp: %v", err.Error())
+		return nil, errors.New("error: add pass xp failed")
+	}
+
+	return result, nil
+}
+
+func (r *seasonRepository) UnlockPremiumPass(pctx context.Context, seasonId, playerId, transactionId string) (*season.SeasonPass, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_passes")
+
+	// A pass that is already premium misses the filter and the upsert
+	// collides with the unique (season_id, player_id) index.
+	result := new(season.SeasonPass)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"season_id": seasonId, "player_id": playerId, "premium": bson.M{"$ne": true}},
+		bson.M{
+			"$set": bson.M{
+				"premium":                true,
+				"premium_transaction_id": transactionId,
+				"updated_at":             utils.LocalTime(),
+			},
+			"$setOnInsert": bson.M{
+				"xp":              int64(0),
+				"claimed_free":    bson.A{},
+				"claimed_premium": bson.A{},
+				"created_at":      utils.LocalTime(),
+			},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("error: premium pass already owned")
+		}
+		log.Printf("error: unlock premium pass: %v", err.Error())
+		return nil, errors.New("error: unlock premium pass failed")
+	}
+
+	return result, nil
+}
+
+// FindOnePass looks in the archive once the season has been archived and
+// returns nil when the player has no pass.
+func (r *seasonRepository) FindOnePass(pctx context.Context, seasonId, playerId string) (*season.SeasonPass, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+
+	for _, name := range []string{"season_passes", "season_passes_archive"} {
+		result := new(season.SeasonPass)
+		err := db.Collection(name).FindOne(ctx, bson.M{"season_id": seasonId, "player_id": playerId}).Decode(result)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("error: find one pass: %v", err.Error())
+			return nil, errors.New("error: find one pass failed")
+		}
+	}
+
+	return nil, nil
+}
+
+// ReserveTierClaim marks a tier of a track as claimed in one update, so
+// concurrent claims of the same tier grant it once.
+func (r *seasonRepository) ReserveTierClaim(pctx context.Context, seasonId, playerId, track string, tier int, xp int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_passes")
+
+	field := "claimed_" + track
+	filter := bson.M{
+		"season_id": seasonId,
+		"player_id": playerId,
+		"xp":        bson.M{"$gte": xp},
+		field:       bson.M{"$ne": tier},
+	}
+	if track == "premium" {
+		filter["premium"] = true
+	}
+
+	result, err := col.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{field: tier},
+		"$set":  bson.M{"updated_at": utils.LocalTime()},
+	})
+	if err != nil {
+		log.Printf("error: reserve tier claim: %v", err.Error())
+		return errors.New("error: reserve tier claim failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: tier reward is not claimable")
+	}
+
+	return nil
+}
+
+func (r *seasonRepository) ReleaseTierClaim(pctx context.Context, seasonId, playerId, track string, tier int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+	col := db.Collection("season_passes")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"season_id": seasonId, "player_id": playerId},
+		bson.M{"$pull": bson.M{"claimed_" + track: tier}},
+	)
+	if err != nil {
+		log.Printf("error: release tier claim: %v", err.Error())
+		return errors.New("error: release tier claim failed")
+	}
+
+	log.Printf("ReleaseTierClaim: %v", result.ModifiedCount)
+
+	return nil
+}
+
+// ArchiveSeasonPasses copies the passes of a season to the archive before
+// removing them with the season's xp events. Running it again after a
+// partial run is safe.
+func (r *seasonRepository) ArchiveSeasonPasses(pctx context.Context, seasonId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 5*time.Minute)
+	defer cancel()
+
+	db := r.seasonDbConn(ctx)
+
+	cursors, err := db.Collection("season_passes").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"season_id": seasonId}}},
+		{{Key: "$set", Value: bson.M{"archived_at": utils.LocalTime()}}},
+		{{Key: "$merge", Value: bson.M{"into": "season_passes_archive", "on": "_id", "whenMatched": "replace"}}},
+	})
+	if err != nil {
+		log.Printf("error: archive season passes: %v", err.Error())
+		return errors.New("error: archive season passes failed")
+	}
+	cursors.Close(ctx)
+
+	if _, err := db.Collection("season_passes").DeleteMany(ctx, bson.M{"season_id": seasonId}); err != nil {
+		log.Printf("error: delete archived season passes: %v", err.Error())
+		return errors.New("error: delete archived season passes failed")
+	}
+
+	if _, err := db.Collection("season_xp_events").DeleteMany(ctx, bson.M{"season_id": seasonId}); err != nil {
+		log.Printf("error: delete season xp events: %v", err.Error())
+		return errors.New("error: delete season xp events failed")
+	}
+
+	return nil
+}
+
+func (r *seasonRepository) FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Item().FindItemsInIds(ctx, req)
+	if err != nil {
+		log.Printf("error: find items in ids failed: %v", err.Error())
+		return nil, errors.New("error: find items in ids failed")
+	}
+
+	return result, nil
+}
+
+func (r *seasonRepository) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchGrant(ctx, req)
+	if err != nil {
+		log.Printf("error: batch grant failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
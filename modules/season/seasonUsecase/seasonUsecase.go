@@ -0,0 +1,360 @@
+package seasonUsecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonRepository"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	SeasonUsecaseService interface {
+		CreateSeason(pctx context.Context, cfg *config.Config, req *season.CreateSeasonReq) (*season.Season, error)
+		FindSeasons(pctx context.Context) ([]*season.Season, error)
+		FindProgress(pctx context.Context, playerId, seasonId string) (*season.SeasonProgressRes, error)
+		ClaimTier(pctx context.Context, cfg *config.Config, playerId, seasonId string, req *season.ClaimTierReq) (*season.SeasonClaimRes, error)
+		AddSeasonXp(pctx context.Context, req *seasonPb.AddSeasonXpReq) (*seasonPb.SeasonPassRes, error)
+		FindActiveSeason(pctx context.Context) (*seasonPb.SeasonRes, error)
+		UnlockPremiumPass(pctx context.Context, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error)
+		ArchiveEndedSeasons(pctx context.Context) error
+	}
+
+	seasonUsecase struct {
+		seasonRepository seasonRepository.SeasonRepositoryService
+	}
+)
+
+func NewSeasonUsecase(seasonRepository seasonRepository.SeasonRepositoryService) SeasonUsecaseService {
+	return &seasonUsecase{seasonRepository}
+}
+
+func (u *seasonUsecase) CreateSeason(pctx context.Context, cfg *config.Config, req *season.CreateSeasonReq) (*season.Season, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("error: ends_at must be after starts_at")
+	}
+
+	overlaps, err := u.seasonRepository.FindSeasons(pctx, bson.D{
+		{Key: "status", Value: "active"},
+		{Key: "starts_at", Value: bson.M{"$lt": req.EndsAt}},
+		{Key: "ends_at", Value: bson.M{"$gt": req.StartsAt}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(overlaps) > 0 {
+		return nil, errors.New("error: season overlaps another season")
+	}
+
+	result := &season.Season{
+		Title:        req.Title,
+		Tiers:        make([]*season.SeasonTier, 0),
+		PremiumPrice: req.PremiumPrice,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Status:       "active",
+		CreatedAt:    utils.LocalTime(),
+		UpdatedAt:    utils.LocalTime(),
+	}
+
+	setIds := make(map[string]bool)
+	normalize := func(itemId string) string {
+		if itemId == "" {
+			return ""
+		}
+		itemId = "item:" + strings.TrimPrefix(itemId, "item:")
+		setIds[itemId] = true
+		return itemId
+	}
+
+	for i, v := range req.Tiers {
+		if i > 0 && v.Xp <= req.Tiers[i-1].Xp {
+			return nil, errors.New("error: tier xp must increase")
+		}
+
+		result.Tiers = append(result.Tiers, &season.SeasonTier{
+			Tier:          i + 1,
+			Xp:            v.Xp,
+			FreeItemId:    normalize(v.FreeItemId),
+			PremiumItemId: normalize(v.PremiumItemId),
+		})
+	}
+
+	if len(setIds) > 0 {
+		itemIds := make([]string, 0)
+		for itemId := range setIds {
+			itemIds = append(itemIds, itemId)
+		}
+
+		items, err := u.seasonRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: itemIds})
+		if err != nil {
+			return nil, err
+		}
+		if len(items.Items) != len(setIds) {
+			return nil, errors.New("error: tier item not found")
+		}
+	}
+
+	seasonId, err := u.seasonRepository.InsertOneSeason(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = seasonId
+
+	return result, nil
+}
+
+func (u *seasonUsecase) FindSeasons(pctx context.Context) ([]*season.Season, error) {
+	return u.seasonRepository.FindSeasons(pctx, bson.D{})
+}
+
+// FindProgress shows the player's pass in the active season when seasonId
+// is empty.
+func (u *seasonUsecase) FindProgress(pctx context.Context, playerId, seasonId string) (*season.SeasonProgressRes, error) {
+	var result *season.Season
+	var err error
+	if seasonId == "" {
+		result, err = u.seasonRepository.FindActiveSeason(pctx)
+	} else {
+		result, err = u.seasonRepository.FindOneSeason(pctx, seasonId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pass, err := u.seasonRepository.FindOnePass(pctx, result.Id.Hex(), playerId)
+	if err != nil {
+		return nil, err
+	}
+	if pass == nil {
+		pass = &season.SeasonPass{ClaimedFree: make([]int, 0), ClaimedPremium: make([]int, 0)}
+	}
+
+	tier := tierForXp(result, pass.Xp)
+	res := &season.SeasonProgressRes{
+		Season:         result,
+		Xp:             pass.Xp,
+		Tier:           tier,
+		Premium:        pass.Premium,
+		ClaimedFree:    pass.ClaimedFree,
+		ClaimedPremium: pass.ClaimedPremium,
+	}
+	if tier < len(result.Tiers) {
+		res.NextTierXp = result.Tiers[tier].Xp
+	}
+
+	return res, nil
+}
+
+// ClaimTier marks the tier claimed before granting its item and clears the
+// mark again when the grant fails.
+func (u *seasonUsecase) ClaimTier(pctx context.Context, cfg *config.Config, playerId, seasonId string, req *season.ClaimTierReq) (*season.SeasonClaimRes, error) {
+	result, err := u.seasonRepository.FindOneSeason(pctx, seasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRunning(result, utils.LocalTime()) {
+		return nil, errors.New("error: season is not running")
+	}
+
+	if req.Tier > len(result.Tiers) {
+		return nil, errors.New("error: tier not found")
+	}
+	tier := result.Tiers[req.Tier-1]
+
+	itemId := tier.FreeItemId
+	if req.Track == "premium" {
+		itemId = tier.PremiumItemId
+	}
+	if itemId == "" {
+		return nil, errors.New("error: tier has no reward on this track")
+	}
+
+	pass, err := u.seasonRepository.FindOnePass(pctx, result.Id.Hex(), playerId)
+	if err != nil {
+		return nil, err
+	}
+	if pass == nil || pass.Xp < tier.Xp {
+		return nil, errors.New("error: tier not reached")
+	}
+	if req.Track == "premium" && !pass.Premium {
+		return nil, errors.New("error: premium pass required")
+	}
+
+	if err := u.seasonRepository.ReserveTierClaim(pctx, result.Id.Hex(), playerId, req.Track, tier.Tier, tier.Xp); err != nil {
+		return nil, err
+	}
+
+	granted, err := u.seasonRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+		Items:             []*inventoryPb.GrantItem{{PlayerId: playerId, ItemId: itemId, Quantity: 1}},
+		Source:            "season",
+		OverflowToMailbox: true,
+	})
+	if err != nil {
+		if err := u.seasonRepository.ReleaseTierClaim(pctx, result.Id.Hex(), playerId, req.Track, tier.Tier); err != nil {
+			log.Printf("Error: release tier claim failed: %v", err.Error())
+		}
+		return nil, err
+	}
+
+	res := &season.SeasonClaimRes{
+		SeasonId: result.Id.Hex(),
+		Tier:     tier.Tier,
+		Track:    req.Track,
+		ItemId:   itemId,
+	}
+	for _, v := range granted.Items {
+		res.InventoryIds = append(res.InventoryIds, v.InventoryId)
+	}
+	for _, v := range granted.Mailed {
+		res.MailIds = append(res.MailIds, v.MailId)
+	}
+
+	return res, nil
+}
+
+// AddSeasonXp is called by game servers. A repeated event id is not counted
+// again and returns the pass as it is.
+func (u *seasonUsecase) AddSeasonXp(pctx context.Context, req *seasonPb.AddSeasonXpReq) (*seasonPb.SeasonPassRes, error) {
+	if req.Xp <= 0 {
+		return nil, errors.New("error: xp must be positive")
+	}
+
+	result, err := u.seasonRepository.FindActiveSeason(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	playerId := "player:" + strings.TrimPrefix(req.PlayerId, "player:")
+
+	eventId, err := u.seasonRepository.InsertOneXpEvent(pctx, &season.SeasonXpEvent{
+		SeasonId:  result.Id.Hex(),
+		PlayerId:  playerId,
+		EventId:   req.EventId,
+		Xp:        req.Xp,
+		Source:    req.Source,
+		CreatedAt: utils.LocalTime(),
+	})
+	if err != nil {
+		if req.EventId == "" {
+			return nil, err
+		}
+
+		pass, findErr := u.seasonRepository.FindOnePass(pctx, result.Id.Hex(), playerId)
+		if findErr != nil || pass == nil {
+			return nil, err
+		}
+		return passRes(result, pass), nil
+	}
+
+	pass, err := u.seasonRepository.AddPassXp(pctx, result.Id.Hex(), playerId, req.Xp)
+	if err != nil {
+		if err := u.seasonRepository.DeleteOneXpEvent(pctx, eventId); err != nil {
+			log.Printf("Error: delete xp event failed: %v", err.Error())
+		}
+		return nil, err
+	}
+
+	return passRes(result, pass), nil
+}
+
+func (u *seasonUsecase) FindActiveSeason(pctx context.Context) (*seasonPb.SeasonRes, error) {
+	result, err := u.seasonRepository.FindActiveSeason(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seasonPb.SeasonRes{
+		SeasonId:     result.Id.Hex(),
+		Title:        result.Title,
+		PremiumPrice: result.PremiumPrice,
+		StartsAt:     result.StartsAt.Format(time.RFC3339),
+		EndsAt:       result.EndsAt.Format(time.RFC3339),
+	}, nil
+}
+
+// UnlockPremiumPass is called by the payment service once the pass has been
+// paid for.
+func (u *seasonUsecase) UnlockPremiumPass(pctx context.Context, req *seasonPb.UnlockPremiumPassReq) (*seasonPb.SeasonPassRes, error) {
+	result, err := u.seasonRepository.FindOneSeason(pctx, req.SeasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRunning(result, utils.LocalTime()) {
+		return nil, errors.New("error: season is not running")
+	}
+
+	pass, err := u.seasonRepository.UnlockPremiumPass(pctx, result.Id.Hex(), req.PlayerId, req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return passRes(result, pass), nil
+}
+
+// ArchiveEndedSeasons moves the passes of every ended season to the archive
+// and marks the season archived.
+func (u *seasonUsecase) ArchiveEndedSeasons(pctx context.Context) error {
+	seasons, err := u.seasonRepository.FindSeasons(pctx, bson.D{
+		{Key: "status", Value: "active"},
+		{Key: "ends_at", Value: bson.M{"$lte": utils.LocalTime()}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range seasons {
+		if err := u.seasonRepository.ArchiveSeasonPasses(pctx, v.Id.Hex()); err != nil {
+			log.Printf("Error: archive season %s failed: %v", v.Id.Hex(), err.Error())
+			continue
+		}
+
+		if err := u.seasonRepository.UpdateOneSeason(pctx, v.Id.Hex(), bson.M{
+			"status":      "archived",
+			"archived_at": utils.LocalTime(),
+			"updated_at":  utils.LocalTime(),
+		}); err != nil {
+			log.Printf("Error: update season %s failed: %v", v.Id.Hex(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+func isRunning(result *season.Season, now time.Time) bool {
+	return result.Status == "active" && !now.Before(result.StartsAt) && now.Before(result.EndsAt)
+}
+
+// tierForXp returns the highest tier reached with xp, 0 before the first.
+func tierForXp(result *season.Season, xp int64) int {
+	tier := 0
+	for _, v := range result.Tiers {
+		if xp < v.Xp {
+			break
+		}
+		tier = v.Tier
+	}
+
+	return tier
+}
+
+func passRes(result *season.Season, pass *season.SeasonPass) *seasonPb.SeasonPassRes {
+	return &seasonPb.SeasonPassRes{
+		SeasonId: result.Id.Hex(),
+		PlayerId: pass.PlayerId,
+		Xp:       pass.Xp,
+		Tier:     int32(tierForXp(result, pass.Xp)),
+		Premium:  pass.Premium,
+	}
+}
@@ -0,0 +1,64 @@
+package subscription
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// SubscriptionPlan is charged every PeriodDays. A lapsed renewal keeps
+	// the benefits for GraceDays before the subscription expires.
+	SubscriptionPlan struct {
+		Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title         string        `json:"title" bson:"title"`
+		Price         float64       `json:"price" bson:"price"`
+		PeriodDays    int           `json:"period_days" bson:"period_days"`
+		GraceDays     int           `json:"grace_days" bson:"grace_days"`
+		DailyMoney    float64       `json:"daily_money" bson:"daily_money"`
+		SellBonusRate float64       `json:"sell_bonus_rate" bson:"sell_bonus_rate"`
+		DiscountRate  float64       `json:"discount_rate" bson:"discount_rate"`
+		UsageStatus   bool          `json:"usage_status" bson:"usage_status"`
+		CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt     time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Subscription is "pending" until the first charge, then "active",
+	// "grace" after a failed renewal and finally "cancelled" or "expired".
+	// Live is set while it is pending, active or in grace, and only one live
+	// subscription is allowed per player.
+	Subscription struct {
+		Id                 bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId           string        `json:"player_id" bson:"player_id"`
+		PlanId             string        `json:"plan_id" bson:"plan_id"`
+		Method             string        `json:"method" bson:"method"`
+		Status             string        `json:"status" bson:"status"`
+		Live               bool          `json:"live" bson:"live"`
+		CurrentPeriodStart time.Time     `json:"current_period_start" bson:"current_period_start"`
+		CurrentPeriodEnd   time.Time     `json:"current_period_end" bson:"current_period_end"`
+		NextRenewalAt      time.Time     `json:"next_renewal_at" bson:"next_renewal_at"`
+		GraceUntil         *time.Time    `json:"grace_until,omitempty" bson:"grace_until,omitempty"`
+		CancelAtPeriodEnd  bool          `json:"cancel_at_period_end" bson:"cancel_at_period_end"`
+		CancelledAt        *time.Time    `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+		LastDailyGrant     string        `json:"last_daily_grant,omitempty" bson:"last_daily_grant,omitempty"`
+		ProviderRef        string        `json:"provider_ref,omitempty" bson:"provider_ref,omitempty"`
+		CreatedAt          time.Time     `json:"created_at" bson:"created_at"`
+		UpdatedAt          time.Time     `json:"updated_at" bson:"updated_at"`
+	}
+
+	// SubscriptionCharge records every charge and renewal attempt.
+	SubscriptionCharge struct {
+		Id             bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+		SubscriptionId string        `json:"subscription_id" bson:"subscription_id"`
+		PlayerId       string        `json:"player_id" bson:"player_id"`
+		Method         string        `json:"method" bson:"method"`
+		Amount         float64       `json:"amount" bson:"amount"`
+		TransactionId  string        `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		ProviderRef    string        `json:"provider_ref,omitempty" bson:"provider_ref,omitempty"`
+		PeriodStart    time.Time     `json:"period_start" bson:"period_start"`
+		PeriodEnd      time.Time     `json:"period_end" bson:"period_end"`
+		Status         string        `json:"status" bson:"status"`
+		Error          string        `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt      time.Time     `json:"created_at" bson:"created_at"`
+	}
+)
@@ -0,0 +1,23 @@
+package subscriptionHandler
+
+import (
+	"context"
+
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionUsecase"
+)
+
+type (
+	subscriptionGrpcHandler struct {
+		subscriptionPb.UnimplementedSubscriptionGrpcServiceServer
+		subscriptionUsecase subscriptionUsecase.SubscriptionUsecaseService
+	}
+)
+
+func NewSubscriptionGrpcHandler(subscriptionUsecase subscriptionUsecase.SubscriptionUsecaseService) *subscriptionGrpcHandler {
+	return &subscriptionGrpcHandler{subscriptionUsecase: subscriptionUsecase}
+}
+
+func (g *subscriptionGrpcHandler) FindEntitlements(ctx context.Context, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	return g.subscriptionUsecase.FindEntitlements(ctx, req)
+}
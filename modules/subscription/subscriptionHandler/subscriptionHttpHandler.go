@@ -0,0 +1,155 @@
+package subscriptionHandler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	SubscriptionHttpHandlerService interface {
+		CreatePlan(c echo.Context) error
+		FindPlans(c echo.Context) error
+		Subscribe(c echo.Context) error
+		FindMySubscription(c echo.Context) error
+		CancelSubscription(c echo.Context) error
+		FindCharges(c echo.Context) error
+		ProviderSubscribe(c echo.Context) error
+		ProviderRenew(c echo.Context) error
+	}
+
+	subscriptionHttpHandler struct {
+		cfg                 *config.Config
+		subscriptionUsecase subscriptionUsecase.SubscriptionUsecaseService
+	}
+)
+
+func NewSubscriptionHttpHandler(cfg *config.Config, subscriptionUsecase subscriptionUsecase.SubscriptionUsecaseService) SubscriptionHttpHandlerService {
+	return &subscriptionHttpHandler{cfg, subscriptionUsecase}
+}
+
+func (h *subscriptionHttpHandler) CreatePlan(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(subscription.CreatePlanReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.subscriptionUsecase.CreatePlan(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *subscriptionHttpHandler) FindPlans(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.subscriptionUsecase.FindPlans(ctx)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *subscriptionHttpHandler) Subscribe(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(subscription.SubscribeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.subscriptionUsecase.Subscribe(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *subscriptionHttpHandler) FindMySubscription(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.subscriptionUsecase.FindMySubscription(ctx, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *subscriptionHttpHandler) CancelSubscription(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.subscriptionUsecase.CancelSubscription(ctx, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *subscriptionHttpHandler) FindCharges(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.subscriptionUsecase.FindCharges(ctx, c.Param("subscription_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *subscriptionHttpHandler) ProviderSubscribe(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(subscription.ProviderSubscribeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.subscriptionUsecase.ProviderSubscribe(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *subscriptionHttpHandler) ProviderRenew(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(subscription.ProviderRenewReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.subscriptionUsecase.ProviderRenew(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
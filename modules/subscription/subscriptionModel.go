@@ -0,0 +1,34 @@
+package subscription
+
+type (
+	CreatePlanReq struct {
+		Title         string  `json:"title" validate:"required,max=64"`
+		Price         float64 `json:"price" validate:"gt=0"`
+		PeriodDays    int     `json:"period_days" validate:"required,min=1,max=366"`
+		GraceDays     int     `json:"grace_days" validate:"min=0,max=30"`
+		DailyMoney    float64 `json:"daily_money" validate:"min=0"`
+		SellBonusRate float64 `json:"sell_bonus_rate" validate:"min=0,max=1"`
+		DiscountRate  float64 `json:"discount_rate" validate:"min=0,max=0.9"`
+	}
+
+	SubscribeReq struct {
+		PlanId string `json:"plan_id" validate:"required,max=64"`
+	}
+
+	// ProviderSubscribeReq records a subscription paid through an external
+	// provider, identified by ProviderRef.
+	ProviderSubscribeReq struct {
+		PlayerId    string `json:"player_id" validate:"required,max=64"`
+		PlanId      string `json:"plan_id" validate:"required,max=64"`
+		ProviderRef string `json:"provider_ref" validate:"required,max=128"`
+	}
+
+	ProviderRenewReq struct {
+		ProviderRef string `json:"provider_ref" validate:"required,max=128"`
+	}
+
+	SubscriptionRes struct {
+		Subscription *Subscription     `json:"subscription"`
+		Plan         *SubscriptionPlan `json:"plan"`
+	}
+)
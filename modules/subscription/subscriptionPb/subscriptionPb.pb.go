@@ -0,0 +1,233 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: modules/subscription/subscriptionPb/subscriptionPb.proto
+
+package mmorpg_shop
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Structures
+type FindEntitlementsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindEntitlementsReq) Reset() {
+	*x = FindEntitlementsReq{}
+	mi := &file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindEntitlementsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindEntitlementsReq) ProtoMessage() {}
+
+func (x *FindEntitlementsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindEntitlementsReq.ProtoReflect.Descriptor instead.
+func (*FindEntitlementsReq) Descriptor() ([]byte, []int) {
+	return file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FindEntitlementsReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+// active is false and the rates are 0 when the player has no live subscription
+type EntitlementsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	PlanId        string                 `protobuf:"bytes,3,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	DiscountRate  float64                `protobuf:"fixed64,4,opt,name=discount_rate,json=discountRate,proto3" json:"discount_rate,omitempty"`
+	SellBonusRate float64                `protobuf:"fixed64,5,opt,name=sell_bonus_rate,json=sellBonusRate,proto3" json:"sell_bonus_rate,omitempty"`
+	DailyMoney    float64                `protobuf:"fixed64,6,opt,name=daily_money,json=dailyMoney,proto3" json:"daily_money,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntitlementsRes) Reset() {
+	*x = EntitlementsRes{}
+	mi := &file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntitlementsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitlementsRes) ProtoMessage() {}
+
+func (x *EntitlementsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitlementsRes.ProtoReflect.Descriptor instead.
+func (*EntitlementsRes) Descriptor() ([]byte, []int) {
+	return file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EntitlementsRes) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *EntitlementsRes) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *EntitlementsRes) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *EntitlementsRes) GetDiscountRate() float64 {
+	if x != nil {
+		return x.DiscountRate
+	}
+	return 0
+}
+
+func (x *EntitlementsRes) GetSellBonusRate() float64 {
+	if x != nil {
+		return x.SellBonusRate
+	}
+	return 0
+}
+
+func (x *EntitlementsRes) GetDailyMoney() float64 {
+	if x != nil {
+		return x.DailyMoney
+	}
+	return 0
+}
+
+func (x *EntitlementsRes) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+var File_modules_subscription_subscriptionPb_subscriptionPb_proto protoreflect.FileDescriptor
+
+const file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDesc = "" +
+	"\n" +
+	"8modules/subscription/subscriptionPb/subscriptionPb.proto\"2\n" +
+	"\x13FindEntitlementsReq\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\"\xec\x01\n" +
+	"\x0fEntitlementsRes\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x17\n" +
+	"\aplan_id\x18\x03 \x01(\tR\x06planId\x12#\n" +
+	"\rdiscount_rate\x18\x04 \x01(\x01R\fdiscountRate\x12&\n" +
+	"\x0fsell_bonus_rate\x18\x05 \x01(\x01R\rsellBonusRate\x12\x1f\n" +
+	"\vdaily_money\x18\x06 \x01(\x01R\n" +
+	"dailyMoney\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tR\texpiresAt2U\n" +
+	"\x17SubscriptionGrpcService\x12:\n" +
+	"\x10FindEntitlements\x12\x14.FindEntitlementsReq\x1a\x10.EntitlementsResB\"Z github.com/Supakornn/mmorpg-shopb\x06proto3"
+
+var (
+	file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescOnce sync.Once
+	file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescData []byte
+)
+
+func file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescGZIP() []byte {
+	file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescOnce.Do(func() {
+		file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDesc), len(file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDesc)))
+	})
+	return file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDescData
+}
+
+var file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_modules_subscription_subscriptionPb_subscriptionPb_proto_goTypes = []any{
+	(*FindEntitlementsReq)(nil), // 0: FindEntitlementsReq
+	(*EntitlementsRes)(nil),     // 1: EntitlementsRes
+}
+var file_modules_subscription_subscriptionPb_subscriptionPb_proto_depIdxs = []int32{
+	0, // 0: SubscriptionGrpcService.FindEntitlements:input_type -> FindEntitlementsReq
+	1, // 1: SubscriptionGrpcService.FindEntitlements:output_type -> EntitlementsRes
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_modules_subscription_subscriptionPb_subscriptionPb_proto_init() }
+func file_modules_subscription_subscriptionPb_subscriptionPb_proto_init() {
+	if File_modules_subscription_subscriptionPb_subscriptionPb_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDesc), len(file_modules_subscription_subscriptionPb_subscriptionPb_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modules_subscription_subscriptionPb_subscriptionPb_proto_goTypes,
+		DependencyIndexes: file_modules_subscription_subscriptionPb_subscriptionPb_proto_depIdxs,
+		MessageInfos:      file_modules_subscription_subscriptionPb_subscriptionPb_proto_msgTypes,
+	}.Build()
+	File_modules_subscription_subscriptionPb_subscriptionPb_proto = out.File
+	file_modules_subscription_subscriptionPb_subscriptionPb_proto_goTypes = nil
+	file_modules_subscription_subscriptionPb_subscriptionPb_proto_depIdxs = nil
+}
@@ -0,0 +1,128 @@
+// Protobuf Version
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: modules/subscription/subscriptionPb/subscriptionPb.proto
+
+package mmorpg_shop
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SubscriptionGrpcService_FindEntitlements_FullMethodName = "/SubscriptionGrpcService/FindEntitlements"
+)
+
+// SubscriptionGrpcServiceClient is the client API for SubscriptionGrpcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Methods
+type SubscriptionGrpcServiceClient interface {
+	FindEntitlements(ctx context.Context, in *FindEntitlementsReq, opts ...grpc.CallOption) (*EntitlementsRes, error)
+}
+
+type subscriptionGrpcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSubscriptionGrpcServiceClient(cc grpc.ClientConnInterface) SubscriptionGrpcServiceClient {
+	return &subscriptionGrpcServiceClient{cc}
+}
+
+func (c *subscriptionGrpcServiceClient) FindEntitlements(ctx context.Context, in *FindEntitlementsReq, opts ...grpc.CallOption) (*EntitlementsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EntitlementsRes)
+	err := c.cc.Invoke(ctx, SubscriptionGrpcService_FindEntitlements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubscriptionGrpcServiceServer is the server API for SubscriptionGrpcService service.
+// All implementations must embed UnimplementedSubscriptionGrpcServiceServer
+// for forward compatibility.
+//
+// Methods
+type SubscriptionGrpcServiceServer interface {
+	FindEntitlements(context.Context, *FindEntitlementsReq) (*EntitlementsRes, error)
+	mustEmbedUnimplementedSubscriptionGrpcServiceServer()
+}
+
+// UnimplementedSubscriptionGrpcServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSubscriptionGrpcServiceServer struct{}
+
+func (UnimplementedSubscriptionGrpcServiceServer) FindEntitlements(context.Context, *FindEntitlementsReq) (*EntitlementsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindEntitlements not implemented")
+}
+func (UnimplementedSubscriptionGrpcServiceServer) mustEmbedUnimplementedSubscriptionGrpcServiceServer() {
+}
+func (UnimplementedSubscriptionGrpcServiceServer) testEmbeddedByValue() {}
+
+// UnsafeSubscriptionGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SubscriptionGrpcServiceServer will
+// result in compilation errors.
+type UnsafeSubscriptionGrpcServiceServer interface {
+	mustEmbedUnimplementedSubscriptionGrpcServiceServer()
+}
+
+func RegisterSubscriptionGrpcServiceServer(s grpc.ServiceRegistrar, srv SubscriptionGrpcServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSubscriptionGrpcServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SubscriptionGrpcService_ServiceDesc, srv)
+}
+
+func _SubscriptionGrpcService_FindEntitlements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindEntitlementsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionGrpcServiceServer).FindEntitlements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionGrpcService_FindEntitlements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionGrpcServiceServer).FindEntitlements(ctx, req.(*FindEntitlementsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SubscriptionGrpcService_ServiceDesc is the grpc.ServiceDesc for SubscriptionGrpcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SubscriptionGrpcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "SubscriptionGrpcService",
+	HandlerType: (*SubscriptionGrpcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindEntitlements",
+			Handler:    _SubscriptionGrpcService_FindEntitlements_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modules/subscription/subscriptionPb/subscriptionPb.proto",
+}
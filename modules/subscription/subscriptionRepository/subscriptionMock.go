@@ -0,0 +1,104 @@
+package subscriptionRepository
+
+import (
+	"context"
+	"time"
+
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SubscriptionRepositoryMock struct {
+	mock.Mock
+}
+
+func NewSubscriptionRepositoryMock() SubscriptionRepositoryService {
+	return &SubscriptionRepositoryMock{}
+}
+
+func (m *SubscriptionRepositoryMock) InsertOnePlan(pctx context.Context, req *subscription.SubscriptionPlan) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindOnePlan(pctx context.Context, planId string) (*subscription.SubscriptionPlan, error) {
+	args := m.Called(pctx, planId)
+	return args.Get(0).(*subscription.SubscriptionPlan), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindPlans(pctx context.Context, filter bson.D) ([]*subscription.SubscriptionPlan, error) {
+	args := m.Called(pctx, filter)
+	return args.Get(0).([]*subscription.SubscriptionPlan), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) InsertOneSubscription(pctx context.Context, req *subscription.Subscription) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindOneSubscription(pctx context.Context, subscriptionId string) (*subscription.Subscription, error) {
+	args := m.Called(pctx, subscriptionId)
+	return args.Get(0).(*subscription.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindLiveSubscription(pctx context.Context, playerId string) (*subscription.Subscription, error) {
+	args := m.Called(pctx, playerId)
+	return args.Get(0).(*subscription.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindSubscriptionByProviderRef(pctx context.Context, providerRef string) (*subscription.Subscription, error) {
+	args := m.Called(pctx, providerRef)
+	return args.Get(0).(*subscription.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) UpdateOneSubscription(pctx context.Context, subscriptionId string, req bson.M) error {
+	args := m.Called(pctx, subscriptionId, req)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) FindDueSubscriptions(pctx context.Context, now time.Time) ([]*subscription.Subscription, error) {
+	args := m.Called(pctx, now)
+	return args.Get(0).([]*subscription.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) LockRenewal(pctx context.Context, subscriptionId string, nextRenewalAt, lockUntil time.Time) error {
+	args := m.Called(pctx, subscriptionId, nextRenewalAt, lockUntil)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) FindDailyGrantSubscriptions(pctx context.Context, today string) ([]*subscription.Subscription, error) {
+	args := m.Called(pctx, today)
+	return args.Get(0).([]*subscription.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) ReserveDailyGrant(pctx context.Context, subscriptionId, today string) error {
+	args := m.Called(pctx, subscriptionId, today)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) ReleaseDailyGrant(pctx context.Context, subscriptionId, today, previous string) error {
+	args := m.Called(pctx, subscriptionId, today, previous)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) InsertOneCharge(pctx context.Context, req *subscription.SubscriptionCharge) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) FindCharges(pctx context.Context, subscriptionId string) ([]*subscription.SubscriptionCharge, error) {
+	args := m.Called(pctx, subscriptionId)
+	return args.Get(0).([]*subscription.SubscriptionCharge), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.CreatePlayerTransactionRes), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Error(0)
+}
@@ -0,0 +1,418 @@
+package subscriptionRepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	SubscriptionRepositoryService interface {
+		InsertOnePlan(pctx context.Context, req *subscription.SubscriptionPlan) (bson.ObjectID, error)
+		FindOnePlan(pctx context.Context, planId string) (*subscription.SubscriptionPlan, error)
+		FindPlans(pctx context.Context, filter bson.D) ([]*subscription.SubscriptionPlan, error)
+		InsertOneSubscription(pctx context.Context, req *subscription.Subscription) (bson.ObjectID, error)
+		FindOneSubscription(pctx context.Context, subscriptionId string) (*subscription.Subscription, error)
+		FindLiveSubscription(pctx context.Context, playerId string) (*subscription.Subscription, error)
+		FindSubscriptionByProviderRef(pctx context.Context, providerRef string) (*subscription.Subscription, error)
+		UpdateOneSubscription(pctx context.Context, subscriptionId string, req bson.M) error
+		FindDueSubscriptions(pctx context.Context, now time.Time) ([]*subscription.Subscription, error)
+		LockRenewal(pctx context.Context, subscriptionId string, nextRenewalAt, lockUntil time.Time) error
+		FindDailyGrantSubscriptions(pctx context.Context, today string) ([]*subscription.Subscription, error)
+		ReserveDailyGrant(pctx context.Context, subscriptionId, today string) error
+		ReleaseDailyGrant(pctx context.Context, subscriptionId, today, previous string) error
+		InsertOneCharge(pctx context.Context, req *subscription.SubscriptionCharge) (bson.ObjectID, error)
+		FindCharges(pctx context.Context, subscriptionId string) ([]*subscription.SubscriptionCharge, error)
+		CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error)
+		RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error
+	}
+
+	subscriptionRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewSubscriptionRepository(db *mongo.Client) SubscriptionRepositoryService {
+	return &subscriptionRepository{db}
+}
+
+func (r *subscriptionRepository) subscriptionDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("subscription_db")
+}
+
+func (r *subscriptionRepository) InsertOnePlan(pctx context.Context, req *subscription.SubscriptionPlan) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscription_plans")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one plan: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one plan failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *subscriptionRepository) FindOnePlan(pctx context.Context, planId string) (*subscription.SubscriptionPlan, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscription_plans")
+
+	result := new(subscription.SubscriptionPlan)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(planId)}).Decode(result); err != nil {
+		log.Printf("error: find one plan: %v", err.Error())
+		return nil, errors.New("error: plan not found")
+	}
+
+	return result, nil
+}
+
+func (r *subscriptionRepository) FindPlans(pctx context.Context, filter bson.D) ([]*subscription.SubscriptionPlan, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscription_plans")
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "price", Value: 1}}))
+	if err != nil {
+		log.Printf("error: find plans: %v", err.Error())
+		return nil, errors.New("error: find plans failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*subscription.SubscriptionPlan, 0)
+	for cursors.Next(ctx) {
+		result := new(subscription.SubscriptionPlan)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode plan: %v", err.Error())
+			return nil, errors.New("error: decode plan failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *subscriptionRepository) InsertOneSubscription(pctx context.Context, req *subscription.Subscription) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return bson.NilObjectID, errors.New("error: player already has a subscription")
+		}
+		log.Printf("error: insert one subscription: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one subscription failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *subscriptionRepository) FindOneSubscription(pctx context.Context, subscriptionId string) (*subscription.Subscription, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result := new(subscription.Subscription)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(subscriptionId)}).Decode(result); err != nil {
+		log.Printf("error: find one subscription: %v", err.Error())
+		return nil, errors.New("error: subscription not found")
+	}
+
+	return result, nil
+}
+
+// FindLiveSubscription returns nil when the player has no live subscription.
+func (r *subscriptionRepository) FindLiveSubscription(pctx context.Context, playerId string) (*subscription.Subscription, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result := new(subscription.Subscription)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId, "live": true}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("error: find live subscription: %v", err.Error())
+		return nil, errors.New("error: find live subscription failed")
+	}
+
+	return result, nil
+}
+
+func (r *subscriptionRepository) FindSubscriptionByProviderRef(pctx context.Context, providerRef string) (*subscription.Subscription, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result := new(subscription.Subscription)
+	if err := col.FindOne(ctx, bson.M{"provider_ref": providerRef, "live": true}).Decode(result); err != nil {
+		log.Printf("error: find subscription by provider ref: %v", err.Error())
+		return nil, errors.New("error: subscription not found")
+	}
+
+	return result, nil
+}
+
+func (r *subscriptionRepository) UpdateOneSubscription(pctx context.Context, subscriptionId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(subscriptionId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one subscription: %v", err.Error())
+		return errors.New("error: update one subscription failed")
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) FindDueSubscriptions(pctx context.Context, now time.Time) ([]*subscription.Subscription, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	cursors, err := col.Find(
+		ctx,
+		bson.M{"live": true, "status": bson.M{"$in": bson.A{"active", "grace"}}, "next_renewal_at": bson.M{"$lte": now}},
+		options.Find().SetSort(bson.D{{Key: "next_renewal_at", Value: 1}}).SetLimit(100),
+	)
+	if err != nil {
+		log.Printf("error: find due subscriptions: %v", err.Error())
+		return nil, errors.New("error: find due subscriptions failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*subscription.Subscription, 0)
+	for cursors.Next(ctx) {
+		result := new(subscription.Subscription)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode subscription: %v", err.Error())
+			return nil, errors.New("error: decode subscription failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// LockRenewal pushes next_renewal_at forward only when it still holds the
+// value that was read, so a subscription is renewed by one worker at a time.
+func (r *subscriptionRepository) LockRenewal(pctx context.Context, subscriptionId string, nextRenewalAt, lockUntil time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(subscriptionId), "next_renewal_at": nextRenewalAt},
+		bson.M{"$set": bson.M{"next_renewal_at": lockUntil, "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: lock renewal: %v", err.Error())
+		return errors.New("error: lock renewal failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: renewal already taken")
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) FindDailyGrantSubscriptions(pctx context.Context, today string) ([]*subscription.Subscription, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	cursors, err := col.Find(
+		ctx,
+		bson.M{"live": true, "status": bson.M{"$in": bson.A{"active", "grace"}}, "last_daily_grant": bson.M{"$ne": today}},
+		options.Find().SetLimit(500),
+	)
+	if err != nil {
+		log.Printf("error: find daily grant subscriptions: %v", err.Error())
+		return nil, errors.New("error: find daily grant subscriptions failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*subscription.Subscription, 0)
+	for cursors.Next(ctx) {
+		result := new(subscription.Subscription)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode subscription: %v", err.Error())
+			return nil, errors.New("error: decode subscription failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ReserveDailyGrant marks today's grant as given before paying it, so the
+// daily money is granted at most once per day.
+func (r *subscriptionRepository) ReserveDailyGrant(pctx context.Context, subscriptionId, today string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(subscriptionId), "live": true, "last_daily_grant": bson.M{"$ne": today}},
+		bson.M{"$set": bson.M{"last_daily_grant": today}},
+	)
+	if err != nil {
+		log.Printf("error: reserve daily grant: %v", err.Error())
+		return errors.New("error: reserve daily grant failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: daily grant already given")
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) ReleaseDailyGrant(pctx context.Context, subscriptionId, today, previous string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscriptions")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(subscriptionId), "last_daily_grant": today},
+		bson.M{"$set": bson.M{"last_daily_grant": previous}},
+	); err != nil {
+		log.Printf("error: release daily grant: %v", err.Error())
+		return errors.New("error: release daily grant failed")
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) InsertOneCharge(pctx context.Context, req *subscription.SubscriptionCharge) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscription_charges")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one charge: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one charge failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *subscriptionRepository) FindCharges(pctx context.Context, subscriptionId string) ([]*subscription.SubscriptionCharge, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.subscriptionDbConn(ctx)
+	col := db.Collection("subscription_charges")
+
+	cursors, err := col.Find(ctx, bson.M{"subscription_id": subscriptionId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("error: find charges: %v", err.Error())
+		return nil, errors.New("error: find charges failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*subscription.SubscriptionCharge, 0)
+	for cursors.Next(ctx) {
+		result := new(subscription.SubscriptionCharge)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode charge: %v", err.Error())
+			return nil, errors.New("error: decode charge failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *subscriptionRepository) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().CreatePlayerTransaction(ctx, req)
+	if err != nil {
+		log.Printf("error: create player transaction failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *subscriptionRepository) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	if _, err := conn.Player().RollbackPlayerTransaction(ctx, req); err != nil {
+		log.Printf("error: rollback player transaction failed: %v", err.Error())
+		return errors.New(status.Convert(err).Message())
+	}
+
+	return nil
+}
@@ -0,0 +1,465 @@
+package subscriptionUsecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionRepository"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// renewalLock is how long a renewal keeps the subscription to itself.
+	renewalLock = 10 * time.Minute
+	// renewalRetry is the wait between wallet charges during the grace period.
+	renewalRetry = 6 * time.Hour
+)
+
+type (
+	SubscriptionUsecaseService interface {
+		CreatePlan(pctx context.Context, req *subscription.CreatePlanReq) (*subscription.SubscriptionPlan, error)
+		FindPlans(pctx context.Context) ([]*subscription.SubscriptionPlan, error)
+		Subscribe(pctx context.Context, cfg *config.Config, playerId string, req *subscription.SubscribeReq) (*subscription.SubscriptionRes, error)
+		FindMySubscription(pctx context.Context, playerId string) (*subscription.SubscriptionRes, error)
+		CancelSubscription(pctx context.Context, playerId string) (*subscription.Subscription, error)
+		FindCharges(pctx context.Context, subscriptionId string) ([]*subscription.SubscriptionCharge, error)
+		ProviderSubscribe(pctx context.Context, req *subscription.ProviderSubscribeReq) (*subscription.SubscriptionRes, error)
+		ProviderRenew(pctx context.Context, req *subscription.ProviderRenewReq) (*subscription.SubscriptionRes, error)
+		RunRenewals(pctx context.Context, cfg *config.Config) error
+		RunDailyGrants(pctx context.Context, cfg *config.Config) error
+		FindEntitlements(pctx context.Context, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error)
+	}
+
+	subscriptionUsecase struct {
+		subscriptionRepository subscriptionRepository.SubscriptionRepositoryService
+	}
+)
+
+func NewSubscriptionUsecase(subscriptionRepository subscriptionRepository.SubscriptionRepositoryService) SubscriptionUsecaseService {
+	return &subscriptionUsecase{subscriptionRepository}
+}
+
+func (u *subscriptionUsecase) CreatePlan(pctx context.Context, req *subscription.CreatePlanReq) (*subscription.SubscriptionPlan, error) {
+	result := &subscription.SubscriptionPlan{
+		Title:         req.Title,
+		Price:         req.Price,
+		PeriodDays:    req.PeriodDays,
+		GraceDays:     req.GraceDays,
+		DailyMoney:    req.DailyMoney,
+		SellBonusRate: req.SellBonusRate,
+		DiscountRate:  req.DiscountRate,
+		UsageStatus:   true,
+		CreatedAt:     utils.LocalTime(),
+		UpdatedAt:     utils.LocalTime(),
+	}
+
+	planId, err := u.subscriptionRepository.InsertOnePlan(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = planId
+
+	return result, nil
+}
+
+func (u *subscriptionUsecase) FindPlans(pctx context.Context) ([]*subscription.SubscriptionPlan, error) {
+	return u.subscriptionRepository.FindPlans(pctx, bson.D{{Key: "usage_status", Value: true}})
+}
+
+// Subscribe charges the first period from the wallet. The subscription is
+// inserted as pending first, so the unique live index stops a second
+// subscription before any money is taken.
+func (u *subscriptionUsecase) Subscribe(pctx context.Context, cfg *config.Config, playerId string, req *subscription.SubscribeReq) (*subscription.SubscriptionRes, error) {
+	plan, err := u.subscriptionRepository.FindOnePlan(pctx, strings.TrimPrefix(req.PlanId, "plan:"))
+	if err != nil {
+		return nil, err
+	}
+	if !plan.UsageStatus {
+		return nil, errors.New("error: plan is not available")
+	}
+
+	now := utils.LocalTime()
+	result := &subscription.Subscription{
+		PlayerId:           playerId,
+		PlanId:             plan.Id.Hex(),
+		Method:             "wallet",
+		Status:             "pending",
+		Live:               true,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 0, plan.PeriodDays),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	result.NextRenewalAt = result.CurrentPeriodEnd
+
+	subscriptionId, err := u.subscriptionRepository.InsertOneSubscription(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = subscriptionId
+
+	transaction, err := u.subscriptionRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+		PlayerId: playerId,
+		Amount:   -plan.Price,
+	})
+	if err != nil {
+		u.recordCharge(pctx, result, plan.Price, "", err)
+		if err := u.subscriptionRepository.UpdateOneSubscription(pctx, subscriptionId.Hex(), bson.M{
+			"status":     "failed",
+			"live":       false,
+			"updated_at": utils.LocalTime(),
+		}); err != nil {
+			log.Printf("Error: fail subscription %s failed: %v", subscriptionId.Hex(), err.Error())
+		}
+		return nil, err
+	}
+	u.recordCharge(pctx, result, plan.Price, transaction.TransactionId, nil)
+
+	result.Status = "active"
+	if err := u.subscriptionRepository.UpdateOneSubscription(pctx, subscriptionId.Hex(), bson.M{
+		"status":     result.Status,
+		"updated_at": utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: activate subscription %s failed: %v", subscriptionId.Hex(), err.Error())
+		if err := u.subscriptionRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: transaction.TransactionId,
+		}); err != nil {
+			log.Printf("Error: rollback subscription charge %s failed: %v", transaction.TransactionId, err.Error())
+		}
+		return nil, err
+	}
+
+	return &subscription.SubscriptionRes{Subscription: result, Plan: plan}, nil
+}
+
+func (u *subscriptionUsecase) FindMySubscription(pctx context.Context, playerId string) (*subscription.SubscriptionRes, error) {
+	result, err := u.subscriptionRepository.FindLiveSubscription(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("error: no active subscription")
+	}
+
+	plan, err := u.subscriptionRepository.FindOnePlan(pctx, result.PlanId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription.SubscriptionRes{Subscription: result, Plan: plan}, nil
+}
+
+// CancelSubscription stops the next renewal. The benefits stay until the
+// end of the paid period.
+func (u *subscriptionUsecase) CancelSubscription(pctx context.Context, playerId string) (*subscription.Subscription, error) {
+	result, err := u.subscriptionRepository.FindLiveSubscription(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.Status == "pending" {
+		return nil, errors.New("error: no active subscription")
+	}
+	if result.CancelAtPeriodEnd {
+		return nil, errors.New("error: subscription already cancelled")
+	}
+
+	now := utils.LocalTime()
+	if err := u.subscriptionRepository.UpdateOneSubscription(pctx, result.Id.Hex(), bson.M{
+		"cancel_at_period_end": true,
+		"cancelled_at":         now,
+		"updated_at":           now,
+	}); err != nil {
+		return nil, err
+	}
+	result.CancelAtPeriodEnd = true
+	result.CancelledAt = &now
+
+	return result, nil
+}
+
+func (u *subscriptionUsecase) FindCharges(pctx context.Context, subscriptionId string) ([]*subscription.SubscriptionCharge, error) {
+	return u.subscriptionRepository.FindCharges(pctx, strings.TrimPrefix(subscriptionId, "subscription:"))
+}
+
+// ProviderSubscribe records a subscription already paid through an
+// external provider. The provider renews it with ProviderRenew.
+func (u *subscriptionUsecase) ProviderSubscribe(pctx context.Context, req *subscription.ProviderSubscribeReq) (*subscription.SubscriptionRes, error) {
+	plan, err := u.subscriptionRepository.FindOnePlan(pctx, strings.TrimPrefix(req.PlanId, "plan:"))
+	if err != nil {
+		return nil, err
+	}
+	if !plan.UsageStatus {
+		return nil, errors.New("error: plan is not available")
+	}
+
+	now := utils.LocalTime()
+	result := &subscription.Subscription{
+		PlayerId:           "player:" + strings.TrimPrefix(req.PlayerId, "player:"),
+		PlanId:             plan.Id.Hex(),
+		Method:             "provider",
+		Status:             "active",
+		Live:               true,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 0, plan.PeriodDays),
+		ProviderRef:        req.ProviderRef,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	result.NextRenewalAt = result.CurrentPeriodEnd
+
+	subscriptionId, err := u.subscriptionRepository.InsertOneSubscription(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = subscriptionId
+
+	u.recordCharge(pctx, result, plan.Price, "", nil)
+
+	return &subscription.SubscriptionRes{Subscription: result, Plan: plan}, nil
+}
+
+func (u *subscriptionUsecase) ProviderRenew(pctx context.Context, req *subscription.ProviderRenewReq) (*subscription.SubscriptionRes, error) {
+	result, err := u.subscriptionRepository.FindSubscriptionByProviderRef(pctx, req.ProviderRef)
+	if err != nil {
+		return nil, err
+	}
+	if result.Method != "provider" {
+		return nil, errors.New("error: subscription is not billed by a provider")
+	}
+
+	plan, err := u.subscriptionRepository.FindOnePlan(pctx, result.PlanId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.extend(pctx, result, plan); err != nil {
+		return nil, err
+	}
+	u.recordCharge(pctx, result, plan.Price, "", nil)
+
+	return &subscription.SubscriptionRes{Subscription: result, Plan: plan}, nil
+}
+
+// RunRenewals handles every subscription whose renewal is due: cancelled
+// ones end, wallet ones are charged and provider ones that were not renewed
+// move to grace. A subscription still unpaid when its grace ends expires.
+func (u *subscriptionUsecase) RunRenewals(pctx context.Context, cfg *config.Config) error {
+	now := utils.LocalTime()
+
+	subscriptions, err := u.subscriptionRepository.FindDueSubscriptions(pctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range subscriptions {
+		if err := u.subscriptionRepository.LockRenewal(pctx, v.Id.Hex(), v.NextRenewalAt, now.Add(renewalLock)); err != nil {
+			continue
+		}
+
+		if v.CancelAtPeriodEnd {
+			u.end(pctx, v, "cancelled")
+			continue
+		}
+
+		plan, err := u.subscriptionRepository.FindOnePlan(pctx, v.PlanId)
+		if err != nil {
+			log.Printf("Error: renew subscription %s failed: %v", v.Id.Hex(), err.Error())
+			continue
+		}
+
+		graceUntil := v.CurrentPeriodEnd.AddDate(0, 0, plan.GraceDays)
+
+		var chargeErr error
+		if v.Method == "wallet" {
+			transaction, err := u.subscriptionRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+				PlayerId: v.PlayerId,
+				Amount:   -plan.Price,
+			})
+			if err == nil {
+				err = u.extend(pctx, v, plan)
+				if err == nil {
+					u.recordCharge(pctx, v, plan.Price, transaction.TransactionId, nil)
+					continue
+				}
+
+				// The period was not extended, so the charge is given back
+				// before the renewal is retried
+				log.Printf("Error: extend subscription %s failed: %v", v.Id.Hex(), err.Error())
+				if err := u.subscriptionRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+					TransactionId: transaction.TransactionId,
+				}); err != nil {
+					log.Printf("Error: rollback renewal charge %s failed: %v", transaction.TransactionId, err.Error())
+					u.recordCharge(pctx, v, plan.Price, transaction.TransactionId, errors.New("error: extend failed and the charge was not rolled back"))
+					continue
+				}
+			}
+			chargeErr = err
+			u.recordCharge(pctx, v, plan.Price, "", err)
+		}
+
+		if !now.Before(graceUntil) {
+			u.end(pctx, v, "expired")
+			continue
+		}
+
+		nextRenewalAt := graceUntil
+		if chargeErr != nil && now.Add(renewalRetry).Before(graceUntil) {
+			nextRenewalAt = now.Add(renewalRetry)
+		}
+		if err := u.subscriptionRepository.UpdateOneSubscription(pctx, v.Id.Hex(), bson.M{
+			"status":          "grace",
+			"grace_until":     graceUntil,
+			"next_renewal_at": nextRenewalAt,
+			"updated_at":      utils.LocalTime(),
+		}); err != nil {
+			log.Printf("Error: move subscription %s to grace failed: %v", v.Id.Hex(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// RunDailyGrants pays the plan's daily money once per day to every active
+// or grace subscription. The day is marked before paying and unmarked when
+// the payment fails, so the next run tries again.
+func (u *subscriptionUsecase) RunDailyGrants(pctx context.Context, cfg *config.Config) error {
+	today := utils.LocalTime().Format("2006-01-02")
+
+	subscriptions, err := u.subscriptionRepository.FindDailyGrantSubscriptions(pctx, today)
+	if err != nil {
+		return err
+	}
+
+	plans := make(map[string]*subscription.SubscriptionPlan)
+	for _, v := range subscriptions {
+		plan, ok := plans[v.PlanId]
+		if !ok {
+			plan, err = u.subscriptionRepository.FindOnePlan(pctx, v.PlanId)
+			if err != nil {
+				log.Printf("Error: daily grant of subscription %s failed: %v", v.Id.Hex(), err.Error())
+				continue
+			}
+			plans[v.PlanId] = plan
+		}
+
+		if err := u.subscriptionRepository.ReserveDailyGrant(pctx, v.Id.Hex(), today); err != nil {
+			continue
+		}
+		if plan.DailyMoney <= 0 {
+			continue
+		}
+
+		if _, err := u.subscriptionRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: v.PlayerId,
+			Amount:   plan.DailyMoney,
+		}); err != nil {
+			log.Printf("Error: daily grant of subscription %s failed: %v", v.Id.Hex(), err.Error())
+			if err := u.subscriptionRepository.ReleaseDailyGrant(pctx, v.Id.Hex(), today, v.LastDailyGrant); err != nil {
+				log.Printf("Error: release daily grant failed: %v", err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindEntitlements is called by the payment and item services. A player
+// without a live subscription gets zero rates rather than an error.
+func (u *subscriptionUsecase) FindEntitlements(pctx context.Context, req *subscriptionPb.FindEntitlementsReq) (*subscriptionPb.EntitlementsRes, error) {
+	playerId := "player:" + strings.TrimPrefix(req.PlayerId, "player:")
+
+	result, err := u.subscriptionRepository.FindLiveSubscription(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || (result.Status != "active" && result.Status != "grace") {
+		return &subscriptionPb.EntitlementsRes{PlayerId: playerId}, nil
+	}
+
+	plan, err := u.subscriptionRepository.FindOnePlan(pctx, result.PlanId)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := result.CurrentPeriodEnd
+	if result.GraceUntil != nil {
+		expiresAt = *result.GraceUntil
+	}
+
+	return &subscriptionPb.EntitlementsRes{
+		PlayerId:      playerId,
+		Active:        true,
+		PlanId:        plan.Id.Hex(),
+		DiscountRate:  plan.DiscountRate,
+		SellBonusRate: plan.SellBonusRate,
+		DailyMoney:    plan.DailyMoney,
+		ExpiresAt:     expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// extend starts the next period where the paid one ended and clears grace.
+// result is only changed once the update is stored.
+func (u *subscriptionUsecase) extend(pctx context.Context, result *subscription.Subscription, plan *subscription.SubscriptionPlan) error {
+	periodStart := result.CurrentPeriodEnd
+	periodEnd := periodStart.AddDate(0, 0, plan.PeriodDays)
+
+	if err := u.subscriptionRepository.UpdateOneSubscription(pctx, result.Id.Hex(), bson.M{
+		"status":               "active",
+		"current_period_start": periodStart,
+		"current_period_end":   periodEnd,
+		"next_renewal_at":      periodEnd,
+		"grace_until":          nil,
+		"updated_at":           utils.LocalTime(),
+	}); err != nil {
+		return err
+	}
+
+	result.CurrentPeriodStart = periodStart
+	result.CurrentPeriodEnd = periodEnd
+	result.NextRenewalAt = periodEnd
+	result.Status = "active"
+	result.GraceUntil = nil
+
+	return nil
+}
+
+func (u *subscriptionUsecase) end(pctx context.Context, result *subscription.Subscription, status string) {
+	if err := u.subscriptionRepository.UpdateOneSubscription(pctx, result.Id.Hex(), bson.M{
+		"status":     status,
+		"live":       false,
+		"updated_at": utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: end subscription %s failed: %v", result.Id.Hex(), err.Error())
+	}
+}
+
+func (u *subscriptionUsecase) recordCharge(pctx context.Context, result *subscription.Subscription, amount float64, transactionId string, chargeErr error) {
+	charge := &subscription.SubscriptionCharge{
+		SubscriptionId: result.Id.Hex(),
+		PlayerId:       result.PlayerId,
+		Method:         result.Method,
+		Amount:         amount,
+		TransactionId:  transactionId,
+		ProviderRef:    result.ProviderRef,
+		PeriodStart:    result.CurrentPeriodStart,
+		PeriodEnd:      result.CurrentPeriodEnd,
+		Status:         "succeeded",
+		CreatedAt:      utils.LocalTime(),
+	}
+	if chargeErr != nil {
+		charge.Status = "failed"
+		charge.Error = chargeErr.Error()
+	}
+
+	if _, err := u.subscriptionRepository.InsertOneCharge(pctx, charge); err != nil {
+		log.Printf("Error: record charge of subscription %s failed: %v", result.Id.Hex(), err.Error())
+	}
+}
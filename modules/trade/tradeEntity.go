@@ -0,0 +1,63 @@
+package trade
+
+import (
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	// Trade is an offer between two players. The player in ProposedBy made
+	// the current terms and has their side locked in Escrow; the other
+	// player may accept, counter or cancel it.
+	Trade struct {
+		Id           bson.ObjectID    `json:"_id" bson:"_id,omitempty"`
+		FromPlayerId string           `json:"from_player_id" bson:"from_player_id"`
+		ToPlayerId   string           `json:"to_player_id" bson:"to_player_id"`
+		FromOffer    *TradeOffer      `json:"from_offer" bson:"from_offer"`
+		ToOffer      *TradeOffer      `json:"to_offer" bson:"to_offer"`
+		ProposedBy   string           `json:"proposed_by" bson:"proposed_by"`
+		Round        int              `json:"round" bson:"round"`
+		Status       string           `json:"status" bson:"status"`
+		Escrow       *TradeEscrow     `json:"escrow,omitempty" bson:"escrow,omitempty"`
+		Settlement   *TradeSettlement `json:"settlement,omitempty" bson:"settlement,omitempty"`
+		Error        string           `json:"error,omitempty" bson:"error,omitempty"`
+		ExpiresAt    time.Time        `json:"expires_at" bson:"expires_at"`
+		CreatedAt    time.Time        `json:"created_at" bson:"created_at"`
+		UpdatedAt    time.Time        `json:"updated_at" bson:"updated_at"`
+	}
+
+	TradeOffer struct {
+		ItemIds []string `json:"item_ids" bson:"item_ids"`
+		Money   float64  `json:"money" bson:"money"`
+	}
+
+	TradeEscrow struct {
+		PlayerId      string             `json:"player_id" bson:"player_id"`
+		Items         []*TradeEscrowItem `json:"items" bson:"items"`
+		Money         float64            `json:"money" bson:"money"`
+		TransactionId string             `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	}
+
+	// TradeSettlement records what a processing trade has done so far, so an
+	// interrupted accept, counter or close can be undone or finished later.
+	// Escrow is the responding player's side locked for this step, Granted
+	// and Mailed the exact copies handed out, Credits the money paid out.
+	// Release is an escrow still owed back to its owner, after which the
+	// trade moves to Status.
+	TradeSettlement struct {
+		Escrow  *TradeEscrow `json:"escrow,omitempty" bson:"escrow,omitempty"`
+		Granted []string     `json:"granted,omitempty" bson:"granted,omitempty"`
+		Mailed  []string     `json:"mailed,omitempty" bson:"mailed,omitempty"`
+		Credits []string     `json:"credits,omitempty" bson:"credits,omitempty"`
+		Release *TradeEscrow `json:"release,omitempty" bson:"release,omitempty"`
+		Status  string       `json:"status,omitempty" bson:"status,omitempty"`
+	}
+
+	TradeEscrowItem struct {
+		InventoryId string                  `json:"inventory_id" bson:"inventory_id"`
+		ItemId      string                  `json:"item_id" bson:"item_id"`
+		Instance    *inventory.ItemInstance `json:"instance,omitempty" bson:"instance,omitempty"`
+	}
+)
@@ -0,0 +1,154 @@
+package tradeHandler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/trade"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/request"
+	"github.com/Supakornn/mmorpg-shop/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	TradeHttpHandlerService interface {
+		CreateTrade(c echo.Context) error
+		FindOneTrade(c echo.Context) error
+		FindPlayerTrades(c echo.Context) error
+		CounterTrade(c echo.Context) error
+		AcceptTrade(c echo.Context) error
+		CancelTrade(c echo.Context) error
+	}
+
+	tradeHttpHandler struct {
+		cfg          *config.Config
+		tradeUsecase tradeUsecase.TradeUsecaseService
+	}
+)
+
+func NewTradeHttpHandler(cfg *config.Config, tradeUsecase tradeUsecase.TradeUsecaseService) TradeHttpHandlerService {
+	return &tradeHttpHandler{cfg, tradeUsecase}
+}
+
+func tradeIdParam(c echo.Context) (string, error) {
+	decodedParam, err := url.QueryUnescape(c.Param("trade_id"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(decodedParam, "trade:"), nil
+}
+
+func (h *tradeHttpHandler) CreateTrade(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(trade.CreateTradeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.tradeUsecase.CreateTrade(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *tradeHttpHandler) FindOneTrade(c echo.Context) error {
+	ctx := context.Background()
+
+	tradeId, err := tradeIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.tradeUsecase.FindOneTrade(ctx, c.Get("player_id").(string), tradeId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *tradeHttpHandler) FindPlayerTrades(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(trade.TradeSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.tradeUsecase.FindPlayerTrades(ctx, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *tradeHttpHandler) CounterTrade(c echo.Context) error {
+	ctx := context.Background()
+
+	tradeId, err := tradeIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(trade.CounterTradeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.tradeUsecase.CounterTrade(ctx, h.cfg, c.Get("player_id").(string), tradeId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *tradeHttpHandler) AcceptTrade(c echo.Context) error {
+	ctx := context.Background()
+
+	tradeId, err := tradeIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.tradeUsecase.AcceptTrade(ctx, h.cfg, c.Get("player_id").(string), tradeId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *tradeHttpHandler) CancelTrade(c echo.Context) error {
+	ctx := context.Background()
+
+	tradeId, err := tradeIdParam(c)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "invalid parameter format")
+	}
+
+	res, err := h.tradeUsecase.CancelTrade(ctx, h.cfg, c.Get("player_id").(string), tradeId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
@@ -0,0 +1,27 @@
+package trade
+
+type (
+	TradeOfferReq struct {
+		ItemIds []string `json:"item_ids" validate:"max=20,dive,max=64"`
+		Money   float64  `json:"money" validate:"min=0"`
+	}
+
+	// CreateTradeReq offers Offer to ToPlayerId in exchange for Request.
+	// ExpiresIn is in minutes and defaults to one day.
+	CreateTradeReq struct {
+		ToPlayerId string         `json:"to_player_id" validate:"required,max=64"`
+		Offer      *TradeOfferReq `json:"offer"`
+		Request    *TradeOfferReq `json:"request"`
+		ExpiresIn  int            `json:"expires_in" validate:"min=0,max=10080"`
+	}
+
+	// CounterTradeReq replaces the terms from the countering player's side.
+	CounterTradeReq struct {
+		Offer   *TradeOfferReq `json:"offer"`
+		Request *TradeOfferReq `json:"request"`
+	}
+
+	TradeSearchReq struct {
+		Status string `query:"status" validate:"omitempty,oneof=pending completed cancelled expired"`
+	}
+)
@@ -0,0 +1,80 @@
+package tradeRepository
+
+import (
+	"context"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/trade"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type TradeRepositoryMock struct {
+	mock.Mock
+}
+
+func NewTradeRepositoryMock() TradeRepositoryService {
+	return &TradeRepositoryMock{}
+}
+
+func (m *TradeRepositoryMock) InsertOneTrade(pctx context.Context, req *trade.Trade) (bson.ObjectID, error) {
+	args := m.Called(pctx, req)
+	return args.Get(0).(bson.ObjectID), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) FindOneTrade(pctx context.Context, tradeId string) (*trade.Trade, error) {
+	args := m.Called(pctx, tradeId)
+	return args.Get(0).(*trade.Trade), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) FindPlayerTrades(pctx context.Context, playerId, status string) ([]*trade.Trade, error) {
+	args := m.Called(pctx, playerId, status)
+	return args.Get(0).([]*trade.Trade), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) FindExpiredTrades(pctx context.Context, now time.Time) ([]*trade.Trade, error) {
+	args := m.Called(pctx, now)
+	return args.Get(0).([]*trade.Trade), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) FindStaleTrades(pctx context.Context, before time.Time) ([]*trade.Trade, error) {
+	args := m.Called(pctx, before)
+	return args.Get(0).([]*trade.Trade), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) ClaimStaleTrade(pctx context.Context, tradeId string, updatedAt time.Time) error {
+	args := m.Called(pctx, tradeId, updatedAt)
+	return args.Error(0)
+}
+
+func (m *TradeRepositoryMock) LockTrade(pctx context.Context, tradeId string, round int) error {
+	args := m.Called(pctx, tradeId, round)
+	return args.Error(0)
+}
+
+func (m *TradeRepositoryMock) UpdateOneTrade(pctx context.Context, tradeId string, req bson.M) error {
+	args := m.Called(pctx, tradeId, req)
+	return args.Error(0)
+}
+
+func (m *TradeRepositoryMock) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchGrantRes), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*inventoryPb.BatchRevokeRes), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Get(0).(*playerPb.CreatePlayerTransactionRes), args.Error(1)
+}
+
+func (m *TradeRepositoryMock) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	args := m.Called(pctx, grpcUrl, req)
+	return args.Error(0)
+}
@@ -0,0 +1,326 @@
+package tradeRepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/trade"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+	"github.com/Supakornn/mmorpg-shop/pkg/jwtauth"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	TradeRepositoryService interface {
+		InsertOneTrade(pctx context.Context, req *trade.Trade) (bson.ObjectID, error)
+		FindOneTrade(pctx context.Context, tradeId string) (*trade.Trade, error)
+		FindPlayerTrades(pctx context.Context, playerId, status string) ([]*trade.Trade, error)
+		FindExpiredTrades(pctx context.Context, now time.Time) ([]*trade.Trade, error)
+		FindStaleTrades(pctx context.Context, before time.Time) ([]*trade.Trade, error)
+		LockTrade(pctx context.Context, tradeId string, round int) error
+		ClaimStaleTrade(pctx context.Context, tradeId string, updatedAt time.Time) error
+		UpdateOneTrade(pctx context.Context, tradeId string, req bson.M) error
+		BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error)
+		BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error)
+		CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error)
+		RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error
+	}
+
+	tradeRepository struct {
+		db *mongo.Client
+	}
+)
+
+func NewTradeRepository(db *mongo.Client) TradeRepositoryService {
+	return &tradeRepository{db}
+}
+
+func (r *tradeRepository) tradeDbConn(pctx context.Context) *mongo.Database {
+	return r.db.Database("trade_db")
+}
+
+func (r *tradeRepository) InsertOneTrade(pctx context.Context, req *trade.Trade) (bson.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("error: insert one trade: %v", err.Error())
+		return bson.NilObjectID, errors.New("error: insert one trade failed")
+	}
+
+	return result.InsertedID.(bson.ObjectID), nil
+}
+
+func (r *tradeRepository) FindOneTrade(pctx context.Context, tradeId string) (*trade.Trade, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	result := new(trade.Trade)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(tradeId)}).Decode(result); err != nil {
+		log.Printf("error: find one trade: %v", err.Error())
+		return nil, errors.New("error: trade not found")
+	}
+
+	return result, nil
+}
+
+func (r *tradeRepository) FindPlayerTrades(pctx context.Context, playerId, status string) ([]*trade.Trade, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.M{"from_player_id": playerId},
+		bson.M{"to_player_id": playerId},
+	}}}
+	if status != "" {
+		filter = append(filter, bson.E{Key: "status", Value: status})
+	}
+
+	cursors, err := col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("error: find player trades: %v", err.Error())
+		return nil, errors.New("error: find player trades failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*trade.Trade, 0)
+	for cursors.Next(ctx) {
+		result := new(trade.Trade)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode trade: %v", err.Error())
+			return nil, errors.New("error: decode trade failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *tradeRepository) FindExpiredTrades(pctx context.Context, now time.Time) ([]*trade.Trade, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	cursors, err := col.Find(ctx, bson.M{"status": "pending", "expires_at": bson.M{"$lte": now}})
+	if err != nil {
+		log.Printf("error: find expired trades: %v", err.Error())
+		return nil, errors.New("error: find expired trades failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*trade.Trade, 0)
+	for cursors.Next(ctx) {
+		result := new(trade.Trade)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode trade: %v", err.Error())
+			return nil, errors.New("error: decode trade failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// FindStaleTrades returns trades left processing since before, e.g. by a
+// crash or a failed compensation.
+func (r *tradeRepository) FindStaleTrades(pctx context.Context, before time.Time) ([]*trade.Trade, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	cursors, err := col.Find(ctx, bson.M{"status": "processing", "updated_at": bson.M{"$lte": before}})
+	if err != nil {
+		log.Printf("error: find stale trades: %v", err.Error())
+		return nil, errors.New("error: find stale trades failed")
+	}
+	defer cursors.Close(ctx)
+
+	results := make([]*trade.Trade, 0)
+	for cursors.Next(ctx) {
+		result := new(trade.Trade)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("error: decode trade: %v", err.Error())
+			return nil, errors.New("error: decode trade failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// LockTrade moves a pending trade into processing so only one accept,
+// counter, cancel or expiry can run its saga. round guards against acting on
+// terms that were countered in the meantime.
+func (r *tradeRepository) LockTrade(pctx context.Context, tradeId string, round int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(tradeId), "status": "pending", "round": round},
+		bson.M{"$set": bson.M{"status": "processing", "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: lock trade: %v", err.Error())
+		return errors.New("error: lock trade failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: trade is no longer pending")
+	}
+
+	return nil
+}
+
+// ClaimStaleTrade takes over a stale processing trade for recovery. It only
+// matches while updated_at is unchanged, so two runs never recover the same
+// trade at once.
+func (r *tradeRepository) ClaimStaleTrade(pctx context.Context, tradeId string, updatedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(tradeId), "status": "processing", "updated_at": updatedAt},
+		bson.M{"$set": bson.M{"updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("error: claim stale trade: %v", err.Error())
+		return errors.New("error: claim stale trade failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: trade is already being recovered")
+	}
+
+	return nil
+}
+
+func (r *tradeRepository) UpdateOneTrade(pctx context.Context, tradeId string, req bson.M) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.tradeDbConn(ctx)
+	col := db.Collection("trades")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(tradeId)}, bson.M{"$set": req}); err != nil {
+		log.Printf("error: update one trade: %v", err.Error())
+		return errors.New("error: update one trade failed")
+	}
+
+	return nil
+}
+
+func (r *tradeRepository) BatchGrant(pctx context.Context, grpcUrl string, req *inventoryPb.BatchGrantReq) (*inventoryPb.BatchGrantRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchGrant(ctx, req)
+	if err != nil {
+		log.Printf("error: batch grant failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *tradeRepository) BatchRevoke(pctx context.Context, grpcUrl string, req *inventoryPb.BatchRevokeReq) (*inventoryPb.BatchRevokeRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Inventory().BatchRevoke(ctx, req)
+	if err != nil {
+		log.Printf("error: batch revoke failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *tradeRepository) CreatePlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.CreatePlayerTransactionReq) (*playerPb.CreatePlayerTransactionRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return nil, errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	result, err := conn.Player().CreatePlayerTransaction(ctx, req)
+	if err != nil {
+		log.Printf("error: create player transaction failed: %v", err.Error())
+		return nil, errors.New(status.Convert(err).Message())
+	}
+
+	return result, nil
+}
+
+func (r *tradeRepository) RollbackPlayerTransaction(pctx context.Context, grpcUrl string, req *playerPb.RollbackPlayerTransactionReq) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := grpcconn.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("error: grpc conn failed: %v", err.Error())
+		return errors.New("error: grpc conn failed")
+	}
+
+	jwtauth.SetApiKeyInContext(&ctx)
+
+	if _, err := conn.Player().RollbackPlayerTransaction(ctx, req); err != nil {
+		log.Printf("error: rollback player transaction failed: %v", err.Error())
+		return errors.New(status.Convert(err).Message())
+	}
+
+	return nil
+}
@@ -0,0 +1,613 @@
+package tradeUsecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/trade"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeRepository"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// staleTradeAfter is how long a trade may sit in processing before
+// RecoverTrades takes it over. Each step refreshes updated_at, so only
+// crashed or failed ones get this old.
+const staleTradeAfter = 5 * time.Minute
+
+type (
+	TradeUsecaseService interface {
+		CreateTrade(pctx context.Context, cfg *config.Config, playerId string, req *trade.CreateTradeReq) (*trade.Trade, error)
+		FindOneTrade(pctx context.Context, playerId, tradeId string) (*trade.Trade, error)
+		FindPlayerTrades(pctx context.Context, playerId string, req *trade.TradeSearchReq) ([]*trade.Trade, error)
+		CounterTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string, req *trade.CounterTradeReq) (*trade.Trade, error)
+		AcceptTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string) (*trade.Trade, error)
+		CancelTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string) (*trade.Trade, error)
+		ExpireTrades(pctx context.Context, cfg *config.Config) error
+		RecoverTrades(pctx context.Context, cfg *config.Config) error
+	}
+
+	tradeUsecase struct {
+		tradeRepository tradeRepository.TradeRepositoryService
+	}
+)
+
+func NewTradeUsecase(tradeRepository tradeRepository.TradeRepositoryService) TradeUsecaseService {
+	return &tradeUsecase{tradeRepository}
+}
+
+func (u *tradeUsecase) CreateTrade(pctx context.Context, cfg *config.Config, playerId string, req *trade.CreateTradeReq) (*trade.Trade, error) {
+	toPlayerId := "player:" + strings.TrimPrefix(req.ToPlayerId, "player:")
+	if toPlayerId == "player:" {
+		return nil, errors.New("error: to_player_id is required")
+	}
+
+	if toPlayerId == playerId {
+		return nil, errors.New("error: cannot trade with yourself")
+	}
+
+	offer, request := toTradeOffer(req.Offer), toTradeOffer(req.Request)
+	if err := validateTerms(offer, request); err != nil {
+		return nil, err
+	}
+
+	expiresIn := req.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 24 * 60
+	}
+
+	escrow, err := u.lockEscrow(pctx, cfg, playerId, offer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	result := &trade.Trade{
+		FromPlayerId: playerId,
+		ToPlayerId:   toPlayerId,
+		FromOffer:    offer,
+		ToOffer:      request,
+		ProposedBy:   playerId,
+		Round:        1,
+		Status:       "pending",
+		Escrow:       escrow,
+		ExpiresAt:    now.Add(time.Duration(expiresIn) * time.Minute),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	tradeId, err := u.tradeRepository.InsertOneTrade(pctx, result)
+	if err != nil {
+		if err := u.releaseEscrow(pctx, cfg, escrow); err != nil {
+			log.Printf("Error: release escrow of player %s failed: %v", playerId, err.Error())
+		}
+		return nil, err
+	}
+	result.Id = tradeId
+
+	return result, nil
+}
+
+func (u *tradeUsecase) FindOneTrade(pctx context.Context, playerId, tradeId string) (*trade.Trade, error) {
+	result, err := u.tradeRepository.FindOneTrade(pctx, tradeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.FromPlayerId != playerId && result.ToPlayerId != playerId {
+		return nil, errors.New("error: trade not found")
+	}
+
+	return result, nil
+}
+
+func (u *tradeUsecase) FindPlayerTrades(pctx context.Context, playerId string, req *trade.TradeSearchReq) ([]*trade.Trade, error) {
+	return u.tradeRepository.FindPlayerTrades(pctx, playerId, req.Status)
+}
+
+// CounterTrade replaces the terms with the countering player's own. Their
+// side is locked and saved with the new terms before the previous
+// proposer's escrow is released.
+func (u *tradeUsecase) CounterTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string, req *trade.CounterTradeReq) (*trade.Trade, error) {
+	result, err := u.findRespondableTrade(pctx, playerId, tradeId)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, request := toTradeOffer(req.Offer), toTradeOffer(req.Request)
+	if err := validateTerms(offer, request); err != nil {
+		return nil, err
+	}
+
+	if err := u.tradeRepository.LockTrade(pctx, tradeId, result.Round); err != nil {
+		return nil, err
+	}
+
+	escrow, err := u.lockEscrow(pctx, cfg, playerId, offer)
+	if err != nil {
+		u.unlockTrade(pctx, tradeId, err)
+		return nil, err
+	}
+
+	settlement := &trade.TradeSettlement{Escrow: escrow}
+	if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+		u.compensate(pctx, cfg, tradeId, settlement, err)
+		return nil, err
+	}
+
+	if playerId == result.FromPlayerId {
+		result.FromOffer, result.ToOffer = offer, request
+	} else {
+		result.FromOffer, result.ToOffer = request, offer
+	}
+	result.ProposedBy = playerId
+	result.Round++
+	result.Status = "processing"
+	result.Settlement = &trade.TradeSettlement{Release: result.Escrow, Status: "pending"}
+	result.Escrow = escrow
+	result.Error = ""
+	result.UpdatedAt = utils.LocalTime()
+
+	if err := u.tradeRepository.UpdateOneTrade(pctx, tradeId, bson.M{
+		"from_offer":  result.FromOffer,
+		"to_offer":    result.ToOffer,
+		"proposed_by": result.ProposedBy,
+		"round":       result.Round,
+		"escrow":      result.Escrow,
+		"settlement":  result.Settlement,
+		"error":       result.Error,
+		"updated_at":  result.UpdatedAt,
+	}); err != nil {
+		u.compensate(pctx, cfg, tradeId, settlement, err)
+		return nil, err
+	}
+
+	// The counter stands from here, a failed release is retried by
+	// RecoverTrades
+	if err := u.finishRelease(pctx, cfg, result); err != nil {
+		log.Printf("Error: release previous escrow of trade %s failed: %v", tradeId, err.Error())
+	}
+
+	return result, nil
+}
+
+// AcceptTrade locks the accepting player's side, then hands each escrow to
+// the other player. Every step is saved on the trade so a failed one can
+// undo exactly what was done and leave the trade pending with the
+// proposer's escrow still held. A trade whose undo fails stays processing
+// until RecoverTrades finishes it.
+func (u *tradeUsecase) AcceptTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string) (*trade.Trade, error) {
+	result, err := u.findRespondableTrade(pctx, playerId, tradeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tradeRepository.LockTrade(pctx, tradeId, result.Round); err != nil {
+		return nil, err
+	}
+
+	proposerEscrow := result.Escrow
+	acceptorEscrow, err := u.lockEscrow(pctx, cfg, playerId, tradeSide(result, playerId))
+	if err != nil {
+		u.unlockTrade(pctx, tradeId, err)
+		return nil, err
+	}
+
+	settlement := &trade.TradeSettlement{Escrow: acceptorEscrow}
+	if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+		u.compensate(pctx, cfg, tradeId, settlement, err)
+		return nil, err
+	}
+
+	// Items
+	exchange := append(
+		escrowGrantItems(proposerEscrow, acceptorEscrow.PlayerId),
+		escrowGrantItems(acceptorEscrow, proposerEscrow.PlayerId)...,
+	)
+	if len(exchange) > 0 {
+		granted, err := u.tradeRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+			Items:             exchange,
+			OverflowToMailbox: true,
+			Source:            "trade",
+		})
+		if err != nil {
+			u.compensate(pctx, cfg, tradeId, settlement, err)
+			return nil, err
+		}
+
+		for _, v := range granted.Items {
+			settlement.Granted = append(settlement.Granted, v.InventoryId)
+		}
+		for _, v := range granted.Mailed {
+			settlement.Mailed = append(settlement.Mailed, v.MailId)
+		}
+
+		if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+			u.compensate(pctx, cfg, tradeId, settlement, err)
+			return nil, err
+		}
+	}
+
+	// Money
+	for _, v := range []struct {
+		playerId string
+		amount   float64
+	}{
+		{acceptorEscrow.PlayerId, proposerEscrow.Money},
+		{proposerEscrow.PlayerId, acceptorEscrow.Money},
+	} {
+		if v.amount == 0 {
+			continue
+		}
+
+		res, err := u.tradeRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: v.playerId,
+			Amount:   v.amount,
+		})
+		if err != nil {
+			u.compensate(pctx, cfg, tradeId, settlement, err)
+			return nil, err
+		}
+
+		settlement.Credits = append(settlement.Credits, res.TransactionId)
+		if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+			u.compensate(pctx, cfg, tradeId, settlement, err)
+			return nil, err
+		}
+	}
+
+	result.Status = "completed"
+	result.Escrow = nil
+	result.Settlement = nil
+	result.Error = ""
+	result.UpdatedAt = utils.LocalTime()
+
+	if err := u.tradeRepository.UpdateOneTrade(pctx, tradeId, bson.M{
+		"status":     result.Status,
+		"escrow":     result.Escrow,
+		"settlement": result.Settlement,
+		"error":      result.Error,
+		"updated_at": result.UpdatedAt,
+	}); err != nil {
+		u.compensate(pctx, cfg, tradeId, settlement, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CancelTrade lets either player walk away from a pending trade.
+func (u *tradeUsecase) CancelTrade(pctx context.Context, cfg *config.Config, playerId, tradeId string) (*trade.Trade, error) {
+	result, err := u.FindOneTrade(pctx, playerId, tradeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status != "pending" {
+		return nil, errors.New("error: trade is no longer pending")
+	}
+
+	if err := u.closeTrade(pctx, cfg, result, "cancelled"); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExpireTrades releases the escrow of pending trades past their expiry.
+// Trades whose release fails stay processing and are finished by
+// RecoverTrades.
+func (u *tradeUsecase) ExpireTrades(pctx context.Context, cfg *config.Config) error {
+	results, err := u.tradeRepository.FindExpiredTrades(pctx, utils.LocalTime())
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if err := u.closeTrade(pctx, cfg, result, "expired"); err != nil {
+			log.Printf("Error: expire trade %s failed: %v", result.Id.Hex(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// RecoverTrades finishes trades left processing by a crash or a failed
+// compensation. An owed escrow is released, anything else done so far is
+// undone and the trade goes back to pending.
+func (u *tradeUsecase) RecoverTrades(pctx context.Context, cfg *config.Config) error {
+	results, err := u.tradeRepository.FindStaleTrades(pctx, utils.LocalTime().Add(-staleTradeAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		tradeId := result.Id.Hex()
+
+		if err := u.tradeRepository.ClaimStaleTrade(pctx, tradeId, result.UpdatedAt); err != nil {
+			continue
+		}
+
+		if result.Settlement != nil && result.Settlement.Release != nil {
+			if err := u.finishRelease(pctx, cfg, result); err != nil {
+				log.Printf("Error: recover trade %s failed: %v", tradeId, err.Error())
+			}
+			continue
+		}
+
+		settlement := result.Settlement
+		if settlement == nil {
+			settlement = new(trade.TradeSettlement)
+		}
+
+		if err := u.compensate(pctx, cfg, tradeId, settlement, errors.New("error: trade was interrupted")); err != nil {
+			log.Printf("Error: recover trade %s failed: %v", tradeId, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// closeTrade hands the escrow back and moves the trade to status. The escrow
+// is saved as owed first so a failed release is retried by RecoverTrades
+// instead of being given back twice.
+func (u *tradeUsecase) closeTrade(pctx context.Context, cfg *config.Config, result *trade.Trade, status string) error {
+	tradeId := result.Id.Hex()
+
+	if err := u.tradeRepository.LockTrade(pctx, tradeId, result.Round); err != nil {
+		return err
+	}
+
+	result.Status = "processing"
+	result.Settlement = &trade.TradeSettlement{Release: result.Escrow, Status: status}
+	result.Escrow = nil
+	result.UpdatedAt = utils.LocalTime()
+
+	if err := u.tradeRepository.UpdateOneTrade(pctx, tradeId, bson.M{
+		"escrow":     result.Escrow,
+		"settlement": result.Settlement,
+		"updated_at": result.UpdatedAt,
+	}); err != nil {
+		u.unlockTrade(pctx, tradeId, err)
+		return err
+	}
+
+	return u.finishRelease(pctx, cfg, result)
+}
+
+// finishRelease returns the escrow a trade still owes and moves it on to
+// the settlement's status.
+func (u *tradeUsecase) finishRelease(pctx context.Context, cfg *config.Config, result *trade.Trade) error {
+	if err := u.releaseEscrow(pctx, cfg, result.Settlement.Release); err != nil {
+		return err
+	}
+
+	result.Status = result.Settlement.Status
+	result.Settlement = nil
+	result.UpdatedAt = utils.LocalTime()
+
+	return u.tradeRepository.UpdateOneTrade(pctx, result.Id.Hex(), bson.M{
+		"status":     result.Status,
+		"settlement": result.Settlement,
+		"updated_at": result.UpdatedAt,
+	})
+}
+
+// compensate undoes a processing step in reverse: credits, the exact copies
+// granted, then the responding player's escrow. Progress is saved after
+// each part so a failure leaves the trade processing with only what is left
+// to undo, for RecoverTrades to retry.
+func (u *tradeUsecase) compensate(pctx context.Context, cfg *config.Config, tradeId string, settlement *trade.TradeSettlement, cause error) error {
+	fail := func(err error) error {
+		log.Printf("Error: compensate trade %s failed: %v", tradeId, err.Error())
+		if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+			log.Printf("Error: save settlement of trade %s failed: %v", tradeId, err.Error())
+		}
+		return err
+	}
+
+	if len(settlement.Credits) > 0 {
+		for _, transactionId := range settlement.Credits {
+			if err := u.tradeRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+				TransactionId: transactionId,
+			}); err != nil {
+				return fail(err)
+			}
+		}
+		settlement.Credits = nil
+	}
+
+	if len(settlement.Granted) > 0 || len(settlement.Mailed) > 0 {
+		if _, err := u.tradeRepository.BatchRevoke(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{
+			InventoryIds: settlement.Granted,
+			MailIds:      settlement.Mailed,
+		}); err != nil {
+			return fail(err)
+		}
+		settlement.Granted, settlement.Mailed = nil, nil
+
+		if err := u.saveSettlement(pctx, tradeId, settlement); err != nil {
+			return fail(err)
+		}
+	}
+
+	if settlement.Escrow != nil {
+		if err := u.releaseEscrow(pctx, cfg, settlement.Escrow); err != nil {
+			return fail(err)
+		}
+		settlement.Escrow = nil
+	}
+
+	u.unlockTrade(pctx, tradeId, cause)
+	return nil
+}
+
+func (u *tradeUsecase) saveSettlement(pctx context.Context, tradeId string, settlement *trade.TradeSettlement) error {
+	return u.tradeRepository.UpdateOneTrade(pctx, tradeId, bson.M{
+		"settlement": settlement,
+		"updated_at": utils.LocalTime(),
+	})
+}
+
+// findRespondableTrade returns a pending trade waiting on playerId.
+func (u *tradeUsecase) findRespondableTrade(pctx context.Context, playerId, tradeId string) (*trade.Trade, error) {
+	result, err := u.FindOneTrade(pctx, playerId, tradeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status != "pending" {
+		return nil, errors.New("error: trade is no longer pending")
+	}
+
+	if !utils.LocalTime().Before(result.ExpiresAt) {
+		return nil, errors.New("error: trade has expired")
+	}
+
+	if result.ProposedBy == playerId {
+		return nil, errors.New("error: waiting for the other player")
+	}
+
+	return result, nil
+}
+
+func (u *tradeUsecase) unlockTrade(pctx context.Context, tradeId string, cause error) {
+	if err := u.tradeRepository.UpdateOneTrade(pctx, tradeId, bson.M{
+		"status":     "pending",
+		"settlement": nil,
+		"error":      cause.Error(),
+		"updated_at": utils.LocalTime(),
+	}); err != nil {
+		log.Printf("Error: unlock trade %s failed: %v", tradeId, err.Error())
+	}
+}
+
+// lockEscrow takes the offered items and money away from a player until the
+// trade completes or the escrow is released.
+func (u *tradeUsecase) lockEscrow(pctx context.Context, cfg *config.Config, playerId string, offer *trade.TradeOffer) (*trade.TradeEscrow, error) {
+	escrow := &trade.TradeEscrow{
+		PlayerId: playerId,
+		Items:    make([]*trade.TradeEscrowItem, 0),
+		Money:    offer.Money,
+	}
+
+	if len(offer.ItemIds) > 0 {
+		revokeReq := &inventoryPb.BatchRevokeReq{
+			Items: make([]*inventoryPb.GrantItem, 0),
+		}
+		quantities := make(map[string]*inventoryPb.GrantItem)
+		for _, itemId := range offer.ItemIds {
+			if v, ok := quantities[itemId]; ok {
+				v.Quantity++
+				continue
+			}
+
+			quantities[itemId] = &inventoryPb.GrantItem{PlayerId: playerId, ItemId: itemId, Quantity: 1}
+			revokeReq.Items = append(revokeReq.Items, quantities[itemId])
+		}
+
+		revoked, err := u.tradeRepository.BatchRevoke(pctx, cfg.Grpc.InventoryUrl, revokeReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range revoked.Items {
+			escrow.Items = append(escrow.Items, &trade.TradeEscrowItem{
+				InventoryId: v.InventoryId,
+				ItemId:      v.ItemId,
+				Instance:    inventory.InstanceFromPb(v.Instance),
+			})
+		}
+	}
+
+	if offer.Money > 0 {
+		res, err := u.tradeRepository.CreatePlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+			PlayerId: playerId,
+			Amount:   -offer.Money,
+		})
+		if err != nil {
+			escrow.Money = 0
+			if err := u.releaseEscrow(pctx, cfg, escrow); err != nil {
+				log.Printf("Error: release escrow of player %s failed: %v", playerId, err.Error())
+			}
+			return nil, err
+		}
+
+		escrow.TransactionId = res.TransactionId
+	}
+
+	return escrow, nil
+}
+
+// releaseEscrow gives the escrowed items and money back to their owner.
+func (u *tradeUsecase) releaseEscrow(pctx context.Context, cfg *config.Config, escrow *trade.TradeEscrow) error {
+	if escrow == nil {
+		return nil
+	}
+
+	if items := escrowGrantItems(escrow, escrow.PlayerId); len(items) > 0 {
+		if _, err := u.tradeRepository.BatchGrant(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{Items: items, OverflowToMailbox: true}); err != nil {
+			return err
+		}
+	}
+
+	if escrow.TransactionId != "" {
+		if err := u.tradeRepository.RollbackPlayerTransaction(pctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{
+			TransactionId: escrow.TransactionId,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func escrowGrantItems(escrow *trade.TradeEscrow, playerId string) []*inventoryPb.GrantItem {
+	items := make([]*inventoryPb.GrantItem, 0)
+	for _, v := range escrow.Items {
+		items = append(items, &inventoryPb.GrantItem{PlayerId: playerId, ItemId: v.ItemId, Quantity: 1, Instance: inventory.InstanceToPb(v.Instance)})
+	}
+	return items
+}
+
+// tradeSide returns what playerId gives under the current terms.
+func tradeSide(t *trade.Trade, playerId string) *trade.TradeOffer {
+	if playerId == t.FromPlayerId {
+		return t.FromOffer
+	}
+	return t.ToOffer
+}
+
+func toTradeOffer(req *trade.TradeOfferReq) *trade.TradeOffer {
+	if req == nil {
+		return &trade.TradeOffer{ItemIds: make([]string, 0)}
+	}
+
+	itemIds := make([]string, 0)
+	for _, itemId := range req.ItemIds {
+		if itemId = strings.TrimSpace(itemId); itemId != "" {
+			itemIds = append(itemIds, itemId)
+		}
+	}
+
+	return &trade.TradeOffer{ItemIds: itemIds, Money: req.Money}
+}
+
+func validateTerms(offer, request *trade.TradeOffer) error {
+	if offer.Money < 0 || request.Money < 0 {
+		return errors.New("error: money must not be negative")
+	}
+
+	if len(offer.ItemIds) == 0 && offer.Money == 0 && len(request.ItemIds) == 0 && request.Money == 0 {
+		return errors.New("error: trade has nothing to exchange")
+	}
+
+	return nil
+}
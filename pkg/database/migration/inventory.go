@@ -8,6 +8,7 @@ import (
 	"github.com/Supakornn/mmorpg-shop/pkg/database"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func InventoryDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -23,12 +24,51 @@ func InventoryMigrate(pctx context.Context, cfg *config.Config) {
 	col := db.Collection("inventories")
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "item_id", Value: 1}}},
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "equipped_slot", Value: 1}}},
+		{Keys: bson.D{{Key: "serial", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetSparse(true)},
 	})
 
 	for _, index := range indexs {
 		log.Printf("index: %s created", index)
 	}
 
+	// Cooldowns
+	cooldownIndexs, _ := db.Collection("item_cooldowns").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "item_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range cooldownIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Capacities
+	capacityIndexs, _ := db.Collection("inventory_capacities").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range capacityIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Mailbox
+	mailIndexs, _ := db.Collection("mailbox").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}}},
+	})
+
+	for _, index := range mailIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Inventory References
+	referenceIndexs, _ := db.Collection("inventory_references").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "reference_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range referenceIndexs {
+		log.Printf("index: %s created", index)
+	}
+
 	col = db.Collection("player_inventory_queue")
 
 	results, err := col.InsertOne(pctx, bson.M{"offset": -1})
@@ -10,6 +10,7 @@ import (
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func ItemDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -26,16 +27,79 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "_id", Value: 1}}},
 		{Keys: bson.D{{Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "sku", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
 	})
 
 	for _, index := range indexs {
 		log.Printf("index: %s created", index)
 	}
 
+	// Backfill SKUs of items created before SKUs existed from their titles
+	backfill, err := col.UpdateMany(pctx, bson.M{"sku": bson.M{"$exists": false}}, bson.A{
+		bson.M{"$set": bson.M{"sku": bson.M{"$replaceAll": bson.M{
+			"input":       bson.M{"$toUpper": bson.M{"$trim": bson.M{"input": "$title"}}},
+			"find":        " ",
+			"replacement": "-",
+		}}}},
+	})
+	if err != nil {
+		log.Printf("error: backfill item skus: %s", err.Error())
+	} else {
+		log.Printf("backfilled %d item skus", backfill.ModifiedCount)
+	}
+
+	// Item Purchases
+	purchaseIndexs, _ := db.Collection("item_purchases").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "item_id", Value: 1}, {Key: "player_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range purchaseIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Item Revisions
+	revisionIndexs, _ := db.Collection("item_revisions").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "item_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+
+	for _, index := range revisionIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Recipes
+	recipeIndexs, _ := db.Collection("recipes").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "usage_status", Value: 1}, {Key: "title", Value: 1}}},
+	})
+
+	for _, index := range recipeIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Wishlists
+	wishlistIndexs, _ := db.Collection("wishlists").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "item_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "item_id", Value: 1}}},
+	})
+
+	for _, index := range wishlistIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Wishlist Notifications
+	wishlistNotificationIndexs, _ := db.Collection("wishlist_notifications").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "revision_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range wishlistNotificationIndexs {
+		log.Printf("index: %s created", index)
+	}
+
 	// Items Datas
 	documents := func() []any {
 		items := []*item.Item{
 			{
+				Sku:         "SWORD",
 				Title:       "Sword",
 				Price:       100,
 				Damage:      10,
@@ -45,6 +109,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "SHIELD",
 				Title:       "Shield",
 				Price:       100,
 				Damage:      10,
@@ -54,6 +119,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "HELMET",
 				Title:       "Helmet",
 				Price:       100,
 				Damage:      10,
@@ -63,6 +129,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "ARMOR",
 				Title:       "Armor",
 				Price:       100,
 				Damage:      10,
@@ -72,6 +139,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "BOOTS",
 				Title:       "Boots",
 				Price:       100,
 				Damage:      10,
@@ -81,6 +149,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "GLOVES",
 				Title:       "Gloves",
 				Price:       100,
 				Damage:      10,
@@ -90,6 +159,7 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 				UpdatedAt:   utils.LocalTime(),
 			},
 			{
+				Sku:         "RING",
 				Title:       "Ring",
 				Price:       100,
 				Damage:      10,
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"context"
+	"log"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/pkg/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func MarketplaceDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
+	return database.DbConn(pctx, cfg).Database("marketplace_db")
+}
+
+func MarketplaceMigrate(pctx context.Context, cfg *config.Config) {
+	db := MarketplaceDbConn(pctx, cfg)
+	defer db.Client().Disconnect(pctx)
+
+	col := db.Collection("listings")
+
+	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+		{Keys: bson.D{{Key: "item_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("marketplace_queue")
+
+	results, err := col.InsertOne(pctx, bson.M{"offset": -1})
+	if err != nil {
+		panic(err)
+	}
+
+	log.Println("Migrate marketplace completed", results)
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"context"
+	"log"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/pkg/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func NotificationDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
+	return database.DbConn(pctx, cfg).Database("notification_db")
+}
+
+func NotificationMigrate(pctx context.Context, cfg *config.Config) {
+	db := NotificationDbConn(pctx, cfg)
+	defer db.Client().Disconnect(pctx)
+
+	col := db.Collection("notifications")
+
+	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "offset", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "read", Value: 1}, {Key: "_id", Value: -1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("notification_queue")
+
+	results, err := col.InsertOne(pctx, bson.M{"offset": -1})
+	if err != nil {
+		panic(err)
+	}
+
+	log.Println("Migrate notification completed", results)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Supakornn/mmorpg-shop/pkg/database"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func PaymentDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -25,5 +26,66 @@ func PaymentMigrate(pctx context.Context, cfg *config.Config) {
 		panic(err)
 	}
 
+	// Payment Orders
+	orderIndexs, _ := db.Collection("payment_orders").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range orderIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Gifts
+	giftIndexs, _ := db.Collection("gifts").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sender_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range giftIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	notificationIndexs, _ := db.Collection("gift_notifications").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "recipient_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range notificationIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	giftLimitIndexs, _ := db.Collection("gift_limits").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range giftLimitIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Loot boxes
+	lootRollIndexs, _ := db.Collection("loot_rolls").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "box_item_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range lootRollIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	lootPityIndexs, _ := db.Collection("loot_pity").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "box_item_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range lootPityIndexs {
+		log.Printf("index: %s created", index)
+	}
+
+	// Crafting
+	craftIndexs, _ := db.Collection("crafts").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range craftIndexs {
+		log.Printf("index: %s created", index)
+	}
+
 	log.Println("Migrate payment completed", results)
 }
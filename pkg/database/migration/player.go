@@ -10,6 +10,7 @@ import (
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -27,6 +28,10 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "_id", Value: 1}}},
 		{Keys: bson.D{{Key: "player_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "reference_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"reference_id": bson.M{"$exists": true}}),
+		},
 	})
 
 	for _, index := range indexs {
@@ -0,0 +1,74 @@
+package migration
+
+import (
+	"context"
+	"log"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/pkg/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func RewardDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
+	return database.DbConn(pctx, cfg).Database("reward_db")
+}
+
+func RewardMigrate(pctx context.Context, cfg *config.Config) {
+	db := RewardDbConn(pctx, cfg)
+	defer db.Client().Disconnect(pctx)
+
+	col := db.Collection("reward_calendars")
+
+	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "usage_status", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("reward_claims")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "calendar_id", Value: 1}, {Key: "period", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("promo_codes")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "campaign", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("promo_redemptions")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "code", Value: 1}, {Key: "player_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "campaign", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("reward_queue")
+
+	results, err := col.InsertOne(pctx, bson.M{"offset": -1})
+	if err != nil {
+		panic(err)
+	}
+
+	log.Println("Migrate reward completed", results)
+}
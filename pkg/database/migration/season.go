@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"context"
+	"log"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/pkg/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func SeasonDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
+	return database.DbConn(pctx, cfg).Database("season_db")
+}
+
+func SeasonMigrate(pctx context.Context, cfg *config.Config) {
+	db := SeasonDbConn(pctx, cfg)
+	defer db.Client().Disconnect(pctx)
+
+	col := db.Collection("seasons")
+
+	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "ends_at", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("season_passes")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "season_id", Value: 1}, {Key: "player_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("season_passes_archive")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "season_id", Value: 1}, {Key: "player_id", Value: 1}}},
+		{Keys: bson.D{{Key: "player_id", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("season_xp_events")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "season_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"event_id": bson.M{"$exists": true}}),
+		},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	log.Println("Migrate season completed")
+}
@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"context"
+	"log"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/pkg/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func SubscriptionDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
+	return database.DbConn(pctx, cfg).Database("subscription_db")
+}
+
+func SubscriptionMigrate(pctx context.Context, cfg *config.Config) {
+	db := SubscriptionDbConn(pctx, cfg)
+	defer db.Client().Disconnect(pctx)
+
+	col := db.Collection("subscriptions")
+
+	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "player_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"live": true}),
+		},
+		{Keys: bson.D{{Key: "live", Value: 1}, {Key: "next_renewal_at", Value: 1}}},
+		{Keys: bson.D{{Key: "provider_ref", Value: 1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	col = db.Collection("subscription_charges")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	for _, index := range indexs {
+		log.Printf("index: %s created", index)
+	}
+
+	log.Println("Migrate subscription completed")
+}
@@ -33,5 +33,17 @@ func main() {
 		migration.InventoryMigrate(ctx, &cfg)
 	case "payment":
 		migration.PaymentMigrate(ctx, &cfg)
+	case "trade":
+		migration.TradeMigrate(ctx, &cfg)
+	case "marketplace":
+		migration.MarketplaceMigrate(ctx, &cfg)
+	case "reward":
+		migration.RewardMigrate(ctx, &cfg)
+	case "season":
+		migration.SeasonMigrate(ctx, &cfg)
+	case "subscription":
+		migration.SubscriptionMigrate(ctx, &cfg)
+	case "notification":
+		migration.NotificationMigrate(ctx, &cfg)
 	}
 }
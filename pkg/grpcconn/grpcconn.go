@@ -13,8 +13,12 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	authPb "github.com/Supakornn/mmorpg-shop/modules/auth/authPb"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	paymentPb "github.com/Supakornn/mmorpg-shop/modules/payment/paymentPb"
 	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 )
 
 type (
@@ -22,6 +26,10 @@ type (
 		Auth() authPb.AuthGrpcServiceClient
 		Player() playerPb.PlayerGrpcServiceClient
 		Item() itemPb.ItemGrpcServiceClient
+		Inventory() inventoryPb.InventoryGrpcServiceClient
+		Payment() paymentPb.PaymentGrpcServiceClient
+		Season() seasonPb.SeasonGrpcServiceClient
+		Subscription() subscriptionPb.SubscriptionGrpcServiceClient
 	}
 
 	grpcClientFactory struct {
@@ -74,6 +82,22 @@ func (g *grpcClientFactory) Item() itemPb.ItemGrpcServiceClient {
 	return itemPb.NewItemGrpcServiceClient(g.client)
 }
 
+func (g *grpcClientFactory) Inventory() inventoryPb.InventoryGrpcServiceClient {
+	return inventoryPb.NewInventoryGrpcServiceClient(g.client)
+}
+
+func (g *grpcClientFactory) Payment() paymentPb.PaymentGrpcServiceClient {
+	return paymentPb.NewPaymentGrpcServiceClient(g.client)
+}
+
+func (g *grpcClientFactory) Season() seasonPb.SeasonGrpcServiceClient {
+	return seasonPb.NewSeasonGrpcServiceClient(g.client)
+}
+
+func (g *grpcClientFactory) Subscription() subscriptionPb.SubscriptionGrpcServiceClient {
+	return subscriptionPb.NewSubscriptionGrpcServiceClient(g.client)
+}
+
 func NewGrpcClient(host string) (GrpcClientFactoryHandler, error) {
 	opts := make([]grpc.DialOption, 0)
 
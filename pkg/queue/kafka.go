@@ -10,7 +10,7 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-func ConnectProducer(brokerUrls []string, apiKey, secret string) (sarama.SyncProducer, error) {
+func newConfig(apiKey, secret string) *sarama.Config {
 	config := sarama.NewConfig()
 	if apiKey != "" && secret != "" {
 		config.Net.SASL.Enable = true
@@ -26,6 +26,12 @@ func ConnectProducer(brokerUrls []string, apiKey, secret string) (sarama.SyncPro
 		}
 	}
 
+	return config
+}
+
+func ConnectProducer(brokerUrls []string, apiKey, secret string) (sarama.SyncProducer, error) {
+	config := newConfig(apiKey, secret)
+
 	config.Producer.Return.Successes = true
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Retry.Max = 3
@@ -66,20 +72,7 @@ func PushMessageWithKeyToQueue(brokerUrls []string, apiKey, secret, topic, key s
 }
 
 func ConnectConsumer(brokerUrls []string, apiKey, secret string) (sarama.Consumer, error) {
-	config := sarama.NewConfig()
-	if apiKey != "" && secret != "" {
-		config.Net.SASL.Enable = true
-		config.Net.SASL.User = apiKey
-		config.Net.SASL.Password = secret
-		config.Net.SASL.Mechanism = "PLAIN"
-		config.Net.SASL.Handshake = true
-		config.Net.SASL.Version = sarama.SASLHandshakeV1
-		config.Net.TLS.Enable = true
-		config.Net.TLS.Config = &tls.Config{
-			InsecureSkipVerify: true,
-			ClientAuth:         tls.NoClientCert,
-		}
-	}
+	config := newConfig(apiKey, secret)
 
 	config.Consumer.Return.Errors = true
 	config.Consumer.Fetch.Max = 3
@@ -93,6 +86,25 @@ func ConnectConsumer(brokerUrls []string, apiKey, secret string) (sarama.Consume
 	return consumer, nil
 }
 
+// GetNewestOffset returns the offset the next message of a topic partition
+// will get.
+func GetNewestOffset(brokerUrls []string, apiKey, secret, topic string, partition int32) (int64, error) {
+	client, err := sarama.NewClient(brokerUrls, newConfig(apiKey, secret))
+	if err != nil {
+		log.Printf("error: failed to connect to kafka: %v", err)
+		return -1, errors.New("error: failed to connect to kafka")
+	}
+	defer client.Close()
+
+	offset, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		log.Printf("error: failed to get newest offset: %v", err)
+		return -1, errors.New("error: failed to get newest offset")
+	}
+
+	return offset, nil
+}
+
 func DecodeMessage(obj any, value []byte) error {
 	if err := json.Unmarshal(value, obj); err != nil {
 		log.Printf("error: failed to decode message: %v", err)
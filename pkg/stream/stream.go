@@ -0,0 +1,53 @@
+package stream
+
+import "sync"
+
+// Hub fans messages out to the connections subscribed to a key, usually a
+// player id. A subscriber that is too slow to keep up misses messages rather
+// than blocking the publisher.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[string]map[chan []byte]struct{})}
+}
+
+// Subscribe returns a channel of messages for key and a function that
+// unsubscribes and closes it.
+func (h *Hub) Subscribe(key string) (<-chan []byte, func()) {
+	ch := make(chan []byte, 16)
+
+	h.mu.Lock()
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[chan []byte]struct{})
+	}
+	h.subscribers[key][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[key], ch)
+			if len(h.subscribers[key]) == 0 {
+				delete(h.subscribers, key)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (h *Hub) Publish(key string, message []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[key] {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+}
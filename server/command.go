@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/item/itemHandler"
+	"github.com/Supakornn/mmorpg-shop/modules/item/itemRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/item/itemUsecase"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// RunCommand runs a one-off service command instead of starting the server,
+// e.g. go run main.go ./env/dev/.env.item import -dry-run items.csv
+func RunCommand(pctx context.Context, cfg *config.Config, db *mongo.Client, args []string) error {
+	switch cfg.App.Name {
+	case "item":
+		repo := itemRepository.NewItemRepository(db)
+		usecase := itemUsecase.NewItemUsecase(repo)
+		cliHandler := itemHandler.NewItemCliHandler(usecase)
+
+		switch args[0] {
+		case "import":
+			return cliHandler.ImportItems(pctx, args[1:])
+		case "export":
+			return cliHandler.ExportItems(pctx, args[1:])
+		}
+	}
+
+	return fmt.Errorf("error: unknown command for %s service: %s", cfg.App.Name, args[0])
+}
@@ -1,9 +1,15 @@
 package server
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryHandler"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
 )
 
 func (s *server) inventoryService() {
@@ -11,6 +17,29 @@ func (s *server) inventoryService() {
 	usecase := inventoryUsecase.NewInventoryUsecase(repo)
 	httpHandler := inventoryHandler.NewInventoryHttpHandler(s.cfg, usecase)
 	queueHandler := inventoryHandler.NewInventoryQueueHandler(s.cfg, usecase)
+	grpcHandler := inventoryHandler.NewInventoryGrpcHandler(s.cfg, usecase)
+
+	// gRPC
+	go func() {
+		grpcServer, lis := grpcconn.NewGrpcServer(&s.cfg.Jwt, s.cfg.Grpc.InventoryUrl)
+
+		inventoryPb.RegisterInventoryGrpcServiceServer(grpcServer, grpcHandler)
+
+		s.app.Logger.Infof("Inventory gRPC server is running on %s", s.cfg.Grpc.InventoryUrl)
+		grpcServer.Serve(lis)
+	}()
+
+	// Remove expired items
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.SweepExpiredItems(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: sweep expired items failed: %v", err.Error())
+			}
+		}
+	}()
 
 	go queueHandler.AddPlayerItem()
 	go queueHandler.RemovePlayerItem()
@@ -19,6 +48,15 @@ func (s *server) inventoryService() {
 
 	inventory := s.app.Group("/inventory_v1")
 
-	inventory.GET("", s.healthCheckService)                                                                               // Health check
-	inventory.GET("/inventory/:player_id", httpHandler.FindPlayerItems, s.mid.JwtAuthorization, s.mid.PlayerIdValidation) // Find Player Items
+	inventory.GET("", s.healthCheckService)                                                                                               // Health check
+	inventory.GET("/inventory/:player_id", httpHandler.FindPlayerItems, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)                 // Find Player Items
+	inventory.POST("/inventory/:player_id/use/:inventory_id", httpHandler.UseItem, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)      // Use Consumable
+	inventory.GET("/mailbox", httpHandler.FindPlayerMails, s.mid.JwtAuthorization)                                                        // Find Player Mails
+	inventory.POST("/mailbox/:mail_id/claim", httpHandler.ClaimMail, s.mid.JwtAuthorization)                                              // Claim Mail
+	inventory.GET("/equipment", httpHandler.GetLoadout, s.mid.JwtAuthorization)                                                           // Equipped Items
+	inventory.POST("/equipment/equip", httpHandler.EquipItem, s.mid.JwtAuthorization)                                                     // Equip Item
+	inventory.POST("/equipment/unequip", httpHandler.UnequipItem, s.mid.JwtAuthorization)                                                 // Unequip Item
+	inventory.GET("/items/:inventory_id", httpHandler.FindOneItemInstance, s.mid.JwtAuthorization)                                        // Find Item Instance
+	inventory.POST("/items/:inventory_id/bind", httpHandler.BindItem, s.mid.JwtAuthorization)                                             // Bind Item
+	inventory.PATCH("/items/:inventory_id", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.UpdateItemInstance, []int{1, 0}))) // Update Item Instance
 }
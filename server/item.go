@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/Supakornn/mmorpg-shop/modules/item/itemHandler"
 	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/item/itemRepository"
@@ -26,12 +30,39 @@ func (s *server) itemService() {
 
 	_ = grpcHandler
 
+	// Wishlist watcher
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.WatchWishlists(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: watch wishlists failed: %v", err.Error())
+			}
+		}
+	}()
+
 	item := s.app.Group("/item_v1")
 
 	item.GET("", s.healthCheckService)                                                                                                          // Health check
 	item.POST("/item", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateItem, []int{1, 0})))                                    // Create Item
+	item.POST("/item/bundle", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateBundle, []int{1, 0})))                           // Create Bundle
+	item.POST("/item/lootbox", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateLootBox, []int{1, 0})))                         // Create Loot Box
+	item.GET("/item/:item_id/odds", httpHandler.FindLootBoxOdds)                                                                                // Find Loot Box Odds
 	item.GET("/item/:item_id", httpHandler.FindOneItem)                                                                                         // Find One Item
+	item.POST("/items/import", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.ImportItems, []int{1, 0})))                           // Import Items
+	item.GET("/items/export", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.ExportItems, []int{1, 0})))                            // Export Items
 	item.GET("/items", httpHandler.FindManyItems)                                                                                               // Find Many Items
 	item.PATCH("/item/:item_id", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.EditItem, []int{1, 0})))                            // Edit Item
 	item.PATCH("/item/:item_id/toggle-status", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.ToggleItemUsageStatus, []int{1, 0}))) // Toggle Item Usage Status
+	item.GET("/item/:item_id/history", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.FindItemHistory, []int{1, 0})))               // Find Item History
+	item.GET("/item/:item_id/price", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.FindItemPriceAt, []int{1, 0})))                 // Find Item Price At
+	item.GET("/item/:item_id/my_price", httpHandler.FindMyItemPrice, s.mid.JwtAuthorization)                                                    // Find Item Price For Me
+	item.POST("/recipe", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateRecipe, []int{1, 0})))                                // Create Recipe
+	item.GET("/recipe/:recipe_id", httpHandler.FindOneRecipe)                                                                                   // Find One Recipe
+	item.GET("/recipes", httpHandler.FindManyRecipes)                                                                                           // Find Many Recipes
+	item.GET("/wishlist/:player_id", httpHandler.FindWishlist, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)                                // Find Wishlist
+	item.POST("/wishlist/:player_id", httpHandler.AddWishlistItem, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)                            // Add Wishlist Item
+	item.DELETE("/wishlist/:player_id/:item_id", httpHandler.RemoveWishlistItem, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)              // Remove Wishlist Item
+	item.GET("/wishlist/:player_id/notifications", httpHandler.FindWishlistNotifications, s.mid.JwtAuthorization, s.mid.PlayerIdValidation)     // Find Wishlist Notifications
 }
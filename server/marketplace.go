@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceHandler"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceUsecase"
+)
+
+func (s *server) marketplaceService() {
+	repo := marketplaceRepository.NewMarketplaceRepository(s.db)
+	usecase := marketplaceUsecase.NewMarketplaceUsecase(repo)
+	httpHandler := marketplaceHandler.NewMarketplaceHttpHandler(s.cfg, usecase)
+	queueHandler := marketplaceHandler.NewMarketplaceQueueHandler(s.cfg, usecase)
+
+	// Queue expired listings for settlement and finish interrupted ones
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.QueueExpiredListings(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: queue expired listings failed: %v", err.Error())
+			}
+			if err := usecase.RecoverListings(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: recover listings failed: %v", err.Error())
+			}
+		}
+	}()
+
+	go queueHandler.SettleListing()
+
+	marketplace := s.app.Group("/marketplace_v1")
+
+	marketplace.GET("", s.healthCheckService)                                                          // Health check
+	marketplace.POST("/listing", httpHandler.CreateListing, s.mid.JwtAuthorization)                    // List an item
+	marketplace.GET("/listings", httpHandler.FindListings)                                             // Browse listings
+	marketplace.GET("/listing/:listing_id", httpHandler.FindOneListing)                                // Find one listing
+	marketplace.POST("/listing/:listing_id/buy", httpHandler.BuyListing, s.mid.JwtAuthorization)       // Buy a fixed price listing
+	marketplace.POST("/listing/:listing_id/bid", httpHandler.PlaceBid, s.mid.JwtAuthorization)         // Bid on an auction
+	marketplace.POST("/listing/:listing_id/cancel", httpHandler.CancelListing, s.mid.JwtAuthorization) // Cancel a listing without bids
+}
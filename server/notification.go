@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationHandler"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationUsecase"
+)
+
+func (s *server) notificationService() {
+	repo := notificationRepository.NewNotificationRepository(s.db)
+	usecase := notificationUsecase.NewNotificationUsecase(repo)
+	httpHandler := notificationHandler.NewNotificationHttpHandler(s.cfg, usecase)
+	queueHandler := notificationHandler.NewNotificationQueueHandler(s.cfg, usecase)
+
+	go queueHandler.StoreEvents()
+	go queueHandler.PushSagaSteps()
+
+	notification := s.app.Group("/notification_v1")
+
+	notification.GET("", s.healthCheckService)                                                              // Health check
+	notification.GET("/notifications", httpHandler.FindNotifications, s.mid.JwtAuthorization)               // Player's notifications
+	notification.GET("/notifications/unread_count", httpHandler.CountUnread, s.mid.JwtAuthorization)        // Unread notifications count
+	notification.POST("/notifications/read_all", httpHandler.MarkAllRead, s.mid.JwtAuthorization)           // Mark all as read
+	notification.POST("/notifications/:notification_id/read", httpHandler.MarkRead, s.mid.JwtAuthorization) // Mark one as read
+	notification.GET("/notifications/stream", httpHandler.Stream, s.mid.JwtAuthorization)                   // Server-sent events stream
+	notification.GET("/ws", httpHandler.Updates, s.mid.JwtAuthorization)                                    // Real-time updates over WebSocket
+}
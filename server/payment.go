@@ -1,20 +1,57 @@
 package server
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentHandler"
+	paymentPb "github.com/Supakornn/mmorpg-shop/modules/payment/paymentPb"
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
 )
 
 func (s *server) paymentService() {
 	repo := paymentRepository.NewPaymentRepository(s.db)
 	usecase := paymentUsecase.NewPaymentUsecase(repo)
 	httpHandler := paymentHandler.NewPaymentHttpHandler(s.cfg, usecase)
+	grpcHandler := paymentHandler.NewPaymentGrpcHandler(s.cfg, usecase)
+
+	// gRPC
+	go func() {
+		grpcServer, lis := grpcconn.NewGrpcServer(&s.cfg.Jwt, s.cfg.Grpc.PaymentUrl)
+
+		paymentPb.RegisterPaymentGrpcServiceServer(grpcServer, grpcHandler)
+
+		s.app.Logger.Infof("Payment gRPC server is running on %s", s.cfg.Grpc.PaymentUrl)
+		grpcServer.Serve(lis)
+	}()
+
+	// Fail orders whose saga stopped, e.g. on a restart
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.RecoverOrders(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: recover orders failed: %v", err.Error())
+			}
+		}
+	}()
 
 	payment := s.app.Group("/payment_v1")
 
 	// Health check
 	payment.GET("", s.healthCheckService)
 	payment.POST("/payment/buy", httpHandler.BuyItem, s.mid.JwtAuthorization)
+	payment.GET("/orders/:order_id/status", httpHandler.FindOrderStatus, s.mid.JwtAuthorization)
 	payment.POST("/payment/sell", httpHandler.SellItem, s.mid.JwtAuthorization)
+	payment.POST("/payment/gift", httpHandler.GiftItem, s.mid.JwtAuthorization)
+	payment.GET("/payment/gifts/received", httpHandler.FindGiftNotifications, s.mid.JwtAuthorization)
+	payment.POST("/payment/lootbox/open", httpHandler.OpenLootBox, s.mid.JwtAuthorization)
+	payment.GET("/payment/lootbox/rolls", httpHandler.FindLootRolls, s.mid.JwtAuthorization)
+	payment.POST("/payment/craft", httpHandler.CraftItem, s.mid.JwtAuthorization)
+	payment.POST("/payment/season/pass", httpHandler.BuySeasonPass, s.mid.JwtAuthorization)
+	payment.GET("/payment/lootbox/roll/:roll_id/verify", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.VerifyLootRoll, []int{1, 0})))
 }
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardHandler"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardUsecase"
+)
+
+func (s *server) rewardService() {
+	repo := rewardRepository.NewRewardRepository(s.db)
+	usecase := rewardUsecase.NewRewardUsecase(repo)
+	httpHandler := rewardHandler.NewRewardHttpHandler(s.cfg, usecase)
+	queueHandler := rewardHandler.NewRewardQueueHandler(s.cfg, usecase)
+
+	go queueHandler.GrantResult()
+
+	reward := s.app.Group("/reward_v1")
+
+	reward.GET("", s.healthCheckService)                                                                                        // Health check
+	reward.POST("/calendar", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateCalendar, []int{1, 0})))          // Create a reward calendar
+	reward.GET("/calendars", httpHandler.FindCalendars)                                                                         // List active calendars
+	reward.GET("/calendar/:calendar_id/status", httpHandler.FindCalendarStatus, s.mid.JwtAuthorization)                         // Today's claim status
+	reward.POST("/calendar/:calendar_id/claim", httpHandler.ClaimReward, s.mid.JwtAuthorization)                                // Claim today's reward
+	reward.GET("/claims", httpHandler.FindClaims, s.mid.JwtAuthorization)                                                       // Claim history
+	reward.POST("/promo/codes", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreatePromoCodes, []int{1, 0})))     // Generate promo codes
+	reward.GET("/promo/codes", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.FindPromoCodes, []int{1, 0})))        // Find promo codes
+	reward.GET("/promo/redemptions", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.FindRedemptions, []int{1, 0}))) // Promo code redemptions
+	reward.POST("/promo/redeem", httpHandler.RedeemCode, s.mid.JwtAuthorization)                                                // Redeem a promo code
+}
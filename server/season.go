@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonHandler"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+)
+
+func (s *server) seasonService() {
+	repo := seasonRepository.NewSeasonRepository(s.db)
+	usecase := seasonUsecase.NewSeasonUsecase(repo)
+	httpHandler := seasonHandler.NewSeasonHttpHandler(s.cfg, usecase)
+	grpcHandler := seasonHandler.NewSeasonGrpcHandler(usecase)
+
+	// gRPC
+	go func() {
+		grpcServer, lis := grpcconn.NewGrpcServer(&s.cfg.Jwt, s.cfg.Grpc.SeasonUrl)
+
+		seasonPb.RegisterSeasonGrpcServiceServer(grpcServer, grpcHandler)
+
+		s.app.Logger.Infof("Season gRPC server is running on %s", s.cfg.Grpc.SeasonUrl)
+		grpcServer.Serve(lis)
+	}()
+
+	// Archive ended seasons
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.ArchiveEndedSeasons(context.Background()); err != nil {
+				log.Printf("Error: archive ended seasons failed: %v", err.Error())
+			}
+		}
+	}()
+
+	season := s.app.Group("/season_v1")
+
+	season.GET("", s.healthCheckService)                                                                           // Health check
+	season.POST("/season", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreateSeason, []int{1, 0}))) // Create a season
+	season.GET("/seasons", httpHandler.FindSeasons)                                                                // List seasons
+	season.GET("/progress", httpHandler.FindActiveProgress, s.mid.JwtAuthorization)                                // Progress in the active season
+	season.GET("/season/:season_id/progress", httpHandler.FindProgress, s.mid.JwtAuthorization)                    // Progress in a season, archived or not
+	season.POST("/season/:season_id/claim", httpHandler.ClaimTier, s.mid.JwtAuthorization)                         // Claim a tier reward
+}
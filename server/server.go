@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -67,9 +68,11 @@ func Start(pctx context.Context, cfg *config.Config, db *mongo.Client) {
 
 	jwtauth.SetApiKey(cfg.Jwt.ApiSecretKey)
 
-	// Request Timeout
+	// Request Timeout, streams and WebSockets stay open for as long as the client is connected
 	s.app.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Skipper:      middleware.DefaultSkipper,
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/stream") || strings.HasSuffix(c.Path(), "/ws")
+		},
 		ErrorMessage: "error: request timeout",
 		Timeout:      30 * time.Second,
 	}))
@@ -94,6 +97,18 @@ func Start(pctx context.Context, cfg *config.Config, db *mongo.Client) {
 		s.inventoryService()
 	case "payment":
 		s.paymentService()
+	case "trade":
+		s.tradeService()
+	case "marketplace":
+		s.marketplaceService()
+	case "reward":
+		s.rewardService()
+	case "season":
+		s.seasonService()
+	case "subscription":
+		s.subscriptionService()
+	case "notification":
+		s.notificationService()
 	}
 
 	// Graceful Shutdown
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionHandler"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/grpcconn"
+)
+
+func (s *server) subscriptionService() {
+	repo := subscriptionRepository.NewSubscriptionRepository(s.db)
+	usecase := subscriptionUsecase.NewSubscriptionUsecase(repo)
+	httpHandler := subscriptionHandler.NewSubscriptionHttpHandler(s.cfg, usecase)
+	grpcHandler := subscriptionHandler.NewSubscriptionGrpcHandler(usecase)
+
+	// gRPC
+	go func() {
+		grpcServer, lis := grpcconn.NewGrpcServer(&s.cfg.Jwt, s.cfg.Grpc.SubscriptionUrl)
+
+		subscriptionPb.RegisterSubscriptionGrpcServiceServer(grpcServer, grpcHandler)
+
+		s.app.Logger.Infof("Subscription gRPC server is running on %s", s.cfg.Grpc.SubscriptionUrl)
+		grpcServer.Serve(lis)
+	}()
+
+	// Renewals and daily grants
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.RunRenewals(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: run renewals failed: %v", err.Error())
+			}
+			if err := usecase.RunDailyGrants(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: run daily grants failed: %v", err.Error())
+			}
+		}
+	}()
+
+	subscription := s.app.Group("/subscription_v1")
+
+	subscription.GET("", s.healthCheckService)                                                                                                        // Health check
+	subscription.POST("/plan", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.CreatePlan, []int{1, 0})))                                  // Create a plan
+	subscription.GET("/plans", httpHandler.FindPlans)                                                                                                 // List plans
+	subscription.POST("/subscribe", httpHandler.Subscribe, s.mid.JwtAuthorization)                                                                    // Subscribe with the wallet
+	subscription.GET("/subscription", httpHandler.FindMySubscription, s.mid.JwtAuthorization)                                                         // My live subscription
+	subscription.POST("/subscription/cancel", httpHandler.CancelSubscription, s.mid.JwtAuthorization)                                                 // Cancel at the end of the period
+	subscription.GET("/subscription/:subscription_id/charges", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.FindCharges, []int{1, 0}))) // Charge history
+	subscription.POST("/provider/subscribe", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.ProviderSubscribe, []int{1, 0})))             // Record a provider subscription
+	subscription.POST("/provider/renew", s.mid.JwtAuthorization(s.mid.RbacAuthorization(httpHandler.ProviderRenew, []int{1, 0})))                     // Record a provider renewal
+}
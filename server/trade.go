@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeHandler"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeUsecase"
+)
+
+func (s *server) tradeService() {
+	repo := tradeRepository.NewTradeRepository(s.db)
+	usecase := tradeUsecase.NewTradeUsecase(repo)
+	httpHandler := tradeHandler.NewTradeHttpHandler(s.cfg, usecase)
+
+	// Release the escrow of expired trades and finish interrupted ones
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := usecase.ExpireTrades(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: expire trades failed: %v", err.Error())
+			}
+			if err := usecase.RecoverTrades(context.Background(), s.cfg); err != nil {
+				log.Printf("Error: recover trades failed: %v", err.Error())
+			}
+		}
+	}()
+
+	trade := s.app.Group("/trade_v1")
+
+	trade.GET("", s.healthCheckService)                                                      // Health check
+	trade.POST("/trade", httpHandler.CreateTrade, s.mid.JwtAuthorization)                    // Offer a trade
+	trade.GET("/trades", httpHandler.FindPlayerTrades, s.mid.JwtAuthorization)               // List my trades
+	trade.GET("/trade/:trade_id", httpHandler.FindOneTrade, s.mid.JwtAuthorization)          // Find one trade
+	trade.POST("/trade/:trade_id/counter", httpHandler.CounterTrade, s.mid.JwtAuthorization) // Counter a trade
+	trade.POST("/trade/:trade_id/accept", httpHandler.AcceptTrade, s.mid.JwtAuthorization)   // Accept a trade
+	trade.POST("/trade/:trade_id/cancel", httpHandler.CancelTrade, s.mid.JwtAuthorization)   // Cancel or decline a trade
+}
@@ -4,10 +4,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryUsecase"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type (
@@ -24,6 +32,86 @@ type (
 		offset int64
 		isErr  bool
 	}
+
+	testHasItem struct {
+		name     string
+		ctx      context.Context
+		req      *inventoryPb.HasItemReq
+		expected bool
+		isErr    bool
+	}
+
+	testBatchGrant struct {
+		name     string
+		ctx      context.Context
+		req      *inventoryPb.BatchGrantReq
+		expected int
+		mailed   int
+		isErr    bool
+	}
+
+	testClaimMail struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		used     int64
+		isErr    bool
+	}
+
+	testEquipItem struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		itemId   string
+		level    int32
+		stale    bool
+		isErr    bool
+	}
+
+	testUpdateItemInstance struct {
+		name     string
+		ctx      context.Context
+		instance inventory.ItemInstance
+		req      *inventory.UpdateItemInstanceReq
+		expected int
+		isErr    bool
+	}
+
+	testUseItem struct {
+		name      string
+		ctx       context.Context
+		itemId    string
+		readyAt   time.Time
+		expiresAt *time.Time
+		removeErr error
+		pushErr   error
+		restored  bool
+		released  bool
+		isErr     bool
+	}
+
+	testSweepExpiredItems struct {
+		name    string
+		ctx     context.Context
+		removed error
+		events  int
+	}
+
+	testBatchRevoke struct {
+		name     string
+		ctx      context.Context
+		req      *inventoryPb.BatchRevokeReq
+		expected int
+		isErr    bool
+	}
+
+	testRollbackRemovePlayerItem struct {
+		name      string
+		ctx       context.Context
+		req       *inventory.RollbackInventoryReq
+		reference *inventory.InventoryReference
+		expected  *inventory.Inventory
+	}
 )
 
 func TestGetOffset(t *testing.T) {
@@ -107,3 +195,719 @@ func TestUpsertOffset(t *testing.T) {
 		})
 	}
 }
+
+func TestHasItem(t *testing.T) {
+	repoMock := new(inventoryRepository.InventoryRepositoryMock)
+	usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	itemId := bson.NewObjectID().Hex()
+
+	tests := []testHasItem{
+		{
+			name:     "success has item",
+			ctx:      ctx,
+			req:      &inventoryPb.HasItemReq{PlayerId: "player:001", ItemId: "item:" + itemId, Quantity: 2},
+			expected: true,
+			isErr:    false,
+		},
+		{
+			name:     "success has item - not enough",
+			ctx:      ctx,
+			req:      &inventoryPb.HasItemReq{PlayerId: "player:001", ItemId: itemId, Quantity: 3},
+			expected: false,
+			isErr:    false,
+		},
+		{
+			name:     "failed has item - count failed",
+			ctx:      ctx,
+			req:      &inventoryPb.HasItemReq{PlayerId: "player:002", ItemId: "item:" + itemId},
+			expected: false,
+			isErr:    true,
+		},
+		{
+			name:     "failed has item - unknown sku",
+			ctx:      ctx,
+			req:      &inventoryPb.HasItemReq{PlayerId: "player:001", ItemId: "sku:UNKNOWN"},
+			expected: false,
+			isErr:    true,
+		},
+	}
+
+	repoMock.On("CountPlayerItem", ctx, "player:001", "item:"+itemId).Return(int64(2), nil)
+	repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{"sku:UNKNOWN"}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{}}, nil)
+	repoMock.On("CountPlayerItem", ctx, "player:002", "item:"+itemId).Return(int64(-1), errors.New("count player item failed"))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.HasItem(test.ctx, cfg, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Has)
+			}
+		})
+	}
+}
+
+func TestBatchRevoke(t *testing.T) {
+	repoMock := new(inventoryRepository.InventoryRepositoryMock)
+	usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+	shieldId := "item:" + bson.NewObjectID().Hex()
+
+	swords := []*inventory.Inventory{
+		{Id: bson.NewObjectID(), PlayerId: "player:001", ItemId: swordId},
+		{Id: bson.NewObjectID(), PlayerId: "player:001", ItemId: swordId},
+	}
+
+	expiredAt := time.Now().Add(-time.Minute)
+	expiredSword := &inventory.Inventory{Id: bson.NewObjectID(), PlayerId: "player:001", ItemId: swordId, ItemInstance: inventory.ItemInstance{ExpiresAt: &expiredAt}}
+
+	mail := &inventory.Mail{Id: bson.NewObjectID(), PlayerId: "player:001", ItemId: swordId}
+	claimedMailId := bson.NewObjectID().Hex()
+
+	tests := []testBatchRevoke{
+		{
+			name: "success batch revoke",
+			ctx:  ctx,
+			req: &inventoryPb.BatchRevokeReq{
+				Items: []*inventoryPb.GrantItem{{PlayerId: "player:001", ItemId: swordId, Quantity: 2}},
+			},
+			expected: 2,
+			isErr:    false,
+		},
+		{
+			name: "failed batch revoke - not enough items restores revoked ones",
+			ctx:  ctx,
+			req: &inventoryPb.BatchRevokeReq{
+				Items: []*inventoryPb.GrantItem{
+					{PlayerId: "player:001", ItemId: swordId, Quantity: 2},
+					{PlayerId: "player:001", ItemId: shieldId, Quantity: 1},
+				},
+			},
+			expected: 0,
+			isErr:    true,
+		},
+		{
+			name: "success batch revoke - exact granted copies",
+			ctx:  ctx,
+			req: &inventoryPb.BatchRevokeReq{
+				InventoryIds: []string{swords[0].Id.Hex()},
+				MailIds:      []string{mail.Id.Hex()},
+			},
+			expected: 2,
+			isErr:    false,
+		},
+		{
+			name: "failed batch revoke - copy has expired",
+			ctx:  ctx,
+			req: &inventoryPb.BatchRevokeReq{
+				InventoryIds: []string{expiredSword.Id.Hex()},
+			},
+			expected: 0,
+			isErr:    true,
+		},
+		{
+			name: "failed batch revoke - claimed mail restores the revoked copy",
+			ctx:  ctx,
+			req: &inventoryPb.BatchRevokeReq{
+				InventoryIds: []string{swords[0].Id.Hex()},
+				MailIds:      []string{claimedMailId},
+			},
+			expected: 0,
+			isErr:    true,
+		},
+	}
+
+	// Matches the tradable filter for itemId: unequipped, unbound and not
+	// expired
+	tradable := func(itemId string) any {
+		return mock.MatchedBy(func(filter bson.D) bool {
+			keys := make(map[string]any)
+			for _, v := range filter {
+				keys[v.Key] = v.Value
+			}
+			_, bound := keys["bound"]
+			_, unexpired := keys["$or"]
+			_, unequipped := keys["equipped_slot"]
+			return keys["item_id"] == itemId && bound && unexpired && unequipped
+		})
+	}
+
+	repoMock.On("FindPlayerItems", ctx, tradable(swordId), mock.Anything).Return(swords, nil)
+	repoMock.On("FindPlayerItems", ctx, tradable(shieldId), mock.Anything).Return([]*inventory.Inventory{}, nil)
+	repoMock.On("RemoveOneInventory", ctx, mock.AnythingOfType("string")).Return(nil)
+	repoMock.On("InsertManyPlayerItems", ctx, swords).Return([]bson.ObjectID{swords[0].Id, swords[1].Id}, nil)
+	repoMock.On("InsertManyPlayerItems", ctx, swords[:1]).Return([]bson.ObjectID{swords[0].Id}, nil)
+	repoMock.On("FindOneInventory", ctx, swords[0].Id.Hex()).Return(swords[0], nil)
+	repoMock.On("FindOneInventory", ctx, expiredSword.Id.Hex()).Return(expiredSword, nil)
+	repoMock.On("FindOneMail", ctx, mail.Id.Hex()).Return(mail, nil)
+	repoMock.On("FindOneMail", ctx, claimedMailId).Return((*inventory.Mail)(nil), errors.New("error: mail not found"))
+	repoMock.On("DeleteOneMail", ctx, mail.Id.Hex()).Return(nil)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.BatchRevoke(test.ctx, cfg, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if len(test.req.MailIds) == 0 && len(test.req.InventoryIds) > 0 {
+					repoMock.AssertNotCalled(t, "RemoveOneInventory", ctx, expiredSword.Id.Hex())
+				} else if len(test.req.InventoryIds) > 0 {
+					repoMock.AssertCalled(t, "InsertManyPlayerItems", ctx, swords[:1])
+				} else {
+					repoMock.AssertCalled(t, "InsertManyPlayerItems", ctx, swords)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, len(result.Items)+len(result.Mailed))
+			}
+		})
+	}
+}
+
+func TestBatchGrant(t *testing.T) {
+	repoMock := new(inventoryRepository.InventoryRepositoryMock)
+	usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testBatchGrant{
+		{
+			name: "success batch grant within capacity",
+			ctx:  ctx,
+			req: &inventoryPb.BatchGrantReq{
+				Items: []*inventoryPb.GrantItem{{PlayerId: "player:001", ItemId: swordId, Quantity: 2}},
+			},
+			expected: 2,
+			mailed:   0,
+			isErr:    false,
+		},
+		{
+			name: "success batch grant overflows to mailbox",
+			ctx:  ctx,
+			req: &inventoryPb.BatchGrantReq{
+				Items:             []*inventoryPb.GrantItem{{PlayerId: "player:002", ItemId: swordId, Quantity: 3}},
+				OverflowToMailbox: true,
+			},
+			expected: 1,
+			mailed:   2,
+			isErr:    false,
+		},
+		{
+			name: "failed batch grant - inventory is full",
+			ctx:  ctx,
+			req: &inventoryPb.BatchGrantReq{
+				Items: []*inventoryPb.GrantItem{{PlayerId: "player:002", ItemId: swordId, Quantity: 3}},
+			},
+			isErr: true,
+		},
+	}
+
+	repoMock.On("CountPlayerItems", ctx, "player:001").Return(int64(0), nil)
+	repoMock.On("CountPlayerItems", ctx, "player:002").Return(cfg.Inventory.DefaultCapacity, nil)
+	repoMock.On("FindExtraSlots", ctx, "player:001").Return(int64(0), nil)
+	repoMock.On("FindExtraSlots", ctx, "player:002").Return(int64(1), nil)
+	repoMock.On("InsertManyPlayerItems", ctx, mock.Anything).Return([]bson.ObjectID{}, nil)
+	repoMock.On("InsertManyMails", ctx, mock.Anything).Return([]bson.ObjectID{}, nil)
+	repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{swordId}}).Return(&itemPb.FindItemsInIdsRes{
+		Items: []*itemPb.Item{{Id: swordId, Title: "Rental Sword", DurationHours: 168}},
+	}, nil)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.BatchGrant(test.ctx, cfg, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, test.expected)
+				assert.Len(t, result.Mailed, test.mailed)
+				for _, v := range result.Items {
+					assert.Greater(t, v.Instance.ExpiresAt, time.Now().Add(167*time.Hour).Unix())
+				}
+			}
+		})
+	}
+}
+
+func TestClaimMail(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	mailId := bson.NewObjectID()
+	inventoryId := bson.NewObjectID()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testClaimMail{
+		{
+			name:     "success claim mail",
+			ctx:      ctx,
+			playerId: "player:001",
+			used:     0,
+			isErr:    false,
+		},
+		{
+			name:     "failed claim mail - inventory is full",
+			ctx:      ctx,
+			playerId: "player:001",
+			used:     cfg.Inventory.DefaultCapacity,
+			isErr:    true,
+		},
+		{
+			name:     "failed claim mail - not the owner",
+			ctx:      ctx,
+			playerId: "player:002",
+			used:     0,
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			repoMock.On("FindOneMail", ctx, mailId.Hex()).Return(&inventory.Mail{Id: mailId, PlayerId: "player:001", ItemId: swordId}, nil)
+			repoMock.On("CountPlayerItems", ctx, test.playerId).Return(test.used, nil)
+			repoMock.On("FindExtraSlots", ctx, test.playerId).Return(int64(0), nil)
+			repoMock.On("DeleteOneMail", ctx, mailId.Hex()).Return(nil)
+			repoMock.On("InsertOnePlayerItem", ctx, mock.Anything).Return(inventoryId, nil)
+
+			result, err := usecase.ClaimMail(test.ctx, cfg, test.playerId, mailId.Hex())
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "DeleteOneMail", ctx, mailId.Hex())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, inventoryId, result.Id)
+				assert.Equal(t, swordId, result.ItemId)
+			}
+		})
+	}
+}
+
+func TestEquipItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	inventoryId := bson.NewObjectID()
+	swordId := "item:" + bson.NewObjectID().Hex()
+	potionId := "item:" + bson.NewObjectID().Hex()
+	disabledId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testEquipItem{
+		{
+			name:     "success equip item",
+			ctx:      ctx,
+			playerId: "player:001",
+			itemId:   swordId,
+			level:    10,
+			isErr:    false,
+		},
+		{
+			name:     "success equip item - older copy left in the slot is ignored",
+			ctx:      ctx,
+			playerId: "player:001",
+			itemId:   swordId,
+			level:    10,
+			stale:    true,
+			isErr:    false,
+		},
+		{
+			name:     "failed equip item - level too low",
+			ctx:      ctx,
+			playerId: "player:001",
+			itemId:   swordId,
+			level:    4,
+			isErr:    true,
+		},
+		{
+			name:     "failed equip item - item has no slot",
+			ctx:      ctx,
+			playerId: "player:001",
+			itemId:   potionId,
+			level:    10,
+			isErr:    true,
+		},
+		{
+			name:     "failed equip item - not the owner",
+			ctx:      ctx,
+			playerId: "player:002",
+			itemId:   swordId,
+			level:    10,
+			isErr:    true,
+		},
+		{
+			name:     "failed equip item - item disabled",
+			ctx:      ctx,
+			playerId: "player:001",
+			itemId:   disabledId,
+			level:    10,
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			sword := &itemPb.Item{Id: swordId, Title: "Sword", Damage: 100, Slot: "weapon", LevelRequired: 5}
+			potion := &itemPb.Item{Id: potionId, Title: "Potion"}
+
+			repoMock.On("FindOneInventory", ctx, inventoryId.Hex()).Return(&inventory.Inventory{Id: inventoryId, PlayerId: "player:001", ItemId: test.itemId}, nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{swordId}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{sword}}, nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{potionId}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{potion}}, nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{disabledId}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{}}, nil)
+			repoMock.On("FindOnePlayerProfile", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(&playerPb.PlayerProfile{Level: test.level}, nil)
+			repoMock.On("EquipInventory", ctx, test.playerId, inventoryId.Hex(), "weapon").Return(nil)
+			equipped := []*inventory.Inventory{
+				{Id: inventoryId, PlayerId: test.playerId, ItemId: swordId, EquippedSlot: "weapon"},
+			}
+			if test.stale {
+				equipped = append(equipped, &inventory.Inventory{Id: bson.NewObjectID(), PlayerId: test.playerId, ItemId: swordId, EquippedSlot: "weapon"})
+			}
+			repoMock.On("FindPlayerItems", ctx, mock.Anything, mock.Anything).Return(equipped, nil)
+
+			result, err := usecase.EquipItem(test.ctx, cfg, test.playerId, &inventory.EquipItemReq{InventoryId: inventoryId.Hex()})
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "EquipInventory", ctx, test.playerId, inventoryId.Hex(), "weapon")
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, 1)
+				assert.Equal(t, inventoryId.Hex(), result.Items[0].InventoryId)
+				assert.Equal(t, "weapon", result.Items[0].EquippedSlot)
+				assert.Equal(t, 100, result.Damage)
+			}
+		})
+	}
+}
+
+func TestUpdateItemInstance(t *testing.T) {
+	ctx := context.Background()
+	inventoryId := bson.NewObjectID()
+	durability := 150
+	enchantLevel := 3
+
+	tests := []testUpdateItemInstance{
+		{
+			name:     "success update item instance - durability capped at max",
+			ctx:      ctx,
+			instance: inventory.ItemInstance{Serial: "SN001", Durability: 40, MaxDurability: 100},
+			req:      &inventory.UpdateItemInstanceReq{Durability: &durability, EnchantLevel: &enchantLevel},
+			expected: 100,
+			isErr:    false,
+		},
+		{
+			name:     "success update item instance - legacy entry gets a serial",
+			ctx:      ctx,
+			instance: inventory.ItemInstance{},
+			req:      &inventory.UpdateItemInstanceReq{EnchantLevel: &enchantLevel},
+			expected: 100,
+			isErr:    false,
+		},
+		{
+			name:     "failed update item instance - nothing to update",
+			ctx:      ctx,
+			instance: inventory.ItemInstance{Serial: "SN001", Durability: 40, MaxDurability: 100},
+			req:      &inventory.UpdateItemInstanceReq{},
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			repoMock.On("FindOneInventory", ctx, inventoryId.Hex()).Return(&inventory.Inventory{Id: inventoryId, PlayerId: "player:001", ItemInstance: test.instance}, nil)
+			repoMock.On("UpdateOneInventory", ctx, inventoryId.Hex(), mock.Anything).Return(nil)
+
+			result, err := usecase.UpdateItemInstance(test.ctx, "inventory:"+inventoryId.Hex(), test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "UpdateOneInventory", ctx, inventoryId.Hex(), mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.Serial)
+				assert.Equal(t, test.expected, result.Durability)
+				assert.Equal(t, enchantLevel, result.EnchantLevel)
+			}
+		})
+	}
+}
+
+func TestUseItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	inventoryId := bson.NewObjectID()
+	potionId := "item:" + bson.NewObjectID().Hex()
+	swordId := "item:" + bson.NewObjectID().Hex()
+	expiredAt := time.Now().Add(-time.Minute)
+
+	tests := []testUseItem{
+		{
+			name:   "success use item",
+			ctx:    ctx,
+			itemId: potionId,
+			isErr:  false,
+		},
+		{
+			name:   "failed use item - not consumable",
+			ctx:    ctx,
+			itemId: swordId,
+			isErr:  true,
+		},
+		{
+			name:    "failed use item - on cooldown",
+			ctx:     ctx,
+			itemId:  potionId,
+			readyAt: time.Now().Add(time.Hour),
+			isErr:   true,
+		},
+		{
+			name:      "failed use item - copy has expired",
+			ctx:       ctx,
+			itemId:    potionId,
+			expiresAt: &expiredAt,
+			isErr:     true,
+		},
+		{
+			name:      "failed use item - remove failed releases the cooldown",
+			ctx:       ctx,
+			itemId:    potionId,
+			removeErr: errors.New("error: inventory item not found"),
+			released:  true,
+			isErr:     true,
+		},
+		{
+			name:     "failed use item - event not sent restores the item",
+			ctx:      ctx,
+			itemId:   potionId,
+			pushErr:  errors.New("error: push message with key to queue failed"),
+			restored: true,
+			released: true,
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			potion := &itemPb.Item{Id: potionId, Title: "Potion", Effect: &itemPb.ItemEffect{Type: "heal", Value: 50}, CooldownSeconds: 30}
+			sword := &itemPb.Item{Id: swordId, Title: "Sword", Slot: "weapon"}
+			owned := &inventory.Inventory{Id: inventoryId, PlayerId: "player:001", ItemId: test.itemId, ItemInstance: inventory.ItemInstance{ExpiresAt: test.expiresAt}}
+
+			repoMock.On("FindOneInventory", ctx, inventoryId.Hex()).Return(owned, nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{potionId}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{potion}}, nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{swordId}}).Return(&itemPb.FindItemsInIdsRes{Items: []*itemPb.Item{sword}}, nil)
+			repoMock.On("ReserveItemCooldown", ctx, "player:001", test.itemId, mock.Anything, mock.Anything).Return(time.Time{}, test.readyAt.IsZero(), nil)
+			repoMock.On("FindItemCooldown", ctx, "player:001", test.itemId).Return(test.readyAt, nil)
+			repoMock.On("ReleaseItemCooldown", ctx, "player:001", test.itemId, mock.Anything, time.Time{}).Return(nil)
+			repoMock.On("RemoveOneInventory", ctx, inventoryId.Hex()).Return(test.removeErr)
+			repoMock.On("PushEvent", ctx, cfg, mock.MatchedBy(func(req *models.DomainEvent) bool {
+				return req.Type == "item.used" && req.PlayerId == "player:001"
+			})).Return(test.pushErr)
+			repoMock.On("InsertOnePlayerItem", ctx, owned).Return(inventoryId, nil)
+
+			result, err := usecase.UseItem(test.ctx, cfg, "player:001", "inventory:"+inventoryId.Hex())
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if test.restored {
+					repoMock.AssertCalled(t, "InsertOnePlayerItem", ctx, owned)
+				} else if test.removeErr == nil {
+					repoMock.AssertNotCalled(t, "RemoveOneInventory", ctx, inventoryId.Hex())
+				}
+				if test.released {
+					repoMock.AssertCalled(t, "ReleaseItemCooldown", ctx, "player:001", test.itemId, mock.Anything, time.Time{})
+				} else {
+					repoMock.AssertNotCalled(t, "ReleaseItemCooldown", ctx, "player:001", test.itemId, mock.Anything, time.Time{})
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "heal", result.Effect.Type)
+				assert.False(t, result.ReadyAt.IsZero())
+				repoMock.AssertCalled(t, "ReserveItemCooldown", ctx, "player:001", potionId, mock.Anything, result.ReadyAt)
+				repoMock.AssertNotCalled(t, "ReleaseItemCooldown", ctx, "player:001", potionId, mock.Anything, time.Time{})
+			}
+		})
+	}
+}
+
+func TestSweepExpiredItems(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	expiredAt := time.Now().Add(-time.Minute)
+	potionId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testSweepExpiredItems{
+		{
+			name:    "success sweep expired items",
+			ctx:     ctx,
+			removed: nil,
+			events:  2,
+		},
+		{
+			name:    "success sweep expired items - copies already gone are skipped",
+			ctx:     ctx,
+			removed: errors.New("error: inventory not found"),
+			events:  0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			expired := []*inventory.Inventory{
+				{Id: bson.NewObjectID(), PlayerId: "player:001", ItemId: potionId, ItemInstance: inventory.ItemInstance{ExpiresAt: &expiredAt}},
+				{Id: bson.NewObjectID(), PlayerId: "player:002", ItemId: potionId, ItemInstance: inventory.ItemInstance{ExpiresAt: &expiredAt}},
+			}
+
+			repoMock.On("FindPlayerItems", ctx, mock.Anything, mock.Anything).Return(expired, nil)
+			repoMock.On("RemoveOneInventory", ctx, mock.AnythingOfType("string")).Return(test.removed)
+			repoMock.On("PushEvent", ctx, cfg, mock.MatchedBy(func(req *models.DomainEvent) bool {
+				return req.Type == "item.expired"
+			})).Return(nil)
+
+			err := usecase.SweepExpiredItems(test.ctx, cfg)
+
+			assert.NoError(t, err)
+			repoMock.AssertNumberOfCalls(t, "PushEvent", test.events)
+		})
+	}
+}
+
+func TestRollbackRemovePlayerItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	inventoryId := bson.NewObjectID()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	sword := &inventory.ItemInstance{
+		Serial:        "serial:001",
+		Durability:    12,
+		MaxDurability: 100,
+		EnchantLevel:  3,
+		Bound:         true,
+		Source:        "purchase",
+	}
+
+	tests := []testRollbackRemovePlayerItem{
+		{
+			name: "success rollback remove player item - restores the removed copy",
+			ctx:  ctx,
+			req: &inventory.RollbackInventoryReq{
+				InventoryId: "inventory:" + inventoryId.Hex(),
+				PlayerId:    "player:001",
+				ItemId:      swordId,
+				Instance:    sword,
+			},
+			expected: &inventory.Inventory{
+				Id:           inventoryId,
+				PlayerId:     "player:001",
+				ItemId:       swordId,
+				ItemInstance: *sword,
+			},
+		},
+		{
+			name: "success rollback remove player item - no instance",
+			ctx:  ctx,
+			req: &inventory.RollbackInventoryReq{
+				PlayerId: "player:001",
+				ItemId:   swordId,
+			},
+			expected: &inventory.Inventory{
+				PlayerId:     "player:001",
+				ItemId:       swordId,
+				ItemInstance: inventory.ItemInstance{Source: "refund"},
+			},
+		},
+		{
+			name: "success rollback remove player item - by reference restores the recorded copy",
+			ctx:  ctx,
+			req:  &inventory.RollbackInventoryReq{PlayerId: "player:001", ReferenceId: "order:001:remove:0"},
+			reference: &inventory.InventoryReference{
+				ReferenceId: "order:001:remove:0",
+				Removed:     &inventory.Inventory{Id: inventoryId, PlayerId: "player:001", ItemId: swordId, ItemInstance: *sword},
+			},
+			expected: &inventory.Inventory{
+				Id:           inventoryId,
+				PlayerId:     "player:001",
+				ItemId:       swordId,
+				ItemInstance: *sword,
+			},
+		},
+		{
+			name: "success rollback remove player item - by reference before the step ran",
+			ctx:  ctx,
+			req:  &inventory.RollbackInventoryReq{PlayerId: "player:001", ReferenceId: "order:001:remove:0"},
+		},
+		{
+			name: "success rollback remove player item - by reference rolled back before",
+			ctx:  ctx,
+			req:  &inventory.RollbackInventoryReq{PlayerId: "player:001", ReferenceId: "order:001:remove:0"},
+			reference: &inventory.InventoryReference{
+				ReferenceId: "order:001:remove:0",
+				Cancelled:   true,
+				Removed:     &inventory.Inventory{Id: inventoryId, PlayerId: "player:001", ItemId: swordId, ItemInstance: *sword},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(inventoryRepository.InventoryRepositoryMock)
+			usecase := inventoryUsecase.NewInventoryUsecase(repoMock)
+
+			repoMock.On("InsertOnePlayerItem", ctx, mock.Anything).Return(inventoryId, nil)
+			repoMock.On("MarkReference", ctx, test.req.ReferenceId, bson.M{"cancelled": true}).Return(test.reference, nil)
+
+			usecase.RollbackRemovePlayerItem(test.ctx, cfg, test.req)
+
+			if test.expected == nil {
+				repoMock.AssertNotCalled(t, "InsertOnePlayerItem", ctx, mock.Anything)
+				return
+			}
+
+			var result *inventory.Inventory
+			for _, call := range repoMock.Calls {
+				if call.Method == "InsertOnePlayerItem" {
+					result = call.Arguments.Get(1).(*inventory.Inventory)
+				}
+			}
+			assert.NotNil(t, result)
+			assert.Equal(t, test.expected.Id, result.Id)
+			assert.Equal(t, test.expected.PlayerId, result.PlayerId)
+			assert.Equal(t, test.expected.ItemId, result.ItemId)
+			assert.Equal(t, test.expected.Source, result.Source)
+			if test.req.Instance != nil || test.reference != nil {
+				assert.Equal(t, test.expected.ItemInstance, result.ItemInstance)
+			}
+		})
+	}
+}
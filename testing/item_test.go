@@ -1,13 +1,19 @@
 package testing
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Supakornn/mmorpg-shop/modules/item"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/item/itemRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/item/itemUsecase"
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
 	"github.com/Supakornn/mmorpg-shop/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,6 +29,14 @@ type (
 		isErr    bool
 	}
 
+	testCreateBundle struct {
+		name     string
+		ctx      context.Context
+		req      *item.CreateBundleReq
+		expected *item.ItemShowCase
+		isErr    bool
+	}
+
 	testFindOneItem struct {
 		name     string
 		ctx      context.Context
@@ -40,6 +54,57 @@ type (
 		isErr    bool
 	}
 
+	testFindItemPriceAt struct {
+		name     string
+		ctx      context.Context
+		itemId   string
+		at       time.Time
+		expected float64
+		isErr    bool
+	}
+
+	testFindItemPriceFor struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		expected float64
+		vip      bool
+	}
+
+	testAddWishlistItem struct {
+		name     string
+		ctx      context.Context
+		req      *item.AddWishlistItemReq
+		wishlist int
+		isErr    bool
+	}
+
+	testWatchWishlists struct {
+		name        string
+		ctx         context.Context
+		revision    *item.ItemRevision
+		usageStatus bool
+		expected    string
+	}
+
+	testImportItems struct {
+		name     string
+		ctx      context.Context
+		req      *item.ItemImportReq
+		data     string
+		existing []*item.Item
+		expected *item.ItemImportRes
+		isErr    bool
+	}
+
+	testReserveItems struct {
+		name     string
+		ctx      context.Context
+		req      *itemPb.ReserveItemsReq
+		expected []string
+		isErr    bool
+	}
+
 	testToggleItemUsageStatus struct {
 		name     string
 		ctx      context.Context
@@ -69,6 +134,7 @@ func TestCreateItem(t *testing.T) {
 			},
 			expected: &item.ItemShowCase{
 				ItemId:   "item:" + itemId.Hex(),
+				Sku:      "SWORD-OF-LEGENDS",
 				Title:    "Sword of Legends",
 				Price:    150.0,
 				ImageUrl: "https://example.com/sword.png",
@@ -76,6 +142,32 @@ func TestCreateItem(t *testing.T) {
 			},
 			isErr: false,
 		},
+		{
+			name: "failed create item - sku already exists",
+			ctx:  ctx,
+			req: &item.CreateItemReq{
+				Sku:      "sword",
+				Title:    "Another Sword",
+				Price:    100.0,
+				ImageUrl: "https://example.com/another.png",
+				Damage:   30,
+			},
+			expected: nil,
+			isErr:    true,
+		},
+		{
+			name: "failed create item - invalid sku",
+			ctx:  ctx,
+			req: &item.CreateItemReq{
+				Sku:      "sword#1",
+				Title:    "Hashed Sword",
+				Price:    100.0,
+				ImageUrl: "https://example.com/hashed.png",
+				Damage:   30,
+			},
+			expected: nil,
+			isErr:    true,
+		},
 		{
 			name: "failed create item - item already exists",
 			ctx:  ctx,
@@ -92,9 +184,13 @@ func TestCreateItem(t *testing.T) {
 
 	// Success case
 	repoMock.On("IsUniqueItem", ctx, "Sword of Legends").Return(true)
-	repoMock.On("InsertOneItem", ctx, mock.AnythingOfType("*item.Item")).Return(itemId, nil)
+	repoMock.On("IsUniqueSku", ctx, "SWORD-OF-LEGENDS").Return(true)
+	repoMock.On("InsertOneItem", ctx, mock.MatchedBy(func(req *item.Item) bool {
+		return req.Sku == "SWORD-OF-LEGENDS"
+	})).Return(itemId, nil)
 	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{
 		Id:          itemId,
+		Sku:         "SWORD-OF-LEGENDS",
 		Title:       "Sword of Legends",
 		Price:       150.0,
 		ImageUrl:    "https://example.com/sword.png",
@@ -105,6 +201,9 @@ func TestCreateItem(t *testing.T) {
 	}, nil)
 
 	// Failed case
+	repoMock.On("IsUniqueItem", ctx, "Another Sword").Return(true)
+	repoMock.On("IsUniqueSku", ctx, "SWORD").Return(false)
+	repoMock.On("IsUniqueItem", ctx, "Hashed Sword").Return(true)
 	repoMock.On("IsUniqueItem", ctx, "Existing Sword").Return(false)
 
 	for _, test := range tests {
@@ -118,6 +217,7 @@ func TestCreateItem(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, test.expected.ItemId, result.ItemId)
+				assert.Equal(t, test.expected.Sku, result.Sku)
 				assert.Equal(t, test.expected.Title, result.Title)
 				assert.Equal(t, test.expected.Price, result.Price)
 				assert.Equal(t, test.expected.Damage, result.Damage)
@@ -126,6 +226,117 @@ func TestCreateItem(t *testing.T) {
 	}
 }
 
+func TestCreateBundle(t *testing.T) {
+	repoMock := new(itemRepository.ItemRepositoryMock)
+	usecase := itemUsecase.NewItemUsecase(repoMock)
+
+	ctx := context.Background()
+	bundleId := bson.NewObjectID()
+	swordId := bson.NewObjectID()
+	potionId := bson.NewObjectID()
+	testTime := utils.LocalTime()
+
+	tests := []testCreateBundle{
+		{
+			name: "success create bundle",
+			ctx:  ctx,
+			req: &item.CreateBundleReq{
+				Title:    "Starter Pack",
+				Price:    250.0,
+				ImageUrl: "https://example.com/starter.png",
+				Components: []*item.BundleComponentReq{
+					{ItemId: "item:" + swordId.Hex(), Quantity: 1},
+					{ItemId: "item:" + potionId.Hex(), Quantity: 10},
+				},
+			},
+			expected: &item.ItemShowCase{
+				ItemId: "item:" + bundleId.Hex(),
+				Title:  "Starter Pack",
+				Price:  250.0,
+				Components: []*item.BundleComponent{
+					{ItemId: "item:" + swordId.Hex(), Quantity: 1},
+					{ItemId: "item:" + potionId.Hex(), Quantity: 10},
+				},
+			},
+			isErr: false,
+		},
+		{
+			name: "failed create bundle - component not found",
+			ctx:  ctx,
+			req: &item.CreateBundleReq{
+				Title:    "Broken Pack",
+				Price:    100.0,
+				ImageUrl: "https://example.com/broken.png",
+				Components: []*item.BundleComponentReq{
+					{ItemId: "item:" + swordId.Hex(), Quantity: 1},
+				},
+			},
+			expected: nil,
+			isErr:    true,
+		},
+		{
+			name: "failed create bundle - duplicate component",
+			ctx:  ctx,
+			req: &item.CreateBundleReq{
+				Title:    "Double Pack",
+				Price:    100.0,
+				ImageUrl: "https://example.com/double.png",
+				Components: []*item.BundleComponentReq{
+					{ItemId: "item:" + swordId.Hex(), Quantity: 1},
+					{ItemId: swordId.Hex(), Quantity: 1},
+				},
+			},
+			expected: nil,
+			isErr:    true,
+		},
+	}
+
+	// Success case
+	repoMock.On("IsUniqueItem", ctx, "Starter Pack").Return(true)
+	repoMock.On("IsUniqueSku", ctx, mock.AnythingOfType("string")).Return(true)
+	repoMock.On("FindManyItems", ctx, mock.AnythingOfType("bson.D"), mock.Anything).Return([]*item.ItemShowCase{
+		{ItemId: "item:" + swordId.Hex(), Title: "Sword"},
+		{ItemId: "item:" + potionId.Hex(), Title: "Potion"},
+	}, nil).Once()
+	repoMock.On("InsertOneItem", ctx, mock.AnythingOfType("*item.Item")).Return(bundleId, nil)
+	repoMock.On("FindOneItem", ctx, bundleId.Hex()).Return(&item.Item{
+		Id:          bundleId,
+		Title:       "Starter Pack",
+		Price:       250.0,
+		ImageUrl:    "https://example.com/starter.png",
+		UsageStatus: true,
+		Components: []*item.BundleComponent{
+			{ItemId: "item:" + swordId.Hex(), Quantity: 1},
+			{ItemId: "item:" + potionId.Hex(), Quantity: 10},
+		},
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}, nil)
+
+	// Failed case
+	repoMock.On("IsUniqueItem", ctx, "Broken Pack").Return(true)
+	repoMock.On("FindManyItems", ctx, mock.AnythingOfType("bson.D"), mock.Anything).Return([]*item.ItemShowCase{}, nil).Once()
+	repoMock.On("IsUniqueItem", ctx, "Double Pack").Return(true)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.CreateBundle(test.ctx, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, test.expected.ItemId, result.ItemId)
+				assert.Equal(t, test.expected.Title, result.Title)
+				assert.Equal(t, test.expected.Price, result.Price)
+				assert.Equal(t, test.expected.Components, result.Components)
+			}
+		})
+	}
+}
+
 func TestFindOneItem(t *testing.T) {
 	repoMock := new(itemRepository.ItemRepositoryMock)
 	usecase := itemUsecase.NewItemUsecase(repoMock)
@@ -148,6 +359,19 @@ func TestFindOneItem(t *testing.T) {
 			},
 			isErr: false,
 		},
+		{
+			name:   "success find one item by sku",
+			ctx:    ctx,
+			itemId: "sku:magic staff",
+			expected: &item.ItemShowCase{
+				ItemId:   "item:" + itemId.Hex(),
+				Title:    "Magic Staff",
+				Price:    200.0,
+				ImageUrl: "https://example.com/staff.png",
+				Damage:   75,
+			},
+			isErr: false,
+		},
 		{
 			name:     "failed find one item - not found",
 			ctx:      ctx,
@@ -158,8 +382,9 @@ func TestFindOneItem(t *testing.T) {
 	}
 
 	// Success case
-	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{
+	staff := &item.Item{
 		Id:          itemId,
+		Sku:         "MAGIC-STAFF",
 		Title:       "Magic Staff",
 		Price:       200.0,
 		ImageUrl:    "https://example.com/staff.png",
@@ -167,7 +392,9 @@ func TestFindOneItem(t *testing.T) {
 		UsageStatus: true,
 		CreatedAt:   testTime,
 		UpdatedAt:   testTime,
-	}, nil)
+	}
+	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(staff, nil)
+	repoMock.On("FindOneItemBySku", ctx, "MAGIC-STAFF").Return(staff, nil)
 
 	// Failed case
 	repoMock.On("FindOneItem", ctx, "invalid_item_id").Return(&item.Item{}, errors.New("item not found"))
@@ -235,8 +462,21 @@ func TestEditItem(t *testing.T) {
 	}
 
 	// Success case
+	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{
+		Id:          itemId,
+		Title:       "Sword",
+		Price:       150.0,
+		ImageUrl:    "https://example.com/sword.png",
+		Damage:      50,
+		UsageStatus: true,
+		CreatedAt:   testTime,
+		UpdatedAt:   testTime,
+	}, nil).Once()
 	repoMock.On("IsUniqueItem", ctx, "Updated Sword").Return(true)
 	repoMock.On("UpdateOneItem", ctx, itemId.Hex(), mock.AnythingOfType("bson.M")).Return(nil)
+	repoMock.On("InsertOneItemRevision", ctx, mock.MatchedBy(func(req *item.ItemRevision) bool {
+		return req.Action == "edit" && req.ChangedBy == "player:001" && req.PreviousPrice == 150.0 && req.Price == 180.0 && len(req.Diff) == 4
+	})).Return(bson.NewObjectID(), nil)
 	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{
 		Id:          itemId,
 		Title:       "Updated Sword",
@@ -249,12 +489,13 @@ func TestEditItem(t *testing.T) {
 	}, nil)
 
 	// Failed case
+	repoMock.On("FindOneItem", ctx, "invalid_item_id").Return(&item.Item{}, nil)
 	repoMock.On("IsUniqueItem", ctx, "Failed Update").Return(true)
-	repoMock.On("UpdateOneItem", ctx, "invalid_item_id", mock.AnythingOfType("bson.M")).Return(errors.New("update failed"))
+	repoMock.On("UpdateOneItem", ctx, bson.NilObjectID.Hex(), mock.AnythingOfType("bson.M")).Return(errors.New("update failed"))
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := usecase.EditItem(test.ctx, test.itemId, test.req)
+			result, err := usecase.EditItem(test.ctx, test.itemId, "player:001", test.req)
 
 			if test.isErr {
 				assert.Error(t, err)
@@ -301,13 +542,16 @@ func TestToggleItemUsageStatus(t *testing.T) {
 		UsageStatus: true,
 	}, nil)
 	repoMock.On("UpdateOneItemUsageStatus", ctx, itemId.Hex(), false).Return(nil)
+	repoMock.On("InsertOneItemRevision", ctx, mock.MatchedBy(func(req *item.ItemRevision) bool {
+		return req.Action == "toggle_status" && len(req.Diff) == 1 && req.Diff[0].Field == "usage_status"
+	})).Return(bson.NewObjectID(), nil)
 
 	// Failed case
 	repoMock.On("FindOneItem", ctx, "invalid_item_id").Return(&item.Item{}, errors.New("item not found"))
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := usecase.ToggleItemUsageStatus(test.ctx, test.itemId)
+			result, err := usecase.ToggleItemUsageStatus(test.ctx, test.itemId, "player:001")
 
 			if test.isErr {
 				assert.Error(t, err)
@@ -319,3 +563,593 @@ func TestToggleItemUsageStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFindItemPriceAt(t *testing.T) {
+	repoMock := new(itemRepository.ItemRepositoryMock)
+	usecase := itemUsecase.NewItemUsecase(repoMock)
+
+	ctx := context.Background()
+	itemId := bson.NewObjectID()
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	beforeEdit := createdAt.Add(time.Hour)
+	afterEdit := createdAt.Add(3 * time.Hour)
+	beforeCreate := createdAt.Add(-time.Hour)
+
+	tests := []testFindItemPriceAt{
+		{
+			name:     "success find price before the first edit",
+			ctx:      ctx,
+			itemId:   itemId.Hex(),
+			at:       beforeEdit,
+			expected: 100.0,
+			isErr:    false,
+		},
+		{
+			name:     "success find price after an edit",
+			ctx:      ctx,
+			itemId:   itemId.Hex(),
+			at:       afterEdit,
+			expected: 150.0,
+			isErr:    false,
+		},
+		{
+			name:     "failed find price before item was created",
+			ctx:      ctx,
+			itemId:   itemId.Hex(),
+			at:       beforeCreate,
+			expected: 0,
+			isErr:    true,
+		},
+	}
+
+	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{
+		Id:        itemId,
+		Price:     200.0,
+		CreatedAt: createdAt,
+	}, nil)
+
+	lteFilter := func(at time.Time) bson.D {
+		return bson.D{
+			{Key: "item_id", Value: "item:" + itemId.Hex()},
+			{Key: "created_at", Value: bson.D{{Key: "$lte", Value: at}}},
+		}
+	}
+	gtFilter := func(at time.Time) bson.D {
+		return bson.D{
+			{Key: "item_id", Value: "item:" + itemId.Hex()},
+			{Key: "created_at", Value: bson.D{{Key: "$gt", Value: at}}},
+		}
+	}
+
+	// Before the first edit
+	repoMock.On("FindItemRevisions", ctx, lteFilter(beforeEdit), mock.Anything).Return([]*item.ItemRevision{}, nil)
+	repoMock.On("FindItemRevisions", ctx, gtFilter(beforeEdit), mock.Anything).Return([]*item.ItemRevision{
+		{PreviousPrice: 100.0, Price: 150.0},
+	}, nil)
+
+	// After an edit
+	repoMock.On("FindItemRevisions", ctx, lteFilter(afterEdit), mock.Anything).Return([]*item.ItemRevision{
+		{PreviousPrice: 100.0, Price: 150.0},
+	}, nil)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.FindItemPriceAt(test.ctx, test.itemId, test.at)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Price)
+			}
+		})
+	}
+}
+
+func TestFindItemPriceFor(t *testing.T) {
+	repoMock := new(itemRepository.ItemRepositoryMock)
+	usecase := itemUsecase.NewItemUsecase(repoMock)
+
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	itemId := bson.NewObjectID()
+
+	tests := []testFindItemPriceFor{
+		{
+			name:     "success vip discount",
+			ctx:      ctx,
+			playerId: "player:001",
+			expected: 85.49,
+			vip:      true,
+		},
+		{
+			name:     "success no subscription",
+			ctx:      ctx,
+			playerId: "player:002",
+			expected: 94.99,
+			vip:      false,
+		},
+		{
+			name:     "success subscription service down",
+			ctx:      ctx,
+			playerId: "player:003",
+			expected: 94.99,
+			vip:      false,
+		},
+	}
+
+	repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{Id: itemId, Price: 94.99}, nil)
+	repoMock.On("FindEntitlements", ctx, cfg.Grpc.SubscriptionUrl, &subscriptionPb.FindEntitlementsReq{PlayerId: "player:001"}).Return(&subscriptionPb.EntitlementsRes{
+		PlayerId:     "player:001",
+		Active:       true,
+		DiscountRate: 0.1,
+	}, nil)
+	repoMock.On("FindEntitlements", ctx, cfg.Grpc.SubscriptionUrl, &subscriptionPb.FindEntitlementsReq{PlayerId: "player:002"}).Return(&subscriptionPb.EntitlementsRes{
+		PlayerId: "player:002",
+	}, nil)
+	repoMock.On("FindEntitlements", ctx, cfg.Grpc.SubscriptionUrl, &subscriptionPb.FindEntitlementsReq{PlayerId: "player:003"}).Return((*subscriptionPb.EntitlementsRes)(nil), errors.New("error: grpc conn failed"))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.FindItemPriceFor(test.ctx, cfg, itemId.Hex(), test.playerId)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result.Price)
+			assert.Equal(t, 94.99, result.ListPrice)
+			assert.Equal(t, test.vip, result.Vip)
+		})
+	}
+}
+
+func TestReserveItems(t *testing.T) {
+	repoMock := new(itemRepository.ItemRepositoryMock)
+	usecase := itemUsecase.NewItemUsecase(repoMock)
+
+	ctx := context.Background()
+	limitedId := bson.NewObjectID()
+	soldOutId := bson.NewObjectID()
+	unlimitedId := bson.NewObjectID()
+	stock := int64(1)
+	noStock := int64(0)
+
+	tests := []testReserveItems{
+		{
+			name: "success reserve items",
+			ctx:  ctx,
+			req: &itemPb.ReserveItemsReq{
+				PlayerId: "player:001",
+				Ids:      []string{"item:" + limitedId.Hex(), "item:" + unlimitedId.Hex()},
+			},
+			expected: []string{"item:" + limitedId.Hex(), "item:" + unlimitedId.Hex()},
+			isErr:    false,
+		},
+		{
+			name: "failed reserve items - out of stock",
+			ctx:  ctx,
+			req: &itemPb.ReserveItemsReq{
+				PlayerId: "player:002",
+				Ids:      []string{"item:" + unlimitedId.Hex(), "item:" + soldOutId.Hex()},
+			},
+			expected: nil,
+			isErr:    true,
+		},
+	}
+
+	repoMock.On("FindOneItem", ctx, limitedId.Hex()).Return(&item.Item{
+		Id:            limitedId,
+		Stock:         &stock,
+		PurchaseLimit: 1,
+	}, nil)
+	repoMock.On("FindOneItem", ctx, soldOutId.Hex()).Return(&item.Item{
+		Id:            soldOutId,
+		Stock:         &noStock,
+		PurchaseLimit: 1,
+	}, nil)
+	repoMock.On("FindOneItem", ctx, unlimitedId.Hex()).Return(&item.Item{
+		Id: unlimitedId,
+	}, nil)
+
+	// Success case
+	repoMock.On("ReservePlayerPurchase", ctx, limitedId.Hex(), "player:001", 1).Return(nil)
+	repoMock.On("ReserveItemStock", ctx, limitedId.Hex()).Return(nil)
+
+	// Failed case
+	repoMock.On("ReservePlayerPurchase", ctx, soldOutId.Hex(), "player:002", 1).Return(nil)
+	repoMock.On("ReserveItemStock", ctx, soldOutId.Hex()).Return(errors.New("error: item is out of stock"))
+	repoMock.On("ReleasePlayerPurchase", ctx, soldOutId.Hex(), "player:002").Return(nil)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.ReserveItems(test.ctx, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Ids)
+			}
+		})
+	}
+
+	repoMock.AssertCalled(t, "ReleasePlayerPurchase", ctx, soldOutId.Hex(), "player:002")
+	repoMock.AssertNotCalled(t, "ReleaseItemStock", ctx, soldOutId.Hex())
+}
+
+func TestImportItems(t *testing.T) {
+	ctx := context.Background()
+
+	existing := []*item.Item{
+		{
+			Id:          bson.NewObjectID(),
+			Sku:         "SWORD",
+			Title:       "Sword",
+			Price:       100,
+			Damage:      10,
+			ImageUrl:    "https://example.com/sword.png",
+			UsageStatus: true,
+		},
+		{
+			Id:          bson.NewObjectID(),
+			Sku:         "SHIELD",
+			Title:       "Shield",
+			Price:       100,
+			Damage:      10,
+			ImageUrl:    "https://example.com/shield.png",
+			UsageStatus: true,
+		},
+		{
+			Id:          bson.NewObjectID(),
+			Sku:         "CHEST",
+			Title:       "Chest",
+			Price:       50,
+			ImageUrl:    "https://example.com/chest.png",
+			UsageStatus: true,
+			LootTable:   &item.LootTable{Entries: []*item.LootEntry{{ItemId: "item:001", Weight: 1}}},
+		},
+	}
+
+	csvCatalog := strings.Join([]string{
+		"sku,title,price,damage,image_url,stock",
+		"sword,Sword,120,10,https://example.com/sword.png,",
+		"SHIELD,Shield,100,10,https://example.com/shield.png,",
+		"AXE,Axe,300,40,https://example.com/axe.png,50",
+	}, "\n")
+
+	tests := []testImportItems{
+		{
+			name:     "success dry run csv import",
+			ctx:      ctx,
+			req:      &item.ItemImportReq{Format: "csv", DryRun: true},
+			data:     csvCatalog,
+			existing: existing,
+			expected: &item.ItemImportRes{DryRun: true, Created: 1, Updated: 1, Unchanged: 1},
+			isErr:    false,
+		},
+		{
+			name:     "success apply csv import",
+			ctx:      ctx,
+			req:      &item.ItemImportReq{Format: "csv"},
+			data:     csvCatalog,
+			existing: existing,
+			expected: &item.ItemImportRes{Created: 1, Updated: 1, Unchanged: 1},
+			isErr:    false,
+		},
+		{
+			name: "failed import - invalid rows",
+			ctx:  ctx,
+			req:  &item.ItemImportReq{Format: "json"},
+			data: `[
+				{"sku": "AXE", "title": "Axe", "price": 300, "image_url": "https://example.com/axe.png"},
+				{"sku": "AXE", "title": "Big Axe", "price": 500, "image_url": "https://example.com/axe.png"},
+				{"sku": "BOW", "price": 200, "image_url": "https://example.com/bow.png"}
+			]`,
+			existing: existing,
+			expected: &item.ItemImportRes{Errors: []*item.ItemImportError{{Row: 2}, {Row: 3}}},
+			isErr:    false,
+		},
+		{
+			name:     "failed import - title used by another item",
+			ctx:      ctx,
+			req:      &item.ItemImportReq{Format: "json"},
+			data:     `[{"sku": "BLADE", "title": "Sword", "price": 100, "image_url": "https://example.com/sword.png"}]`,
+			existing: existing,
+			expected: &item.ItemImportRes{Errors: []*item.ItemImportError{{Row: 1}}},
+			isErr:    false,
+		},
+		{
+			name:     "failed import - sku is a loot box",
+			ctx:      ctx,
+			req:      &item.ItemImportReq{Format: "json"},
+			data:     `[{"sku": "CHEST", "title": "Chest", "price": 60, "image_url": "https://example.com/chest.png"}]`,
+			existing: existing,
+			expected: &item.ItemImportRes{Errors: []*item.ItemImportError{{Row: 1}}},
+			isErr:    false,
+		},
+		{
+			name:     "failed import - unsupported format",
+			ctx:      ctx,
+			req:      &item.ItemImportReq{Format: "xlsx"},
+			data:     csvCatalog,
+			existing: existing,
+			expected: nil,
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(itemRepository.ItemRepositoryMock)
+			usecase := itemUsecase.NewItemUsecase(repoMock)
+
+			repoMock.On("FindManyItemDocuments", test.ctx, mock.Anything, mock.Anything).Return(test.existing, nil)
+			repoMock.On("InsertOneItem", test.ctx, mock.AnythingOfType("*item.Item")).Return(bson.NewObjectID(), nil)
+			repoMock.On("UpdateOneItem", test.ctx, mock.Anything, mock.AnythingOfType("bson.M")).Return(nil)
+			repoMock.On("InsertOneItemRevision", test.ctx, mock.AnythingOfType("*item.ItemRevision")).Return(bson.NewObjectID(), nil)
+
+			result, err := usecase.ImportItems(test.ctx, "player:001", test.req, strings.NewReader(test.data))
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Errors, len(test.expected.Errors))
+			for i, rowErr := range test.expected.Errors {
+				assert.Equal(t, rowErr.Row, result.Errors[i].Row)
+			}
+			assert.Equal(t, test.expected.Created, result.Created)
+			assert.Equal(t, test.expected.Updated, result.Updated)
+			assert.Equal(t, test.expected.Unchanged, result.Unchanged)
+
+			if test.req.DryRun || len(test.expected.Errors) > 0 {
+				repoMock.AssertNotCalled(t, "InsertOneItem", mock.Anything, mock.Anything)
+				repoMock.AssertNotCalled(t, "UpdateOneItem", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				repoMock.AssertNumberOfCalls(t, "InsertOneItem", test.expected.Created)
+				repoMock.AssertNumberOfCalls(t, "UpdateOneItem", test.expected.Updated)
+			}
+		})
+	}
+}
+
+func TestExportItems(t *testing.T) {
+	repoMock := new(itemRepository.ItemRepositoryMock)
+	usecase := itemUsecase.NewItemUsecase(repoMock)
+
+	ctx := context.Background()
+	stock := int64(5)
+
+	repoMock.On("FindManyItemDocuments", ctx, mock.Anything, mock.Anything).Return([]*item.Item{
+		{Id: bson.NewObjectID(), Sku: "AXE", Title: "Axe", Price: 300, Damage: 40, ImageUrl: "https://example.com/axe.png", UsageStatus: true, Stock: &stock},
+		{Id: bson.NewObjectID(), Sku: "SWORD", Title: "Sword", Price: 100.5, Damage: 10, ImageUrl: "https://example.com/sword.png", UsageStatus: false},
+	}, nil)
+
+	buf := new(bytes.Buffer)
+	err := usecase.ExportItems(ctx, &item.ItemExportReq{Format: "csv"}, buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"sku,title,price,damage,image_url,usage_status,stock,purchase_limit,slot,level_required,effect_type,effect_value,effect_duration_seconds,cooldown_seconds,duration_hours",
+		"AXE,Axe,300,40,https://example.com/axe.png,true,5,0,,0,,,,0,0",
+		"SWORD,Sword,100.5,10,https://example.com/sword.png,false,,0,,0,,,,0,0",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestExportImportItems(t *testing.T) {
+	ctx := context.Background()
+	stock := int64(5)
+
+	catalog := []*item.Item{
+		{
+			Id:            bson.NewObjectID(),
+			Sku:           "HELM",
+			Title:         "Helm",
+			Price:         250,
+			Damage:        5,
+			Slot:          "head",
+			LevelRequired: 12,
+			ImageUrl:      "https://example.com/helm.png",
+			UsageStatus:   true,
+			Stock:         &stock,
+			PurchaseLimit: 1,
+			DurationHours: 72,
+		},
+		{
+			Id:              bson.NewObjectID(),
+			Sku:             "POTION",
+			Title:           "Potion",
+			Price:           12.5,
+			Effect:          &item.ItemEffect{Type: "heal", Value: 50, DurationSeconds: 10},
+			CooldownSeconds: 30,
+			ImageUrl:        "https://example.com/potion.png",
+			UsageStatus:     false,
+		},
+	}
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run("success export then import - "+format, func(t *testing.T) {
+			repoMock := new(itemRepository.ItemRepositoryMock)
+			usecase := itemUsecase.NewItemUsecase(repoMock)
+
+			repoMock.On("FindManyItemDocuments", ctx, mock.Anything, mock.Anything).Return(catalog, nil).Once()
+			repoMock.On("FindManyItemDocuments", ctx, mock.Anything, mock.Anything).Return([]*item.Item{}, nil).Once()
+			repoMock.On("InsertOneItem", ctx, mock.AnythingOfType("*item.Item")).Return(bson.NewObjectID(), nil)
+
+			buf := new(bytes.Buffer)
+			assert.NoError(t, usecase.ExportItems(ctx, &item.ItemExportReq{Format: format}, buf))
+
+			result, err := usecase.ImportItems(ctx, "player:001", &item.ItemImportReq{Format: format}, buf)
+			assert.NoError(t, err)
+			assert.Empty(t, result.Errors)
+			assert.Equal(t, len(catalog), result.Created)
+
+			for i, call := range repoMock.Calls[2:] {
+				imported := call.Arguments.Get(1).(*item.Item)
+				imported.Id = catalog[i].Id
+				imported.CreatedAt, imported.UpdatedAt = catalog[i].CreatedAt, catalog[i].UpdatedAt
+				assert.Equal(t, catalog[i], imported)
+			}
+		})
+
+		t.Run("success export then import - "+format+" unchanged", func(t *testing.T) {
+			repoMock := new(itemRepository.ItemRepositoryMock)
+			usecase := itemUsecase.NewItemUsecase(repoMock)
+
+			repoMock.On("FindManyItemDocuments", ctx, mock.Anything, mock.Anything).Return(catalog, nil)
+
+			buf := new(bytes.Buffer)
+			assert.NoError(t, usecase.ExportItems(ctx, &item.ItemExportReq{Format: format}, buf))
+
+			result, err := usecase.ImportItems(ctx, "player:001", &item.ItemImportReq{Format: format}, buf)
+			assert.NoError(t, err)
+			assert.Equal(t, len(catalog), result.Unchanged)
+			repoMock.AssertNotCalled(t, "UpdateOneItem", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddWishlistItem(t *testing.T) {
+	ctx := context.Background()
+	itemId := bson.NewObjectID()
+
+	tests := []testAddWishlistItem{
+		{
+			name:  "success add wishlist item",
+			ctx:   ctx,
+			req:   &item.AddWishlistItemReq{ItemId: "item:" + itemId.Hex()},
+			isErr: false,
+		},
+		{
+			name:     "failed add wishlist item - wishlist is full",
+			ctx:      ctx,
+			req:      &item.AddWishlistItemReq{ItemId: "item:" + itemId.Hex()},
+			wishlist: 100,
+			isErr:    true,
+		},
+		{
+			name:  "failed add wishlist item - item not found",
+			ctx:   ctx,
+			req:   &item.AddWishlistItemReq{ItemId: "item:missing"},
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(itemRepository.ItemRepositoryMock)
+			usecase := itemUsecase.NewItemUsecase(repoMock)
+
+			wishlist := make([]*item.WishlistItem, test.wishlist)
+
+			repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{Id: itemId, Price: 120}, nil)
+			repoMock.On("FindOneItem", ctx, "missing").Return((*item.Item)(nil), errors.New("error: item not found"))
+			repoMock.On("FindWishlistItems", ctx, bson.D{{Key: "player_id", Value: "player:001"}}).Return(wishlist, nil)
+			repoMock.On("InsertOneWishlistItem", ctx, mock.Anything).Return(bson.NewObjectID(), nil)
+
+			result, err := usecase.AddWishlistItem(test.ctx, "player:001", test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneWishlistItem", ctx, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "item:"+itemId.Hex(), result.ItemId)
+				assert.Equal(t, 120.0, result.PriceAtAdd)
+			}
+		})
+	}
+}
+
+func TestWatchWishlists(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	itemId := bson.NewObjectID()
+
+	revision := func(previousPrice, price float64, diff ...*item.ItemRevisionDiff) *item.ItemRevision {
+		return &item.ItemRevision{
+			Id:            bson.NewObjectID(),
+			ItemId:        "item:" + itemId.Hex(),
+			Diff:          diff,
+			PreviousPrice: previousPrice,
+			Price:         price,
+		}
+	}
+
+	tests := []testWatchWishlists{
+		{
+			name:        "success price drop",
+			ctx:         ctx,
+			revision:    revision(100, 80, &item.ItemRevisionDiff{Field: "price", Old: 100.0, New: 80.0}),
+			usageStatus: true,
+			expected:    "price_drop",
+		},
+		{
+			name:        "success item re-enabled",
+			ctx:         ctx,
+			revision:    revision(100, 100, &item.ItemRevisionDiff{Field: "usage_status", Old: false, New: true}),
+			usageStatus: true,
+			expected:    "available",
+		},
+		{
+			name:        "success back in stock",
+			ctx:         ctx,
+			revision:    revision(100, 100, &item.ItemRevisionDiff{Field: "stock", Old: int64(0), New: int64(20)}),
+			usageStatus: true,
+			expected:    "restock",
+		},
+		{
+			name:        "success price rise is ignored",
+			ctx:         ctx,
+			revision:    revision(100, 150, &item.ItemRevisionDiff{Field: "price", Old: 100.0, New: 150.0}),
+			usageStatus: true,
+			expected:    "",
+		},
+		{
+			name:        "success price drop of a disabled item is ignored",
+			ctx:         ctx,
+			revision:    revision(100, 80, &item.ItemRevisionDiff{Field: "price", Old: 100.0, New: 80.0}),
+			usageStatus: false,
+			expected:    "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(itemRepository.ItemRepositoryMock)
+			usecase := itemUsecase.NewItemUsecase(repoMock)
+
+			repoMock.On("GetWishlistWatch", ctx).Return(bson.NilObjectID, nil)
+			repoMock.On("FindItemRevisions", ctx, bson.D{}, mock.Anything).Return([]*item.ItemRevision{test.revision}, nil)
+			repoMock.On("FindOneItem", ctx, itemId.Hex()).Return(&item.Item{Id: itemId, Title: "Sword", UsageStatus: test.usageStatus}, nil)
+			repoMock.On("FindWishlistItems", ctx, bson.D{{Key: "item_id", Value: "item:" + itemId.Hex()}}).Return([]*item.WishlistItem{
+				{PlayerId: "player:001", ItemId: "item:" + itemId.Hex()},
+			}, nil)
+			repoMock.On("InsertOneWishlistNotification", ctx, mock.Anything).Return(bson.NewObjectID(), nil)
+			repoMock.On("PushEvent", ctx, cfg, mock.Anything).Return(nil)
+			repoMock.On("UpsertWishlistWatch", ctx, test.revision.Id).Return(nil)
+
+			err := usecase.WatchWishlists(test.ctx, cfg)
+
+			assert.NoError(t, err)
+			repoMock.AssertCalled(t, "UpsertWishlistWatch", ctx, test.revision.Id)
+			if test.expected == "" {
+				repoMock.AssertNotCalled(t, "InsertOneWishlistNotification", ctx, mock.Anything)
+				repoMock.AssertNotCalled(t, "PushEvent", ctx, cfg, mock.Anything)
+				return
+			}
+			repoMock.AssertCalled(t, "InsertOneWishlistNotification", ctx, mock.MatchedBy(func(req *item.WishlistNotification) bool {
+				return req.Kind == test.expected && req.PlayerId == "player:001" && req.RevisionId == test.revision.Id.Hex()
+			}))
+			repoMock.AssertCalled(t, "PushEvent", ctx, cfg, mock.MatchedBy(func(req *models.DomainEvent) bool {
+				return req.Type == "wishlist."+test.expected && req.PlayerId == "player:001"
+			}))
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/marketplace/marketplaceUsecase"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testCreateListing struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		req      *marketplace.CreateListingReq
+		isErr    bool
+	}
+
+	testBuyListing struct {
+		name        string
+		ctx         context.Context
+		grantErr    error
+		rollbackErr error
+		expected    string
+		isErr       bool
+	}
+
+	testPlaceBid struct {
+		name      string
+		ctx       context.Context
+		playerId  string
+		req       *marketplace.PlaceBidReq
+		updateErr error
+		isErr     bool
+	}
+
+	testSettleListing struct {
+		name     string
+		ctx      context.Context
+		listing  *marketplace.Listing
+		expected string
+	}
+
+	testRecoverListings struct {
+		name     string
+		ctx      context.Context
+		listing  *marketplace.Listing
+		expected string
+	}
+)
+
+func TestCreateListing(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	listingId := bson.NewObjectID()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testCreateListing{
+		{
+			name:     "success create listing",
+			ctx:      ctx,
+			playerId: "player:001",
+			req:      &marketplace.CreateListingReq{ItemId: swordId, Kind: "auction", Price: 100},
+			isErr:    false,
+		},
+		{
+			name:     "failed create listing - item not owned refunds fee",
+			ctx:      ctx,
+			playerId: "player:002",
+			req:      &marketplace.CreateListingReq{ItemId: swordId, Kind: "fixed", Price: 100},
+			isErr:    true,
+		},
+		{
+			name:     "failed create listing - invalid kind",
+			ctx:      ctx,
+			playerId: "player:001",
+			req:      &marketplace.CreateListingReq{ItemId: swordId, Kind: "barter", Price: 100},
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(marketplaceRepository.MarketplaceRepositoryMock)
+			usecase := marketplaceUsecase.NewMarketplaceUsecase(repoMock)
+
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{
+				PlayerId: test.playerId,
+				Amount:   -cfg.Marketplace.ListingFee,
+			}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "fee001"}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "fee001"}).Return(nil)
+			if test.playerId == "player:001" {
+				repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchRevokeRes{
+					Items: []*inventoryPb.InventoryItem{{InventoryId: "inventory:001", PlayerId: test.playerId, ItemId: swordId}},
+				}, nil)
+			} else {
+				repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return((*inventoryPb.BatchRevokeRes)(nil), errors.New("error: player does not have enough items"))
+			}
+			repoMock.On("InsertOneListing", ctx, mock.Anything).Return(listingId, nil)
+
+			result, err := usecase.CreateListing(test.ctx, cfg, test.playerId, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneListing", ctx, mock.Anything)
+				if test.playerId == "player:002" {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "fee001"})
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, listingId, result.Id)
+				assert.Equal(t, "active", result.Status)
+				assert.Equal(t, "inventory:001", result.InventoryId)
+				assert.Equal(t, cfg.Marketplace.ListingFee, result.ListingFee)
+			}
+		})
+	}
+}
+
+func TestBuyListing(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testBuyListing{
+		{
+			name:     "success buy listing pays the seller before granting",
+			ctx:      ctx,
+			expected: "sold",
+			isErr:    false,
+		},
+		{
+			name:     "failed buy listing - grant fails refunds buyer and seller proceeds",
+			ctx:      ctx,
+			grantErr: errors.New("error: insert many player items failed"),
+			expected: "active",
+			isErr:    true,
+		},
+		{
+			name:        "failed buy listing - failed refund leaves listing processing",
+			ctx:         ctx,
+			grantErr:    errors.New("error: insert many player items failed"),
+			rollbackErr: errors.New("error: rollback player transaction failed"),
+			expected:    "processing",
+			isErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(marketplaceRepository.MarketplaceRepositoryMock)
+			usecase := marketplaceUsecase.NewMarketplaceUsecase(repoMock)
+
+			listing := &marketplace.Listing{
+				Id:        bson.NewObjectID(),
+				SellerId:  "player:001",
+				ItemId:    swordId,
+				Kind:      "fixed",
+				Price:     100,
+				Status:    "active",
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+			listingId := listing.Id.Hex()
+			unlocked := mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == "active"
+			})
+
+			repoMock.On("FindOneListing", ctx, listingId).Return(listing, nil)
+			repoMock.On("LockListing", ctx, listingId, 0).Return(nil)
+			repoMock.On("UpdateOneListing", ctx, listingId, mock.Anything).Return(nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:002", Amount: -100}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "buy001"}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: 95}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "sale001"}, nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{}, test.grantErr)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(test.rollbackErr)
+
+			result, err := usecase.BuyListing(test.ctx, cfg, "player:002", listingId)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "BatchRevoke", ctx, cfg.Grpc.InventoryUrl, mock.Anything)
+				if test.expected == "active" {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "sale001"})
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "buy001"})
+					repoMock.AssertCalled(t, "UpdateOneListing", ctx, listingId, unlocked)
+				} else {
+					repoMock.AssertNotCalled(t, "UpdateOneListing", ctx, listingId, unlocked)
+					repoMock.AssertCalled(t, "UpdateOneListing", ctx, listingId, mock.MatchedBy(func(req bson.M) bool {
+						settlement, ok := req["settlement"].(*marketplace.ListingSettlement)
+						return ok && settlement.Proceeds == "sale001" && settlement.Payment == "buy001" && !settlement.Granting
+					}))
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Status)
+				assert.Equal(t, "player:002", result.BuyerId)
+				assert.Equal(t, 5.0, result.Tax)
+				assert.Nil(t, result.Settlement)
+				repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPlaceBid(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	listingId := bson.NewObjectID()
+
+	tests := []testPlaceBid{
+		{
+			name:     "success place bid refunds previous bidder",
+			ctx:      ctx,
+			playerId: "player:003",
+			req:      &marketplace.PlaceBidReq{Amount: 200},
+			isErr:    false,
+		},
+		{
+			name:      "failed place bid - saving the bid releases it and keeps the previous one",
+			ctx:       ctx,
+			playerId:  "player:003",
+			req:       &marketplace.PlaceBidReq{Amount: 200},
+			updateErr: errors.New("error: update one listing failed"),
+			isErr:     true,
+		},
+		{
+			name:     "failed place bid - not higher than current bid",
+			ctx:      ctx,
+			playerId: "player:003",
+			req:      &marketplace.PlaceBidReq{Amount: 150},
+			isErr:    true,
+		},
+		{
+			name:     "failed place bid - seller cannot bid",
+			ctx:      ctx,
+			playerId: "player:001",
+			req:      &marketplace.PlaceBidReq{Amount: 500},
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(marketplaceRepository.MarketplaceRepositoryMock)
+			usecase := marketplaceUsecase.NewMarketplaceUsecase(repoMock)
+
+			repoMock.On("FindOneListing", ctx, listingId.Hex()).Return(&marketplace.Listing{
+				Id:         listingId,
+				SellerId:   "player:001",
+				Kind:       "auction",
+				Price:      100,
+				HighestBid: &marketplace.ListingBid{PlayerId: "player:002", Amount: 150, TransactionId: "bid001"},
+				BidCount:   1,
+				Status:     "active",
+				ExpiresAt:  time.Now().Add(time.Hour),
+			}, nil)
+			repoMock.On("LockListing", ctx, listingId.Hex(), 1).Return(nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "bid002"}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("UpdateOneListing", ctx, listingId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+				_, ok := req["highest_bid"]
+				return ok
+			})).Return(test.updateErr)
+			repoMock.On("UpdateOneListing", ctx, listingId.Hex(), mock.Anything).Return(nil)
+
+			result, err := usecase.PlaceBid(test.ctx, cfg, test.playerId, listingId.Hex(), test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if test.updateErr != nil {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "bid002"})
+					repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "bid001"})
+					repoMock.AssertCalled(t, "UpdateOneListing", ctx, listingId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+						return req["status"] == "active"
+					}))
+				} else {
+					repoMock.AssertNotCalled(t, "LockListing", ctx, listingId.Hex(), 1)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.playerId, result.HighestBid.PlayerId)
+				assert.Equal(t, test.req.Amount, result.HighestBid.Amount)
+				assert.Equal(t, 2, result.BidCount)
+				assert.Equal(t, "active", result.Status)
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "bid001"})
+			}
+		})
+	}
+}
+
+func TestSettleListing(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testSettleListing{
+		{
+			name: "success settle auction to highest bidder",
+			ctx:  ctx,
+			listing: &marketplace.Listing{
+				Id:         bson.NewObjectID(),
+				SellerId:   "player:001",
+				ItemId:     swordId,
+				Kind:       "auction",
+				Price:      100,
+				HighestBid: &marketplace.ListingBid{PlayerId: "player:002", Amount: 200, TransactionId: "bid001"},
+				BidCount:   1,
+				Status:     "active",
+				ExpiresAt:  time.Now().Add(-time.Minute),
+			},
+			expected: "sold",
+		},
+		{
+			name: "success settle unsold listing back to seller",
+			ctx:  ctx,
+			listing: &marketplace.Listing{
+				Id:        bson.NewObjectID(),
+				SellerId:  "player:001",
+				ItemId:    swordId,
+				Kind:      "fixed",
+				Price:     100,
+				Status:    "active",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			},
+			expected: "expired",
+		},
+		{
+			name: "success settle skips listing not yet expired",
+			ctx:  ctx,
+			listing: &marketplace.Listing{
+				Id:        bson.NewObjectID(),
+				SellerId:  "player:001",
+				ItemId:    swordId,
+				Kind:      "fixed",
+				Price:     100,
+				Status:    "active",
+				ExpiresAt: time.Now().Add(time.Hour),
+			},
+			expected: "active",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(marketplaceRepository.MarketplaceRepositoryMock)
+			usecase := marketplaceUsecase.NewMarketplaceUsecase(repoMock)
+
+			listingId := test.listing.Id.Hex()
+			proceeds := &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: 190}
+
+			repoMock.On("FindOneListing", ctx, listingId).Return(test.listing, nil)
+			repoMock.On("LockListing", ctx, listingId, test.listing.BidCount).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, proceeds).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "sale001"}, nil)
+			repoMock.On("UpdateOneListing", ctx, listingId, mock.Anything).Return(nil)
+
+			err := usecase.SettleListing(test.ctx, cfg, &marketplace.SettleListingReq{ListingId: listingId})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, test.listing.Status)
+			switch test.expected {
+			case "sold":
+				assert.Equal(t, "player:002", test.listing.BuyerId)
+				assert.Equal(t, 10.0, test.listing.Tax)
+				repoMock.AssertCalled(t, "BatchGrant", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+					Items:             []*inventoryPb.GrantItem{{PlayerId: "player:002", ItemId: swordId, Quantity: 1}},
+					OverflowToMailbox: true,
+					Source:            "marketplace",
+				})
+				repoMock.AssertCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, proceeds)
+			case "expired":
+				repoMock.AssertCalled(t, "BatchGrant", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+					Items:             []*inventoryPb.GrantItem{{PlayerId: "player:001", ItemId: swordId, Quantity: 1}},
+					OverflowToMailbox: true,
+				})
+			default:
+				repoMock.AssertNotCalled(t, "LockListing", ctx, listingId, test.listing.BidCount)
+			}
+		})
+	}
+}
+
+func TestRecoverListings(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	newListing := func(settlement *marketplace.ListingSettlement) *marketplace.Listing {
+		return &marketplace.Listing{
+			Id:         bson.NewObjectID(),
+			SellerId:   "player:001",
+			ItemId:     swordId,
+			Kind:       "auction",
+			Price:      100,
+			HighestBid: &marketplace.ListingBid{PlayerId: "player:003", Amount: 200, TransactionId: "bid002"},
+			BidCount:   2,
+			Status:     "processing",
+			Settlement: settlement,
+			UpdatedAt:  time.Now().Add(-time.Hour),
+		}
+	}
+
+	tests := []testRecoverListings{
+		{
+			name:     "success recover listing - outbid bid is refunded",
+			ctx:      ctx,
+			listing:  newListing(&marketplace.ListingSettlement{Refund: "bid001"}),
+			expected: "active",
+		},
+		{
+			name:     "success recover listing - granted sale is finished",
+			ctx:      ctx,
+			listing:  newListing(&marketplace.ListingSettlement{BuyerId: "player:003", Price: 200, Tax: 10, Proceeds: "sale001", Granting: true}),
+			expected: "sold",
+		},
+		{
+			name:     "success recover listing - sale without the item is undone",
+			ctx:      ctx,
+			listing:  newListing(&marketplace.ListingSettlement{BuyerId: "player:003", Price: 200, Tax: 10, Proceeds: "sale001"}),
+			expected: "active",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(marketplaceRepository.MarketplaceRepositoryMock)
+			usecase := marketplaceUsecase.NewMarketplaceUsecase(repoMock)
+
+			listingId := test.listing.Id.Hex()
+			settlement := *test.listing.Settlement
+
+			repoMock.On("FindStaleListings", ctx, mock.Anything).Return([]*marketplace.Listing{test.listing}, nil)
+			repoMock.On("ClaimStaleListing", ctx, listingId, test.listing.UpdatedAt).Return(nil)
+			repoMock.On("UpdateOneListing", ctx, listingId, mock.Anything).Return(nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+
+			err := usecase.RecoverListings(test.ctx, cfg)
+
+			assert.NoError(t, err)
+			repoMock.AssertCalled(t, "UpdateOneListing", ctx, listingId, mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == test.expected
+			}))
+			repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "bid002"})
+			switch {
+			case settlement.Refund != "":
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "bid001"})
+			case settlement.Granting:
+				assert.Equal(t, "player:003", test.listing.BuyerId)
+				assert.Equal(t, 10.0, test.listing.Tax)
+				repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+			default:
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "sale001"})
+			}
+		})
+	}
+}
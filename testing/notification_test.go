@@ -0,0 +1,228 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Supakornn/mmorpg-shop/modules/models"
+	"github.com/Supakornn/mmorpg-shop/modules/notification"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/notification/notificationUsecase"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testStoreEvent struct {
+		name      string
+		ctx       context.Context
+		event     string
+		insertErr error
+		expected  string
+		stored    bool
+		isErr     bool
+	}
+
+	testPushSagaStep struct {
+		name     string
+		key      string
+		res      *payment.PaymentTransferRes
+		expected string
+	}
+
+	testFindNotifications struct {
+		name     string
+		ctx      context.Context
+		req      *notification.NotificationSearchReq
+		expected bson.D
+		limit    int64
+	}
+)
+
+func TestStoreEvent(t *testing.T) {
+	ctx := context.Background()
+	notificationId := bson.NewObjectID()
+
+	tests := []testStoreEvent{
+		{
+			name:     "success store purchase",
+			ctx:      ctx,
+			event:    `{"type":"purchase.completed","player_id":"player:001","payload":{"item_ids":["item:001","item:002"],"amount":150}}`,
+			expected: "You bought 2 item(s) for 150.00.",
+			stored:   true,
+		},
+		{
+			name:     "success store refund",
+			ctx:      ctx,
+			event:    `{"type":"payment.refunded","player_id":"001","payload":{"amount":50,"reason":"loot box could not be opened"}}`,
+			expected: "50.00 was refunded to your account because the loot box could not be opened.",
+			stored:   true,
+		},
+		{
+			name:  "skip unknown event",
+			ctx:   ctx,
+			event: `{"type":"item.used","player_id":"player:001","payload":{}}`,
+		},
+		{
+			name:  "skip event without player",
+			ctx:   ctx,
+			event: `{"type":"purchase.completed","payload":{}}`,
+		},
+		{
+			name:      "failed store - event replayed",
+			ctx:       ctx,
+			event:     `{"type":"sale.failed","player_id":"player:001","payload":{"error":"error: item not found"}}`,
+			insertErr: errors.New("error: notification already stored"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(notificationRepository.NotificationRepositoryMock)
+			usecase := notificationUsecase.NewNotificationUsecase(repoMock)
+
+			repoMock.On("InsertOneNotification", ctx, mock.Anything).Return(notificationId, test.insertErr)
+
+			messages, unsubscribe := usecase.Subscribe("player:001")
+			defer unsubscribe()
+
+			event := new(models.DomainEvent)
+			assert.NoError(t, json.Unmarshal([]byte(test.event), event))
+
+			result, err := usecase.StoreEvent(test.ctx, 7, event)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Empty(t, messages)
+				return
+			}
+
+			assert.NoError(t, err)
+			if !test.stored {
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneNotification", ctx, mock.Anything)
+				return
+			}
+
+			assert.Equal(t, "player:001", result.PlayerId)
+			assert.Equal(t, test.expected, result.Message)
+			assert.Equal(t, int64(7), result.Offset)
+			assert.False(t, result.Read)
+
+			published := new(notification.Notification)
+			assert.NoError(t, json.Unmarshal(<-messages, published))
+			assert.Equal(t, notificationId, published.Id)
+			assert.Equal(t, test.expected, published.Message)
+		})
+	}
+}
+
+func TestPushSagaStep(t *testing.T) {
+	tests := []testPushSagaStep{
+		{
+			name:     "success push money docked",
+			key:      "buy",
+			res:      &payment.PaymentTransferRes{PlayerId: "player:001", TransactionId: "tx001", Amount: -100},
+			expected: "balance.changed",
+		},
+		{
+			name:     "success push item added",
+			key:      "buy",
+			res:      &payment.PaymentTransferRes{PlayerId: "001", ItemId: "item:001", InventoryId: "inv001"},
+			expected: "inventory.added",
+		},
+		{
+			name:     "success push bundle added",
+			key:      "buy",
+			res:      &payment.PaymentTransferRes{PlayerId: "player:001", ItemId: "item:002", InventoryIds: []string{"inv002", "inv003"}},
+			expected: "inventory.added",
+		},
+		{
+			name:     "success push item removed",
+			key:      "sell",
+			res:      &payment.PaymentTransferRes{PlayerId: "player:001", ItemId: "item:001", InventoryId: "inv001"},
+			expected: "inventory.removed",
+		},
+		{
+			name:     "success push failed step",
+			key:      "buy",
+			res:      &payment.PaymentTransferRes{PlayerId: "player:001", Error: "error: player balance is not enough"},
+			expected: "saga.step_failed",
+		},
+		{
+			name: "skip other player",
+			key:  "buy",
+			res:  &payment.PaymentTransferRes{PlayerId: "player:002", TransactionId: "tx002"},
+		},
+		{
+			name: "skip step without changes",
+			key:  "buy",
+			res:  &payment.PaymentTransferRes{PlayerId: "player:001", ItemId: "item:001"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(notificationRepository.NotificationRepositoryMock)
+			usecase := notificationUsecase.NewNotificationUsecase(repoMock)
+
+			updates, unsubscribe := usecase.SubscribeUpdates("player:001")
+			defer unsubscribe()
+
+			usecase.PushSagaStep(test.key, test.res)
+
+			if test.expected == "" {
+				assert.Empty(t, updates)
+				return
+			}
+
+			update := new(notification.Update)
+			assert.NoError(t, json.Unmarshal(<-updates, update))
+			assert.Equal(t, test.expected, update.Type)
+		})
+	}
+}
+
+func TestFindNotifications(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []testFindNotifications{
+		{
+			name:     "success find all",
+			ctx:      ctx,
+			req:      &notification.NotificationSearchReq{},
+			expected: bson.D{{Key: "player_id", Value: "player:001"}},
+			limit:    50,
+		},
+		{
+			name:     "success find unread",
+			ctx:      ctx,
+			req:      &notification.NotificationSearchReq{Unread: true, Limit: 10},
+			expected: bson.D{{Key: "player_id", Value: "player:001"}, {Key: "read", Value: false}},
+			limit:    10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(notificationRepository.NotificationRepositoryMock)
+			usecase := notificationUsecase.NewNotificationUsecase(repoMock)
+
+			repoMock.On("FindNotifications", ctx, test.expected, test.limit).Return([]*notification.Notification{
+				{Id: bson.NewObjectID(), PlayerId: "player:001", Type: "items.granted"},
+			}, nil)
+
+			result, err := usecase.FindNotifications(test.ctx, "001", test.req)
+
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
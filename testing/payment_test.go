@@ -3,12 +3,24 @@ package testing
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/Supakornn/mmorpg-shop/config"
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	itemPb "github.com/Supakornn/mmorpg-shop/modules/item/itemPb"
 	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	paymentPb "github.com/Supakornn/mmorpg-shop/modules/payment/paymentPb"
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentRepository"
 	"github.com/Supakornn/mmorpg-shop/modules/payment/paymentUsecase"
+	"github.com/Supakornn/mmorpg-shop/modules/player"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type (
@@ -33,6 +45,93 @@ type (
 		req     []*payment.ItemServiceReqDatum
 		isErr   bool
 	}
+
+	testGrant struct {
+		name     string
+		ctx      context.Context
+		cfg      *config.Config
+		req      *paymentPb.GrantReq
+		expected string
+		isErr    bool
+	}
+
+	testGiftItem struct {
+		name      string
+		ctx       context.Context
+		senderId  string
+		req       *payment.GiftReq
+		createdAt time.Time
+		limitHit  bool
+		isErr     bool
+	}
+
+	testOpenLootBox struct {
+		name      string
+		ctx       context.Context
+		req       *payment.OpenLootBoxReq
+		table     *itemPb.LootTable
+		price     float64
+		pity      int
+		revokeErr error
+		grantErr  error
+		expected  string
+		isErr     bool
+	}
+
+	testCraftItem struct {
+		name      string
+		ctx       context.Context
+		recipe    *itemPb.Recipe
+		revokeErr error
+		grantErr  error
+		expected  string
+		success   bool
+		isErr     bool
+	}
+
+	testBuySeasonPass struct {
+		name      string
+		ctx       context.Context
+		chargeErr error
+		unlockErr error
+		isErr     bool
+	}
+
+	testGetOrder struct {
+		name     string
+		ctx      context.Context
+		req      *paymentPb.GetOrderReq
+		expected string
+		isErr    bool
+	}
+
+	testBuyItemAsync struct {
+		name      string
+		ctx       context.Context
+		req       *payment.BuyItemReq
+		insertErr error
+		panics    bool
+		isErr     bool
+	}
+
+	testRecoverOrders struct {
+		name        string
+		ctx         context.Context
+		kind        string
+		reservedIds []string
+		findErr     error
+		claimErr    error
+		isErr       bool
+	}
+
+	testFindOrderStatus struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		orderId  string
+		expected string
+		isErr    bool
+	}
 )
 
 func TestPaymentGetOffset(t *testing.T) {
@@ -119,3 +218,866 @@ func TestPaymentUpsertOffset(t *testing.T) {
 
 // Note: BuyItem และ SellItem methods ซับซ้อนมากเนื่องจากมี async processing
 // และ transaction queue ที่ต้อง mock หลายส่วน ซึ่งเหมาะกับ integration test มากกว่า unit test
+
+func TestFindeItemsInIds(t *testing.T) {
+	repoMock := new(paymentRepository.PaymentRepositoryMock)
+	usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+	ctx := context.Background()
+	grpcUrl := "localhost:1523"
+	swordId := bson.NewObjectID()
+
+	tests := []testFindItemsInIds{
+		{
+			name:    "success find items by id and sku",
+			ctx:     ctx,
+			grpcUrl: grpcUrl,
+			req: []*payment.ItemServiceReqDatum{
+				{ItemId: "item:" + swordId.Hex()},
+				{ItemId: "sku:sword"},
+			},
+			isErr: false,
+		},
+		{
+			name:    "failed find items - unknown sku",
+			ctx:     ctx,
+			grpcUrl: grpcUrl,
+			req: []*payment.ItemServiceReqDatum{
+				{ItemId: "sku:AXE"},
+			},
+			isErr: true,
+		},
+	}
+
+	repoMock.On("FindItemsInIds", ctx, grpcUrl, mock.Anything).Return(&itemPb.FindItemsInIdsRes{
+		Items: []*itemPb.Item{
+			{Id: "item:" + swordId.Hex(), Sku: "SWORD", Title: "Sword", Price: 100},
+		},
+	}, nil)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := usecase.FindeItemsInIds(test.ctx, test.grpcUrl, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				for _, v := range test.req {
+					assert.Equal(t, "item:"+swordId.Hex(), v.ItemId)
+					assert.Equal(t, 100.0, v.Price)
+				}
+			}
+		})
+	}
+}
+
+func TestGrant(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Grpc: config.Grpc{ItemUrl: "localhost:1523", InventoryUrl: "localhost:1524"}}
+	swordId := bson.NewObjectID()
+	orderId := bson.NewObjectID()
+
+	tests := []testGrant{
+		{
+			name:     "success grant item",
+			ctx:      ctx,
+			cfg:      cfg,
+			req:      &paymentPb.GrantReq{PlayerId: "player:001", ItemIds: []string{"sku:SWORD"}, Reason: "quest"},
+			expected: "completed",
+			isErr:    false,
+		},
+		{
+			name:     "failed grant item - inventory unavailable",
+			ctx:      ctx,
+			cfg:      cfg,
+			req:      &paymentPb.GrantReq{PlayerId: "player:002", ItemIds: []string{"sku:SWORD"}, Reason: "quest"},
+			expected: "failed",
+			isErr:    false,
+		},
+		{
+			name:  "failed grant item - player_id is required",
+			ctx:   ctx,
+			cfg:   cfg,
+			req:   &paymentPb.GrantReq{ItemIds: []string{"sku:SWORD"}},
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			repoMock.On("PushEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, mock.Anything).Return(&itemPb.FindItemsInIdsRes{
+				Items: []*itemPb.Item{
+					{Id: "item:" + swordId.Hex(), Sku: "SWORD", Title: "Sword", Price: 100},
+				},
+			}, nil)
+			repoMock.On("InsertOnePaymentOrder", ctx, mock.Anything).Return(orderId, nil)
+			repoMock.On("UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.Anything).Return(nil)
+
+			if test.expected == "completed" {
+				repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{
+					Items: []*inventoryPb.InventoryItem{
+						{InventoryId: "inventory:001", PlayerId: test.req.PlayerId, ItemId: "item:" + swordId.Hex()},
+					},
+				}, nil)
+			} else {
+				repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return((*inventoryPb.BatchGrantRes)(nil), errors.New("error: inventory unavailable"))
+			}
+
+			result, err := usecase.Grant(test.ctx, test.cfg, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Order.Status)
+				assert.Equal(t, "order:"+orderId.Hex(), result.Order.OrderId)
+				assert.Equal(t, "grant", result.Order.Kind)
+				assert.Equal(t, 0.0, result.Order.Amount)
+				if test.expected == "completed" {
+					assert.Equal(t, "item:"+swordId.Hex(), result.Order.Items[0].ItemId)
+					assert.Equal(t, []string{"inventory:001"}, result.Order.Items[0].InventoryIds)
+				} else {
+					assert.NotEmpty(t, result.Order.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	repoMock := new(paymentRepository.PaymentRepositoryMock)
+	usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+	ctx := context.Background()
+	orderId := bson.NewObjectID()
+
+	tests := []testGetOrder{
+		{
+			name:     "success get order",
+			ctx:      ctx,
+			req:      &paymentPb.GetOrderReq{OrderId: "order:" + orderId.Hex()},
+			expected: "completed",
+			isErr:    false,
+		},
+		{
+			name:  "failed get order - not found",
+			ctx:   ctx,
+			req:   &paymentPb.GetOrderReq{OrderId: "order:" + bson.NilObjectID.Hex()},
+			isErr: true,
+		},
+	}
+
+	repoMock.On("FindOnePaymentOrder", ctx, orderId.Hex()).Return(&payment.PaymentOrder{
+		Id:       orderId,
+		PlayerId: "player:001",
+		Kind:     "buy",
+		Status:   "completed",
+		Amount:   100,
+	}, nil)
+	repoMock.On("FindOnePaymentOrder", ctx, bson.NilObjectID.Hex()).Return((*payment.PaymentOrder)(nil), errors.New("error: payment order not found"))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.GetOrder(test.ctx, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Order.Status)
+				assert.Equal(t, test.req.OrderId, result.Order.OrderId)
+			}
+		})
+	}
+}
+
+func TestBuyItemAsync(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	orderId := bson.NewObjectID()
+	items := payment.ItemServiceReq{Items: []*payment.ItemServiceReqDatum{{ItemId: "item:001"}}}
+
+	tests := []testBuyItemAsync{
+		{
+			name: "success accept order",
+			ctx:  ctx,
+			req:  &payment.BuyItemReq{ItemServiceReq: items, Async: true},
+		},
+		{
+			name: "success accept order with callback",
+			ctx:  ctx,
+			req:  &payment.BuyItemReq{ItemServiceReq: items, Async: true, CallbackUrl: "https://example.com/orders"},
+		},
+		{
+			name:   "success accept order - panic in the saga fails the order",
+			ctx:    ctx,
+			req:    &payment.BuyItemReq{ItemServiceReq: items, Async: true},
+			panics: true,
+		},
+		{
+			name:  "failed accept order - callback host not allowed",
+			ctx:   ctx,
+			req:   &payment.BuyItemReq{ItemServiceReq: items, Async: true, CallbackUrl: "http://localhost:1327/payment_v1/orders"},
+			isErr: true,
+		},
+		{
+			name:  "failed accept order - callback to metadata endpoint",
+			ctx:   ctx,
+			req:   &payment.BuyItemReq{ItemServiceReq: items, Async: true, CallbackUrl: "http://169.254.169.254/latest/meta-data"},
+			isErr: true,
+		},
+		{
+			name:  "failed accept order - empty cart",
+			ctx:   ctx,
+			req:   &payment.BuyItemReq{Async: true},
+			isErr: true,
+		},
+		{
+			name:      "failed accept order - insert fails",
+			ctx:       ctx,
+			req:       &payment.BuyItemReq{ItemServiceReq: items, Async: true},
+			insertErr: errors.New("error: insert one payment order failed"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			done := make(chan struct{})
+			finished := func(mock.Arguments) { close(done) }
+
+			// A saga that panics never gets to publish its result
+			event := repoMock.On("PushEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			if test.panics {
+				event.Maybe()
+			}
+			repoMock.On("InsertOnePaymentOrder", ctx, mock.MatchedBy(func(req *payment.PaymentOrder) bool {
+				return req.Status == "pending" && req.Kind == "buy" && req.CallbackUrl == test.req.CallbackUrl
+			})).Return(orderId, test.insertErr)
+			// The saga fails at the item lookup, so the order ends as failed
+			if test.panics {
+				repoMock.On("FindItemsInIds", mock.Anything, cfg.Grpc.ItemUrl, mock.Anything).Return((*itemPb.FindItemsInIdsRes)(nil), nil)
+			} else {
+				repoMock.On("FindItemsInIds", mock.Anything, cfg.Grpc.ItemUrl, mock.Anything).Return((*itemPb.FindItemsInIdsRes)(nil), errors.New("error: item service unavailable"))
+			}
+
+			update := repoMock.On("UpdateOnePaymentOrder", mock.Anything, orderId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == "failed"
+			})).Return(nil)
+			if test.req.CallbackUrl == "" {
+				update.Run(finished)
+			} else {
+				repoMock.On("PostOrderCallback", mock.Anything, cfg, test.req.CallbackUrl, mock.MatchedBy(func(req *payment.OrderStatusRes) bool {
+					return req.OrderId == "order:"+orderId.Hex() && req.Status == "failed"
+				})).Return(nil).Run(finished)
+			}
+
+			result, err := usecase.BuyItemAsync(test.ctx, cfg, "player:001", test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "order:"+orderId.Hex(), result.OrderId)
+			assert.Equal(t, "pending", result.Status)
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("order was not completed in the background")
+			}
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecoverOrders(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	orderId := bson.NewObjectID()
+	updatedAt := time.Now().Add(-time.Hour)
+
+	tests := []testRecoverOrders{
+		{
+			name:        "success recover buy order - steps rolled back and stock released",
+			ctx:         ctx,
+			kind:        "buy",
+			reservedIds: []string{"item:001"},
+		},
+		{
+			name: "success recover buy order - nothing reserved",
+			ctx:  ctx,
+			kind: "buy",
+		},
+		{
+			name: "success recover sell order",
+			ctx:  ctx,
+			kind: "sell",
+		},
+		{
+			name:     "success recover - order claimed by another run is skipped",
+			ctx:      ctx,
+			kind:     "buy",
+			claimErr: errors.New("error: order is already being recovered"),
+		},
+		{
+			name:    "failed recover - find stale orders fails",
+			ctx:     ctx,
+			kind:    "buy",
+			findErr: errors.New("error: find stale orders failed"),
+			isErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			order := &payment.PaymentOrder{
+				Id:          orderId,
+				PlayerId:    "player:001",
+				Kind:        test.kind,
+				Status:      "pending",
+				Items:       []*payment.PaymentOrderItem{{ItemId: "item:001"}},
+				ReservedIds: test.reservedIds,
+				UpdatedAt:   updatedAt,
+			}
+
+			repoMock.On("FindStaleOrders", ctx, mock.Anything).Return([]*payment.PaymentOrder{order}, test.findErr)
+			repoMock.On("ClaimStaleOrder", ctx, orderId.Hex(), updatedAt).Return(test.claimErr)
+			repoMock.On("RollbackTransaction", ctx, cfg, mock.Anything).Return(nil)
+			repoMock.On("RollbackAddPlayerItem", ctx, cfg, mock.Anything).Return(nil)
+			repoMock.On("RollbackRemovePlayerItem", ctx, cfg, mock.Anything).Return(nil)
+			repoMock.On("ReleaseItems", ctx, cfg.Grpc.ItemUrl, mock.Anything).Return(&itemPb.ReleaseItemsRes{}, nil)
+			repoMock.On("UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.Anything).Return(nil)
+
+			err := usecase.RecoverOrders(test.ctx, cfg)
+
+			if test.isErr {
+				assert.Error(t, err)
+				repoMock.AssertNotCalled(t, "ClaimStaleOrder", ctx, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if test.claimErr != nil {
+				repoMock.AssertNotCalled(t, "RollbackTransaction", ctx, cfg, mock.Anything)
+				repoMock.AssertNotCalled(t, "UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.Anything)
+				return
+			}
+
+			sagaId := "order:" + orderId.Hex()
+			if test.kind == "buy" {
+				repoMock.AssertCalled(t, "RollbackTransaction", ctx, cfg, &player.RollbackPlayerTransactionReq{ReferenceId: sagaId + ":debit:0"})
+				repoMock.AssertCalled(t, "RollbackAddPlayerItem", ctx, cfg, &inventory.RollbackInventoryReq{ReferenceId: sagaId + ":add:0"})
+			} else {
+				repoMock.AssertCalled(t, "RollbackTransaction", ctx, cfg, &player.RollbackPlayerTransactionReq{ReferenceId: sagaId + ":credit:0"})
+				repoMock.AssertCalled(t, "RollbackRemovePlayerItem", ctx, cfg, &inventory.RollbackInventoryReq{PlayerId: "player:001", ReferenceId: sagaId + ":remove:0"})
+			}
+
+			if len(test.reservedIds) > 0 {
+				repoMock.AssertCalled(t, "ReleaseItems", ctx, cfg.Grpc.ItemUrl, &itemPb.ReleaseItemsReq{PlayerId: "player:001", Ids: test.reservedIds})
+			} else {
+				repoMock.AssertNotCalled(t, "ReleaseItems", ctx, cfg.Grpc.ItemUrl, mock.Anything)
+			}
+
+			repoMock.AssertCalled(t, "UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == "failed" && req["error"] == "error: order was interrupted"
+			}))
+		})
+	}
+}
+
+func TestFindOrderStatus(t *testing.T) {
+	repoMock := new(paymentRepository.PaymentRepositoryMock)
+	usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+	ctx := context.Background()
+	orderId := bson.NewObjectID()
+
+	tests := []testFindOrderStatus{
+		{
+			name:     "success find order status",
+			ctx:      ctx,
+			playerId: "player:001",
+			orderId:  "order:" + orderId.Hex(),
+			expected: "pending",
+		},
+		{
+			name:     "failed find order status - other player",
+			ctx:      ctx,
+			playerId: "player:002",
+			orderId:  "order:" + orderId.Hex(),
+			isErr:    true,
+		},
+		{
+			name:     "failed find order status - not found",
+			ctx:      ctx,
+			playerId: "player:001",
+			orderId:  "order:" + bson.NilObjectID.Hex(),
+			isErr:    true,
+		},
+	}
+
+	repoMock.On("FindOnePaymentOrder", ctx, orderId.Hex()).Return(&payment.PaymentOrder{
+		Id:       orderId,
+		PlayerId: "player:001",
+		Kind:     "buy",
+		Status:   "pending",
+	}, nil)
+	repoMock.On("FindOnePaymentOrder", ctx, bson.NilObjectID.Hex()).Return((*payment.PaymentOrder)(nil), errors.New("error: payment order not found"))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := usecase.FindOrderStatus(test.ctx, test.playerId, test.orderId)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Status)
+				assert.Equal(t, test.orderId, result.OrderId)
+			}
+		})
+	}
+}
+
+func TestGiftItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	giftId := bson.NewObjectID()
+	layout := "2006-01-02 15:04:05.999 -0700 MST"
+	oldAccount := time.Now().AddDate(0, 0, -30)
+
+	tests := []testGiftItem{
+		{
+			name:      "success gift money",
+			ctx:       ctx,
+			senderId:  "player:001",
+			req:       &payment.GiftReq{RecipientId: "player:002", Money: 100, Message: "gg"},
+			createdAt: oldAccount,
+			isErr:     false,
+		},
+		{
+			name:      "failed gift money - recipient credit fails rolls back debit",
+			ctx:       ctx,
+			senderId:  "player:001",
+			req:       &payment.GiftReq{RecipientId: "player:003", Money: 100},
+			createdAt: oldAccount,
+			isErr:     true,
+		},
+		{
+			name:      "failed gift - account is too new",
+			ctx:       ctx,
+			senderId:  "player:001",
+			req:       &payment.GiftReq{RecipientId: "player:002", Money: 100},
+			createdAt: time.Now(),
+			isErr:     true,
+		},
+		{
+			name:      "failed gift - daily limit reached",
+			ctx:       ctx,
+			senderId:  "player:001",
+			req:       &payment.GiftReq{RecipientId: "player:002", Money: 100},
+			createdAt: oldAccount,
+			limitHit:  true,
+			isErr:     true,
+		},
+		{
+			name:      "failed gift - gift to yourself",
+			ctx:       ctx,
+			senderId:  "player:001",
+			req:       &payment.GiftReq{RecipientId: "001", Money: 100},
+			createdAt: oldAccount,
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			repoMock.On("PushEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+
+			debit := &playerPb.CreatePlayerTransactionReq{PlayerId: test.senderId, Amount: -test.req.Money}
+
+			repoMock.On("FindOnePlayerProfile", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(&playerPb.PlayerProfile{
+				CreatedAt: test.createdAt.Format(layout),
+			}, nil)
+			repoMock.On("ReserveGiftSlot", ctx, test.senderId, mock.Anything, cfg.Gift.DailyLimit).Return(!test.limitHit, nil)
+			repoMock.On("ReleaseGiftSlot", ctx, test.senderId, mock.Anything).Return(nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, debit).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:002", Amount: test.req.Money}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx002"}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:003", Amount: test.req.Money}).Return((*playerPb.CreatePlayerTransactionRes)(nil), errors.New("error: player not found"))
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("InsertOneGift", ctx, mock.Anything).Return(giftId, nil)
+			repoMock.On("InsertOneGiftNotification", ctx, mock.Anything).Return(bson.NewObjectID(), nil)
+
+			result, err := usecase.GiftItem(test.ctx, cfg, test.senderId, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneGift", ctx, mock.Anything)
+				if test.req.RecipientId == "player:003" {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+					repoMock.AssertCalled(t, "ReleaseGiftSlot", ctx, test.senderId, mock.Anything)
+				}
+				if test.limitHit {
+					repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, giftId, result.Id)
+				assert.Equal(t, "player:002", result.RecipientId)
+				repoMock.AssertNotCalled(t, "ReleaseGiftSlot", ctx, test.senderId, mock.Anything)
+				repoMock.AssertCalled(t, "InsertOneGiftNotification", ctx, mock.MatchedBy(func(req *payment.GiftNotification) bool {
+					return req.RecipientId == "player:002" && req.GiftId == "gift:"+giftId.Hex() && req.Message == "gg"
+				}))
+			}
+		})
+	}
+}
+
+func TestOpenLootBox(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	rollId := bson.NewObjectID()
+
+	table := &itemPb.LootTable{
+		Entries: []*itemPb.LootEntry{
+			{ItemId: "item:common", Weight: 1000},
+			{ItemId: "item:rare", Weight: 1, Rare: true},
+		},
+		PityThreshold: 10,
+	}
+
+	tests := []testOpenLootBox{
+		{
+			name:     "success open box",
+			ctx:      ctx,
+			req:      &payment.OpenLootBoxReq{ItemId: "item:box"},
+			table:    &itemPb.LootTable{Entries: []*itemPb.LootEntry{{ItemId: "item:common", Weight: 5}}},
+			price:    100,
+			pity:     3,
+			expected: "item:common",
+			isErr:    false,
+		},
+		{
+			name:     "success open box - free to open",
+			ctx:      ctx,
+			req:      &payment.OpenLootBoxReq{ItemId: "item:box"},
+			table:    &itemPb.LootTable{Entries: []*itemPb.LootEntry{{ItemId: "item:common", Weight: 5}}},
+			expected: "item:common",
+			isErr:    false,
+		},
+		{
+			name:     "success pity guarantees rare drop",
+			ctx:      ctx,
+			req:      &payment.OpenLootBoxReq{ItemId: "item:box"},
+			table:    table,
+			price:    100,
+			pity:     9,
+			expected: "item:rare",
+			isErr:    false,
+		},
+		{
+			name:      "failed open - player has no box",
+			ctx:       ctx,
+			req:       &payment.OpenLootBoxReq{ItemId: "item:box"},
+			table:     table,
+			price:     100,
+			revokeErr: errors.New("error: player does not have enough items"),
+			isErr:     true,
+		},
+		{
+			name:     "failed open - grant fails refunds payment",
+			ctx:      ctx,
+			req:      &payment.OpenLootBoxReq{ItemId: "item:box"},
+			table:    table,
+			price:    100,
+			grantErr: errors.New("error: inventory is full"),
+			isErr:    true,
+		},
+		{
+			name:  "failed open - item is not a loot box",
+			ctx:   ctx,
+			req:   &payment.OpenLootBoxReq{ItemId: "item:box"},
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			revoked := &inventoryPb.BatchRevokeRes{
+				Items: []*inventoryPb.InventoryItem{{InventoryId: "inv001", PlayerId: "player:001", ItemId: "item:box"}},
+			}
+			if test.revokeErr != nil {
+				revoked = nil
+			}
+
+			repoMock.On("PushEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+			repoMock.On("FindItemsInIds", ctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{"item:box"}}).Return(&itemPb.FindItemsInIdsRes{
+				Items: []*itemPb.Item{{Id: "item:box", Price: test.price, LootTable: test.table}},
+			}, nil)
+			repoMock.On("IncLootPity", ctx, "player:001", "item:box", 1).Return(test.pity+1, nil)
+			repoMock.On("IncLootPity", ctx, "player:001", "item:box", mock.Anything).Return(0, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: -test.price}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{
+				Items:        []*inventoryPb.GrantItem{{PlayerId: "player:001", ItemId: "item:box", Quantity: 1}},
+				IncludeBound: true,
+			}).Return(revoked, test.revokeErr)
+			repoMock.On("InsertOneLootRoll", ctx, mock.Anything).Return(rollId, nil)
+			repoMock.On("UpdateOneLootRoll", ctx, rollId.Hex(), mock.Anything).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{
+				Items: []*inventoryPb.InventoryItem{{InventoryId: "inv002"}},
+			}, test.grantErr)
+
+			result, err := usecase.OpenLootBox(test.ctx, cfg, "player:001", test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if test.table != nil {
+					repoMock.AssertCalled(t, "IncLootPity", ctx, "player:001", "item:box", -1)
+				}
+				if test.revokeErr != nil {
+					repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+				}
+				if test.grantErr != nil {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+					repoMock.AssertCalled(t, "UpdateOneLootRoll", ctx, rollId.Hex(), bson.M{"status": "failed", "error": test.grantErr.Error()})
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result.ItemId)
+			assert.Equal(t, "completed", result.Status)
+			assert.Equal(t, test.pity, result.Pity)
+			assert.Equal(t, "inv001", result.InventoryId)
+			repoMock.AssertNotCalled(t, "IncLootPity", ctx, "player:001", "item:box", -1)
+			if result.Rare {
+				repoMock.AssertCalled(t, "IncLootPity", ctx, "player:001", "item:box", -(test.pity + 1))
+			}
+			if test.price > 0 {
+				assert.Equal(t, "tx001", result.TransactionId)
+			} else {
+				repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+			}
+
+			repoMock.On("FindOneLootRoll", ctx, rollId.Hex()).Return(result, nil)
+			verified, err := usecase.VerifyLootRoll(ctx, rollId.Hex())
+			assert.NoError(t, err)
+			assert.True(t, verified.Valid)
+		})
+	}
+}
+
+func TestCraftItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	craftId := bson.NewObjectID()
+
+	recipe := func(successRate float64, consolation string) *itemPb.Recipe {
+		return &itemPb.Recipe{
+			Id:                "recipe:001",
+			Inputs:            []*itemPb.BundleComponent{{ItemId: "item:ore", Quantity: 3}},
+			OutputItemId:      "item:sword",
+			OutputQuantity:    1,
+			Cost:              50,
+			SuccessRate:       successRate,
+			ConsolationItemId: consolation,
+		}
+	}
+
+	tests := []testCraftItem{
+		{
+			name:     "success craft",
+			ctx:      ctx,
+			recipe:   recipe(1, ""),
+			expected: "item:sword",
+			success:  true,
+			isErr:    false,
+		},
+		{
+			name:     "success failed roll grants consolation",
+			ctx:      ctx,
+			recipe:   recipe(math.SmallestNonzeroFloat64, "item:scrap"),
+			expected: "item:scrap",
+			success:  false,
+			isErr:    false,
+		},
+		{
+			name:      "failed craft - missing inputs",
+			ctx:       ctx,
+			recipe:    recipe(1, ""),
+			revokeErr: errors.New("error: player does not have enough tradable items"),
+			isErr:     true,
+		},
+		{
+			name:     "failed craft - grant fails rolls back cost and inputs",
+			ctx:      ctx,
+			recipe:   recipe(1, ""),
+			grantErr: errors.New("error: grant failed"),
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			repoMock.On("PushEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+
+			revoked := &inventoryPb.BatchRevokeRes{Items: []*inventoryPb.InventoryItem{
+				{InventoryId: "inv001", PlayerId: "player:001", ItemId: "item:ore"},
+				{InventoryId: "inv002", PlayerId: "player:001", ItemId: "item:ore"},
+				{InventoryId: "inv003", PlayerId: "player:001", ItemId: "item:ore"},
+			}}
+			if test.revokeErr != nil {
+				revoked = nil
+			}
+
+			repoMock.On("FindOneRecipe", ctx, cfg.Grpc.ItemUrl, &itemPb.FindOneRecipeReq{RecipeId: "recipe:001"}).Return(test.recipe, nil)
+			repoMock.On("InsertOneCraft", ctx, mock.Anything).Return(craftId, nil)
+			repoMock.On("UpdateOneCraft", ctx, craftId.Hex(), mock.Anything).Return(nil)
+			repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{
+				Items: []*inventoryPb.GrantItem{{PlayerId: "player:001", ItemId: "item:ore", Quantity: 3}},
+			}).Return(revoked, test.revokeErr)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: -50}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.MatchedBy(func(req *inventoryPb.BatchGrantReq) bool {
+				return req.Source == "craft"
+			})).Return(&inventoryPb.BatchGrantRes{Items: []*inventoryPb.InventoryItem{{InventoryId: "inv004"}}}, test.grantErr)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.MatchedBy(func(req *inventoryPb.BatchGrantReq) bool {
+				return req.Source == ""
+			})).Return(&inventoryPb.BatchGrantRes{}, nil)
+
+			result, err := usecase.CraftItem(test.ctx, cfg, "player:001", &payment.CraftReq{RecipeId: "001"})
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertCalled(t, "UpdateOneCraft", ctx, craftId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+					return req["status"] == "failed"
+				}))
+				if test.revokeErr != nil {
+					repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+				}
+				if test.grantErr != nil {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+					repoMock.AssertCalled(t, "BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.MatchedBy(func(req *inventoryPb.BatchGrantReq) bool {
+						return req.Source == "" && len(req.Items) == 3
+					}))
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "completed", result.Status)
+			assert.Equal(t, test.success, result.Success)
+			assert.Equal(t, test.expected, result.ItemId)
+			assert.Equal(t, []string{"inv001", "inv002", "inv003"}, result.ConsumedIds)
+			assert.Equal(t, "tx001", result.TransactionId)
+		})
+	}
+}
+
+func TestBuySeasonPass(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	orderId := bson.NewObjectID()
+
+	tests := []testBuySeasonPass{
+		{
+			name:  "success buy season pass",
+			ctx:   ctx,
+			isErr: false,
+		},
+		{
+			name:      "failed buy season pass - not enough money",
+			ctx:       ctx,
+			chargeErr: errors.New("error: player money is not enough"),
+			isErr:     true,
+		},
+		{
+			name:      "failed buy season pass - already owned refunds",
+			ctx:       ctx,
+			unlockErr: errors.New("error: premium pass already owned"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(paymentRepository.PaymentRepositoryMock)
+			usecase := paymentUsecase.NewPaymentUsecase(repoMock)
+
+			repoMock.On("PushEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+
+			charge := &playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}
+			if test.chargeErr != nil {
+				charge = nil
+			}
+
+			repoMock.On("FindActiveSeason", ctx, cfg.Grpc.SeasonUrl).Return(&seasonPb.SeasonRes{SeasonId: "s001", PremiumPrice: 950}, nil)
+			repoMock.On("InsertOnePaymentOrder", ctx, mock.Anything).Return(orderId, nil)
+			repoMock.On("UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.Anything).Return(nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: -950}).Return(charge, test.chargeErr)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("UnlockPremiumPass", ctx, cfg.Grpc.SeasonUrl, &seasonPb.UnlockPremiumPassReq{
+				SeasonId:      "s001",
+				PlayerId:      "player:001",
+				TransactionId: "tx001",
+			}).Return(&seasonPb.SeasonPassRes{SeasonId: "s001", PlayerId: "player:001", Premium: true}, test.unlockErr)
+
+			result, err := usecase.BuySeasonPass(test.ctx, cfg, "player:001")
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertCalled(t, "UpdateOnePaymentOrder", ctx, orderId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+					return req["status"] == "failed"
+				}))
+				if test.chargeErr != nil {
+					repoMock.AssertNotCalled(t, "UnlockPremiumPass", ctx, cfg.Grpc.SeasonUrl, mock.Anything)
+				}
+				if test.unlockErr != nil {
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "completed", result.Status)
+			assert.Equal(t, "season_pass", result.Kind)
+			assert.Equal(t, float64(950), result.Amount)
+			assert.Equal(t, "season:s001", result.Items[0].ItemId)
+			assert.Equal(t, "tx001", result.Items[0].TransactionId)
+			repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+		})
+	}
+}
@@ -41,6 +41,12 @@ type (
 		isErr    bool
 	}
 
+	testRollbackPlayerTransaction struct {
+		name string
+		ctx  context.Context
+		req  *player.RollbackPlayerTransactionReq
+	}
+
 	testGetPlayerSavingAccount struct {
 		name     string
 		ctx      context.Context
@@ -251,6 +257,46 @@ func TestAddPlayerMoney(t *testing.T) {
 	}
 }
 
+func TestRollbackPlayerTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []testRollbackPlayerTransaction{
+		{
+			name: "success rollback player transaction - by id",
+			ctx:  ctx,
+			req:  &player.RollbackPlayerTransactionReq{TransactionId: "tx001"},
+		},
+		{
+			name: "success rollback player transaction - by reference",
+			ctx:  ctx,
+			req:  &player.RollbackPlayerTransactionReq{ReferenceId: "order:001:debit:0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(playerRepository.PlayerRepositoryMock)
+			usecase := playerUsecase.NewPlayerUsecase(repoMock)
+
+			repoMock.On("DeleteOnePlayerTransaction", ctx, mock.Anything).Return(nil)
+			repoMock.On("VoidPlayerTransaction", ctx, mock.Anything).Return(nil)
+
+			usecase.RollbackPlayerTransaction(test.ctx, test.req)
+
+			// A debit that lands after its rollback is voided by the same
+			// reference, so the reference path never deletes by id
+			if test.req.ReferenceId != "" {
+				repoMock.AssertCalled(t, "VoidPlayerTransaction", ctx, test.req.ReferenceId)
+				repoMock.AssertNotCalled(t, "DeleteOnePlayerTransaction", ctx, mock.Anything)
+				return
+			}
+
+			repoMock.AssertCalled(t, "DeleteOnePlayerTransaction", ctx, test.req.TransactionId)
+			repoMock.AssertNotCalled(t, "VoidPlayerTransaction", ctx, mock.Anything)
+		})
+	}
+}
+
 func TestGetPlayerSavingAccount(t *testing.T) {
 	repoMock := new(playerRepository.PlayerRepositoryMock)
 	usecase := playerUsecase.NewPlayerUsecase(repoMock)
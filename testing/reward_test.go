@@ -0,0 +1,348 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Supakornn/mmorpg-shop/modules/inventory"
+	"github.com/Supakornn/mmorpg-shop/modules/payment"
+	"github.com/Supakornn/mmorpg-shop/modules/player"
+	"github.com/Supakornn/mmorpg-shop/modules/reward"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/reward/rewardUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testClaimReward struct {
+		name     string
+		ctx      context.Context
+		calendar *reward.RewardCalendar
+		last     *reward.RewardClaim
+		pushErr  error
+		expected int
+		isErr    bool
+	}
+
+	testRedeemCode struct {
+		name       string
+		ctx        context.Context
+		playerId   string
+		req        *reward.RedeemCodeReq
+		reserveErr error
+		insertErr  error
+		isErr      bool
+	}
+
+	testGrantResult struct {
+		name     string
+		ctx      context.Context
+		key      string
+		res      *payment.PaymentTransferRes
+		expected bson.M
+		isErr    bool
+	}
+)
+
+func TestClaimReward(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	claimId := bson.NewObjectID()
+	calendarId := bson.NewObjectID()
+
+	now := utils.LocalTime()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	twoDaysAgo := now.AddDate(0, 0, -2).Format("2006-01-02")
+	started := now.AddDate(0, 0, -1)
+	ends := now.AddDate(0, 0, 2)
+	ended := now.Add(-time.Hour)
+
+	days := []*reward.RewardDay{
+		{Day: 1, Money: 100},
+		{Day: 2, ItemId: "item:001"},
+		{Day: 3, Money: 300, ItemId: "item:002"},
+	}
+	calendar := func(kind string, startsAt, endsAt *time.Time) *reward.RewardCalendar {
+		return &reward.RewardCalendar{Id: calendarId, Kind: kind, Days: days, StartsAt: startsAt, EndsAt: endsAt, UsageStatus: true}
+	}
+
+	tests := []testClaimReward{
+		{
+			name:     "success first daily claim",
+			ctx:      ctx,
+			calendar: calendar("daily", nil, nil),
+			expected: 1,
+		},
+		{
+			name:     "success daily claim wraps around",
+			ctx:      ctx,
+			calendar: calendar("daily", nil, nil),
+			last:     &reward.RewardClaim{Period: twoDaysAgo, Day: 3},
+			expected: 1,
+		},
+		{
+			name:     "success streak continues",
+			ctx:      ctx,
+			calendar: calendar("streak", nil, nil),
+			last:     &reward.RewardClaim{Period: yesterday, Day: 1},
+			expected: 2,
+		},
+		{
+			name:     "success streak resets after a missed day",
+			ctx:      ctx,
+			calendar: calendar("streak", nil, nil),
+			last:     &reward.RewardClaim{Period: twoDaysAgo, Day: 2},
+			expected: 1,
+		},
+		{
+			name:     "success event pays day since start",
+			ctx:      ctx,
+			calendar: calendar("event", &started, &ends),
+			expected: 2,
+		},
+		{
+			name:     "success push failure is marked failed",
+			ctx:      ctx,
+			calendar: calendar("daily", nil, nil),
+			last:     &reward.RewardClaim{Period: yesterday, Day: 2},
+			pushErr:  errors.New("error: push message failed"),
+			expected: 3,
+		},
+		{
+			name:     "success retry failed grant",
+			ctx:      ctx,
+			calendar: calendar("daily", nil, nil),
+			last:     &reward.RewardClaim{Id: claimId, Period: today, Day: 3, Money: 300, ItemId: "item:002", MoneyStatus: "granted", ItemStatus: "failed"},
+			expected: 3,
+		},
+		{
+			name:     "failed already claimed",
+			ctx:      ctx,
+			calendar: calendar("daily", nil, nil),
+			last:     &reward.RewardClaim{Id: claimId, Period: today, Day: 1, Money: 100, MoneyStatus: "pending"},
+			isErr:    true,
+		},
+		{
+			name:     "failed event has ended",
+			ctx:      ctx,
+			calendar: calendar("event", &started, &ended),
+			isErr:    true,
+		},
+		{
+			name:     "failed calendar disabled",
+			ctx:      ctx,
+			calendar: &reward.RewardCalendar{Id: calendarId, Kind: "daily", Days: days},
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(rewardRepository.RewardRepositoryMock)
+			usecase := rewardUsecase.NewRewardUsecase(repoMock)
+
+			repoMock.On("FindOneCalendar", ctx, calendarId.Hex()).Return(test.calendar, nil)
+			repoMock.On("FindLastClaim", ctx, "player:001", calendarId.Hex()).Return(test.last, nil)
+			repoMock.On("InsertOneClaim", ctx, mock.Anything).Return(claimId, nil)
+			repoMock.On("RetryClaim", ctx, claimId.Hex(), mock.Anything).Return(nil)
+			repoMock.On("UpdateOneClaim", ctx, claimId.Hex(), mock.Anything).Return(nil)
+			repoMock.On("AddPlayerMoney", ctx, cfg, mock.Anything).Return(test.pushErr)
+			repoMock.On("AddPlayerItem", ctx, cfg, mock.Anything).Return(test.pushErr)
+
+			result, err := usecase.ClaimReward(test.ctx, cfg, "player:001", calendarId.Hex())
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneClaim", ctx, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result.Day)
+			assert.Equal(t, today, result.Period)
+
+			if test.last != nil && test.last.Period == today {
+				repoMock.AssertNotCalled(t, "InsertOneClaim", ctx, mock.Anything)
+				repoMock.AssertCalled(t, "RetryClaim", ctx, claimId.Hex(), "item_status")
+				repoMock.AssertNotCalled(t, "AddPlayerMoney", ctx, cfg, mock.Anything)
+				assert.Equal(t, "pending", result.ItemStatus)
+				return
+			}
+
+			day := days[test.expected-1]
+			status := "pending"
+			if test.pushErr != nil {
+				status = "failed"
+			}
+			if day.Money > 0 {
+				assert.Equal(t, status, result.MoneyStatus)
+				repoMock.AssertCalled(t, "AddPlayerMoney", ctx, cfg, mock.MatchedBy(func(r *player.CreatePlayerTransactionReq) bool {
+					return r.Amount == day.Money && r.ReplyTopic == "reward" && r.ReferenceId == claimId.Hex()
+				}))
+			} else {
+				assert.Empty(t, result.MoneyStatus)
+				repoMock.AssertNotCalled(t, "AddPlayerMoney", ctx, cfg, mock.Anything)
+			}
+			if day.ItemId != "" {
+				assert.Equal(t, status, result.ItemStatus)
+			} else {
+				assert.Empty(t, result.ItemStatus)
+				repoMock.AssertNotCalled(t, "AddPlayerItem", ctx, cfg, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRedeemCode(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	redemptionId := bson.NewObjectID()
+
+	promo := &reward.PromoCode{
+		Code:           "LAUNCH-ABCDEFGH2345",
+		Campaign:       "launch",
+		Money:          500,
+		ItemId:         "item:001",
+		MaxRedemptions: 100,
+		UsageStatus:    true,
+	}
+
+	tests := []testRedeemCode{
+		{
+			name:     "success redeem code",
+			ctx:      ctx,
+			playerId: "player:001",
+			req:      &reward.RedeemCodeReq{Code: " launch-abcdefgh2345 "},
+		},
+		{
+			name:       "failed code fully redeemed",
+			ctx:        ctx,
+			playerId:   "player:001",
+			req:        &reward.RedeemCodeReq{Code: "LAUNCH-ABCDEFGH2345"},
+			reserveErr: errors.New("error: promo code is expired or fully redeemed"),
+			isErr:      true,
+		},
+		{
+			name:      "failed code already redeemed gives the count back",
+			ctx:       ctx,
+			playerId:  "player:002",
+			req:       &reward.RedeemCodeReq{Code: "LAUNCH-ABCDEFGH2345"},
+			insertErr: errors.New("error: promo code already redeemed"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(rewardRepository.RewardRepositoryMock)
+			usecase := rewardUsecase.NewRewardUsecase(repoMock)
+
+			repoMock.On("FindOnePromoCode", ctx, promo.Code).Return(promo, nil)
+			repoMock.On("ReservePromoCode", ctx, promo.Code).Return(test.reserveErr)
+			repoMock.On("ReleasePromoCode", ctx, promo.Code).Return(nil)
+			repoMock.On("InsertOneRedemption", ctx, mock.Anything).Return(redemptionId, test.insertErr)
+			repoMock.On("AddPlayerMoney", ctx, cfg, mock.Anything).Return(nil)
+			repoMock.On("AddPlayerItem", ctx, cfg, mock.Anything).Return(nil)
+
+			result, err := usecase.RedeemCode(test.ctx, cfg, test.playerId, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "AddPlayerMoney", ctx, cfg, mock.Anything)
+				if test.insertErr != nil {
+					repoMock.AssertCalled(t, "ReleasePromoCode", ctx, promo.Code)
+				} else {
+					repoMock.AssertNotCalled(t, "InsertOneRedemption", ctx, mock.Anything)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, redemptionId, result.Id)
+			assert.Equal(t, "launch", result.Campaign)
+			assert.Equal(t, "pending", result.MoneyStatus)
+			assert.Equal(t, "pending", result.ItemStatus)
+			repoMock.AssertNotCalled(t, "ReleasePromoCode", ctx, promo.Code)
+			repoMock.AssertCalled(t, "AddPlayerItem", ctx, cfg, mock.MatchedBy(func(r *inventory.UpdateInventoryReq) bool {
+				return r.ItemId == "item:001" && r.Source == "promo" && r.ReferenceId == "redemption:"+redemptionId.Hex()
+			}))
+		})
+	}
+}
+
+func TestGrantResult(t *testing.T) {
+	ctx := context.Background()
+	claimId := bson.NewObjectID().Hex()
+
+	tests := []testGrantResult{
+		{
+			name:     "success money granted",
+			ctx:      ctx,
+			key:      "sell",
+			res:      &payment.PaymentTransferRes{TransactionId: "transaction:001", ReferenceId: claimId},
+			expected: bson.M{"money_status": "granted", "transaction_id": "transaction:001"},
+		},
+		{
+			name:     "success item failed",
+			ctx:      ctx,
+			key:      "buy",
+			res:      &payment.PaymentTransferRes{ReferenceId: claimId, Error: "error: inventory is full"},
+			expected: bson.M{"item_status": "failed", "inventory_id": "", "error": "error: inventory is full"},
+		},
+		{
+			name:     "success promo redemption money granted",
+			ctx:      ctx,
+			key:      "sell",
+			res:      &payment.PaymentTransferRes{TransactionId: "transaction:002", ReferenceId: "redemption:" + claimId},
+			expected: bson.M{"money_status": "granted", "transaction_id": "transaction:002"},
+		},
+		{
+			name:  "failed missing reference",
+			ctx:   ctx,
+			key:   "sell",
+			res:   &payment.PaymentTransferRes{TransactionId: "transaction:001"},
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(rewardRepository.RewardRepositoryMock)
+			usecase := rewardUsecase.NewRewardUsecase(repoMock)
+
+			repoMock.On("UpdateOneClaim", ctx, claimId, mock.Anything).Return(nil)
+			repoMock.On("UpdateOneRedemption", ctx, claimId, mock.Anything).Return(nil)
+
+			err := usecase.GrantResult(test.ctx, test.key, test.res)
+
+			if test.isErr {
+				assert.Error(t, err)
+				repoMock.AssertNotCalled(t, "UpdateOneClaim", ctx, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			method := "UpdateOneClaim"
+			if strings.HasPrefix(test.res.ReferenceId, "redemption:") {
+				method = "UpdateOneRedemption"
+			}
+			repoMock.AssertCalled(t, method, ctx, claimId, mock.MatchedBy(func(req bson.M) bool {
+				for k, v := range test.expected {
+					if req[k] != v {
+						return false
+					}
+				}
+				return req["updated_at"] != nil
+			}))
+		})
+	}
+}
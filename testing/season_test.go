@@ -0,0 +1,205 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season"
+	seasonPb "github.com/Supakornn/mmorpg-shop/modules/season/seasonPb"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/season/seasonUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testClaimTier struct {
+		name     string
+		ctx      context.Context
+		pass     *season.SeasonPass
+		req      *season.ClaimTierReq
+		grantErr error
+		expected string
+		isErr    bool
+	}
+
+	testAddSeasonXp struct {
+		name      string
+		ctx       context.Context
+		req       *seasonPb.AddSeasonXpReq
+		insertErr error
+		expected  int64
+		tier      int32
+		isErr     bool
+	}
+)
+
+func testSeason(seasonId bson.ObjectID) *season.Season {
+	now := utils.LocalTime()
+
+	return &season.Season{
+		Id: seasonId,
+		Tiers: []*season.SeasonTier{
+			{Tier: 1, Xp: 100, FreeItemId: "item:001", PremiumItemId: "item:101"},
+			{Tier: 2, Xp: 300, PremiumItemId: "item:102"},
+			{Tier: 3, Xp: 600, FreeItemId: "item:003", PremiumItemId: "item:103"},
+		},
+		PremiumPrice: 950,
+		StartsAt:     now.Add(-24 * time.Hour),
+		EndsAt:       now.Add(24 * time.Hour),
+		Status:       "active",
+	}
+}
+
+func TestClaimTier(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	seasonId := bson.NewObjectID()
+
+	tests := []testClaimTier{
+		{
+			name:     "success claim free tier",
+			ctx:      ctx,
+			pass:     &season.SeasonPass{PlayerId: "player:001", Xp: 350},
+			req:      &season.ClaimTierReq{Tier: 1, Track: "free"},
+			expected: "item:001",
+		},
+		{
+			name:     "success claim premium tier",
+			ctx:      ctx,
+			pass:     &season.SeasonPass{PlayerId: "player:001", Xp: 350, Premium: true},
+			req:      &season.ClaimTierReq{Tier: 2, Track: "premium"},
+			expected: "item:102",
+		},
+		{
+			name:  "failed claim - tier not reached",
+			ctx:   ctx,
+			pass:  &season.SeasonPass{PlayerId: "player:001", Xp: 350},
+			req:   &season.ClaimTierReq{Tier: 3, Track: "free"},
+			isErr: true,
+		},
+		{
+			name:  "failed claim - premium pass required",
+			ctx:   ctx,
+			pass:  &season.SeasonPass{PlayerId: "player:001", Xp: 350},
+			req:   &season.ClaimTierReq{Tier: 1, Track: "premium"},
+			isErr: true,
+		},
+		{
+			name:  "failed claim - no reward on track",
+			ctx:   ctx,
+			pass:  &season.SeasonPass{PlayerId: "player:001", Xp: 350},
+			req:   &season.ClaimTierReq{Tier: 2, Track: "free"},
+			isErr: true,
+		},
+		{
+			name:     "failed claim - grant fails releases the tier",
+			ctx:      ctx,
+			pass:     &season.SeasonPass{PlayerId: "player:001", Xp: 350},
+			req:      &season.ClaimTierReq{Tier: 1, Track: "free"},
+			grantErr: errors.New("error: grant failed"),
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(seasonRepository.SeasonRepositoryMock)
+			usecase := seasonUsecase.NewSeasonUsecase(repoMock)
+
+			granted := &inventoryPb.BatchGrantRes{Items: []*inventoryPb.InventoryItem{{InventoryId: "inv001"}}}
+			if test.grantErr != nil {
+				granted = nil
+			}
+
+			repoMock.On("FindOneSeason", ctx, seasonId.Hex()).Return(testSeason(seasonId), nil)
+			repoMock.On("FindOnePass", ctx, seasonId.Hex(), "player:001").Return(test.pass, nil)
+			repoMock.On("ReserveTierClaim", ctx, seasonId.Hex(), "player:001", test.req.Track, test.req.Tier, mock.Anything).Return(nil)
+			repoMock.On("ReleaseTierClaim", ctx, seasonId.Hex(), "player:001", test.req.Track, test.req.Tier).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.MatchedBy(func(req *inventoryPb.BatchGrantReq) bool {
+				return req.Source == "season" && req.OverflowToMailbox
+			})).Return(granted, test.grantErr)
+
+			result, err := usecase.ClaimTier(test.ctx, cfg, "player:001", seasonId.Hex(), test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if test.grantErr != nil {
+					repoMock.AssertCalled(t, "ReleaseTierClaim", ctx, seasonId.Hex(), "player:001", test.req.Track, test.req.Tier)
+				} else {
+					repoMock.AssertNotCalled(t, "ReserveTierClaim", ctx, seasonId.Hex(), "player:001", test.req.Track, test.req.Tier, mock.Anything)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result.ItemId)
+			assert.Equal(t, []string{"inv001"}, result.InventoryIds)
+			repoMock.AssertNotCalled(t, "ReleaseTierClaim", ctx, seasonId.Hex(), "player:001", test.req.Track, test.req.Tier)
+		})
+	}
+}
+
+func TestAddSeasonXp(t *testing.T) {
+	ctx := context.Background()
+	seasonId := bson.NewObjectID()
+	eventId := bson.NewObjectID()
+
+	tests := []testAddSeasonXp{
+		{
+			name:     "success add xp",
+			ctx:      ctx,
+			req:      &seasonPb.AddSeasonXpReq{PlayerId: "001", Xp: 250, EventId: "match:001"},
+			expected: 350,
+			tier:     2,
+		},
+		{
+			name:      "success repeated event is not counted",
+			ctx:       ctx,
+			req:       &seasonPb.AddSeasonXpReq{PlayerId: "001", Xp: 250, EventId: "match:001"},
+			insertErr: errors.New("error: xp event already counted"),
+			expected:  100,
+			tier:      1,
+		},
+		{
+			name:  "failed add xp - not positive",
+			ctx:   ctx,
+			req:   &seasonPb.AddSeasonXpReq{PlayerId: "001", Xp: 0},
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(seasonRepository.SeasonRepositoryMock)
+			usecase := seasonUsecase.NewSeasonUsecase(repoMock)
+
+			repoMock.On("FindActiveSeason", ctx).Return(testSeason(seasonId), nil)
+			repoMock.On("InsertOneXpEvent", ctx, mock.Anything).Return(eventId, test.insertErr)
+			repoMock.On("FindOnePass", ctx, seasonId.Hex(), "player:001").Return(&season.SeasonPass{PlayerId: "player:001", Xp: 100}, nil)
+			repoMock.On("AddPassXp", ctx, seasonId.Hex(), "player:001", test.req.Xp).Return(&season.SeasonPass{PlayerId: "player:001", Xp: 100 + test.req.Xp}, nil)
+
+			result, err := usecase.AddSeasonXp(test.ctx, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "AddPassXp", ctx, seasonId.Hex(), "player:001", test.req.Xp)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result.Xp)
+			assert.Equal(t, test.tier, result.Tier)
+			if test.insertErr != nil {
+				repoMock.AssertNotCalled(t, "AddPassXp", ctx, seasonId.Hex(), "player:001", test.req.Xp)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription"
+	subscriptionPb "github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionPb"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/subscription/subscriptionUsecase"
+	"github.com/Supakornn/mmorpg-shop/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testSubscribe struct {
+		name      string
+		ctx       context.Context
+		insertErr error
+		chargeErr error
+		isErr     bool
+	}
+
+	testRunRenewals struct {
+		name      string
+		ctx       context.Context
+		sub       *subscription.Subscription
+		lockErr   error
+		chargeErr error
+		extendErr error
+		expected  string
+	}
+
+	testFindEntitlements struct {
+		name     string
+		ctx      context.Context
+		sub      *subscription.Subscription
+		expected float64
+		active   bool
+	}
+)
+
+func testPlan(planId bson.ObjectID) *subscription.SubscriptionPlan {
+	return &subscription.SubscriptionPlan{
+		Id:            planId,
+		Title:         "VIP",
+		Price:         300,
+		PeriodDays:    30,
+		GraceDays:     3,
+		DailyMoney:    10,
+		SellBonusRate: 0.1,
+		DiscountRate:  0.05,
+		UsageStatus:   true,
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	planId := bson.NewObjectID()
+	subscriptionId := bson.NewObjectID()
+
+	tests := []testSubscribe{
+		{
+			name: "success subscribe",
+			ctx:  ctx,
+		},
+		{
+			name:      "failed subscribe - already subscribed",
+			ctx:       ctx,
+			insertErr: errors.New("error: player already has a subscription"),
+			isErr:     true,
+		},
+		{
+			name:      "failed subscribe - charge fails",
+			ctx:       ctx,
+			chargeErr: errors.New("error: not enough money"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(subscriptionRepository.SubscriptionRepositoryMock)
+			usecase := subscriptionUsecase.NewSubscriptionUsecase(repoMock)
+
+			charged := &playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}
+			if test.chargeErr != nil {
+				charged = nil
+			}
+
+			repoMock.On("FindOnePlan", ctx, planId.Hex()).Return(testPlan(planId), nil)
+			repoMock.On("InsertOneSubscription", ctx, mock.Anything).Return(subscriptionId, test.insertErr)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: -300}).Return(charged, test.chargeErr)
+			repoMock.On("InsertOneCharge", ctx, mock.Anything).Return(bson.NewObjectID(), nil)
+			repoMock.On("UpdateOneSubscription", ctx, subscriptionId.Hex(), mock.Anything).Return(nil)
+
+			result, err := usecase.Subscribe(test.ctx, cfg, "player:001", &subscription.SubscribeReq{PlanId: "plan:" + planId.Hex()})
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if test.chargeErr != nil {
+					repoMock.AssertCalled(t, "UpdateOneSubscription", ctx, subscriptionId.Hex(), mock.MatchedBy(func(req bson.M) bool {
+						return req["status"] == "failed" && req["live"] == false
+					}))
+				}
+				if test.insertErr != nil {
+					repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "active", result.Subscription.Status)
+				assert.Equal(t, result.Subscription.CurrentPeriodEnd, result.Subscription.NextRenewalAt)
+				repoMock.AssertCalled(t, "InsertOneCharge", ctx, mock.MatchedBy(func(req *subscription.SubscriptionCharge) bool {
+					return req.Status == "succeeded" && req.TransactionId == "tx001"
+				}))
+			}
+		})
+	}
+}
+
+func TestRunRenewals(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	planId := bson.NewObjectID()
+	now := utils.LocalTime()
+
+	due := func(method, status string, periodEnd time.Time) *subscription.Subscription {
+		return &subscription.Subscription{
+			Id:               bson.NewObjectID(),
+			PlayerId:         "player:001",
+			PlanId:           planId.Hex(),
+			Method:           method,
+			Status:           status,
+			Live:             true,
+			CurrentPeriodEnd: periodEnd,
+			NextRenewalAt:    periodEnd,
+		}
+	}
+
+	cancelled := due("wallet", "active", now.Add(-time.Minute))
+	cancelled.CancelAtPeriodEnd = true
+
+	tests := []testRunRenewals{
+		{
+			name:     "success wallet renewal extends the period",
+			ctx:      ctx,
+			sub:      due("wallet", "active", now.Add(-time.Minute)),
+			expected: "active",
+		},
+		{
+			name:      "failed wallet renewal moves to grace",
+			ctx:       ctx,
+			sub:       due("wallet", "active", now.Add(-time.Minute)),
+			chargeErr: errors.New("error: not enough money"),
+			expected:  "grace",
+		},
+		{
+			name:      "failed wallet renewal - extend failure rolls back the charge",
+			ctx:       ctx,
+			sub:       due("wallet", "active", now.Add(-time.Minute)),
+			extendErr: errors.New("error: update subscription failed"),
+			expected:  "grace",
+		},
+		{
+			name:      "failed wallet renewal after grace expires",
+			ctx:       ctx,
+			sub:       due("wallet", "grace", now.AddDate(0, 0, -4)),
+			chargeErr: errors.New("error: not enough money"),
+			expected:  "expired",
+		},
+		{
+			name:     "provider without renewal moves to grace",
+			ctx:      ctx,
+			sub:      due("provider", "active", now.Add(-time.Minute)),
+			expected: "grace",
+		},
+		{
+			name:     "cancelled subscription ends",
+			ctx:      ctx,
+			sub:      cancelled,
+			expected: "cancelled",
+		},
+		{
+			name:     "locked subscription is skipped",
+			ctx:      ctx,
+			sub:      due("wallet", "active", now.Add(-time.Minute)),
+			lockErr:  errors.New("error: renewal already taken"),
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(subscriptionRepository.SubscriptionRepositoryMock)
+			usecase := subscriptionUsecase.NewSubscriptionUsecase(repoMock)
+
+			charged := &playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}
+			if test.chargeErr != nil {
+				charged = nil
+			}
+
+			repoMock.On("FindDueSubscriptions", ctx, mock.Anything).Return([]*subscription.Subscription{test.sub}, nil)
+			repoMock.On("LockRenewal", ctx, test.sub.Id.Hex(), test.sub.NextRenewalAt, mock.Anything).Return(test.lockErr)
+			repoMock.On("FindOnePlan", ctx, planId.Hex()).Return(testPlan(planId), nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(charged, test.chargeErr)
+			repoMock.On("InsertOneCharge", ctx, mock.Anything).Return(bson.NewObjectID(), nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			repoMock.On("UpdateOneSubscription", ctx, test.sub.Id.Hex(), mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == "active"
+			})).Return(test.extendErr)
+			repoMock.On("UpdateOneSubscription", ctx, test.sub.Id.Hex(), mock.Anything).Return(nil)
+
+			periodEnd := test.sub.CurrentPeriodEnd
+
+			err := usecase.RunRenewals(test.ctx, cfg)
+
+			assert.NoError(t, err)
+			if test.expected == "" {
+				repoMock.AssertNotCalled(t, "UpdateOneSubscription", ctx, test.sub.Id.Hex(), mock.Anything)
+				return
+			}
+			repoMock.AssertCalled(t, "UpdateOneSubscription", ctx, test.sub.Id.Hex(), mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == test.expected
+			}))
+			if test.expected == "active" {
+				assert.Equal(t, periodEnd.AddDate(0, 0, 30), test.sub.CurrentPeriodEnd)
+			}
+			if test.extendErr != nil {
+				assert.Equal(t, periodEnd, test.sub.CurrentPeriodEnd)
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+			} else {
+				repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+			}
+			if test.sub.Method == "provider" {
+				repoMock.AssertNotCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestFindEntitlements(t *testing.T) {
+	ctx := context.Background()
+	planId := bson.NewObjectID()
+	now := utils.LocalTime()
+
+	tests := []testFindEntitlements{
+		{
+			name:     "success active subscription",
+			ctx:      ctx,
+			sub:      &subscription.Subscription{Id: bson.NewObjectID(), PlanId: planId.Hex(), Status: "active", Live: true, CurrentPeriodEnd: now.AddDate(0, 0, 10)},
+			expected: 0.05,
+			active:   true,
+		},
+		{
+			name:     "success grace subscription keeps benefits",
+			ctx:      ctx,
+			sub:      &subscription.Subscription{Id: bson.NewObjectID(), PlanId: planId.Hex(), Status: "grace", Live: true, CurrentPeriodEnd: now.AddDate(0, 0, -1)},
+			expected: 0.05,
+			active:   true,
+		},
+		{
+			name:     "success pending subscription has no benefits",
+			ctx:      ctx,
+			sub:      &subscription.Subscription{Id: bson.NewObjectID(), PlanId: planId.Hex(), Status: "pending", Live: true},
+			expected: 0,
+		},
+		{
+			name:     "success no subscription",
+			ctx:      ctx,
+			sub:      nil,
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(subscriptionRepository.SubscriptionRepositoryMock)
+			usecase := subscriptionUsecase.NewSubscriptionUsecase(repoMock)
+
+			repoMock.On("FindLiveSubscription", ctx, "player:001").Return(test.sub, nil)
+			repoMock.On("FindOnePlan", ctx, planId.Hex()).Return(testPlan(planId), nil)
+
+			result, err := usecase.FindEntitlements(test.ctx, &subscriptionPb.FindEntitlementsReq{PlayerId: "001"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.active, result.Active)
+			assert.Equal(t, test.expected, result.DiscountRate)
+			assert.Equal(t, "player:001", result.PlayerId)
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	inventoryPb "github.com/Supakornn/mmorpg-shop/modules/inventory/inventoryPb"
+	playerPb "github.com/Supakornn/mmorpg-shop/modules/player/playerPb"
+	"github.com/Supakornn/mmorpg-shop/modules/trade"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeRepository"
+	"github.com/Supakornn/mmorpg-shop/modules/trade/tradeUsecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type (
+	testCreateTrade struct {
+		name     string
+		ctx      context.Context
+		playerId string
+		req      *trade.CreateTradeReq
+		isErr    bool
+	}
+
+	testAcceptTrade struct {
+		name      string
+		ctx       context.Context
+		playerId  string
+		trade     *trade.Trade
+		creditErr error
+		revokeErr error
+		expected  string
+		isErr     bool
+	}
+
+	testCounterTrade struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		expected  string
+		isErr     bool
+	}
+
+	testRecoverTrades struct {
+		name     string
+		ctx      context.Context
+		trade    *trade.Trade
+		expected string
+	}
+)
+
+func TestCreateTrade(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	tradeId := bson.NewObjectID()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	tests := []testCreateTrade{
+		{
+			name:     "success create trade",
+			ctx:      ctx,
+			playerId: "player:001",
+			req: &trade.CreateTradeReq{
+				ToPlayerId: "player:002",
+				Offer:      &trade.TradeOfferReq{ItemIds: []string{swordId, swordId}, Money: 50},
+				Request:    &trade.TradeOfferReq{Money: 300},
+			},
+			isErr: false,
+		},
+		{
+			name:     "failed create trade - balance is not enough releases items",
+			ctx:      ctx,
+			playerId: "player:003",
+			req: &trade.CreateTradeReq{
+				ToPlayerId: "player:002",
+				Offer:      &trade.TradeOfferReq{ItemIds: []string{swordId}, Money: 5000},
+			},
+			isErr: true,
+		},
+		{
+			name:     "failed create trade - trade with yourself",
+			ctx:      ctx,
+			playerId: "player:001",
+			req: &trade.CreateTradeReq{
+				ToPlayerId: "player:001",
+				Offer:      &trade.TradeOfferReq{Money: 50},
+			},
+			isErr: true,
+		},
+		{
+			name:     "failed create trade - nothing to exchange",
+			ctx:      ctx,
+			playerId: "player:001",
+			req:      &trade.CreateTradeReq{ToPlayerId: "player:002"},
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(tradeRepository.TradeRepositoryMock)
+			usecase := tradeUsecase.NewTradeUsecase(repoMock)
+
+			repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchRevokeRes{
+				Items: []*inventoryPb.InventoryItem{
+					{InventoryId: "inventory:001", PlayerId: test.playerId, ItemId: swordId},
+					{InventoryId: "inventory:002", PlayerId: test.playerId, ItemId: swordId},
+				},
+			}, nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: -50}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}, nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:003", Amount: -5000}).Return((*playerPb.CreatePlayerTransactionRes)(nil), errors.New("error: player balance is not enough"))
+			repoMock.On("InsertOneTrade", ctx, mock.Anything).Return(tradeId, nil)
+
+			result, err := usecase.CreateTrade(test.ctx, cfg, test.playerId, test.req)
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				repoMock.AssertNotCalled(t, "InsertOneTrade", ctx, mock.Anything)
+				if test.playerId == "player:003" {
+					repoMock.AssertCalled(t, "BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tradeId, result.Id)
+				assert.Equal(t, "pending", result.Status)
+				assert.Equal(t, test.playerId, result.ProposedBy)
+				assert.Len(t, result.Escrow.Items, 2)
+				assert.Equal(t, "tx001", result.Escrow.TransactionId)
+				assert.Equal(t, 300.0, result.ToOffer.Money)
+			}
+		})
+	}
+}
+
+func TestAcceptTrade(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	newTrade := func() *trade.Trade {
+		return &trade.Trade{
+			Id:           bson.NewObjectID(),
+			FromPlayerId: "player:001",
+			ToPlayerId:   "player:002",
+			FromOffer:    &trade.TradeOffer{ItemIds: []string{swordId}},
+			ToOffer:      &trade.TradeOffer{ItemIds: []string{}, Money: 300},
+			ProposedBy:   "player:001",
+			Round:        1,
+			Status:       "pending",
+			Escrow: &trade.TradeEscrow{
+				PlayerId: "player:001",
+				Items:    []*trade.TradeEscrowItem{{InventoryId: "inventory:001", ItemId: swordId}},
+			},
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	expiredTrade := newTrade()
+	expiredTrade.ExpiresAt = time.Now().Add(-time.Hour)
+
+	tests := []testAcceptTrade{
+		{
+			name:     "success accept trade",
+			ctx:      ctx,
+			playerId: "player:002",
+			trade:    newTrade(),
+			expected: "completed",
+			isErr:    false,
+		},
+		{
+			name:     "failed accept trade - balance is not enough keeps trade pending",
+			ctx:      ctx,
+			playerId: "player:002",
+			trade:    newTrade(),
+			expected: "pending",
+			isErr:    true,
+		},
+		{
+			name:      "failed accept trade - credit fails revokes the granted copies",
+			ctx:       ctx,
+			playerId:  "player:002",
+			trade:     newTrade(),
+			creditErr: errors.New("error: create player transaction failed"),
+			expected:  "pending",
+			isErr:     true,
+		},
+		{
+			name:      "failed accept trade - failed revoke leaves trade processing",
+			ctx:       ctx,
+			playerId:  "player:002",
+			trade:     newTrade(),
+			creditErr: errors.New("error: create player transaction failed"),
+			revokeErr: errors.New("error: inventory not found"),
+			expected:  "processing",
+			isErr:     true,
+		},
+		{
+			name:     "failed accept trade - proposer cannot accept",
+			ctx:      ctx,
+			playerId: "player:001",
+			trade:    newTrade(),
+			isErr:    true,
+		},
+		{
+			name:     "failed accept trade - expired",
+			ctx:      ctx,
+			playerId: "player:002",
+			trade:    expiredTrade,
+			isErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(tradeRepository.TradeRepositoryMock)
+			usecase := tradeUsecase.NewTradeUsecase(repoMock)
+
+			tradeId := test.trade.Id.Hex()
+			debit := &playerPb.CreatePlayerTransactionReq{PlayerId: "player:002", Amount: -300}
+			credit := &playerPb.CreatePlayerTransactionReq{PlayerId: "player:001", Amount: 300}
+			revoke := &inventoryPb.BatchRevokeReq{InventoryIds: []string{"inventory:101"}, MailIds: []string{"mail:001"}}
+
+			repoMock.On("FindOneTrade", ctx, tradeId).Return(test.trade, nil)
+			repoMock.On("LockTrade", ctx, tradeId, 1).Return(nil)
+			repoMock.On("UpdateOneTrade", ctx, tradeId, mock.Anything).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchGrantReq{
+				Items:             []*inventoryPb.GrantItem{{PlayerId: "player:002", ItemId: swordId, Quantity: 1}},
+				OverflowToMailbox: true,
+				Source:            "trade",
+			}).Return(&inventoryPb.BatchGrantRes{
+				Items:  []*inventoryPb.InventoryItem{{InventoryId: "inventory:101", PlayerId: "player:002", ItemId: swordId}},
+				Mailed: []*inventoryPb.MailItem{{MailId: "mail:001", PlayerId: "player:002", ItemId: swordId}},
+			}, nil)
+			repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, revoke).Return(&inventoryPb.BatchRevokeRes{}, test.revokeErr)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+			if test.creditErr != nil {
+				repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, credit).Return((*playerPb.CreatePlayerTransactionRes)(nil), test.creditErr)
+			} else {
+				repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, credit).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx002"}, nil)
+			}
+			if test.expected == "pending" && test.creditErr == nil {
+				repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, debit).Return((*playerPb.CreatePlayerTransactionRes)(nil), errors.New("error: player balance is not enough"))
+			} else {
+				repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, debit).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx001"}, nil)
+			}
+
+			result, err := usecase.AcceptTrade(test.ctx, cfg, test.playerId, tradeId)
+
+			unlocked := mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == "pending"
+			})
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				switch {
+				case test.creditErr == nil:
+					repoMock.AssertNotCalled(t, "BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything)
+					if test.expected == "pending" {
+						repoMock.AssertCalled(t, "UpdateOneTrade", ctx, tradeId, unlocked)
+					}
+				case test.expected == "pending":
+					repoMock.AssertCalled(t, "BatchRevoke", ctx, cfg.Grpc.InventoryUrl, revoke)
+					repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+					repoMock.AssertCalled(t, "UpdateOneTrade", ctx, tradeId, unlocked)
+				default:
+					repoMock.AssertNotCalled(t, "UpdateOneTrade", ctx, tradeId, unlocked)
+					repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything)
+					repoMock.AssertCalled(t, "UpdateOneTrade", ctx, tradeId, mock.MatchedBy(func(req bson.M) bool {
+						settlement, ok := req["settlement"].(*trade.TradeSettlement)
+						return ok && len(settlement.Granted) == 1 && settlement.Escrow != nil
+					}))
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result.Status)
+				assert.Nil(t, result.Escrow)
+				repoMock.AssertCalled(t, "CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, credit)
+			}
+		})
+	}
+}
+
+func TestCounterTrade(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+
+	tests := []testCounterTrade{
+		{
+			name:     "success counter trade",
+			ctx:      ctx,
+			expected: "pending",
+			isErr:    false,
+		},
+		{
+			name:      "failed counter trade - saving the terms releases the new escrow",
+			ctx:       ctx,
+			updateErr: errors.New("error: update one trade failed"),
+			isErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(tradeRepository.TradeRepositoryMock)
+			usecase := tradeUsecase.NewTradeUsecase(repoMock)
+
+			result := &trade.Trade{
+				Id:           bson.NewObjectID(),
+				FromPlayerId: "player:001",
+				ToPlayerId:   "player:002",
+				FromOffer:    &trade.TradeOffer{ItemIds: []string{}, Money: 50},
+				ToOffer:      &trade.TradeOffer{ItemIds: []string{}, Money: 300},
+				ProposedBy:   "player:001",
+				Round:        1,
+				Status:       "pending",
+				Escrow:       &trade.TradeEscrow{PlayerId: "player:001", Money: 50, TransactionId: "tx001"},
+				ExpiresAt:    time.Now().Add(time.Hour),
+			}
+			tradeId := result.Id.Hex()
+			terms := mock.MatchedBy(func(req bson.M) bool {
+				_, ok := req["round"]
+				return ok
+			})
+
+			repoMock.On("FindOneTrade", ctx, tradeId).Return(result, nil)
+			repoMock.On("LockTrade", ctx, tradeId, 1).Return(nil)
+			repoMock.On("UpdateOneTrade", ctx, tradeId, terms).Return(test.updateErr)
+			repoMock.On("UpdateOneTrade", ctx, tradeId, mock.Anything).Return(nil)
+			repoMock.On("CreatePlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.CreatePlayerTransactionReq{PlayerId: "player:002", Amount: -100}).Return(&playerPb.CreatePlayerTransactionRes{TransactionId: "tx002"}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+
+			res, err := usecase.CounterTrade(test.ctx, cfg, "player:002", tradeId, &trade.CounterTradeReq{
+				Offer:   &trade.TradeOfferReq{Money: 100},
+				Request: &trade.TradeOfferReq{Money: 50},
+			})
+
+			if test.isErr {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx002"})
+				repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+				repoMock.AssertCalled(t, "UpdateOneTrade", ctx, tradeId, mock.MatchedBy(func(req bson.M) bool {
+					return req["status"] == "pending"
+				}))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, res.Status)
+				assert.Equal(t, "tx002", res.Escrow.TransactionId)
+				assert.Nil(t, res.Settlement)
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+			}
+		})
+	}
+}
+
+func TestRecoverTrades(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	swordId := "item:" + bson.NewObjectID().Hex()
+
+	newTrade := func(settlement *trade.TradeSettlement) *trade.Trade {
+		return &trade.Trade{
+			Id:           bson.NewObjectID(),
+			FromPlayerId: "player:001",
+			ToPlayerId:   "player:002",
+			Round:        1,
+			Status:       "processing",
+			Escrow:       &trade.TradeEscrow{PlayerId: "player:001", Money: 50, TransactionId: "tx001"},
+			Settlement:   settlement,
+			UpdatedAt:    time.Now().Add(-time.Hour),
+		}
+	}
+
+	tests := []testRecoverTrades{
+		{
+			name: "success recover trade - owed escrow is released",
+			ctx:  ctx,
+			trade: newTrade(&trade.TradeSettlement{
+				Release: &trade.TradeEscrow{PlayerId: "player:002", Items: []*trade.TradeEscrowItem{{InventoryId: "inventory:002", ItemId: swordId}}},
+				Status:  "cancelled",
+			}),
+			expected: "cancelled",
+		},
+		{
+			name: "success recover trade - interrupted accept is undone",
+			ctx:  ctx,
+			trade: newTrade(&trade.TradeSettlement{
+				Escrow:  &trade.TradeEscrow{PlayerId: "player:002", Money: 300, TransactionId: "tx002"},
+				Granted: []string{"inventory:101"},
+				Credits: []string{"tx003"},
+			}),
+			expected: "pending",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(tradeRepository.TradeRepositoryMock)
+			usecase := tradeUsecase.NewTradeUsecase(repoMock)
+
+			tradeId := test.trade.Id.Hex()
+
+			repoMock.On("FindStaleTrades", ctx, mock.Anything).Return([]*trade.Trade{test.trade}, nil)
+			repoMock.On("ClaimStaleTrade", ctx, tradeId, test.trade.UpdatedAt).Return(nil)
+			repoMock.On("UpdateOneTrade", ctx, tradeId, mock.Anything).Return(nil)
+			repoMock.On("BatchGrant", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchGrantRes{}, nil)
+			repoMock.On("BatchRevoke", ctx, cfg.Grpc.InventoryUrl, mock.Anything).Return(&inventoryPb.BatchRevokeRes{}, nil)
+			repoMock.On("RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, mock.Anything).Return(nil)
+
+			err := usecase.RecoverTrades(test.ctx, cfg)
+
+			assert.NoError(t, err)
+			repoMock.AssertCalled(t, "UpdateOneTrade", ctx, tradeId, mock.MatchedBy(func(req bson.M) bool {
+				return req["status"] == test.expected
+			}))
+			repoMock.AssertNotCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx001"})
+			if test.expected == "pending" {
+				repoMock.AssertCalled(t, "BatchRevoke", ctx, cfg.Grpc.InventoryUrl, &inventoryPb.BatchRevokeReq{InventoryIds: []string{"inventory:101"}})
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx003"})
+				repoMock.AssertCalled(t, "RollbackPlayerTransaction", ctx, cfg.Grpc.PlayerUrl, &playerPb.RollbackPlayerTransactionReq{TransactionId: "tx002"})
+			}
+		})
+	}
+}